@@ -327,8 +327,10 @@ func runPause() {
 		// *is* the pause; if it was already paused, unpausing means resuming the
 		// last channel on the new server.
 		wasPlaying := false
+		// A paused stream is interrupted by the restart as well, so that
+		// counts as paused already and plays on the fresh server.
 		if st, err := c.Status(); err == nil {
-			wasPlaying = st.Status != protocol.StatusStopped
+			wasPlaying = st.Status != protocol.StatusStopped && st.Status != protocol.StatusPaused
 		}
 		c = restartForUpgrade(c, serverVersion)
 		if wasPlaying {
@@ -341,9 +343,12 @@ func runPause() {
 	if err != nil {
 		fail("%v", err)
 	}
-	if st.Status == protocol.StatusStopped {
+	switch st.Status {
+	case protocol.StatusPaused:
 		fmt.Println("Paused")
-	} else {
+	case protocol.StatusStopped:
+		fmt.Println("Stopped")
+	default:
 		fmt.Printf("Playing: %s\n", st.ChannelTitle)
 	}
 }
@@ -393,6 +398,8 @@ func runStatus(args []string) {
 		if st.TrackTitle != "" {
 			fmt.Printf("Track:   %s\n", st.TrackTitle)
 		}
+	case protocol.StatusPaused:
+		fmt.Printf("Paused:  %s\n", st.ChannelTitle)
 	case protocol.StatusConnecting:
 		fmt.Printf("Connecting: %s\n", st.ChannelTitle)
 	case protocol.StatusReconnecting:
@@ -403,6 +410,10 @@ func runStatus(args []string) {
 	if st.StreamError != "" {
 		fmt.Printf("Error:   %s\n", st.StreamError)
 	}
+	if st.Muted {
+		fmt.Printf("Volume:  %d%% (muted)\n", volumePercent(st.Volume))
+		return
+	}
 	fmt.Printf("Volume:  %d%%\n", volumePercent(st.Volume))
 }
 
@@ -528,6 +539,40 @@ func showVolume() {
 	fmt.Printf("Volume:  %d%%\n", volumePercent(st.GetVolume()))
 }
 
+// runLoved prints the loved tracks, newest first, one per line with the
+// time each was loved and its channel. With --json, it prints them as a JSON
+// array, which doubles as an export of the list.
+func runLoved(args []string) {
+	args, jsonOut := parseJSONFlag("loved", "soma loved [--json]", args)
+	if len(args) != 0 {
+		fail("usage: soma loved [--json]")
+	}
+	c := ensureServer()
+	defer func() { _ = c.Close() }()
+
+	tracks, err := c.Loved()
+	if err != nil {
+		fail("%v", err)
+	}
+	if jsonOut {
+		printJSON(tracks)
+		return
+	}
+	fmt.Print(formatLovedTracks(tracks))
+}
+
+// formatLovedTracks renders loved tracks as aligned time, title and channel
+// columns, in local time.
+func formatLovedTracks(tracks []protocol.TrackEntry) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, t := range tracks {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", t.Time.Local().Format("2006-01-02 15:04"), t.Title, t.Channel)
+	}
+	_ = w.Flush()
+	return b.String()
+}
+
 func runServerStop() {
 	c, _, running := dialServer()
 	if !running {
@@ -105,10 +105,13 @@ func (d *fakeDaemon) handle(req protocol.Request) any {
 		d.setPlayingLocked(d.payload.Channels[0].ID)
 		return d.status
 	case protocol.MethodPlayPause:
-		if d.status.Status == protocol.StatusStopped {
+		switch d.status.Status {
+		case protocol.StatusStopped:
 			d.setPlayingLocked(d.payload.LastChannelID)
-		} else {
-			d.status = protocol.PlaybackState{Status: protocol.StatusStopped, Volume: d.status.Volume}
+		case protocol.StatusPaused:
+			d.status.Status = protocol.StatusPlaying
+		default:
+			d.status.Status = protocol.StatusPaused
 		}
 		return d.status
 	case protocol.MethodStop:
@@ -214,6 +217,9 @@ func TestRunPause_TogglesBothWays(t *testing.T) {
 
 	out = captureStdout(t, func() { runPause() })
 	assert.Contains(t, out, "Paused")
+
+	out = captureStdout(t, func() { runPause() })
+	assert.Contains(t, out, "Playing: Groove Salad", "pause while paused resumes")
 }
 
 func TestRunPlayRelative_PassesDelta(t *testing.T) {
@@ -2,10 +2,14 @@ package main
 
 import (
 	"flag"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"somad/internal/channels"
+	"somad/internal/config"
 	"somad/internal/protocol"
 
 	"github.com/stretchr/testify/assert"
@@ -164,6 +168,20 @@ func TestFormatChannelList_EmptyCatalog(t *testing.T) {
 	assert.Empty(t, formatChannelList(protocol.ChannelsPayload{}))
 }
 
+func TestFormatLovedTracks(t *testing.T) {
+	at := time.Date(2026, 3, 1, 14, 5, 0, 0, time.Local)
+	out := formatLovedTracks([]protocol.TrackEntry{
+		{Time: at, ChannelID: "groovesalad", Channel: "Groove Salad", Title: "Tycho - Awake"},
+		{Time: at.Add(-time.Hour), ChannelID: "dronezone", Channel: "Drone Zone", Title: "Stars of the Lid - Requiem"},
+	})
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "2026-03-01 14:05  Tycho - Awake               Groove Salad", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "2026-03-01 13:05  Stars of the Lid - Requiem"))
+
+	assert.Empty(t, formatLovedTracks(nil))
+}
+
 func TestParseVolumeArg(t *testing.T) {
 	tests := []struct {
 		arg      string
@@ -254,3 +272,14 @@ func TestPrintFlagDefaults_UsesDoubleDashes(t *testing.T) {
 	assert.Contains(t, out, "(default true)")
 	assert.NotContains(t, out, "\n  -listen", "options must not be shown with a single dash")
 }
+
+func TestUseConfigFile_ExportsAbsolutePath(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv(config.EnvPath, "")
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	require.NoError(t, useConfigFile("soma.yaml"))
+
+	assert.Equal(t, filepath.Join(cwd, "soma.yaml"), os.Getenv(config.EnvPath))
+}
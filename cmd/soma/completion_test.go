@@ -39,14 +39,14 @@ func TestPrintChannelCompletions_NoCache(t *testing.T) {
 func TestCompletionScriptsCoverCLI(t *testing.T) {
 	commands := []string{
 		"play", "list", "favorite", "next", "prev", "pause", "stop",
-		"status", "volume", "daemon", "completion",
+		"status", "volume", "loved", "daemon", "completion",
 	}
 	flags := []string{
 		// global connection/TUI flags
 		"--server", "--tls", "--tls-ca", "--tls-fingerprint", "--psk-file",
-		"--shutdown-on-exit",
+		"--shutdown-on-exit", "--compact", "--config",
 		// daemon flags
-		"--idle-timeout", "--no-tray", "--listen", "--tls-cert", "--tls-key",
+		"--idle-timeout", "--no-tray", "--reconnect-attempts", "--max-http-requests", "--stream-quality", "--player", "--equalizer", "--normalize", "--silence-timeout", "--catalog-ttl", "--refresh-interval", "--probe-interval", "--notify", "--now-playing-file", "--musicbrainz", "--listen", "--tls-cert", "--tls-key",
 		"--show-cert",
 		// per-command output flag
 		"--json",
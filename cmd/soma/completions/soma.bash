@@ -23,21 +23,33 @@ _soma() {
     fi
 
     local global_flags="--server --tls --tls-ca --tls-fingerprint --psk-file
-        --shutdown-on-exit --version --help"
+        --shutdown-on-exit --compact --config --version --help"
     local commands="play list favorite next prev pause stop status volume
-        daemon completion help version"
+        loved daemon completion help version"
 
     # Flags whose value is the next word (or follows "=").
     case "$prev" in
-    --tls-ca | --psk-file | --tls-cert | --tls-key)
+    --tls-ca | --psk-file | --tls-cert | --tls-key | --config | --now-playing-file)
         compopt -o default 2>/dev/null # complete filenames
         COMPREPLY=()
         return
         ;;
-    --server | --tls-fingerprint | --listen | --idle-timeout)
+    --server | --tls-fingerprint | --listen | --idle-timeout | --reconnect-attempts | --max-http-requests | --silence-timeout | --catalog-ttl | --refresh-interval | --probe-interval)
         COMPREPLY=()
         return
         ;;
+    --stream-quality)
+        COMPREPLY=($(compgen -W "low high highest" -- "$cur"))
+        return
+        ;;
+    --equalizer)
+        COMPREPLY=($(compgen -W "flat bass speech" -- "$cur"))
+        return
+        ;;
+    --player)
+        COMPREPLY=($(compgen -W "internal mpv" -- "$cur"))
+        return
+        ;;
     esac
 
     # Find the subcommand: the first non-flag word, skipping values of
@@ -47,8 +59,8 @@ _soma() {
     for ((i = 1; i < COMP_CWORD; i++)); do
         w="${COMP_WORDS[i]}"
         case "$w" in
-        --server=* | --tls-ca=* | --tls-fingerprint=* | --psk-file=*) ;;
-        --server | --tls-ca | --tls-fingerprint | --psk-file)
+        --server=* | --tls-ca=* | --tls-fingerprint=* | --psk-file=* | --config=*) ;;
+        --server | --tls-ca | --tls-fingerprint | --psk-file | --config)
             ((i++))
             [[ "${COMP_WORDS[i]}" == "=" ]] && ((i++))
             ;;
@@ -81,11 +93,11 @@ _soma() {
             COMPREPLY=($(compgen -W "$(soma completion channels 2>/dev/null | cut -f1)" -- "$cur"))
         fi
         ;;
-    list | status)
+    list | status | loved)
         COMPREPLY=($(compgen -W "--json" -- "$cur"))
         ;;
     daemon)
-        COMPREPLY=($(compgen -W "stop --idle-timeout --no-tray --listen --tls
+        COMPREPLY=($(compgen -W "stop --idle-timeout --no-tray --reconnect-attempts --max-http-requests --stream-quality --player --equalizer --normalize --silence-timeout --catalog-ttl --refresh-interval --probe-interval --notify --now-playing-file --musicbrainz --listen --tls
             --tls-cert --tls-key --psk-file --insecure --show-cert" -- "$cur"))
         ;;
     completion)
@@ -19,11 +19,14 @@ import (
 
 	"somad/internal/app"
 	"somad/internal/audio"
+	"somad/internal/channels"
 	"somad/internal/client"
 	"somad/internal/config"
+	"somad/internal/directory"
 	"somad/internal/platform"
 	"somad/internal/platform/tray"
 	"somad/internal/protocol"
+	"somad/internal/security"
 	"somad/internal/server"
 	"somad/internal/state"
 	"somad/internal/tlsutil"
@@ -73,12 +76,20 @@ func main() {
 	fs.StringVar(&cf.tlsFingerprint, "tls-fingerprint", "", "pin the server certificate by SHA-256 fingerprint (implies --tls)")
 	fs.StringVar(&cf.pskFile, "psk-file", "", "file holding the server's pre-shared key")
 	shutdownOnExit := fs.Bool("shutdown-on-exit", false, "stop playback and shut down the server when the TUI exits")
+	noAltScreen := fs.Bool("no-altscreen", false, "render the TUI inline instead of on the alternate screen")
+	compact := fs.Bool("compact", false, "start the TUI collapsed to a channel selector and status line")
+	configPath := fs.String("config", "", "read the config file from this path (also via $"+config.EnvPath+")")
 	showVersion := fs.Bool("version", false, "print version information")
 	_ = fs.Parse(args)
 	if *showVersion {
 		fmt.Printf("soma %s (commit: %s, built: %s)\n", version, commit, date)
 		return
 	}
+	if *configPath != "" {
+		if err := useConfigFile(*configPath); err != nil {
+			fail("%v", err)
+		}
+	}
 	rest := fs.Args()
 
 	// The daemon-start form dispatches before anything client-side happens;
@@ -87,9 +98,14 @@ func main() {
 		// The global client flags don't apply to the daemon itself; refuse
 		// rather than silently ignoring them, naming the offending flag —
 		// "put it after the subcommand" would be wrong advice for the
-		// TUI-only --shutdown-on-exit.
+		// TUI-only --shutdown-on-exit, --no-altscreen and --compact.
+		// --config is the exception: it names the file the daemon reads.
 		var set []string
-		fs.Visit(func(f *flag.Flag) { set = append(set, "--"+f.Name) })
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name != "config" {
+				set = append(set, "--"+f.Name)
+			}
+		})
 		if len(set) > 0 {
 			fail("%s does not apply to the daemon; daemon flags go after the subcommand: soma daemon [flags]", strings.Join(set, ", "))
 		}
@@ -115,13 +131,58 @@ func main() {
 	}
 
 	if len(rest) == 0 {
-		// The config file supplies the default only when the flag was not
+		// The config file supplies the defaults only when the flag was not
 		// given explicitly.
-		so := *shutdownOnExit
+		opts := tuiOptions{shutdownOnExit: *shutdownOnExit, noAltScreen: *noAltScreen, compact: *compact}
 		if !flagWasSet(fs, "shutdown-on-exit") && cfg.TUI.ShutdownOnExit != nil {
-			so = *cfg.TUI.ShutdownOnExit
+			opts.shutdownOnExit = *cfg.TUI.ShutdownOnExit
+		}
+		if !flagWasSet(fs, "no-altscreen") && cfg.TUI.AltScreen != nil {
+			opts.noAltScreen = !*cfg.TUI.AltScreen
+		}
+		if !flagWasSet(fs, "compact") && cfg.TUI.Compact != nil {
+			opts.compact = *cfg.TUI.Compact
+		}
+		opts.grid = cfg.TUI.Grid != nil && *cfg.TUI.Grid
+		opts.splash = cfg.TUI.Splash != nil && *cfg.TUI.Splash
+		opts.utcTimes = cfg.TUI.UTCTimes != nil && *cfg.TUI.UTCTimes
+		opts.prebuffer = cfg.TUI.Prebuffer != nil && *cfg.TUI.Prebuffer
+		opts.visualizer = cfg.TUI.Visualizer != nil && *cfg.TUI.Visualizer
+		opts.showGenre = cfg.TUI.SecondaryLine != nil && *cfg.TUI.SecondaryLine == "genre"
+		if cfg.TUI.DefaultSort != nil {
+			opts.sort = app.SortOrder(*cfg.TUI.DefaultSort)
+		}
+		if cfg.TUI.MinQuality != nil {
+			opts.minQuality = *cfg.TUI.MinQuality
+		}
+		if cfg.TUI.SpaceKey != nil {
+			opts.spaceAction = app.SpaceAction(*cfg.TUI.SpaceKey)
 		}
-		runTUI(so)
+		if cfg.TUI.PreviewDelay != nil {
+			opts.previewDelay = time.Duration(*cfg.TUI.PreviewDelay)
+		}
+		opts.details = cfg.TUI.Details != nil && *cfg.TUI.Details
+		opts.columns = ui.ListColumns(cfg.TUI.Columns, cfg.TUI.ColumnWidths)
+		if cfg.TUI.DetailsWidth != nil {
+			opts.detailsWidth = *cfg.TUI.DetailsWidth
+		}
+		var themeName string
+		if cfg.TUI.Theme != nil {
+			themeName = *cfg.TUI.Theme
+		}
+		if opts.theme, err = ui.LookupTheme(themeName, cfg.TUI.Colors); err != nil {
+			fail("error loading config: %v", err)
+		}
+		opts.customAccent = cfg.TUI.CustomAccent
+		opts.customGlyph = cfg.TUI.CustomGlyph
+		if cfg.TUI.TrackSearch != nil {
+			opts.trackSearch = *cfg.TUI.TrackSearch
+		}
+		opts.imageProtocol = ui.DetectImageProtocol()
+		if cfg.TUI.ImageProtocol != nil && *cfg.TUI.ImageProtocol != "auto" {
+			opts.imageProtocol = ui.ImageProtocol(*cfg.TUI.ImageProtocol)
+		}
+		runTUI(opts)
 		return
 	}
 
@@ -149,6 +210,8 @@ func main() {
 		runStatus(rest[1:])
 	case "volume":
 		runVolume(rest[1:])
+	case "loved":
+		runLoved(rest[1:])
 	default:
 		fmt.Fprintf(os.Stderr, "soma: unknown command %q\n\n", rest[0])
 		printUsage(os.Stderr)
@@ -192,7 +255,9 @@ func printFlagDefaults(fs *flag.FlagSet) {
 func printUsage(w io.Writer) {
 	_, _ = fmt.Fprint(w, `Usage:
   soma                        start the TUI (spawns the playback server if needed)
-                                 (--shutdown-on-exit stops playback and server on quit)
+                                 (--shutdown-on-exit stops playback and server on quit;
+                                  --no-altscreen renders inline, keeping scrollback;
+                                  --compact fits small panes: selector + status)
   soma play [channel]         play a channel by ID or name, or resume the
                                  last played channel (spawns the server if needed)
   soma list [--json]          list all channels (favorites first, marked *)
@@ -204,6 +269,7 @@ func printUsage(w io.Writer) {
   soma stop                   stop playback
   soma status [--json]        show what is playing
   soma volume [<0-100>|+n|-n] show, set, or adjust the playback volume
+  soma loved [--json]         list the tracks marked as loved (l in the TUI)
   soma daemon [flags]         run the playback server in the foreground
                                  (--no-tray hides the tray / menu-bar icon;
                                   --listen <host:port> also serves frontends
@@ -223,6 +289,10 @@ machine instead of the local one:
   --tls-fingerprint <fp>      pin the server certificate ("sha256:...", as
                                  printed by soma daemon --show-cert)
   --psk-file <file>           read the server's pre-shared key from a file
+
+  --config <file>             read settings from this file instead of the
+                                 default location (also via $SOMAD_CONFIG;
+                                 applies to soma daemon too)
 `)
 	if path, err := config.Path(); err == nil {
 		_, _ = fmt.Fprintf(w, `
@@ -240,6 +310,7 @@ Server and connection flags can also be set in %s
     psk: "secret"
   tui:
     shutdown_on_exit: true
+    alt_screen: false  # render inline (same as --no-altscreen)
 `, path)
 	}
 }
@@ -250,7 +321,7 @@ func runServer(args []string) {
 	// On first start, materialize a commented-out template so the settings
 	// are discoverable; failing to (e.g. a read-only home) is no reason not
 	// to run.
-	if path, created, err := config.EnsureTemplate(server.DefaultIdleTimeout); err != nil {
+	if path, created, err := config.EnsureTemplate(server.DefaultIdleTimeout, security.DefaultMaxConcurrentRequests, server.DefaultCatalogTTL, server.DefaultRefreshInterval); err != nil {
 		log.Printf("warning: could not write the default config template: %v", err)
 	} else if created {
 		log.Printf("wrote a default config template to %s", path)
@@ -266,7 +337,31 @@ func runServer(args []string) {
 	if cfg.Server.IdleTimeout != nil {
 		defaultIdleTimeout = time.Duration(*cfg.Server.IdleTimeout)
 	}
+	var defaultSilenceTimeout time.Duration
+	if cfg.Server.SilenceTimeout != nil {
+		defaultSilenceTimeout = time.Duration(*cfg.Server.SilenceTimeout)
+	}
+	defaultCatalogTTL := server.DefaultCatalogTTL
+	if cfg.Server.CatalogTTL != nil {
+		defaultCatalogTTL = time.Duration(*cfg.Server.CatalogTTL)
+	}
+	defaultRefreshInterval := server.DefaultRefreshInterval
+	if cfg.Server.RefreshInterval != nil {
+		defaultRefreshInterval = time.Duration(*cfg.Server.RefreshInterval)
+	}
+	var defaultProbeInterval time.Duration
+	if cfg.Server.ProbeInterval != nil {
+		defaultProbeInterval = time.Duration(*cfg.Server.ProbeInterval)
+	}
 	defaultNoTray := cfg.Server.Tray != nil && !*cfg.Server.Tray
+	defaultReconnectAttempts := 0
+	if cfg.Server.ReconnectAttempts != nil {
+		defaultReconnectAttempts = *cfg.Server.ReconnectAttempts
+	}
+	defaultMaxHTTPRequests := security.DefaultMaxConcurrentRequests
+	if cfg.Server.MaxHTTPRequests != nil {
+		defaultMaxHTTPRequests = *cfg.Server.MaxHTTPRequests
+	}
 	str := func(p *string) string {
 		if p == nil {
 			return ""
@@ -284,6 +379,32 @@ func runServer(args []string) {
 		"exit after this long with no clients and stopped playback (0 disables)")
 	noTray := fs.Bool("no-tray", defaultNoTray,
 		"do not show the system tray / menu-bar icon while the server runs")
+	reconnectAttempts := fs.Int("reconnect-attempts", defaultReconnectAttempts,
+		"give up and stop after this many failed reconnects in a row (0 retries forever)")
+	maxHTTPRequests := fs.Int("max-http-requests", defaultMaxHTTPRequests,
+		"run at most this many background HTTP requests (catalog, playlists, directory) at once")
+	streamQuality := fs.String("stream-quality", str(cfg.Server.StreamQuality),
+		"SomaFM stream quality to play until a client picks one: low, high or highest (empty: best available)")
+	playerBackend := fs.String("player", str(cfg.Server.Player),
+		"audio backend: internal, or mpv to play through an external mpv (empty: internal)")
+	equalizer := fs.String("equalizer", str(cfg.Server.Equalizer),
+		"equalizer preset to play with until a client picks one: flat, bass or speech")
+	normalize := fs.Bool("normalize", cfg.Server.Normalize != nil && *cfg.Server.Normalize,
+		"even out loudness across channels")
+	catalogTTL := fs.Duration("catalog-ttl", defaultCatalogTTL,
+		"start from the cached channel list for this long after downloading it (0 always downloads)")
+	refreshInterval := fs.Duration("refresh-interval", defaultRefreshInterval,
+		"download the channel list again this often while running (0 disables)")
+	probeInterval := fs.Duration("probe-interval", defaultProbeInterval,
+		"check this often that every channel's stream answers (0 disables)")
+	silenceTimeout := fs.Duration("silence-timeout", defaultSilenceTimeout,
+		"reconnect a stream that plays only silence for this long (0 disables)")
+	notify := fs.Bool("notify", cfg.Server.Notify != nil && *cfg.Server.Notify,
+		"show a desktop notification when the track changes")
+	nowPlayingFile := fs.String("now-playing-file", str(cfg.Server.NowPlayingFile),
+		"keep this file holding the playing channel, artist and song, e.g. for a streaming overlay")
+	musicBrainz := fs.Bool("musicbrainz", cfg.Server.MusicBrainz != nil && *cfg.Server.MusicBrainz,
+		"look each new track up on MusicBrainz for its album, year and IDs")
 	listen := fs.String("listen", str(cfg.Server.Listen),
 		"also listen for frontends on this TCP host:port (empty: Unix socket only)")
 	tlsOn := fs.Bool("tls", cfg.Server.TLS != nil && *cfg.Server.TLS,
@@ -299,6 +420,34 @@ func runServer(args []string) {
 	showCert := fs.Bool("show-cert", false,
 		"print the TLS certificate path and fingerprint, then exit")
 	_ = fs.Parse(args)
+	if *reconnectAttempts < 0 {
+		log.Fatal("--reconnect-attempts must not be negative")
+	}
+	if *maxHTTPRequests < 1 {
+		log.Fatal("--max-http-requests must be at least 1")
+	}
+	if *silenceTimeout < 0 {
+		log.Fatal("--silence-timeout must not be negative")
+	}
+	if *catalogTTL < 0 {
+		log.Fatal("--catalog-ttl must not be negative")
+	}
+	if *refreshInterval < 0 {
+		log.Fatal("--refresh-interval must not be negative")
+	}
+	if *probeInterval < 0 {
+		log.Fatal("--probe-interval must not be negative")
+	}
+	if *streamQuality != "" && !channels.ValidQuality(*streamQuality) {
+		log.Fatal("--stream-quality must be one of low, high, highest")
+	}
+	if *playerBackend != "" && *playerBackend != "internal" && *playerBackend != "mpv" {
+		log.Fatal("--player must be one of internal, mpv")
+	}
+	if *equalizer != "" && !audio.ValidEqualizer(*equalizer) {
+		log.Fatal("--equalizer must be one of flat, bass, speech")
+	}
+	security.SetMaxConcurrentRequests(*maxHTTPRequests)
 
 	certPath, keyPath := *tlsCert, *tlsKey
 	if (certPath == "") != (keyPath == "") {
@@ -356,7 +505,7 @@ func runServer(args []string) {
 		listeners = append(listeners, tcpLn)
 	}
 
-	player, err := audio.NewPlayer(userAgent())
+	player, err := newPlayer(*playerBackend, *normalize, *silenceTimeout)
 	if err != nil {
 		cleanup()
 		log.Fatalf("error initializing the audio player: %v", err)
@@ -374,6 +523,21 @@ func runServer(args []string) {
 		log.Printf("warning: MPRIS initialization failed: %v", err)
 	}
 
+	sleep, err := platform.NewSleepWatcher()
+	if err != nil {
+		// Without logind the stream just drops over a suspend and reconnects.
+		log.Printf("warning: suspend detection unavailable: %v", err)
+	}
+
+	var notifier *platform.Notifier
+	if *notify {
+		if notifier, err = platform.NewNotifier(); err != nil {
+			log.Printf("warning: desktop notifications unavailable: %v", err)
+		} else if notifier == nil {
+			log.Print("warning: desktop notifications are not supported on this platform")
+		}
+	}
+
 	// The tray icon lives in the server process, so it appears whenever the
 	// server is running. It is skipped when disabled, unsupported, or when no
 	// GUI is present (a headless host), so the server still runs anywhere.
@@ -383,14 +547,26 @@ func runServer(args []string) {
 	}
 
 	srv := server.New(server.Config{
-		Version:     version,
-		UserAgent:   userAgent(),
-		Player:      player,
-		State:       appState,
-		MPRIS:       mpris,
-		Tray:        tr,
-		IdleTimeout: *idleTimeout,
-		PSK:         psk,
+		Version:        version,
+		UserAgent:      userAgent(),
+		Player:         player,
+		State:          appState,
+		MPRIS:          mpris,
+		Tray:           tr,
+		Sleep:          sleep,
+		Notifier:       notifier,
+		NowPlayingFile: *nowPlayingFile,
+		MusicBrainz:    *musicBrainz,
+		Sources:        stationSources(cfg.Server.Sources, customStations(cfg.Stations)),
+		CatalogTTL:     *catalogTTL,
+		IdleTimeout:    *idleTimeout,
+		PSK:            psk,
+
+		RefreshInterval:   *refreshInterval,
+		ProbeInterval:     *probeInterval,
+		ReconnectAttempts: *reconnectAttempts,
+		StreamQuality:     *streamQuality,
+		Equalizer:         *equalizer,
 	})
 
 	// The server must survive its spawning terminal closing; SIGINT/SIGTERM
@@ -477,6 +653,25 @@ func checkTCPSecurity(addr string, useTLS bool, psk string, insecure bool) error
 	return nil
 }
 
+// newPlayer creates the audio backend the server plays through. The mpv
+// backend does its own decoding, so the filters on the decoded audio do not
+// apply to it and asking for them only warns.
+func newPlayer(backend string, normalize bool, silenceTimeout time.Duration) (audio.Player, error) {
+	if backend == "mpv" {
+		if normalize || silenceTimeout > 0 {
+			log.Print("warning: --normalize and --silence-timeout have no effect with --player mpv")
+		}
+		return audio.NewMPVPlayer(userAgent())
+	}
+	player, err := audio.NewPlayer(userAgent())
+	if err != nil {
+		return nil, err
+	}
+	player.SetNormalize(normalize)
+	player.SetSilenceTimeout(silenceTimeout)
+	return player, nil
+}
+
 // isLoopbackAddr reports whether a listen address can only be reached from
 // this machine. An empty host (":5454") binds all interfaces.
 func isLoopbackAddr(addr string) bool {
@@ -519,13 +714,50 @@ func listenTCP(addr string, useTLS bool, certPath, keyPath, psk string, insecure
 	return tcpLn, nil
 }
 
-func runTUI(shutdownOnExit bool) {
+// tuiOptions are the TUI settings resolved from flags and the config file.
+type tuiOptions struct {
+	shutdownOnExit bool
+	noAltScreen    bool
+	compact        bool
+	grid           bool
+	splash         bool
+	utcTimes       bool
+	showGenre      bool
+	sort           app.SortOrder
+	minQuality     string
+	spaceAction    app.SpaceAction
+	previewDelay   time.Duration
+	prebuffer      bool
+	visualizer     bool
+	details        bool
+	detailsWidth   int
+	// columns are the list's columns right of the title; nil keeps the
+	// default.
+	columns []ui.Column
+	// theme colors every part of the TUI.
+	theme ui.Theme
+	// customAccent and customGlyph override the delegate's accent for
+	// non-SomaFM stations; nil keeps the theme's.
+	customAccent *string
+	customGlyph  *string
+	// imageProtocol draws the album art in the now-playing pane.
+	imageProtocol ui.ImageProtocol
+	trackSearch   string
+}
+
+func runTUI(opts tuiOptions) {
+	shutdownOnExit := opts.shutdownOnExit
 	c, hr, err := client.EnsureServer(endpoint, version)
 	if err != nil {
 		fmt.Printf("Alas, there's been an error reaching the soma daemon: %v\n", err)
 		os.Exit(1)
 	}
 
+	// Settle the color profile before the first render, so the palette
+	// degrades cleanly on 256/16-color terminals and over SSH.
+	ui.UseTerminalColorProfile(os.Stdout)
+	ui.ApplyTheme(opts.theme)
+
 	// Create the main application model (need playing ID for delegate)
 	m := &app.Model{
 		Backend: c,
@@ -534,6 +766,22 @@ func runTUI(shutdownOnExit bool) {
 		ServerVersion:  hr.ServerVersion,
 		Loading:        true,
 		ShutdownOnExit: shutdownOnExit,
+		NoAltScreen:    opts.noAltScreen,
+		Compact:        opts.compact,
+		Grid:           opts.grid,
+		Splash:         opts.splash,
+		UTC:            opts.utcTimes,
+		ShowGenre:      opts.showGenre,
+		Sort:           opts.sort,
+		MinQuality:     opts.minQuality,
+		SpaceAction:    opts.spaceAction,
+		PreviewDelay:   opts.previewDelay,
+		Prebuffer:      opts.prebuffer,
+		Visualizer:     opts.visualizer,
+		DetailsOpen:    opts.details,
+		DetailsWidth:   opts.detailsWidth,
+		ImageProtocol:  opts.imageProtocol,
+		TrackSearch:    opts.trackSearch,
 		About: app.AboutInfo{
 			Version: version,
 			Commit:  commit,
@@ -550,11 +798,22 @@ func runTUI(shutdownOnExit bool) {
 	}
 
 	// Initialize the Bubble Tea list component with styled delegate
-	delegate := ui.NewStyledDelegate(&m.PlayingID, m.IsMatch, m.IsFavorite)
+	delegate := ui.NewStyledDelegate(&m.PlayingID, m.IsMatch, m.IsFavorite, m.IsCustom)
+	delegate.ShowGenre = &m.ShowGenre
+	delegate.TrendChecker = m.ListenerTrend
+	delegate.DeadChecker = m.IsDead
+	delegate.Columns = opts.columns
+	if opts.customAccent != nil {
+		delegate.CustomColor = lipgloss.Color(*opts.customAccent)
+	}
+	if opts.customGlyph != nil {
+		delegate.CustomGlyph = *opts.customGlyph
+	}
 	l := list.New([]list.Item{}, delegate, 0, 0)
 	l.SetShowTitle(false)        // We render our own header with column titles
 	l.SetFilteringEnabled(false) // Disable filtering, we use search instead
 	l.SetStatusBarItemName("channel", "channels")
+	l.KeyMap = app.NewListKeyMap() // g, G, h, l, d and f are app keys
 	l.Styles.PaginationStyle = lipgloss.NewStyle().Foreground(ui.SubtleColor)
 	l.Styles.HelpStyle = lipgloss.NewStyle().Foreground(ui.SubtleColor).Padding(0, 0, 0, 2)
 
@@ -566,9 +825,14 @@ func runTUI(shutdownOnExit bool) {
 		return shortHelp
 	}
 	m.List = l
+	m.Delegate = delegate
 
 	// Start the Bubble Tea program with window size handling
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	var progOpts []tea.ProgramOption
+	if !opts.noAltScreen {
+		progOpts = append(progOpts, tea.WithAltScreen())
+	}
+	p := tea.NewProgram(m, progOpts...)
 
 	// Bridge server events into the Bubble Tea program, reconnecting (and
 	// respawning the server) when the connection drops.
@@ -656,3 +920,53 @@ func reconnect() (*client.Client, string, error) {
 	}
 	return nil, "", fmt.Errorf("lost connection to the soma daemon and could not restore it: %w", err)
 }
+
+// useConfigFile points config loading at path for this process and, through
+// the environment, for a server it auto-spawns. The path is made absolute so
+// it names the same file whatever directory it is later resolved from.
+func useConfigFile(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("invalid --config path: %w", err)
+	}
+	return os.Setenv(config.EnvPath, abs)
+}
+
+// stationSources returns the catalog's sources: the ones the config file
+// names, else SomaFM followed by the config file's stations, if it lists
+// any.
+func stationSources(names []string, stations []channels.Channel) []directory.Directory {
+	if names == nil {
+		names = []string{directory.SomaFMName}
+		if len(stations) > 0 {
+			names = append(names, directory.StationsName)
+		}
+	}
+	sources := make([]directory.Directory, 0, len(names))
+	for _, name := range names {
+		src, err := directory.New(name, stations)
+		if err != nil {
+			log.Printf("warning: %v; skipping it", err)
+			continue
+		}
+		sources = append(sources, src)
+	}
+	return sources
+}
+
+// customStations turns the config file's stations into channels. Two titles
+// that differ only in punctuation would share an ID; the first one wins.
+func customStations(stations []config.Station) []channels.Channel {
+	chs := make([]channels.Channel, 0, len(stations))
+	seen := make(map[string]bool, len(stations))
+	for _, st := range stations {
+		ch := channels.Custom(st.Title, st.URL, st.Description, st.Genre)
+		if seen[ch.ID] {
+			log.Printf("warning: station %q has the same ID as another (%s); skipping it", st.Title, ch.ID)
+			continue
+		}
+		seen[ch.ID] = true
+		chs = append(chs, ch)
+	}
+	return chs
+}
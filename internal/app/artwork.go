@@ -0,0 +1,178 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg" // covers are JPEG or PNG
+	_ "image/png"
+	"strings"
+
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// maxArtworkRows caps the cover's height in the now-playing pane; it is
+// twice as many columns wide, which is square in a typical cell.
+const maxArtworkRows = 16
+
+// artworkChromeRows is what the now-playing pane spends besides the cover:
+// border, header, track lines, footer and the spacing between them.
+const artworkChromeRows = 12
+
+// artworkMsg carries the cover of the track titled Title; Image is nil when
+// none was found.
+type artworkMsg struct {
+	Title string
+	Image image.Image
+	Err   error
+}
+
+// fetchArtworkCmd asks the server for the playing track's cover and
+// decodes it off the UI goroutine.
+func (m *Model) fetchArtworkCmd() tea.Cmd {
+	b := m.Backend
+	m.artworkLoading = true
+	return func() tea.Msg {
+		result, err := b.Artwork()
+		if err != nil || len(result.Image) == 0 {
+			return artworkMsg{Title: result.Title, Err: err}
+		}
+		img, _, err := image.Decode(bytes.NewReader(result.Image))
+		return artworkMsg{Title: result.Title, Image: img, Err: err}
+	}
+}
+
+// ToggleArtwork opens or closes the now-playing pane, fetching the cover
+// on open unless it is the playing track's already or the terminal cannot
+// show it.
+func (m *Model) ToggleArtwork() tea.Cmd {
+	m.ArtworkOpen = !m.ArtworkOpen
+	if !m.ArtworkOpen || m.ImageProtocol == ui.ImageNone ||
+		m.Snapshot.TrackTitle == "" || m.Snapshot.TrackTitle == m.artworkTitle {
+		return nil
+	}
+	return m.fetchArtworkCmd()
+}
+
+// applyArtwork records a fetched cover. A failed fetch or undecodable image
+// leaves the pane without a cover rather than retrying.
+func (m *Model) applyArtwork(msg artworkMsg) {
+	m.artworkLoading = false
+	m.artworkTitle = msg.Title
+	m.artwork = msg.Image
+	m.artworkSeq, m.artworkSeqKey = "", ""
+	if msg.Err != nil {
+		m.RequestErr = "artwork failed: " + msg.Err.Error()
+		m.artwork = nil
+	}
+}
+
+// refreshArtwork fetches the cover for a new track while the pane or the
+// full-screen now-playing view is open.
+func (m *Model) refreshArtwork(prevTrack string) tea.Cmd {
+	if !m.ArtworkOpen && !m.NowPlayingOpen || m.ImageProtocol == ui.ImageNone ||
+		m.Snapshot.TrackTitle == prevTrack || m.Snapshot.TrackTitle == "" {
+		return nil
+	}
+	return m.fetchArtworkCmd()
+}
+
+// updateArtwork handles keys while the now-playing pane is open: esc or A
+// closes it.
+func (m *Model) updateArtwork(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m.quitCmd()
+	case "esc", "A":
+		m.ArtworkOpen = false
+	}
+	return nil
+}
+
+// artworkSize is the cover's size in cells for the current window: as
+// large as fits beside the pane's other rows, up to maxArtworkRows.
+func (m *Model) artworkSize() (cols, rows int) {
+	rows = min(m.List.Height()-artworkChromeRows, maxArtworkRows, (m.Width-8)/2)
+	if rows < 2 {
+		return 0, 0
+	}
+	return rows * 2, rows
+}
+
+// renderArtworkImage returns the cover's rows: blank cells, with the image
+// drawn over them from the end of the last one. The escape sequence is
+// kept for as long as the cover and its size stay the same, since encoding
+// it is too slow to repeat every frame.
+func (m *Model) renderArtworkImage(cols, rows int) []string {
+	key := fmt.Sprintf("%s\x00%dx%d", m.artworkTitle, cols, rows)
+	if m.artworkSeqKey != key {
+		m.artworkSeq = ui.RenderImage(m.artwork, cols, rows, m.ImageProtocol)
+		m.artworkSeqKey = key
+	}
+	lines := make([]string, rows)
+	for i := range lines {
+		lines[i] = strings.Repeat(" ", cols)
+	}
+	lines[rows-1] += m.artworkSeq
+	return lines
+}
+
+// renderArtwork renders the now-playing pane: the cover, where the terminal
+// can show images, above the song, artist, album and year (when known),
+// channel and MusicBrainz ID (when looked up), as a bordered box centered
+// over the list area.
+func (m *Model) renderArtwork() string {
+	width := max(m.Width-8, 20)
+	subtle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+
+	var lines []string
+	cols, rows := m.artworkSize()
+	switch {
+	case m.Snapshot.TrackTitle == "":
+		lines = append(lines, subtle.Render("Nothing is playing."))
+	case m.ImageProtocol == ui.ImageNone:
+		lines = append(lines, subtle.Render("This terminal cannot show images."))
+	case m.artworkLoading || m.artworkTitle != m.Snapshot.TrackTitle:
+		lines = append(lines, subtle.Render("Looking up the cover…"))
+	case m.artwork == nil:
+		lines = append(lines, subtle.Render("No cover found."))
+	case cols == 0:
+		lines = append(lines, subtle.Render("The window is too small for the cover."))
+	default:
+		lines = append(lines, m.renderArtworkImage(cols, rows)...)
+	}
+
+	song := m.Snapshot.TrackSong
+	if song == "" {
+		song = m.Snapshot.TrackTitle
+	}
+	lines = append(lines, "",
+		ansi.Truncate(lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true).Render(song), width, "…"),
+		ansi.Truncate(lipgloss.NewStyle().Foreground(ui.TextColor).Render(m.Snapshot.TrackArtist), width, "…"),
+	)
+	album := m.Snapshot.TrackAlbum
+	if m.Snapshot.TrackYear > 0 {
+		album = strings.TrimSpace(fmt.Sprintf("%s (%d)", album, m.Snapshot.TrackYear))
+	}
+	if album != "" {
+		lines = append(lines, ansi.Truncate(subtle.Render(album), width, "…"))
+	}
+	lines = append(lines, ansi.Truncate(subtle.Render(m.Snapshot.ChannelTitle), width, "…"))
+	if m.Snapshot.TrackRecordingID != "" {
+		lines = append(lines, ansi.Truncate(subtle.Render("MusicBrainz recording "+m.Snapshot.TrackRecordingID), width, "…"))
+	}
+
+	header := ui.TitleStyle.UnsetMarginLeft().Render("Now playing")
+	footer := subtle.Render("esc closes")
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.SubtleColor).
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, "", strings.Join(lines, "\n"), "", footer))
+
+	return lipgloss.Place(m.Width, m.List.Height(), lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"somad/internal/protocol"
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// playingTrack puts m in the playing state with the given track.
+func playingTrack(m *Model, title, artist, song string) {
+	m.Update(ServerStateMsg{State: protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "groovesalad", ChannelTitle: "Groove Salad",
+		TrackTitle: title, TrackArtist: artist, TrackSong: song, TrackAlbum: song + " (album)",
+	}})
+}
+
+func testCoverPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+	return buf.Bytes()
+}
+
+func TestArtwork_ShowsTrackAndCover(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 120, 50
+	m.List.SetHeight(40)
+	m.ImageProtocol = ui.ImageKitty
+	playingTrack(m, "Bonobo - Kerala", "Bonobo", "Kerala")
+	backend(m).artwork = protocol.ArtworkResult{Title: "Bonobo - Kerala", Image: testCoverPNG(t)}
+
+	_, cmd := sendKey(m, 'A')
+	require.True(t, m.ArtworkOpen)
+	require.NotNil(t, cmd, "opening the pane fetches the cover")
+	assert.Contains(t, m.View(), "Looking up the cover")
+	m.Update(runCmd(cmd))
+
+	view := m.View()
+	assert.Contains(t, view, "Now playing")
+	assert.Contains(t, view, "Kerala")
+	assert.Contains(t, view, "Bonobo")
+	assert.Contains(t, view, "Kerala (album)")
+	assert.Contains(t, view, "Groove Salad")
+	assert.Contains(t, view, "\x1b_Ga=T", "the cover is drawn")
+
+	_, cmd = sendKey(m, 'A')
+	assert.Nil(t, cmd)
+	assert.False(t, m.ArtworkOpen)
+	assert.Contains(t, m.View(), ui.ClearImages(ui.ImageKitty), "closing removes the cover")
+
+	_, cmd = sendKey(m, 'A')
+	assert.Nil(t, cmd, "the cover of the same track is not fetched again")
+}
+
+func TestArtwork_ShowsMusicBrainzDetails(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 120
+	m.ImageProtocol = ui.ImageNone
+	m.Update(ServerStateMsg{State: protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "groovesalad", ChannelTitle: "Groove Salad",
+		TrackTitle: "Tycho - Awake", TrackArtist: "Tycho", TrackSong: "Awake",
+		TrackAlbum: "Awake (LP)", TrackYear: 2014, TrackRecordingID: "rec-1",
+	}})
+
+	sendKey(m, 'A')
+	view := m.View()
+	assert.Contains(t, view, "Awake (LP) (2014)")
+	assert.Contains(t, view, "MusicBrainz recording rec-1")
+}
+
+func TestArtwork_NoCover(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 120
+	m.ImageProtocol = ui.ImageSixel
+	playingTrack(m, "Unknown - Untitled", "Unknown", "Untitled")
+	backend(m).artwork = protocol.ArtworkResult{Title: "Unknown - Untitled"}
+
+	_, cmd := sendKey(m, 'A')
+	m.Update(runCmd(cmd))
+	assert.Contains(t, m.View(), "No cover found.")
+	assert.Empty(t, m.RequestErr)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, m.ArtworkOpen)
+}
+
+func TestArtwork_WithoutImageSupportSkipsTheFetch(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 120
+	m.ImageProtocol = ui.ImageNone
+	playingTrack(m, "Bonobo - Kerala", "Bonobo", "Kerala")
+
+	_, cmd := sendKey(m, 'A')
+	assert.Nil(t, cmd)
+	view := m.View()
+	assert.Contains(t, view, "This terminal cannot show images.")
+	assert.Contains(t, view, "Kerala")
+}
+
+func TestArtwork_RefetchesOnTrackChangeWhileOpen(t *testing.T) {
+	m := newTestModel(t)
+	m.ImageProtocol = ui.ImageITerm
+	m.ArtworkOpen = true
+
+	_, cmd := m.Update(ServerStateMsg{State: protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "dronezone", TrackTitle: "New Song",
+	}})
+	batch, ok := runCmd(cmd).(tea.BatchMsg)
+	require.True(t, ok, "the level poll and the cover fetch")
+	assert.Len(t, batch, 2)
+	assert.True(t, m.artworkLoading)
+
+	m.ArtworkOpen = false
+	_, cmd = m.Update(ServerStateMsg{State: protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "dronezone", TrackTitle: "Next Song",
+	}})
+	_, isBatch := runCmd(cmd).(tea.BatchMsg)
+	assert.False(t, isBatch, "a closed pane fetches nothing")
+}
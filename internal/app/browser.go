@@ -0,0 +1,66 @@
+package app
+
+import (
+	"net/url"
+	"strings"
+
+	"somad/internal/platform"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openURL opens a URL in the default browser. A variable so tests can
+// capture the URL instead of launching a browser.
+var openURL = platform.OpenURL
+
+// TrackSearchURLs are the track search presets, by name. "{query}" stands
+// for the escaped "Artist Song" of the playing track.
+var TrackSearchURLs = map[string]string{
+	"youtube":     "https://www.youtube.com/results?search_query={query}",
+	"bandcamp":    "https://bandcamp.com/search?q={query}",
+	"musicbrainz": "https://musicbrainz.org/search?type=recording&query={query}",
+}
+
+// DefaultTrackSearch is the preset used when TrackSearch is empty.
+const DefaultTrackSearch = "youtube"
+
+// BrowserMsg reports the outcome of opening a track search.
+type BrowserMsg struct {
+	Query string
+	Err   error
+}
+
+// trackSearchURL builds the search URL for a track from TrackSearch, a
+// preset name or a URL template with "{query}". The query is "Artist Song"
+// when the artist is known, the whole title otherwise.
+func (m *Model) trackSearchURL(title, artist, song string) (query, link string) {
+	query = title
+	if artist != "" {
+		query = artist + " " + song
+	}
+	template := m.TrackSearch
+	if template == "" {
+		template = DefaultTrackSearch
+	}
+	if preset, ok := TrackSearchURLs[template]; ok {
+		template = preset
+	}
+	return query, strings.ReplaceAll(template, "{query}", url.QueryEscape(query))
+}
+
+// searchTrackCmd opens a web search for a track in the browser.
+func (m *Model) searchTrackCmd(title, artist, song string) tea.Cmd {
+	query, link := m.trackSearchURL(title, artist, song)
+	return func() tea.Msg {
+		return BrowserMsg{Query: query, Err: openURL(link)}
+	}
+}
+
+// SearchTrack opens a web search for the playing track in the browser, so
+// a track heard on the radio is easy to find and buy.
+func (m *Model) SearchTrack() tea.Cmd {
+	if m.Snapshot.TrackTitle == "" {
+		return nil
+	}
+	return m.searchTrackCmd(m.Snapshot.TrackTitle, m.Snapshot.TrackArtist, m.Snapshot.TrackSong)
+}
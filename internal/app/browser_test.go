@@ -0,0 +1,64 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubOpenURL captures the URLs the TUI opens for one test.
+func stubOpenURL(t *testing.T, err error) *[]string {
+	t.Helper()
+	var opened []string
+	prev := openURL
+	openURL = func(u string) error {
+		opened = append(opened, u)
+		return err
+	}
+	t.Cleanup(func() { openURL = prev })
+	return &opened
+}
+
+func TestSearchTrack_OpensThePreset(t *testing.T) {
+	opened := stubOpenURL(t, nil)
+	m := newTestModel(t)
+
+	_, cmd := sendKey(m, 'o')
+	assert.Nil(t, cmd, "nothing to search while no track plays")
+
+	playingTrack(m, "Tycho - Awake", "Tycho", "Awake")
+	_, cmd = sendKey(m, 'o')
+	m.Update(runCmd(cmd))
+	assert.Equal(t, []string{"https://www.youtube.com/results?search_query=Tycho+Awake"}, *opened)
+	assert.Equal(t, "Searching the web for Tycho Awake", m.Notice)
+
+	m.TrackSearch = "bandcamp"
+	_, cmd = sendKey(m, 'o')
+	m.Update(runCmd(cmd))
+	assert.Equal(t, "https://bandcamp.com/search?q=Tycho+Awake", (*opened)[1])
+}
+
+func TestSearchTrack_CustomTemplate(t *testing.T) {
+	opened := stubOpenURL(t, nil)
+	m := newTestModel(t)
+	m.TrackSearch = "https://www.discogs.com/search?q={query}&type=all"
+	playingTrack(m, "Station ID & more", "", "Station ID & more")
+
+	_, cmd := sendKey(m, 'o')
+	m.Update(runCmd(cmd))
+	require.Len(t, *opened, 1)
+	assert.Equal(t, "https://www.discogs.com/search?q=Station+ID+%26+more&type=all", (*opened)[0],
+		"a title without an artist is searched whole, escaped")
+}
+
+func TestSearchTrack_ReportsFailure(t *testing.T) {
+	stubOpenURL(t, errors.New("xdg-open: not found"))
+	m := newTestModel(t)
+	playingTrack(m, "Tycho - Awake", "Tycho", "Awake")
+
+	_, cmd := sendKey(m, 'o')
+	m.Update(runCmd(cmd))
+	assert.Contains(t, m.RequestErr, "xdg-open: not found")
+}
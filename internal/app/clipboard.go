@@ -0,0 +1,37 @@
+package app
+
+import (
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+// copyToClipboard puts text on the clipboard. A variable so tests can capture
+// the text instead of touching the real clipboard.
+var copyToClipboard = writeClipboard
+
+// writeClipboard uses the system clipboard when a clipboard tool is
+// available. Without one (typically over SSH) it asks the terminal to copy
+// via OSC 52, which cannot report failure, so that path always succeeds.
+func writeClipboard(text string) error {
+	if !clipboard.Unsupported && clipboard.WriteAll(text) == nil {
+		return nil
+	}
+	termenv.Copy(text)
+	return nil
+}
+
+// ClipboardMsg reports the outcome of a clipboard copy.
+type ClipboardMsg struct {
+	What string // what was copied, for the confirmation ("station ID")
+	Text string
+	Err  error
+}
+
+// copyCmd copies text off the Update goroutine, since the system clipboard
+// shells out to a helper tool.
+func copyCmd(what, text string) tea.Cmd {
+	return func() tea.Msg {
+		return ClipboardMsg{What: what, Text: text, Err: copyToClipboard(text)}
+	}
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"somad/internal/ui"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureClipboard swaps copyToClipboard for a recorder (failing with err
+// when set) for the duration of the test.
+func captureClipboard(t *testing.T, err error) *[]string {
+	t.Helper()
+	var copied []string
+	prev := copyToClipboard
+	copyToClipboard = func(text string) error {
+		copied = append(copied, text)
+		return err
+	}
+	t.Cleanup(func() { copyToClipboard = prev })
+	return &copied
+}
+
+func TestUpdate_CopyStationID(t *testing.T) {
+	copied := captureClipboard(t, nil)
+	m := newTestModel(t)
+	m.Width = 200
+	m.List.Select(1)
+	sel, ok := m.List.SelectedItem().(ui.Item)
+	require.True(t, ok)
+
+	_, cmd := sendKey(m, 'y')
+	m.Update(runCmd(cmd))
+
+	assert.Equal(t, []string{sel.Channel.ID}, *copied)
+	assert.Contains(t, m.RenderStatusBar(), "Copied station ID: "+sel.Channel.ID)
+
+	// The confirmation goes away with the next key press.
+	sendKey(m, 'j')
+	assert.Empty(t, m.Notice)
+}
+
+func TestUpdate_CopyStationIDFailure(t *testing.T) {
+	captureClipboard(t, errors.New("no display"))
+	m := newTestModel(t)
+
+	_, cmd := sendKey(m, 'y')
+	m.Update(runCmd(cmd))
+
+	assert.Empty(t, m.Notice)
+	assert.Contains(t, m.RequestErr, "copy failed: no display")
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"errors"
 
+	"somad/internal/channels"
 	"somad/internal/client"
 	"somad/internal/protocol"
 
@@ -14,10 +15,32 @@ import (
 type Backend interface {
 	Status() (protocol.PlaybackState, error)
 	Channels() (protocol.ChannelsPayload, error)
+	RefreshChannels() error
 	Play(channelID string) (protocol.PlaybackState, error)
+	Preview(channelID string) (protocol.PlaybackState, error)
+	Prebuffer(channelID string) error
 	Stop() (protocol.PlaybackState, error)
+	Level() (float64, error)
+	Spectrum(bands int) ([]float64, error)
+	Stats() (protocol.StatsResult, error)
+	History() ([]protocol.TrackEntry, error)
+	Loved() ([]protocol.TrackEntry, error)
+	ToggleLove(channelID, title string) (protocol.LovedResult, error)
+	Artwork() (protocol.ArtworkResult, error)
+	ChannelArtwork(channelID string) (protocol.ArtworkResult, error)
+	RecentSongs(channelID string) (protocol.RecentSongsResult, error)
+	PlayPause() (protocol.PlaybackState, error)
 	SetVolume(v float64) (protocol.PlaybackState, error)
+	ToggleMute() (protocol.PlaybackState, error)
+	SetStreamQuality(quality string) (protocol.PlaybackState, error)
+	SetEqualizer(preset string) (protocol.PlaybackState, error)
+	TimeShift(seconds float64, live bool) (protocol.PlaybackState, error)
 	ToggleFavorite(channelID string) ([]string, error)
+	MoveFavorite(channelID string, delta int) ([]string, error)
+	DismissFavoritesHint() error
+	SetChannelSort(sort string) error
+	SetChannelSource(source string) error
+	SearchStations(query string) ([]channels.Channel, error)
 	// Shutdown stops the server so the reconnect loop respawns a fresh one; the
 	// TUI uses it to upgrade an out-of-date server when the user changes or
 	// stops the stream.
@@ -74,10 +97,20 @@ type FavoritesMsg struct {
 	Favorites []string
 }
 
+// StationsMsg carries the results of a station directory search.
+type StationsMsg struct {
+	Query    string
+	Channels []channels.Channel
+}
+
 // opLoadChannels marks catalog fetches so Update can escalate a failure
 // during the initial load to the full error screen.
 const opLoadChannels = "loading channels"
 
+// opSearchStations marks directory searches so Update can clear the
+// searching indicator when one fails.
+const opSearchStations = "station search"
+
 // requestErr wraps a failed request as a RequestErrorMsg — except for
 // connection loss, which the event bridge already surfaces as ServerLostMsg.
 func requestErr(op string, err error) tea.Msg {
@@ -111,6 +144,19 @@ func (m *Model) fetchChannels() tea.Cmd {
 	}
 }
 
+// refreshChannelsCmd asks the server to download the catalog now. The
+// header says so until the channels event with the outcome arrives.
+func (m *Model) refreshChannelsCmd() tea.Cmd {
+	m.Refreshing = true
+	b := m.Backend
+	return func() tea.Msg {
+		if err := b.RefreshChannels(); err != nil {
+			return requestErr("refresh channels", err)
+		}
+		return nil
+	}
+}
+
 // playCmd starts a channel on the server. Progress and failures arrive as
 // pushed state events, so the returned snapshot is just the fast path.
 func (m *Model) playCmd(channelID string) tea.Cmd {
@@ -149,10 +195,20 @@ func (m *Model) quitCmd() tea.Cmd {
 	b := m.Backend
 	shutdown := m.ShutdownOnExit
 	onExit := m.OnExit
+	previewing := m.Snapshot.Preview
+	prebuffered := m.prebuffered != ""
 	return func() tea.Msg {
 		if onExit != nil {
 			onExit()
 		}
+		// A preview or prebuffer belongs to this TUI's cursor; leaving
+		// ends it.
+		if previewing && !shutdown {
+			_, _ = b.Preview("")
+		}
+		if prebuffered && !shutdown {
+			_ = b.Prebuffer("")
+		}
 		if shutdown {
 			_ = b.Shutdown()
 		}
@@ -160,6 +216,16 @@ func (m *Model) quitCmd() tea.Cmd {
 	}
 }
 
+// stopPlaybackCmd stops playback. Stopping interrupts the stream anyway, so
+// an out-of-date server is upgraded while we're at it (the fresh one comes up
+// stopped).
+func (m *Model) stopPlaybackCmd() tea.Cmd {
+	if m.skewed() && m.Snapshot.Status != protocol.StatusStopped {
+		return m.restartCmd()
+	}
+	return m.stopCmd()
+}
+
 // stopCmd halts playback on the server.
 func (m *Model) stopCmd() tea.Cmd {
 	b := m.Backend
@@ -172,6 +238,18 @@ func (m *Model) stopCmd() tea.Cmd {
 	}
 }
 
+// playPauseCmd pauses or resumes playback on the server.
+func (m *Model) playPauseCmd() tea.Cmd {
+	b := m.Backend
+	return func() tea.Msg {
+		st, err := b.PlayPause()
+		if err != nil {
+			return requestErr("pause", err)
+		}
+		return ServerStateMsg{State: st}
+	}
+}
+
 // setVolumeCmd applies a volume on the server, which clamps and persists it.
 func (m *Model) setVolumeCmd(v float64) tea.Cmd {
 	b := m.Backend
@@ -183,3 +261,81 @@ func (m *Model) setVolumeCmd(v float64) tea.Cmd {
 		return ServerStateMsg{State: st}
 	}
 }
+
+// toggleMuteCmd mutes or unmutes playback on the server.
+func (m *Model) toggleMuteCmd() tea.Cmd {
+	b := m.Backend
+	return func() tea.Msg {
+		st, err := b.ToggleMute()
+		if err != nil {
+			return requestErr("mute", err)
+		}
+		return ServerStateMsg{State: st}
+	}
+}
+
+// timeShiftCmd moves playback seconds further behind the live stream, or
+// toward it when negative; live jumps back to the live stream.
+func (m *Model) timeShiftCmd(seconds float64, live bool) tea.Cmd {
+	b := m.Backend
+	return func() tea.Msg {
+		st, err := b.TimeShift(seconds, live)
+		if err != nil {
+			return requestErr("time shift", err)
+		}
+		return ServerStateMsg{State: st}
+	}
+}
+
+// nextQuality is the stream quality the Q key switches to after current;
+// empty (the best available) counts as "highest".
+var nextQuality = map[string]string{"": "high", "highest": "high", "high": "low", "low": "highest"}
+
+// setQualityCmd picks the preferred stream quality on the server, which
+// persists it and reconnects a playing channel.
+func (m *Model) setQualityCmd(quality string) tea.Cmd {
+	b := m.Backend
+	return func() tea.Msg {
+		st, err := b.SetStreamQuality(quality)
+		if err != nil {
+			return requestErr("stream quality", err)
+		}
+		return ServerStateMsg{State: st}
+	}
+}
+
+// setEqualizerCmd picks the equalizer preset on the server, which persists
+// it and applies it to the playing stream.
+func (m *Model) setEqualizerCmd(preset string) tea.Cmd {
+	b := m.Backend
+	return func() tea.Msg {
+		st, err := b.SetEqualizer(preset)
+		if err != nil {
+			return requestErr("equalizer", err)
+		}
+		return ServerStateMsg{State: st}
+	}
+}
+
+// searchStationsCmd queries the station directory through the server.
+func (m *Model) searchStationsCmd(query string) tea.Cmd {
+	b := m.Backend
+	return func() tea.Msg {
+		chs, err := b.SearchStations(query)
+		if err != nil {
+			return requestErr(opSearchStations, err)
+		}
+		return StationsMsg{Query: query, Channels: chs}
+	}
+}
+
+// switchChannelCmd plays a channel. Changing channel interrupts the stream
+// anyway, so an out-of-date server is restarted first and the channel is
+// played once the reconnect delivers a fresh backend.
+func (m *Model) switchChannelCmd(id string) tea.Cmd {
+	if m.skewed() {
+		m.pendingPlayID = id
+		return m.restartCmd()
+	}
+	return m.playCmd(id)
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"somad/internal/ui"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// overlayOpen reports whether a view that takes over the list area is
+// open. Compact mode makes way for these, so every key keeps showing what
+// it opens.
+func (m *Model) overlayOpen() bool {
+	return m.Dashboard || m.PlayedOpen || m.ListeningOpen || m.EqualizerOpen || m.GenresOpen ||
+		m.StatsOpen || m.HistoryOpen || m.LovedOpen || m.ArtworkOpen
+}
+
+// renderSelector renders the compact mode's stand-in for the list: the
+// highlighted channel with its position, marks and listener count, e.g.
+// "‹ 3/42 › ▶ ♥ Groove Salad · 1,042 ♪".
+func (m *Model) renderSelector() string {
+	i, ok := m.List.SelectedItem().(ui.Item)
+	if !ok {
+		return lipgloss.NewStyle().Foreground(ui.SubtleColor).Render("No channels")
+	}
+	idx := m.List.Index()
+	title := i.Title()
+	if m.IsCustom(idx) {
+		title = ui.DefaultCustomGlyph + " " + title
+	}
+	if m.IsFavorite(idx) {
+		title = "♥ " + title
+	}
+	if m.IsDead(idx) {
+		title = "⚠ " + title
+	}
+	style := ui.StatusConnectingStyle
+	if i.Channel.ID == m.PlayingID {
+		title = "▶ " + title
+		style = ui.StatusPlayingStyle
+	}
+	subtle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+	line := subtle.Render(fmt.Sprintf("‹ %d/%d › ", idx+1, len(m.List.Items()))) + style.Render(title)
+	if listeners := i.Listeners(); listeners != "" {
+		line += subtle.Render(" · " + listeners + " ♪")
+	}
+	return line
+}
+
+// renderCompact renders compact mode: the search bar while one is up, the
+// selector and the status fields on one line, each cut to the window
+// width. The list keys move the selector through the list as usual.
+func (m *Model) renderCompact() string {
+	width := max(m.Width, 1)
+	var lines []string
+	if bar := m.RenderSearchBar(); bar != "" {
+		for _, line := range strings.Split(bar, "\n") {
+			lines = append(lines, strings.TrimLeft(line, " "))
+		}
+	}
+	lines = append(lines,
+		m.renderSelector(),
+		strings.Join(m.statusParts(), " │ "),
+	)
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, width, "…")
+	}
+	// The cover and channel logo panes are hidden; so are their images.
+	return ui.ClearImages(m.ImageProtocol) + strings.Join(lines, "\n")
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"somad/internal/protocol"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompact_SelectorAndStatusLine(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 60, 3
+	m.applySnapshot(protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "groovesalad", ChannelTitle: "Groove Salad", Volume: 1,
+	})
+
+	sendKey(m, 'z')
+	require.True(t, m.Compact)
+
+	lines := strings.Split(ansi.Strip(m.View()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "‹ 1/3 › ▶ Groove Salad · 1,000 ♪", lines[0])
+	assert.Contains(t, lines[1], "Playing")
+	for _, line := range lines {
+		assert.LessOrEqual(t, ansi.StringWidth(line), 60, "cut to the window")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Contains(t, ansi.Strip(m.View()), "‹ 2/3 › Drone Zone", "the list keys move the selector")
+
+	sendKey(m, 'z')
+	assert.False(t, m.Compact)
+	assert.Contains(t, m.View(), "SomaFM Stations")
+}
+
+func TestCompact_SearchBarAndOverlays(t *testing.T) {
+	m := newTestModel(t)
+	m.Compact = true
+
+	sendKey(m, '/')
+	for _, r := range "secret" {
+		sendKey(m, r)
+	}
+	view := ansi.Strip(m.View())
+	assert.True(t, strings.HasPrefix(view, "/secret [1/1]"), view)
+	assert.Contains(t, view, "‹ 3/3 › Secret Agent")
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	sendKey(m, 'E')
+	assert.Contains(t, m.View(), "Equalizer", "overlays still open full size")
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.NotContains(t, m.View(), "SomaFM Stations", "back to compact once closed")
+}
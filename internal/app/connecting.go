@@ -0,0 +1,48 @@
+package app
+
+import (
+	"somad/internal/protocol"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// connecting reports whether a stream is being connected or reconnected,
+// which can take seconds: the playlist is fetched before the stream.
+func (m *Model) connecting() bool {
+	return m.Snapshot.Status == protocol.StatusConnecting || m.Snapshot.Status == protocol.StatusReconnecting
+}
+
+// spin starts the status bar's spinner while connecting, unless it turns
+// already. It stops by itself once the stream plays or fails.
+func (m *Model) spin() tea.Cmd {
+	if m.spinning || !m.connecting() {
+		return nil
+	}
+	if len(m.spinner.Spinner.Frames) == 0 {
+		m.spinner = spinner.New(spinner.WithSpinner(spinner.MiniDot))
+	}
+	m.spinning = true
+	return m.spinner.Tick
+}
+
+// applySpinnerTick advances the spinner and schedules its next frame while
+// still connecting.
+func (m *Model) applySpinnerTick(msg spinner.TickMsg) tea.Cmd {
+	if !m.connecting() {
+		m.spinning = false
+		return nil
+	}
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return cmd
+}
+
+// connectingIcon is the status bar's icon while connecting: the spinner's
+// frame while it turns, otherwise icon.
+func (m *Model) connectingIcon(icon string) string {
+	if !m.spinning {
+		return icon
+	}
+	return m.spinner.View()
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"testing"
+
+	"somad/internal/protocol"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpinner_TurnsWhileConnecting(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 200
+
+	_, cmd := m.Update(ServerStateMsg{State: protocol.PlaybackState{
+		Status: protocol.StatusConnecting, ChannelID: "groovesalad", ChannelTitle: "Groove Salad", Volume: 1,
+	}})
+	require.True(t, m.spinning)
+	tick, ok := runCmd(cmd).(spinner.TickMsg)
+	require.True(t, ok, "connecting starts the spinner")
+
+	_, again := m.Update(ServerStateMsg{State: protocol.PlaybackState{
+		Status: protocol.StatusConnecting, ChannelID: "groovesalad", ChannelTitle: "Groove Salad", Volume: 1,
+	}})
+	assert.Nil(t, again, "a second snapshot does not start another spinner")
+
+	_, next := m.Update(tick)
+	require.NotNil(t, next, "the spinner turns on while connecting")
+	bar := ansi.Strip(m.RenderStatusBar())
+	assert.Contains(t, bar, spinner.MiniDot.Frames[1]+" Connecting to Groove Salad…")
+	assert.NotContains(t, bar, "│  Groove Salad", "the channel is named once")
+
+	m.Update(ServerStateMsg{State: protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "groovesalad", ChannelTitle: "Groove Salad", Volume: 1,
+	}})
+	_, next = m.Update(tick)
+	assert.Nil(t, next, "the spinner stops once playing")
+	assert.False(t, m.spinning)
+	assert.Contains(t, ansi.Strip(m.RenderStatusBar()), "▶ Playing")
+}
+
+func TestSpinner_Reconnecting(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 200
+
+	_, cmd := m.Update(ServerStateMsg{State: protocol.PlaybackState{
+		Status: protocol.StatusReconnecting, ChannelID: "groovesalad", ChannelTitle: "Groove Salad", ReconnectAttempt: 2, Volume: 1,
+	}})
+	require.NotNil(t, cmd)
+	assert.Contains(t, ansi.Strip(m.RenderStatusBar()), spinner.MiniDot.Frames[0]+" Reconnecting #2")
+}
@@ -0,0 +1,114 @@
+package app
+
+import (
+	"strings"
+
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// dashboardRow is one favorite on the "what's on" dashboard.
+type dashboardRow struct {
+	ID          string
+	Title       string
+	LastPlaying string // the catalog's latest track; empty if unknown
+}
+
+// dashboardRows returns a row per favorite, in favorites order, with what
+// each last played according to the catalog. The server refreshes the
+// catalog every few minutes, so the titles are recent without a fetch per
+// station. Favorites missing from the catalog are skipped.
+func (m *Model) dashboardRows() []dashboardRow {
+	rows := make([]dashboardRow, 0, len(m.Favorites))
+	for _, id := range m.Favorites {
+		for _, ch := range m.catalog {
+			if ch.ID == id {
+				rows = append(rows, dashboardRow{ID: ch.ID, Title: ch.Title, LastPlaying: ch.LastPlaying})
+				break
+			}
+		}
+	}
+	return rows
+}
+
+// OpenDashboard shows what is on across the favorites.
+func (m *Model) OpenDashboard() {
+	m.Dashboard = true
+	m.dashboardCursor = 0
+}
+
+// updateDashboard handles keys while the dashboard is open: j/k move, enter
+// plays the highlighted favorite, esc or w closes.
+func (m *Model) updateDashboard(msg tea.KeyMsg) tea.Cmd {
+	rows := m.dashboardRows()
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m.quitCmd()
+	case "esc", "w":
+		m.Dashboard = false
+	case "up", "k":
+		if m.dashboardCursor > 0 {
+			m.dashboardCursor--
+		}
+	case "down", "j":
+		if m.dashboardCursor < len(rows)-1 {
+			m.dashboardCursor++
+		}
+	case "enter", " ":
+		if m.dashboardCursor < len(rows) {
+			id := rows[m.dashboardCursor].ID
+			m.Dashboard = false
+			m.selectChannelByID(id)
+			return m.switchChannelCmd(id)
+		}
+	}
+	return nil
+}
+
+// renderDashboard renders the dashboard as a bordered table centered over
+// the list area.
+func (m *Model) renderDashboard() string {
+	rows := m.dashboardRows()
+	width := max(m.Width-8, 20)
+
+	var lines []string
+	if len(rows) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ui.SubtleColor).
+			Render("No favorites yet — press f on a channel to add one."))
+	}
+	titleWidth := 0
+	for _, r := range rows {
+		titleWidth = max(titleWidth, lipgloss.Width(r.Title))
+	}
+	for i, r := range rows {
+		marker := "  "
+		if r.ID == m.PlayingID {
+			marker = "▶ "
+		}
+		playing := r.LastPlaying
+		if playing == "" {
+			playing = "—"
+		}
+		line := marker + r.Title + strings.Repeat(" ", titleWidth-lipgloss.Width(r.Title)) + "  ♫ " + playing
+		line = ansi.Truncate(line, width, "…")
+
+		style := lipgloss.NewStyle().Foreground(ui.TextColor)
+		if i == m.dashboardCursor {
+			style = lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true)
+		}
+		lines = append(lines, style.Render(line))
+	}
+
+	header := ui.TitleStyle.UnsetMarginLeft().Render("What's on your favorites")
+	footer := lipgloss.NewStyle().Foreground(ui.SubtleColor).Render("enter plays · esc closes")
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.SubtleColor).
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, "", strings.Join(lines, "\n"), "", footer))
+
+	return lipgloss.Place(m.Width, m.List.Height(), lipgloss.Center, lipgloss.Center, box)
+}
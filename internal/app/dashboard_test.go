@@ -0,0 +1,74 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+// dashboardModel returns a test model with two favorites, one of them with
+// a known current track.
+func dashboardModel(t *testing.T) *Model {
+	t.Helper()
+	m := newTestModel(t)
+	m.Width = 120
+	m.catalog[1].LastPlaying = "Stars of the Lid - Requiem for Dying Mothers"
+	m.Favorites = []string{"secretagent", "dronezone", "gone"}
+	return m
+}
+
+func TestDashboardRows_FollowFavoritesWithLastPlaying(t *testing.T) {
+	m := dashboardModel(t)
+
+	rows := m.dashboardRows()
+
+	assert.Equal(t, []dashboardRow{
+		{ID: "secretagent", Title: "Secret Agent"},
+		{ID: "dronezone", Title: "Drone Zone", LastPlaying: "Stars of the Lid - Requiem for Dying Mothers"},
+	}, rows, "favorites order, and a favorite missing from the catalog is skipped")
+}
+
+func TestDashboard_RendersTable(t *testing.T) {
+	m := dashboardModel(t)
+	sendKey(m, 'w')
+
+	view := m.View()
+
+	assert.True(t, m.Dashboard)
+	assert.Contains(t, view, "What's on your favorites")
+	assert.Contains(t, view, "Stars of the Lid")
+	assert.Contains(t, view, "Secret Agent")
+}
+
+func TestDashboard_EnterPlaysHighlightedFavorite(t *testing.T) {
+	m := dashboardModel(t)
+	sendKey(m, 'w')
+	sendKey(m, 'j')
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	runCmd(cmd)
+
+	assert.False(t, m.Dashboard)
+	assert.Equal(t, []string{"dronezone"}, backend(m).playIDs)
+}
+
+func TestDashboard_EscClosesWithoutPlaying(t *testing.T) {
+	m := dashboardModel(t)
+	sendKey(m, 'w')
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	assert.Nil(t, cmd)
+	assert.False(t, m.Dashboard)
+	assert.Empty(t, backend(m).playIDs)
+}
+
+func TestDashboard_EmptyFavorites(t *testing.T) {
+	m := newTestModel(t)
+	sendKey(m, 'w')
+
+	assert.Contains(t, m.View(), "No favorites yet")
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, cmd, "nothing to play")
+}
@@ -0,0 +1,254 @@
+package app
+
+import (
+	"bytes"
+	"image"
+	"slices"
+	"strings"
+	"time"
+
+	"somad/internal/channels"
+	"somad/internal/protocol"
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DefaultDetailsWidth is how many columns the channel details pane takes,
+// border included, until resized.
+const DefaultDetailsWidth = 40
+
+// MinDetailsWidth is the narrowest the details pane gets, resized or
+// squeezed by a narrow terminal; the channel logo still fits.
+const MinDetailsWidth = 28
+
+// detailsWidthStep is how many columns [ and ] resize the pane by.
+const detailsWidthStep = 4
+
+// detailsMinListWidth is the narrowest the list gets beside the details
+// pane; a terminal too narrow for both hides the pane.
+const detailsMinListWidth = 40
+
+// channelArtRows is the height of the channel logo atop the details pane;
+// it is twice as many columns wide, which is square in a typical cell.
+const channelArtRows = 8
+
+// channelArtMsg carries the logo of ChannelID; Image is nil when it has
+// none.
+type channelArtMsg struct {
+	ChannelID string
+	Image     image.Image
+	Err       error
+}
+
+// ToggleDetails shows or hides the details pane beside the list, fetching
+// the highlighted channel's logo on open.
+func (m *Model) ToggleDetails() tea.Cmd {
+	m.DetailsOpen = !m.DetailsOpen
+	m.UpdateListSize()
+	return m.fetchDetails()
+}
+
+// fetchDetails fetches the highlighted channel's logo for the open
+// details pane, e.g. once the channels arrive with the pane open from the
+// start.
+func (m *Model) fetchDetails() tea.Cmd {
+	if i, ok := m.List.SelectedItem().(ui.Item); ok {
+		return m.fetchChannelArt(i.Channel.ID)
+	}
+	return nil
+}
+
+// fetchChannelArt asks the server for a channel's logo while the details
+// pane is open and the terminal can show it, once per channel, decoding it
+// off the UI goroutine.
+func (m *Model) fetchChannelArt(id string) tea.Cmd {
+	if !m.DetailsOpen || m.ImageProtocol == ui.ImageNone || id == "" {
+		return nil
+	}
+	if _, ok := m.channelArt[id]; ok {
+		return nil
+	}
+	if m.channelArt == nil {
+		m.channelArt = make(map[string]image.Image)
+	}
+	m.channelArt[id] = nil // fetching; a second hover does not ask again
+	b := m.Backend
+	return func() tea.Msg {
+		result, err := b.ChannelArtwork(id)
+		if err != nil || len(result.Image) == 0 {
+			return channelArtMsg{ChannelID: id, Err: err}
+		}
+		img, _, err := image.Decode(bytes.NewReader(result.Image))
+		return channelArtMsg{ChannelID: id, Image: img, Err: err}
+	}
+}
+
+// applyChannelArt records a fetched logo. A failed fetch is forgotten so
+// the next hover tries again; the pane just goes without a logo meanwhile.
+func (m *Model) applyChannelArt(msg channelArtMsg) {
+	if msg.Err != nil {
+		delete(m.channelArt, msg.ChannelID)
+		return
+	}
+	m.channelArt[msg.ChannelID] = msg.Image
+}
+
+// detailsArt returns the logo to draw atop the details pane for the
+// highlighted channel, or nil when there is none, the pane is hidden or
+// the list is too short to leave room for the text below it.
+func (m *Model) detailsArt() (string, image.Image) {
+	if m.detailsPaneWidth() == 0 || m.ImageProtocol == ui.ImageNone ||
+		m.List.Height()-2 < 2*channelArtRows {
+		return "", nil
+	}
+	i, ok := m.List.SelectedItem().(ui.Item)
+	if !ok {
+		return "", nil
+	}
+	return i.Channel.ID, m.channelArt[i.Channel.ID]
+}
+
+// renderChannelArt returns the logo's rows: blank cells, with the image
+// drawn over them from the end of the last one. The escape sequence is
+// kept while the highlighted channel stays the same, since encoding it is
+// too slow to repeat every frame.
+func (m *Model) renderChannelArt(id string, img image.Image) []string {
+	cols, rows := channelArtRows*2, channelArtRows
+	if m.channelArtSeqKey != id {
+		m.channelArtSeq = ui.RenderImage(img, cols, rows, m.ImageProtocol)
+		m.channelArtSeqKey = id
+	}
+	lines := make([]string, rows)
+	for i := range lines {
+		lines[i] = strings.Repeat(" ", cols)
+	}
+	lines[rows-1] += m.channelArtSeq
+	return lines
+}
+
+// detailsPaneWidth returns how many columns the details pane takes from
+// the list: its DetailsWidth, narrowed to leave the list detailsMinListWidth
+// columns, or none while it is closed or the terminal is too narrow to
+// leave MinDetailsWidth.
+func (m *Model) detailsPaneWidth() int {
+	width := m.DetailsWidth
+	if width == 0 {
+		width = DefaultDetailsWidth
+	}
+	width = min(width, m.Width-detailsMinListWidth)
+	if !m.DetailsOpen || width < MinDetailsWidth {
+		return 0
+	}
+	return width
+}
+
+// ResizeDetails widens the open details pane by delta columns, or narrows
+// it for a negative delta, within MinDetailsWidth and what leaves the list
+// detailsMinListWidth columns.
+func (m *Model) ResizeDetails(delta int) {
+	current := m.detailsPaneWidth()
+	if current == 0 {
+		return
+	}
+	m.DetailsWidth = max(min(current+delta, m.Width-detailsMinListWidth), MinDetailsWidth)
+	m.UpdateListSize()
+}
+
+// streamSummary lists a channel's playlist formats with their qualities,
+// e.g. "mp3 highest, high, low · aac high".
+func streamSummary(playlists []channels.Playlist) string {
+	var formats []string
+	qualities := make(map[string][]string)
+	for _, p := range playlists {
+		if !slices.Contains(formats, p.Format) {
+			formats = append(formats, p.Format)
+		}
+		if p.Quality != "" && !slices.Contains(qualities[p.Format], p.Quality) {
+			qualities[p.Format] = append(qualities[p.Format], p.Quality)
+		}
+	}
+	parts := make([]string, len(formats))
+	for i, f := range formats {
+		parts[i] = strings.TrimSpace(f + " " + strings.Join(qualities[f], ", "))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// renderDetails renders the highlighted channel's logo (where the terminal
+// can show images), why its stream is unreachable (if it is), description,
+// genres, DJ, listeners, streams and last played track (the playing one,
+// once known, for the active channel) as a bordered pane as tall as the
+// list, followed by the playing channel and track when another channel is
+// highlighted.
+func (m *Model) renderDetails() string {
+	width := m.detailsPaneWidth()
+	inner := width - 4 // border and padding
+	subtle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+	text := lipgloss.NewStyle().Foreground(ui.TextColor).Width(inner)
+	field := func(label, value string) string {
+		return subtle.Render(label) + "\n" + text.Render(value)
+	}
+
+	var sections []string
+	i, ok := m.List.SelectedItem().(ui.Item)
+	if !ok {
+		sections = append(sections, subtle.Render("No channel selected."))
+	} else {
+		ch := i.Channel
+		if id, img := m.detailsArt(); img != nil {
+			sections = append(sections, strings.Join(m.renderChannelArt(id, img), "\n"))
+		}
+		sections = append(sections, lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true).Width(inner).Render(ch.Title))
+		if reason, ok := m.DeadChannels[ch.ID]; ok {
+			warn := lipgloss.NewStyle().Foreground(ui.ErrorColor).Width(inner)
+			sections = append(sections, subtle.Render("Unreachable")+"\n"+warn.Render(reason))
+		}
+		if ch.Description != "" {
+			sections = append(sections, text.Render(ch.Description))
+		}
+		if genres := ch.Genres(); len(genres) > 0 {
+			sections = append(sections, field("Genres", strings.Join(genres, " · ")))
+		}
+		if ch.DJ != "" {
+			sections = append(sections, field("DJ", ch.DJ))
+		}
+		if ch.Listeners > 0 {
+			sections = append(sections, field("Listeners", ui.FormatCount(ch.Listeners)))
+		}
+		if d := m.listenedFor(ch.ID); d >= time.Second {
+			sections = append(sections, field("You listened", formatListened(d)))
+		}
+		switch {
+		case ch.StreamURL != "":
+			sections = append(sections, field("Stream", ansi.Truncate(ch.StreamURL, inner, "…")))
+		case len(ch.Playlists) > 0:
+			sections = append(sections, field("Streams", streamSummary(ch.Playlists)))
+		}
+		switch {
+		case ch.ID == m.Snapshot.ChannelID && m.Snapshot.Status != protocol.StatusStopped && m.Snapshot.TrackTitle != "":
+			sections = append(sections, field("Now playing", m.Snapshot.TrackTitle))
+		case ch.LastPlaying != "":
+			sections = append(sections, field("Last playing", ch.LastPlaying))
+		}
+		// What plays meanwhile, while browsing other channels.
+		if st := m.Snapshot; st.Status != protocol.StatusStopped && st.ChannelID != "" && st.ChannelID != ch.ID {
+			playing := st.ChannelTitle
+			if st.TrackTitle != "" {
+				playing += "\n" + st.TrackTitle
+			}
+			sections = append(sections, field("Playing", playing))
+		}
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.SubtleColor).
+		Padding(0, 1).
+		Width(width - 2).
+		Height(max(m.List.Height()-2, 1)).
+		MaxHeight(m.List.Height()).
+		Render(strings.Join(sections, "\n\n"))
+}
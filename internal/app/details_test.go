@@ -0,0 +1,181 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"somad/internal/channels"
+	"somad/internal/protocol"
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetails_FollowTheCursor(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 120, 40
+	m.UpdateListSize()
+
+	sendKey(m, 'D')
+
+	assert.Equal(t, 120-DefaultDetailsWidth, m.List.Width(), "the pane takes columns from the list")
+	view := m.View()
+	assert.Contains(t, view, "A nicely chilled plate of ambient beats")
+	assert.Contains(t, view, "Listeners")
+	assert.Contains(t, view, "mp3")
+
+	sendKey(m, 'j')
+	view = m.View()
+	assert.Contains(t, view, "ambient · space")
+
+	sendKey(m, 'D')
+	assert.Equal(t, 120, m.List.Width())
+	assert.NotContains(t, m.View(), "ambient · space")
+}
+
+func TestDetails_HiddenWhenTooNarrow(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 60, 40
+
+	sendKey(m, 'D')
+
+	assert.True(t, m.DetailsOpen)
+	assert.Equal(t, 60, m.List.Width())
+}
+
+func TestDetails_DrawsTheChannelLogo(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 120, 40
+	m.ImageProtocol = ui.ImageKitty
+	m.UpdateListSize()
+	backend(m).logos = map[string][]byte{"groovesalad": testCoverPNG(t)}
+
+	_, cmd := sendKey(m, 'D')
+	require.NotNil(t, cmd, "opening the pane fetches the logo")
+	m.Update(runCmd(cmd))
+	view := m.View()
+	assert.Contains(t, view, "\x1b_Ga=T", "the logo is drawn")
+	assert.Equal(t, 1, strings.Count(view, ui.ClearImages(ui.ImageKitty)), "only the logo replaces earlier images")
+
+	_, cmd = sendKey(m, 'j')
+	m.Update(runCmd(cmd))
+	view = m.View()
+	assert.NotContains(t, view, "\x1b_Ga=T", "dronezone has no logo")
+	assert.Contains(t, view, ui.ClearImages(ui.ImageKitty), "so the previous one is cleared")
+
+	sendKey(m, 'k')
+	sendKey(m, 'j')
+	assert.Equal(t, []string{"groovesalad", "dronezone"}, backend(m).logoCalls, "each logo is fetched once")
+}
+
+func TestDetails_NoLogoWithoutImageSupport(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 120, 40
+	m.ImageProtocol = ui.ImageNone
+	m.UpdateListSize()
+
+	_, cmd := sendKey(m, 'D')
+	assert.Nil(t, cmd)
+	assert.Empty(t, backend(m).logoCalls)
+}
+
+func TestStreamSummary_GroupsQualitiesByFormat(t *testing.T) {
+	summary := streamSummary([]channels.Playlist{
+		{Format: "mp3", Quality: "highest"},
+		{Format: "aac", Quality: "highest"},
+		{Format: "mp3", Quality: "highest"},
+		{Format: "mp3", Quality: "low"},
+		{Format: "aacp", Quality: "high"},
+	})
+
+	assert.Equal(t, "mp3 highest, low · aac highest · aacp high", summary)
+}
+
+func TestDetails_SayWhyAChannelIsUnreachable(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 120, 40
+	m.UpdateListSize()
+	m.applyChannels(protocol.ChannelsPayload{
+		Channels: testChannels(),
+		Dead:     map[string]string{"groovesalad": "unexpected status code: 404"},
+	})
+
+	sendKey(m, 'D')
+
+	view := m.View()
+	assert.Contains(t, view, "Unreachable")
+	assert.Contains(t, view, "unexpected status code: 404")
+	assert.True(t, m.IsDead(0))
+	assert.False(t, m.IsDead(1))
+}
+
+func TestDetails_NowPlayingReplacesTheLastKnownTrack(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 120, 40
+	m.UpdateListSize()
+	m.catalog[0].LastPlaying = "Tycho - Awake"
+	m.applyChannels(protocol.ChannelsPayload{Channels: m.catalog})
+	sendKey(m, 'D')
+	assert.Contains(t, m.View(), "Tycho - Awake")
+
+	m.applySnapshot(protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "groovesalad", TrackTitle: "Bonobo - Kerala", Volume: 1,
+	})
+	view := m.View()
+	assert.Contains(t, view, "Now playing")
+	assert.Contains(t, view, "Bonobo - Kerala")
+	assert.NotContains(t, view, "Tycho - Awake")
+}
+
+func TestDetails_ResizeAndSqueeze(t *testing.T) {
+	m := newTestModel(t)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	sendKey(m, ']')
+	assert.Zero(t, m.DetailsWidth, "nothing to resize while the pane is closed")
+
+	sendKey(m, 'D')
+	sendKey(m, ']')
+	assert.Equal(t, 120-DefaultDetailsWidth-detailsWidthStep, m.List.Width())
+	for range 20 {
+		sendKey(m, ']')
+	}
+	assert.Equal(t, detailsMinListWidth, m.List.Width(), "the list keeps its minimum")
+	for range 30 {
+		sendKey(m, '[')
+	}
+	assert.Equal(t, MinDetailsWidth, m.DetailsWidth)
+	assert.Equal(t, 120-MinDetailsWidth, m.List.Width())
+
+	m.DetailsWidth = 60
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+	assert.Equal(t, detailsMinListWidth, m.List.Width(), "a narrower terminal squeezes the pane first")
+	for _, line := range strings.Split(m.View(), "\n") {
+		assert.LessOrEqual(t, ansi.StringWidth(line), 80)
+	}
+	m.Update(tea.WindowSizeMsg{Width: 60, Height: 40})
+	assert.Equal(t, 60, m.List.Width(), "then hides it")
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	assert.Equal(t, 60, m.List.Width(), "and brings it back at its width")
+}
+
+func TestDetails_ShowWhatPlaysElsewhere(t *testing.T) {
+	m := newTestModel(t)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	sendKey(m, 'D')
+	m.applySnapshot(protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "dronezone", ChannelTitle: "Drone Zone", TrackTitle: "Stars of the Lid - Requiem", Volume: 1,
+	})
+
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "Playing")
+	assert.Contains(t, view, "Stars of the Lid - Requiem")
+
+	sendKey(m, 'j')
+	view = ansi.Strip(m.View())
+	assert.Contains(t, view, "Now playing", "the highlighted channel plays")
+	assert.NotContains(t, view, "│ Playing")
+}
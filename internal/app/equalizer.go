@@ -0,0 +1,99 @@
+package app
+
+import (
+	"strings"
+
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// equalizerPreset is a row of the equalizer overlay.
+type equalizerPreset struct {
+	Name        string
+	Description string
+}
+
+// equalizerPresets are the server's equalizer presets, in overlay order.
+var equalizerPresets = []equalizerPreset{
+	{Name: "flat", Description: "as broadcast"},
+	{Name: "bass", Description: "boosts the lows"},
+	{Name: "speech", Description: "cuts the rumble, lifts voices"},
+}
+
+// activeEqualizer returns the preset the server applies; empty counts as
+// flat.
+func (m *Model) activeEqualizer() string {
+	if m.Snapshot.Equalizer == "" {
+		return "flat"
+	}
+	return m.Snapshot.Equalizer
+}
+
+// OpenEqualizer shows the equalizer presets with the active one highlighted.
+func (m *Model) OpenEqualizer() {
+	m.EqualizerOpen = true
+	m.equalizerCursor = 0
+	for i, p := range equalizerPresets {
+		if p.Name == m.activeEqualizer() {
+			m.equalizerCursor = i
+		}
+	}
+}
+
+// updateEqualizer handles keys while the equalizer is open: j/k move, enter
+// applies the highlighted preset, esc or E closes.
+func (m *Model) updateEqualizer(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m.quitCmd()
+	case "esc", "E":
+		m.EqualizerOpen = false
+	case "up", "k":
+		if m.equalizerCursor > 0 {
+			m.equalizerCursor--
+		}
+	case "down", "j":
+		if m.equalizerCursor < len(equalizerPresets)-1 {
+			m.equalizerCursor++
+		}
+	case "enter", " ":
+		m.EqualizerOpen = false
+		return m.setEqualizerCmd(equalizerPresets[m.equalizerCursor].Name)
+	}
+	return nil
+}
+
+// renderEqualizer renders the presets as a bordered box centered over the
+// list area, marking the active one.
+func (m *Model) renderEqualizer() string {
+	nameWidth := 0
+	for _, p := range equalizerPresets {
+		nameWidth = max(nameWidth, len(p.Name))
+	}
+	var lines []string
+	for i, p := range equalizerPresets {
+		marker := "  "
+		if p.Name == m.activeEqualizer() {
+			marker = "● "
+		}
+		line := marker + p.Name + strings.Repeat(" ", nameWidth-len(p.Name)) + "  " + p.Description
+
+		style := lipgloss.NewStyle().Foreground(ui.TextColor)
+		if i == m.equalizerCursor {
+			style = lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true)
+		}
+		lines = append(lines, style.Render(line))
+	}
+
+	header := ui.TitleStyle.UnsetMarginLeft().Render("Equalizer")
+	footer := lipgloss.NewStyle().Foreground(ui.SubtleColor).Render("enter applies · esc closes")
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.SubtleColor).
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, "", strings.Join(lines, "\n"), "", footer))
+
+	return lipgloss.Place(m.Width, m.List.Height(), lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEqualizer_OpensOnActivePreset(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 120
+	m.Snapshot.Equalizer = "bass"
+	sendKey(m, 'E')
+
+	require.True(t, m.EqualizerOpen)
+	assert.Equal(t, "bass", equalizerPresets[m.equalizerCursor].Name)
+	assert.Contains(t, m.View(), "● bass")
+}
+
+func TestEqualizer_EnterAppliesHighlightedPreset(t *testing.T) {
+	m := newTestModel(t)
+	sendKey(m, 'E')
+	sendKey(m, 'j')
+	sendKey(m, 'j')
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m.Update(runCmd(cmd))
+
+	assert.False(t, m.EqualizerOpen)
+	assert.Equal(t, []string{"speech"}, backend(m).presets)
+	assert.Contains(t, m.RenderStatusBar(), "eq speech")
+}
+
+func TestEqualizer_EscClosesWithoutApplying(t *testing.T) {
+	m := newTestModel(t)
+	sendKey(m, 'E')
+	sendKey(m, 'j')
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	assert.Nil(t, cmd)
+	assert.False(t, m.EqualizerOpen)
+	assert.Empty(t, backend(m).presets)
+}
@@ -34,6 +34,8 @@ func (m *Model) ToggleFavorite() tea.Cmd {
 		return nil
 	}
 	selectedID := sel.Channel.ID
+	// The server retires the onboarding hint along with the toggle.
+	m.FavoritesHintSeen = true
 
 	if i := slices.Index(m.Favorites, selectedID); i >= 0 {
 		m.Favorites = slices.Delete(slices.Clone(m.Favorites), i, i+1)
@@ -41,14 +43,9 @@ func (m *Model) ToggleFavorite() tea.Cmd {
 		m.Favorites = append(slices.Clone(m.Favorites), selectedID)
 	}
 
-	// Re-sort items with favorites on top, keeping the cursor on the same channel
-	m.List.SetItems(m.sortItemsWithFavorites(m.List.Items()))
-	m.selectChannelByID(selectedID)
-
-	// Update search matches since indices changed
-	if m.SearchQuery != "" {
-		m.UpdateSearchMatches()
-	}
+	// Re-sort items with favorites on top (dropping an unfavorited channel
+	// from a favorites-only list), keeping the cursor on the same channel.
+	m.setCatalogItems(m.catalogItems(), selectedID)
 
 	b := m.Backend
 	return func() tea.Msg {
@@ -63,24 +60,49 @@ func (m *Model) ToggleFavorite() tea.Cmd {
 	}
 }
 
+// MoveFavorite moves the selected favorite delta places up (negative) or
+// down (positive) among the favorites, optimistically like ToggleFavorite,
+// and returns a command that persists the new order on the server. The
+// cursor follows the moved channel.
+func (m *Model) MoveFavorite(delta int) tea.Cmd {
+	sel, ok := m.List.SelectedItem().(ui.Item)
+	if !ok || !m.isFavoriteID(sel.Channel.ID) {
+		return nil
+	}
+	selectedID := sel.Channel.ID
+	i := slices.Index(m.Favorites, selectedID)
+	j := min(max(i+delta, 0), len(m.Favorites)-1)
+	if j == i {
+		return nil
+	}
+	favs := slices.Delete(slices.Clone(m.Favorites), i, i+1)
+	m.Favorites = slices.Insert(favs, j, selectedID)
+	m.setCatalogItems(m.catalogItems(), selectedID)
+
+	b := m.Backend
+	return func() tea.Msg {
+		if b == nil {
+			return nil
+		}
+		favs, err := b.MoveFavorite(selectedID, delta)
+		if err != nil {
+			return requestErr("move favorite", err)
+		}
+		return FavoritesMsg{Favorites: favs}
+	}
+}
+
 // applyFavorites installs the server's authoritative favorites list and
 // re-sorts, keeping the cursor on the selected channel. It reconciles the
 // optimistic flip in ToggleFavorite with what the server actually persisted.
 func (m *Model) applyFavorites(favs []string) {
 	m.Favorites = favs
-	var selectedID string
-	if sel, ok := m.List.SelectedItem().(ui.Item); ok {
-		selectedID = sel.Channel.ID
-	}
-	m.List.SetItems(m.sortItemsWithFavorites(m.List.Items()))
-	m.selectChannelByID(selectedID)
-	if m.SearchQuery != "" {
-		m.UpdateSearchMatches()
-	}
+	m.applyFilters()
 }
 
-// sortItemsWithFavorites returns items partitioned with favorites first,
-// preserving relative order within each group. O(n) via two-pass partition.
+// sortItemsWithFavorites returns items partitioned with favorites first, in
+// the order of the favorites list (the user's pinned order), followed by
+// the rest in their relative order.
 func (m *Model) sortItemsWithFavorites(items []list.Item) []list.Item {
 	sorted := make([]list.Item, 0, len(items))
 	for _, item := range items {
@@ -88,6 +110,9 @@ func (m *Model) sortItemsWithFavorites(items []list.Item) []list.Item {
 			sorted = append(sorted, item)
 		}
 	}
+	slices.SortStableFunc(sorted, func(a, b list.Item) int {
+		return slices.Index(m.Favorites, a.(ui.Item).Channel.ID) - slices.Index(m.Favorites, b.(ui.Item).Channel.ID)
+	})
 	for _, item := range items {
 		if i, ok := item.(ui.Item); ok && !m.isFavoriteID(i.Channel.ID) {
 			sorted = append(sorted, item)
@@ -95,3 +120,27 @@ func (m *Model) sortItemsWithFavorites(items []list.Item) []list.Item {
 	}
 	return sorted
 }
+
+// favoritesHint nudges new users towards favorites.
+const favoritesHint = "Press f to favorite a station (esc to dismiss)"
+
+// showFavoritesHint reports whether the favorites onboarding hint is due: the
+// user has no favorites and has neither dismissed the hint nor favorited
+// anything before. Directory stations cannot be favorited, so it stays
+// hidden there.
+func (m *Model) showFavoritesHint() bool {
+	return !m.FavoritesHintSeen && len(m.Favorites) == 0 && !m.Directory
+}
+
+// dismissFavoritesHint hides the onboarding hint and returns a command that
+// persists the dismissal on the server, so it is not shown again.
+func (m *Model) dismissFavoritesHint() tea.Cmd {
+	m.FavoritesHintSeen = true
+	b := m.Backend
+	return func() tea.Msg {
+		if err := b.DismissFavoritesHint(); err != nil {
+			return requestErr("dismiss hint", err)
+		}
+		return nil
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"testing"
 
 	"somad/internal/channels"
+	"somad/internal/protocol"
 	"somad/internal/ui"
 
 	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -154,3 +156,97 @@ func TestSortItemsWithFavorites_PreservesRelativeOrder(t *testing.T) {
 	// Both favorites first (in their original relative order), then non-favorite
 	assert.Equal(t, []string{"groovesalad", "secretagent", "dronezone"}, ids)
 }
+
+func TestSortItemsWithFavorites_PinnedOrder(t *testing.T) {
+	m := newTestModel(t)
+	m.Favorites = []string{"secretagent", "groovesalad"}
+
+	result := m.sortItemsWithFavorites(ChannelsToItems(testChannels()))
+
+	ids := make([]string, len(result))
+	for i, r := range result {
+		ids[i] = r.(ui.Item).Channel.ID
+	}
+	assert.Equal(t, []string{"secretagent", "groovesalad", "dronezone"}, ids,
+		"favorites follow the favorites list, whatever the catalog order")
+}
+
+func TestMoveFavorite_ShiftKeysReorderAndPersist(t *testing.T) {
+	m := newTestModel(t)
+	m.Favorites = []string{"groovesalad", "secretagent"}
+	backend(m).favorites = []string{"groovesalad", "secretagent"}
+	m.applyFilters()
+	m.selectChannelByID("secretagent")
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyShiftUp})
+	msg := runCmd(cmd)
+
+	assert.Equal(t, []string{"secretagent", "groovesalad", "dronezone"}, listIDs(m))
+	assert.Equal(t, 0, m.List.Index(), "the cursor follows the moved channel")
+	assert.Equal(t, []string{"secretagent", "groovesalad"}, backend(m).favorites)
+	require.IsType(t, FavoritesMsg{}, msg)
+
+	sendKey(m, 'J')
+	assert.Equal(t, []string{"groovesalad", "secretagent", "dronezone"}, listIDs(m))
+	assert.Equal(t, 1, m.List.Index())
+}
+
+func TestMoveFavorite_IgnoresNonFavoritesAndEnds(t *testing.T) {
+	m := newTestModel(t)
+	m.Favorites = []string{"groovesalad"}
+	m.applyFilters()
+
+	m.selectChannelByID("groovesalad")
+	assert.Nil(t, m.MoveFavorite(-1), "already first")
+	assert.Nil(t, m.MoveFavorite(1), "the only favorite stays put")
+
+	m.selectChannelByID("dronezone")
+	assert.Nil(t, m.MoveFavorite(-1), "only favorites are pinned")
+	assert.Equal(t, []string{"groovesalad"}, m.Favorites)
+}
+
+func TestFavoritesHint_ShownWithNoFavoritesUntilSeen(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 200
+
+	assert.Contains(t, m.RenderStatusBar(), favoritesHint, "no favorites and the hint unseen")
+
+	m.applyChannels(protocol.ChannelsPayload{Channels: testChannels(), FavoritesHintSeen: true})
+
+	assert.NotContains(t, m.RenderStatusBar(), favoritesHint, "the server says the hint was seen")
+}
+
+func TestFavoritesHint_HiddenOnceSomethingIsFavorited(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 200
+	m.Favorites = []string{"groovesalad"}
+
+	assert.NotContains(t, m.RenderStatusBar(), favoritesHint)
+}
+
+func TestFavoritesHint_EscDismissesAndPersists(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 200
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	runCmd(cmd)
+
+	assert.True(t, m.FavoritesHintSeen)
+	assert.Equal(t, 1, backend(m).hintDismissals)
+	assert.NotContains(t, m.RenderStatusBar(), favoritesHint)
+
+	// A payload from before the dismissal must not bring it back.
+	m.applyChannels(protocol.ChannelsPayload{Channels: testChannels()})
+	assert.NotContains(t, m.RenderStatusBar(), favoritesHint)
+}
+
+func TestFavoritesHint_RetiredByToggleFavorite(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 200
+
+	m.ToggleFavorite()
+	m.ToggleFavorite() // unfavorite again: the hint stays gone
+
+	assert.True(t, m.FavoritesHintSeen)
+	assert.NotContains(t, m.RenderStatusBar(), favoritesHint)
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"slices"
+	"strings"
+
+	"somad/internal/channels"
+	"somad/internal/ui"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// Filters narrow the SomaFM catalog shown in the list. They compose: a
+// channel is listed only if it passes every active filter. The configured
+// MinQuality applies underneath and is not cleared with them.
+type Filters struct {
+	FavoritesOnly bool   // only favorite channels
+	Genre         string // only channels tagged with this genre; empty for any
+}
+
+// Active reports whether any filter is narrowing the list.
+func (f Filters) Active() bool {
+	return f.FavoritesOnly || f.Genre != ""
+}
+
+// String summarizes the active filters for the search bar.
+func (f Filters) String() string {
+	var parts []string
+	if f.FavoritesOnly {
+		parts = append(parts, "favorites")
+	}
+	if f.Genre != "" {
+		parts = append(parts, "genre "+f.Genre)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// filterChannels returns the channels passing the active filters.
+func (m *Model) filterChannels(chs []channels.Channel) []channels.Channel {
+	if !m.Filters.Active() {
+		return chs
+	}
+	kept := make([]channels.Channel, 0, len(chs))
+	for _, ch := range chs {
+		if m.Filters.FavoritesOnly && !m.isFavoriteID(ch.ID) {
+			continue
+		}
+		if m.Filters.Genre != "" && !ch.HasGenre(m.Filters.Genre) {
+			continue
+		}
+		kept = append(kept, ch)
+	}
+	return kept
+}
+
+// applyFilters rebuilds the catalog list under the current filters, keeping
+// the cursor on the selected channel when it is still listed. The station
+// directory has its own results and is left alone.
+func (m *Model) applyFilters() {
+	if m.Directory {
+		return
+	}
+	var selectedID string
+	if sel, ok := m.List.SelectedItem().(ui.Item); ok {
+		selectedID = sel.Channel.ID
+	}
+	m.setCatalogItems(m.catalogItems(), selectedID)
+}
+
+// setCatalogItems installs items and puts the cursor on selectedID, or on
+// the first item if it is no longer listed.
+func (m *Model) setCatalogItems(items []list.Item, selectedID string) {
+	m.List.SetItems(items)
+	m.List.Select(0)
+	m.selectChannelByID(selectedID)
+	if m.SearchQuery != "" {
+		m.refreshSearchMatches()
+	}
+	m.UpdateListSize()
+}
+
+// nextGenre returns the catalog genre after the current genre filter,
+// wrapping around to no genre filter after the last one.
+func (m *Model) nextGenre() string {
+	genres := channels.Genres(channels.FilterByMP3Quality(m.catalog, m.MinQuality))
+	i := slices.Index(genres, m.Filters.Genre)
+	if i+1 >= len(genres) {
+		return ""
+	}
+	return genres[i+1]
+}
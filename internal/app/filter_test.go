@@ -0,0 +1,69 @@
+package app
+
+import (
+	"testing"
+
+	"somad/internal/protocol"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilters_GenreAndFavoritesOnlyCombine(t *testing.T) {
+	m := newTestModel(t)
+	m.Favorites = []string{"dronezone", "secretagent"}
+	m.applyFilters()
+
+	sendKey(m, 'e') // ambient, the first genre
+	assert.Equal(t, "ambient", m.Filters.Genre)
+	assert.Equal(t, []string{"dronezone", "groovesalad"}, listIDs(m))
+
+	sendKey(m, 'F')
+	assert.Equal(t, []string{"dronezone"}, listIDs(m), "only favorites tagged ambient")
+	assert.Contains(t, m.RenderSearchBar(), "Filter: favorites · genre ambient")
+}
+
+func TestFilters_ClearAllRestoresFullList(t *testing.T) {
+	m := newTestModel(t)
+	m.Favorites = []string{"secretagent"}
+	sendKey(m, 'F')
+	sendKey(m, 'e')
+	assert.Empty(t, listIDs(m), "no favorite is tagged ambient")
+
+	sendKey(m, 'x')
+
+	assert.Equal(t, Filters{}, m.Filters)
+	assert.Equal(t, []string{"secretagent", "groovesalad", "dronezone"}, listIDs(m))
+	assert.Empty(t, m.RenderSearchBar())
+}
+
+func TestFilters_GenreCycleWrapsToOff(t *testing.T) {
+	m := newTestModel(t)
+
+	var seen []string
+	for range 5 {
+		sendKey(m, 'e')
+		seen = append(seen, m.Filters.Genre)
+	}
+
+	assert.Equal(t, []string{"ambient", "lounge", "space", "spy", ""}, seen)
+}
+
+func TestFilters_UnfavoritingDropsChannelFromFavoritesOnly(t *testing.T) {
+	m := newTestModel(t)
+	m.Favorites = []string{"dronezone", "secretagent"}
+	sendKey(m, 'F')
+	m.selectChannelByID("dronezone")
+
+	sendKey(m, 'f')
+
+	assert.Equal(t, []string{"secretagent"}, listIDs(m))
+}
+
+func TestFilters_SurviveCatalogRefresh(t *testing.T) {
+	m := newTestModel(t)
+	sendKey(m, 'e')
+
+	m.applyChannels(protocol.ChannelsPayload{Channels: testChannels()})
+
+	assert.Equal(t, []string{"groovesalad", "dronezone"}, listIDs(m))
+}
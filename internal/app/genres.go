@@ -0,0 +1,131 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"somad/internal/channels"
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// genreGroup is a row of the genre picker: a genre and the channels tagged
+// with it. The first row, with an empty Genre, lists every channel.
+type genreGroup struct {
+	Genre    string
+	Channels []channels.Channel
+}
+
+// genreGroups groups the catalog the list draws from by genre. A channel
+// with several genres is in each of their groups.
+func (m *Model) genreGroups() []genreGroup {
+	chs := channels.FilterByMP3Quality(m.catalog, m.MinQuality)
+	groups := []genreGroup{{Channels: chs}}
+	for _, genre := range channels.Genres(chs) {
+		g := genreGroup{Genre: genre}
+		for _, ch := range chs {
+			if ch.HasGenre(genre) {
+				g.Channels = append(g.Channels, ch)
+			}
+		}
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+// OpenGenres shows the catalog grouped by genre, with the genre filter's
+// row highlighted. The station directory has no genre filter.
+func (m *Model) OpenGenres() {
+	if m.Directory {
+		return
+	}
+	m.GenresOpen = true
+	m.genreCursor = 0
+	for i, g := range m.genreGroups() {
+		if g.Genre == m.Filters.Genre {
+			m.genreCursor = i
+		}
+	}
+}
+
+// updateGenres handles keys while the genre picker is open: j/k move, enter
+// narrows the list to the highlighted genre (or lifts the filter on the
+// first row), esc or g closes.
+func (m *Model) updateGenres(msg tea.KeyMsg) tea.Cmd {
+	groups := m.genreGroups()
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m.quitCmd()
+	case "esc", "g":
+		m.GenresOpen = false
+	case "up", "k":
+		if m.genreCursor > 0 {
+			m.genreCursor--
+		}
+	case "down", "j":
+		if m.genreCursor < len(groups)-1 {
+			m.genreCursor++
+		}
+	case "enter", " ":
+		m.GenresOpen = false
+		if m.genreCursor < len(groups) {
+			m.Filters.Genre = groups[m.genreCursor].Genre
+			m.applyFilters()
+		}
+	}
+	return nil
+}
+
+// renderGenres renders the genres as a bordered table centered over the
+// list area: each with its channel count and the first channel titles,
+// scrolled to keep the highlighted genre in view.
+func (m *Model) renderGenres() string {
+	width := max(m.Width-8, 20)
+	groups := m.genreGroups()
+	// The box spends six rows on its border, header, footer and spacing.
+	visible := max(m.List.Height()-6, 1)
+	first := max(m.genreCursor-visible+1, 0)
+
+	nameWidth := len("all genres")
+	for _, g := range groups {
+		nameWidth = max(nameWidth, ansi.StringWidth(g.Genre))
+	}
+	var lines []string
+	for i := first; i < len(groups) && i < first+visible; i++ {
+		g := groups[i]
+		name := g.Genre
+		if name == "" {
+			name = "all genres"
+		}
+		marker := "  "
+		if g.Genre == m.Filters.Genre {
+			marker = "● "
+		}
+		titles := make([]string, len(g.Channels))
+		for j, ch := range g.Channels {
+			titles[j] = ch.Title
+		}
+		line := fmt.Sprintf("%s%-*s %3d  %s", marker, nameWidth, name, len(g.Channels), strings.Join(titles, ", "))
+		line = ansi.Truncate(line, width, "…")
+
+		style := lipgloss.NewStyle().Foreground(ui.TextColor)
+		if i == m.genreCursor {
+			style = lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true)
+		}
+		lines = append(lines, style.Render(line))
+	}
+
+	header := ui.TitleStyle.UnsetMarginLeft().Render("Genres")
+	footer := lipgloss.NewStyle().Foreground(ui.SubtleColor).
+		Render("enter shows the genre's channels · esc closes · x clears filters")
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.SubtleColor).
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, "", strings.Join(lines, "\n"), "", footer))
+
+	return lipgloss.Place(m.Width, m.List.Height(), lipgloss.Center, lipgloss.Center, box)
+}
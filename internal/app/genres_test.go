@@ -0,0 +1,57 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenres_GroupsChannelsByGenre(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 120, 40
+	m.UpdateListSize()
+
+	sendKey(m, 'g')
+	require.True(t, m.GenresOpen)
+
+	view := m.View()
+	assert.Contains(t, view, "all genres")
+	assert.Contains(t, view, "ambient      2  Groove Salad, Drone Zone")
+	assert.Contains(t, view, "spy          1  Secret Agent")
+
+	sendKey(m, 'g')
+	assert.False(t, m.GenresOpen)
+	assert.Empty(t, m.Filters.Genre, "closing picks nothing")
+}
+
+func TestGenres_EnterFiltersTheList(t *testing.T) {
+	m := newTestModel(t)
+
+	sendKey(m, 'g')
+	sendKey(m, 'j') // ambient
+	sendKey(m, 'j') // lounge
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	assert.False(t, m.GenresOpen)
+	assert.Equal(t, "lounge", m.Filters.Genre)
+	assert.Equal(t, []string{"secretagent"}, listIDs(m))
+
+	sendKey(m, 'g')
+	assert.Equal(t, 2, m.genreCursor, "the picker opens on the active genre")
+	sendKey(m, 'k')
+	sendKey(m, 'k')
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Empty(t, m.Filters.Genre, "the first row lifts the filter")
+	assert.Len(t, listIDs(m), 3)
+}
+
+func TestGenres_NotInDirectory(t *testing.T) {
+	m := newTestModel(t)
+	m.Directory = true
+
+	sendKey(m, 'g')
+
+	assert.False(t, m.GenresOpen)
+}
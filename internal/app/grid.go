@@ -0,0 +1,75 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"somad/internal/ui"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// gridMinCardWidth is the narrowest card of the grid layout; a list too
+// narrow for two of them side by side stays a list.
+const gridMinCardWidth = 28
+
+// gridColumns returns how many cards fit side by side in the list area.
+func (m *Model) gridColumns() int {
+	return m.List.Width() / gridMinCardWidth
+}
+
+// gridActive reports whether the channels show as a grid of cards: the
+// grid layout is on and the list area is wide enough for it.
+func (m *Model) gridActive() bool {
+	return m.Grid && m.gridColumns() >= 2
+}
+
+// moveGrid moves the highlight through the grid: by one card left or
+// right, by a row up or down, stopping at the first and last channel.
+func (m *Model) moveGrid(key string) {
+	step := 1
+	switch key {
+	case "up", "k":
+		step = -m.gridColumns()
+	case "down", "j":
+		step = m.gridColumns()
+	case "left":
+		step = -1
+	}
+	target := m.List.Index() + step
+	if target < 0 || target >= len(m.List.Items()) {
+		return
+	}
+	m.List.Select(target)
+}
+
+// renderGrid renders the channels as rows of cards filling the list area,
+// scrolled to keep the highlighted channel's row in view, below a count
+// like the list's.
+func (m *Model) renderGrid() string {
+	items := m.List.Items()
+	cols := m.gridColumns()
+	width := m.List.Width() / cols
+	// One row goes to the count above the cards.
+	visible := max((m.List.Height()-1)/ui.CardHeight, 1)
+	first := max(m.List.Index()/cols-visible+1, 0)
+
+	noun := "channels"
+	if len(items) == 1 {
+		noun = "channel"
+	}
+	lines := []string{lipgloss.NewStyle().Foreground(ui.SubtleColor).Padding(0, 0, 0, 2).
+		Render(fmt.Sprintf("%d %s", len(items), noun))}
+	for row := first; row < first+visible && row*cols < len(items); row++ {
+		cards := make([]string, 0, cols)
+		for i := row * cols; i < min((row+1)*cols, len(items)); i++ {
+			cards = append(cards, m.Delegate.RenderCard(m.List, i, width))
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, cards...))
+	}
+	return lipgloss.NewStyle().
+		Width(m.List.Width()).
+		Height(m.List.Height()).
+		MaxHeight(m.List.Height()).
+		Render(strings.Join(lines, "\n"))
+}
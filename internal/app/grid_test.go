@@ -0,0 +1,71 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"somad/internal/protocol"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrid_CardsAndMoves(t *testing.T) {
+	m := newTestModel(t)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m.applySnapshot(protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "dronezone", ChannelTitle: "Drone Zone", Volume: 1,
+	})
+
+	sendKey(m, 'G')
+	require.True(t, m.Grid)
+	require.Equal(t, 4, m.gridColumns())
+
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "3 channels")
+	assert.NotContains(t, view, "Listeners", "the cards carry the counts")
+	row := ""
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, "Groove Salad") {
+			row = line
+			break
+		}
+	}
+	assert.Contains(t, row, "▶ Drone Zone", "the cards sit side by side")
+	assert.Contains(t, row, "Secret Agent")
+	assert.Contains(t, view, "1,000 ♪")
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	assert.Equal(t, 1, m.List.Index())
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, 1, m.List.Index(), "no row below")
+	m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	assert.Equal(t, 0, m.List.Index(), "stops at the first channel")
+
+	sendKey(m, 'G')
+	assert.Contains(t, ansi.Strip(m.View()), "Listeners", "back to the list")
+}
+
+func TestGrid_RowsOnNarrowerListsAndFallback(t *testing.T) {
+	m := newTestModel(t)
+	m.Grid = true
+	m.Update(tea.WindowSizeMsg{Width: 60, Height: 30})
+	require.Equal(t, 2, m.gridColumns())
+
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, 2, m.List.Index(), "down moves a row of two")
+	m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	assert.Equal(t, 0, m.List.Index())
+	for _, line := range strings.Split(ansi.Strip(m.View()), "\n") {
+		assert.LessOrEqual(t, ansi.StringWidth(line), 60)
+	}
+
+	m.Update(tea.WindowSizeMsg{Width: 40, Height: 30})
+	assert.False(t, m.gridActive(), "too narrow for two cards")
+	assert.Contains(t, ansi.Strip(m.View()), "Listeners")
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, 1, m.List.Index(), "the list moves as usual")
+}
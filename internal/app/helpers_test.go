@@ -1,6 +1,7 @@
 package app
 
 import (
+	"fmt"
 	"slices"
 	"sync"
 	"testing"
@@ -18,12 +19,36 @@ import (
 type fakeBackend struct {
 	mu        sync.Mutex
 	playIDs   []string
+	previews  []string
+	prebufs   []string
 	stops     int
 	shutdowns int
 	volumes   []float64
+	level     float64
+	spectrum  []float64
+	stats     protocol.StatsResult
+	history   []protocol.TrackEntry
+	artwork   protocol.ArtworkResult
+	logos     map[string][]byte // channel logos by ID
+	logoCalls []string
+	songs     map[string][]protocol.SongEntry
+	loved     []protocol.TrackEntry
+	loves     []string // "channelID|title" per ToggleLove call
+	qualities []string
+	presets   []string
 	favorites []string
 	status    protocol.PlaybackState
 	payload   protocol.ChannelsPayload
+	queries   []string
+	// hintDismissals counts DismissFavoritesHint calls.
+	hintDismissals int
+	// refreshes counts RefreshChannels calls.
+	refreshes int
+	// sorts records SetChannelSort calls.
+	sorts []string
+	// sources records SetChannelSource calls.
+	sources  []string
+	stations []channels.Channel
 	// callErr, when set, fails every request method; shutdownErr fails
 	// Shutdown specifically.
 	callErr     error
@@ -65,6 +90,121 @@ func (b *fakeBackend) Play(channelID string) (protocol.PlaybackState, error) {
 	return b.status, nil
 }
 
+func (b *fakeBackend) Preview(channelID string) (protocol.PlaybackState, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return protocol.PlaybackState{}, b.callErr
+	}
+	b.previews = append(b.previews, channelID)
+	if channelID == "" {
+		b.status = protocol.PlaybackState{Status: protocol.StatusStopped, Volume: b.status.Volume}
+		return b.status, nil
+	}
+	b.status.Status = protocol.StatusPlaying
+	b.status.ChannelID = channelID
+	b.status.Preview = true
+	return b.status, nil
+}
+
+func (b *fakeBackend) Prebuffer(channelID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return b.callErr
+	}
+	b.prebufs = append(b.prebufs, channelID)
+	return nil
+}
+
+func (b *fakeBackend) History() ([]protocol.TrackEntry, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return nil, b.callErr
+	}
+	return b.history, nil
+}
+
+func (b *fakeBackend) Loved() ([]protocol.TrackEntry, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return nil, b.callErr
+	}
+	return b.loved, nil
+}
+
+func (b *fakeBackend) ToggleLove(channelID, title string) (protocol.LovedResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return protocol.LovedResult{}, b.callErr
+	}
+	b.loves = append(b.loves, channelID+"|"+title)
+	return protocol.LovedResult{Tracks: b.loved}, nil
+}
+
+func (b *fakeBackend) Artwork() (protocol.ArtworkResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return protocol.ArtworkResult{}, b.callErr
+	}
+	return b.artwork, nil
+}
+
+func (b *fakeBackend) ChannelArtwork(channelID string) (protocol.ArtworkResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.logoCalls = append(b.logoCalls, channelID)
+	if b.callErr != nil {
+		return protocol.ArtworkResult{}, b.callErr
+	}
+	return protocol.ArtworkResult{Image: b.logos[channelID]}, nil
+}
+
+func (b *fakeBackend) RecentSongs(channelID string) (protocol.RecentSongsResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return protocol.RecentSongsResult{}, b.callErr
+	}
+	return protocol.RecentSongsResult{ChannelID: channelID, Songs: b.songs[channelID]}, nil
+}
+
+func (b *fakeBackend) Level() (float64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return 0, b.callErr
+	}
+	return b.level, nil
+}
+
+func (b *fakeBackend) Spectrum(int) ([]float64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return nil, b.callErr
+	}
+	return b.spectrum, nil
+}
+
+func (b *fakeBackend) PlayPause() (protocol.PlaybackState, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return protocol.PlaybackState{}, b.callErr
+	}
+	if b.status.Status == protocol.StatusPaused {
+		b.status.Status = protocol.StatusPlaying
+	} else {
+		b.status.Status = protocol.StatusPaused
+	}
+	return b.status, nil
+}
+
 func (b *fakeBackend) Stop() (protocol.PlaybackState, error) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -93,6 +233,60 @@ func (b *fakeBackend) SetVolume(v float64) (protocol.PlaybackState, error) {
 	return b.status, nil
 }
 
+func (b *fakeBackend) ToggleMute() (protocol.PlaybackState, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return protocol.PlaybackState{}, b.callErr
+	}
+	b.status.Muted = !b.status.Muted
+	return b.status, nil
+}
+
+func (b *fakeBackend) SetStreamQuality(quality string) (protocol.PlaybackState, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return protocol.PlaybackState{}, b.callErr
+	}
+	b.qualities = append(b.qualities, quality)
+	b.status.StreamQuality = quality
+	return b.status, nil
+}
+
+func (b *fakeBackend) Stats() (protocol.StatsResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return protocol.StatsResult{}, b.callErr
+	}
+	return b.stats, nil
+}
+
+func (b *fakeBackend) SetEqualizer(preset string) (protocol.PlaybackState, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return protocol.PlaybackState{}, b.callErr
+	}
+	b.presets = append(b.presets, preset)
+	b.status.Equalizer = preset
+	return b.status, nil
+}
+
+func (b *fakeBackend) TimeShift(seconds float64, live bool) (protocol.PlaybackState, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return protocol.PlaybackState{}, b.callErr
+	}
+	b.status.Behind = max(b.status.Behind+seconds, 0)
+	if live {
+		b.status.Behind = 0
+	}
+	return b.status, nil
+}
+
 func (b *fakeBackend) Shutdown() error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -114,6 +308,71 @@ func (b *fakeBackend) ToggleFavorite(channelID string) ([]string, error) {
 	return slices.Clone(b.favorites), nil
 }
 
+func (b *fakeBackend) MoveFavorite(channelID string, delta int) ([]string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return nil, b.callErr
+	}
+	i := slices.Index(b.favorites, channelID)
+	if i < 0 {
+		return nil, fmt.Errorf("not a favorite: %s", channelID)
+	}
+	j := min(max(i+delta, 0), len(b.favorites)-1)
+	b.favorites = slices.Insert(slices.Delete(b.favorites, i, i+1), j, channelID)
+	return slices.Clone(b.favorites), nil
+}
+
+func (b *fakeBackend) SetChannelSource(source string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return b.callErr
+	}
+	b.sources = append(b.sources, source)
+	return nil
+}
+
+func (b *fakeBackend) SetChannelSort(sort string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return b.callErr
+	}
+	b.sorts = append(b.sorts, sort)
+	return nil
+}
+
+func (b *fakeBackend) RefreshChannels() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return b.callErr
+	}
+	b.refreshes++
+	return nil
+}
+
+func (b *fakeBackend) DismissFavoritesHint() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return b.callErr
+	}
+	b.hintDismissals++
+	return nil
+}
+
+func (b *fakeBackend) SearchStations(query string) ([]channels.Channel, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.callErr != nil {
+		return nil, b.callErr
+	}
+	b.queries = append(b.queries, query)
+	return slices.Clone(b.stations), nil
+}
+
 // testChannels returns a fixed set of channels used across test files.
 func testChannels() []channels.Channel {
 	return []channels.Channel{
@@ -122,7 +381,7 @@ func testChannels() []channels.Channel {
 			Title:       "Groove Salad",
 			Description: "A nicely chilled plate of ambient beats",
 			Genre:       "ambient",
-			Listeners:   "1000",
+			Listeners:   1000,
 			Playlists:   []channels.Playlist{{URL: "http://somafm.com/groovesalad.pls", Format: "mp3"}},
 		},
 		{
@@ -130,7 +389,7 @@ func testChannels() []channels.Channel {
 			Title:       "Drone Zone",
 			Description: "Atmospheric texture and ambient space music",
 			Genre:       "ambient|space",
-			Listeners:   "500",
+			Listeners:   500,
 			Playlists:   []channels.Playlist{{URL: "http://somafm.com/dronezone.pls", Format: "mp3"}},
 		},
 		{
@@ -138,7 +397,7 @@ func testChannels() []channels.Channel {
 			Title:       "Secret Agent",
 			Description: "The soundtrack for your spy movie marathon",
 			Genre:       "lounge|spy",
-			Listeners:   "750",
+			Listeners:   750,
 			Playlists:   []channels.Playlist{{URL: "http://somafm.com/secretagent.pls", Format: "mp3"}},
 		},
 	}
@@ -155,14 +414,17 @@ func newTestModel(t *testing.T) *Model {
 		Width:        80,
 		Height:       24,
 		CurrentMatch: -1,
+		catalog:      testChannels(),
 	}
 
 	items := ChannelsToItems(testChannels())
-	delegate := ui.NewStyledDelegate(&m.PlayingID, m.IsMatch, m.IsFavorite)
+	delegate := ui.NewStyledDelegate(&m.PlayingID, m.IsMatch, m.IsFavorite, m.IsCustom)
 	l := list.New(items, delegate, 80, 24)
 	l.SetShowTitle(false)
 	l.SetFilteringEnabled(false)
+	l.KeyMap = NewListKeyMap()
 	m.List = l
+	m.Delegate = delegate
 
 	return m
 }
@@ -179,3 +441,18 @@ func runCmd(cmd tea.Cmd) tea.Msg {
 	}
 	return cmd()
 }
+
+// updateWith runs cmd, and every command of a batch it returns, feeding
+// each message to the model.
+func updateWith(m *Model, cmd tea.Cmd) {
+	msg := runCmd(cmd)
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			updateWith(m, c)
+		}
+		return
+	}
+	if msg != nil {
+		m.Update(msg)
+	}
+}
@@ -0,0 +1,194 @@
+package app
+
+import (
+	"strings"
+
+	"somad/internal/protocol"
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// historyMsg carries the server's track history.
+type historyMsg struct {
+	Tracks []protocol.TrackEntry
+	Err    error
+}
+
+// fetchHistoryCmd asks the server for the tracks it has seen.
+func (m *Model) fetchHistoryCmd() tea.Cmd {
+	b := m.Backend
+	return func() tea.Msg {
+		tracks, err := b.History()
+		return historyMsg{Tracks: tracks, Err: err}
+	}
+}
+
+// historyWidth is how many columns the track history panel takes, border
+// included.
+const historyWidth = 44
+
+// ToggleHistory opens or closes the track history panel, fetching the
+// history on open along with the loved tracks its hearts come from.
+func (m *Model) ToggleHistory() tea.Cmd {
+	m.HistoryOpen = !m.HistoryOpen
+	m.UpdateListSize()
+	if !m.HistoryOpen {
+		return nil
+	}
+	m.historyCursor = 0
+	return tea.Batch(m.fetchHistoryCmd(), m.fetchLovedCmd())
+}
+
+// applyHistory records a fetched history; a failed fetch keeps the last one.
+func (m *Model) applyHistory(msg historyMsg) {
+	if msg.Err != nil {
+		m.RequestErr = "history failed: " + msg.Err.Error()
+		return
+	}
+	m.History = msg.Tracks
+}
+
+// refreshHistory refetches the open history when the playing track changed,
+// so the newest entry shows up without reopening it.
+func (m *Model) refreshHistory(prevTrack string) tea.Cmd {
+	if !m.HistoryOpen || m.Snapshot.TrackTitle == prevTrack || m.Snapshot.TrackTitle == "" {
+		return nil
+	}
+	return m.fetchHistoryCmd()
+}
+
+// historyTracks returns the history of the playing channel (the last one
+// played, once stopped), newest first.
+func (m *Model) historyTracks() []protocol.TrackEntry {
+	var tracks []protocol.TrackEntry
+	for _, t := range m.History {
+		if t.ChannelID == m.Snapshot.ChannelID {
+			tracks = append(tracks, t)
+		}
+	}
+	return tracks
+}
+
+// highlightedTrack returns the track under the panel's cursor, which
+// stays within the playing channel's history as it grows or changes.
+func (m *Model) highlightedTrack() (protocol.TrackEntry, bool) {
+	tracks := m.historyTracks()
+	if len(tracks) == 0 {
+		return protocol.TrackEntry{}, false
+	}
+	m.historyCursor = min(m.historyCursor, len(tracks)-1)
+	return tracks[m.historyCursor], true
+}
+
+// isLoved reports whether t is among the loved tracks.
+func (m *Model) isLoved(t protocol.TrackEntry) bool {
+	for _, l := range m.Loved {
+		if l.ChannelID == t.ChannelID && l.Title == t.Title {
+			return true
+		}
+	}
+	return false
+}
+
+// historyPaneWidth returns how many columns the track history panel takes
+// from the list: none while it is closed, or when the terminal is too
+// narrow to show it beside the list, in which case it takes the list's
+// place.
+func (m *Model) historyPaneWidth() int {
+	if !m.HistoryOpen || m.Width < m.detailsPaneWidth()+historyWidth+detailsMinListWidth {
+		return 0
+	}
+	return historyWidth
+}
+
+// updateHistory handles the panel's keys while it is open: j/k move, y
+// copies the highlighted title, l loves or unloves it, o searches the web
+// for it, esc or h closes. It reports false for any other key, which works
+// the list as usual.
+func (m *Model) updateHistory(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "esc", "h":
+		m.HistoryOpen = false
+		m.UpdateListSize()
+	case "up", "k":
+		if m.historyCursor > 0 {
+			m.historyCursor--
+		}
+	case "down", "j":
+		if m.historyCursor < len(m.historyTracks())-1 {
+			m.historyCursor++
+		}
+	case "y":
+		if t, ok := m.highlightedTrack(); ok {
+			return copyCmd("track", t.Title), true
+		}
+	case "l":
+		if t, ok := m.highlightedTrack(); ok {
+			return m.toggleLoveCmd(t.ChannelID, t.Title), true
+		}
+	case "o":
+		if t, ok := m.highlightedTrack(); ok {
+			return m.searchTrackCmd(t.Title, t.Artist, t.Song), true
+		}
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// renderHistory renders the playing channel's track history as a bordered
+// panel width columns wide and as tall as the list, scrolled to keep the
+// highlighted track in view. Loved tracks carry a heart.
+func (m *Model) renderHistory(width int) string {
+	inner := max(width-4, 1) // border and padding
+	subtle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+	// The panel spends seven rows on its border, header, footer and spacing.
+	visible := max(m.List.Height()-7, 1)
+
+	channel := m.Snapshot.ChannelTitle
+	if channel == "" {
+		channel = "Nothing played yet"
+	}
+	lines := []string{
+		ui.TitleStyle.UnsetMarginLeft().Render("Track history"),
+		subtle.Render(ansi.Truncate(channel, inner, "…")),
+		"",
+	}
+
+	tracks := m.historyTracks()
+	_, _ = m.highlightedTrack() // clamps the cursor
+	first := max(m.historyCursor-visible+1, 0)
+	if len(tracks) == 0 && m.Snapshot.ChannelID != "" {
+		lines = append(lines, subtle.Width(inner).Render("No tracks yet — titles appear here as the channel plays."))
+	}
+	for i := first; i < len(tracks) && i < first+visible; i++ {
+		t := tracks[i]
+		mark := "  "
+		if m.isLoved(t) {
+			mark = "♥ "
+		}
+		line := ansi.Truncate(m.formatClock(t.Time)+" "+mark+t.Title, inner, "…")
+
+		style := lipgloss.NewStyle().Foreground(ui.TextColor)
+		if i == m.historyCursor {
+			style = lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true)
+		}
+		lines = append(lines, style.Render(line))
+	}
+
+	footer := subtle.Width(inner).Render("y copy · l love · o search · esc close")
+	body := strings.Join(lines, "\n")
+	// The footer sits at the bottom of the panel, however few the tracks.
+	blank := max(m.List.Height()-2-lipgloss.Height(body)-lipgloss.Height(footer), 1)
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.SubtleColor).
+		Padding(0, 1).
+		Width(width - 2).
+		Height(max(m.List.Height()-2, 1)).
+		MaxHeight(m.List.Height()).
+		Render(body + strings.Repeat("\n", blank+1) + footer)
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"somad/internal/protocol"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory_PanelShowsThePlayingChannel(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 120, 30
+	m.UTC = true
+	at := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+	backend(m).history = []protocol.TrackEntry{
+		{Time: at, ChannelID: "dronezone", Channel: "Drone Zone", Title: "Stars of the Lid - Requiem"},
+		{Time: at.Add(-5 * time.Minute), ChannelID: "groovesalad", Channel: "Groove Salad", Title: "Tycho - Awake", Artist: "Tycho", Song: "Awake"},
+		{Time: at.Add(-10 * time.Minute), ChannelID: "groovesalad", Channel: "Groove Salad", Title: "Bonobo - Kerala"},
+	}
+	backend(m).loved = []protocol.TrackEntry{{ChannelID: "groovesalad", Title: "Bonobo - Kerala"}}
+	m.applySnapshot(protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "groovesalad", ChannelTitle: "Groove Salad", TrackTitle: "Tycho - Awake", Volume: 1,
+	})
+
+	_, cmd := sendKey(m, 'h')
+	require.True(t, m.HistoryOpen)
+	updateWith(m, cmd)
+	assert.Equal(t, 120-historyWidth, m.List.Width(), "the panel sits beside the list")
+
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "Track history")
+	assert.Contains(t, view, "SomaFM Stations", "the list stays up")
+	assert.Contains(t, view, "14:00   Tycho - Awake")
+	assert.Contains(t, view, "13:55 ♥ Bonobo - Kerala")
+	assert.NotContains(t, view, "Stars of the Lid", "only the playing channel's tracks")
+
+	sendKey(m, 'j')
+	_, cmd = sendKey(m, 'y')
+	msg, ok := runCmd(cmd).(ClipboardMsg)
+	require.True(t, ok)
+	assert.Equal(t, "Bonobo - Kerala", msg.Text)
+
+	_, cmd = sendKey(m, 'l')
+	m.Update(runCmd(cmd))
+	assert.Equal(t, []string{"groovesalad|Bonobo - Kerala"}, backend(m).loves, "l loves the highlighted track")
+
+	opened := stubOpenURL(t, nil)
+	sendKey(m, 'k')
+	_, cmd = sendKey(m, 'o')
+	m.Update(runCmd(cmd))
+	assert.Equal(t, []string{"https://www.youtube.com/results?search_query=Tycho+Awake"}, *opened)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, 1, m.historyCursor, "the arrows move the panel's cursor")
+	_, cmd = sendKey(m, 's')
+	assert.NotNil(t, cmd, "other keys work the list as usual")
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, m.HistoryOpen)
+	assert.Equal(t, 120, m.List.Width())
+}
+
+func TestHistory_PanelTakesTheListOnNarrowTerminals(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 60, 30
+	m.applySnapshot(protocol.PlaybackState{Status: protocol.StatusPlaying, ChannelID: "groovesalad", ChannelTitle: "Groove Salad", Volume: 1})
+
+	_, cmd := sendKey(m, 'h')
+	updateWith(m, cmd)
+	view := ansi.Strip(m.View())
+	assert.Contains(t, view, "No tracks yet")
+	assert.NotContains(t, view, "Drone Zone", "the panel takes the list's place")
+	for _, line := range strings.Split(view, "\n") {
+		assert.LessOrEqual(t, ansi.StringWidth(line), 60)
+	}
+}
+
+func TestHistory_RefreshesOnTrackChangeWhileOpen(t *testing.T) {
+	m := newTestModel(t)
+	m.HistoryOpen = true
+
+	_, cmd := m.Update(ServerStateMsg{State: protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "dronezone", TrackTitle: "New Song",
+	}})
+	require.NotNil(t, cmd)
+	batch, ok := runCmd(cmd).(tea.BatchMsg)
+	require.True(t, ok, "the level poll and the history fetch")
+	assert.Len(t, batch, 2)
+
+	m.HistoryOpen = false
+	_, cmd = m.Update(ServerStateMsg{State: protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "dronezone", TrackTitle: "Next Song",
+	}})
+	_, isBatch := runCmd(cmd).(tea.BatchMsg)
+	assert.False(t, isBatch, "a closed history is not fetched")
+}
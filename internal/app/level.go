@@ -0,0 +1,86 @@
+package app
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"somad/internal/protocol"
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// levelInterval is how often the level meter polls the server while a
+// stream plays.
+const levelInterval = 100 * time.Millisecond
+
+// levelFloorDB is the quietest level the meter shows; anything below reads
+// as silence.
+const levelFloorDB = -48
+
+// levelBars are the meter's cells, rising left to right.
+var levelBars = []rune("▁▂▃▄▅▆")
+
+// levelMsg carries a polled audio level, and the spectrum when the
+// visualizer is on.
+type levelMsg struct {
+	Level    float64
+	Spectrum []float64
+}
+
+// levelCmd asks the server for the audio level, and the spectrum with the
+// visualizer on, after levelInterval. A failed poll reads as silence rather
+// than a request error: the meter is not worth a status bar notice ten
+// times a second.
+func (m *Model) levelCmd() tea.Cmd {
+	b, visualizer := m.Backend, m.Visualizer
+	return tea.Tick(levelInterval, func(time.Time) tea.Msg {
+		level, err := b.Level()
+		if err != nil {
+			return levelMsg{}
+		}
+		msg := levelMsg{Level: level}
+		if visualizer {
+			msg.Spectrum, _ = b.Spectrum(spectrumBands)
+		}
+		return msg
+	})
+}
+
+// pollLevel starts the level poll loop when a stream is playing and no loop
+// runs yet. The loop ends by itself once playback stops.
+func (m *Model) pollLevel() tea.Cmd {
+	if m.levelPolling || m.Snapshot.Status != protocol.StatusPlaying {
+		return nil
+	}
+	m.levelPolling = true
+	return m.levelCmd()
+}
+
+// applyLevel records a polled level and spectrum and schedules the next
+// poll while playing.
+func (m *Model) applyLevel(msg levelMsg) tea.Cmd {
+	if m.Snapshot.Status != protocol.StatusPlaying {
+		m.levelPolling = false
+		m.Level, m.Spectrum = 0, nil
+		return nil
+	}
+	m.Level, m.Spectrum = msg.Level, msg.Spectrum
+	return m.levelCmd()
+}
+
+// renderLevelMeter draws level (linear RMS in [0, 1]) on a decibel scale
+// from levelFloorDB to full scale: lit cells rise, unlit ones are dimmed.
+func renderLevelMeter(level float64) string {
+	lit := 0
+	if level > 0 {
+		scaled := (20*math.Log10(level) - levelFloorDB) / -levelFloorDB
+		lit = int(math.Round(math.Max(0, math.Min(1, scaled)) * float64(len(levelBars))))
+	}
+	litStyle := lipgloss.NewStyle().Foreground(ui.PlayingColor)
+	dimStyle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+	return litStyle.Render(string(levelBars[:lit])) +
+		dimStyle.Render(strings.Repeat(string(levelBars[0]), len(levelBars)-lit))
+}
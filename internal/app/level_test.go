@@ -0,0 +1,48 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"somad/internal/protocol"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevel_PollsOnlyWhilePlaying(t *testing.T) {
+	m := newTestModel(t)
+	backend(m).level = 0.5
+
+	_, cmd := m.Update(ServerStateMsg{State: protocol.PlaybackState{Status: protocol.StatusPlaying}})
+	require.NotNil(t, cmd, "playing starts the poll loop")
+	_, again := m.Update(ServerStateMsg{State: protocol.PlaybackState{Status: protocol.StatusPlaying}})
+	assert.Nil(t, again, "a second snapshot does not start another loop")
+
+	_, next := m.Update(runCmd(cmd))
+	assert.InDelta(t, 0.5, m.Level, 1e-9)
+	assert.NotNil(t, next, "the loop continues while playing")
+
+	m.Update(ServerStateMsg{State: protocol.PlaybackState{Status: protocol.StatusPaused}})
+	_, next = m.Update(runCmd(next))
+	assert.Nil(t, next, "the loop ends once playback stops")
+	assert.Zero(t, m.Level)
+}
+
+func TestRenderLevelMeter_Scale(t *testing.T) {
+	assert.Equal(t, strings.Repeat("▁", 6), ansi.Strip(renderLevelMeter(0)))
+	assert.Equal(t, "▁▂▃▄▅▆", ansi.Strip(renderLevelMeter(1)))
+	assert.Equal(t, "▁▂▃▁▁▁", ansi.Strip(renderLevelMeter(0.0631)), "-24 dB lights half the meter")
+}
+
+func TestRenderStatusBar_ShowsLevelMeterWhilePlaying(t *testing.T) {
+	m := newTestModel(t)
+	m.applySnapshot(protocol.PlaybackState{Status: protocol.StatusPlaying, ChannelTitle: "Groove Salad"})
+	m.Level = 1
+
+	assert.Contains(t, ansi.Strip(m.RenderStatusBar()), "▁▂▃▄▅▆")
+
+	m.applySnapshot(protocol.PlaybackState{Status: protocol.StatusStopped})
+	assert.NotContains(t, ansi.Strip(m.RenderStatusBar()), "▁▁▁")
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// listenedFor returns how long the channel has been played in total,
+// including the listen running right now.
+func (m *Model) listenedFor(id string) time.Duration {
+	d := time.Duration(m.Listened[id]) * time.Second
+	if m.Snapshot.ChannelID == id && !m.Snapshot.ListeningSince.IsZero() {
+		d += max(time.Since(m.Snapshot.ListeningSince), 0)
+	}
+	return d
+}
+
+// listeningRow is one channel in the listening time table.
+type listeningRow struct {
+	ID       string
+	Title    string
+	Listened time.Duration
+}
+
+// listeningRows returns the catalog channels that have been played, most
+// listened first.
+func (m *Model) listeningRows() []listeningRow {
+	var rows []listeningRow
+	for _, ch := range m.catalog {
+		if d := m.listenedFor(ch.ID); d >= time.Second {
+			rows = append(rows, listeningRow{ID: ch.ID, Title: ch.Title, Listened: d})
+		}
+	}
+	slices.SortStableFunc(rows, func(a, b listeningRow) int {
+		return cmp.Or(cmp.Compare(b.Listened, a.Listened), strings.Compare(a.Title, b.Title))
+	})
+	return rows
+}
+
+// OpenListening shows the listening time per channel.
+func (m *Model) OpenListening() {
+	m.ListeningOpen = true
+	m.listeningCursor = 0
+}
+
+// updateListening handles keys while the listening time table is open: j/k
+// move, enter plays the highlighted channel, esc or T closes.
+func (m *Model) updateListening(msg tea.KeyMsg) tea.Cmd {
+	rows := m.listeningRows()
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m.quitCmd()
+	case "esc", "T":
+		m.ListeningOpen = false
+	case "up", "k":
+		if m.listeningCursor > 0 {
+			m.listeningCursor--
+		}
+	case "down", "j":
+		if m.listeningCursor < len(rows)-1 {
+			m.listeningCursor++
+		}
+	case "enter", " ":
+		if m.listeningCursor < len(rows) {
+			id := rows[m.listeningCursor].ID
+			m.ListeningOpen = false
+			m.selectChannelByID(id)
+			return m.switchChannelCmd(id)
+		}
+	}
+	return nil
+}
+
+// renderListening renders the listening time per channel as a bordered
+// table centered over the list area, with the total at the top, scrolled
+// to keep the highlighted channel in view.
+func (m *Model) renderListening() string {
+	rows := m.listeningRows()
+	width := max(m.Width-8, 20)
+	// The box spends six rows on its border, header, footer and spacing.
+	visible := max(m.List.Height()-6, 1)
+	first := max(m.listeningCursor-visible+1, 0)
+
+	var lines []string
+	if len(rows) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ui.SubtleColor).
+			Render("Nothing played yet — listening time adds up as you play channels."))
+	}
+	var total time.Duration
+	titleWidth := 0
+	for _, r := range rows {
+		total += r.Listened
+		titleWidth = max(titleWidth, lipgloss.Width(r.Title))
+	}
+	for i := first; i < len(rows) && i < first+visible; i++ {
+		r := rows[i]
+		marker := "  "
+		if r.ID == m.PlayingID {
+			marker = "▶ "
+		}
+		line := fmt.Sprintf("%s%s%s  %8s", marker, r.Title, strings.Repeat(" ", titleWidth-lipgloss.Width(r.Title)), formatListened(r.Listened))
+		line = ansi.Truncate(line, width, "…")
+
+		style := lipgloss.NewStyle().Foreground(ui.TextColor)
+		if i == m.listeningCursor {
+			style = lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true)
+		}
+		lines = append(lines, style.Render(line))
+	}
+
+	header := ui.TitleStyle.UnsetMarginLeft().Render("Listening time")
+	if total > 0 {
+		header += lipgloss.NewStyle().Foreground(ui.SubtleColor).Render("  " + formatListened(total) + " in total")
+	}
+	footer := lipgloss.NewStyle().Foreground(ui.SubtleColor).Render("enter plays · esc closes")
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.SubtleColor).
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, "", strings.Join(lines, "\n"), "", footer))
+
+	return lipgloss.Place(m.Width, m.List.Height(), lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"somad/internal/protocol"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatListened(t *testing.T) {
+	assert.Equal(t, "<1m", formatListened(40*time.Second))
+	assert.Equal(t, "45m", formatListened(45*time.Minute+30*time.Second))
+	assert.Equal(t, "12h 05m", formatListened(12*time.Hour+5*time.Minute))
+}
+
+// listeningModel returns a test model with some listening time on two
+// channels, one of them playing right now.
+func listeningModel(t *testing.T) *Model {
+	t.Helper()
+	m := newTestModel(t)
+	m.Width = 120
+	m.Listened = map[string]int64{"groovesalad": 600, "secretagent": 3600, "gone": 9999}
+	m.Snapshot = protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "groovesalad",
+		ListeningSince: time.Now().Add(-time.Hour),
+	}
+	return m
+}
+
+func TestListeningRows_MostListenedFirstWithRunningListen(t *testing.T) {
+	m := listeningModel(t)
+
+	rows := m.listeningRows()
+
+	assert.Len(t, rows, 2, "unplayed channels and channels gone from the catalog are left out")
+	assert.Equal(t, "groovesalad", rows[0].ID, "the running hour puts it on top")
+	assert.InDelta(t, (70 * time.Minute).Seconds(), rows[0].Listened.Seconds(), 1)
+	assert.Equal(t, "secretagent", rows[1].ID)
+}
+
+func TestListening_RendersTableAndPlays(t *testing.T) {
+	m := listeningModel(t)
+	sendKey(m, 'T')
+
+	view := m.View()
+	assert.Contains(t, view, "Listening time")
+	assert.Contains(t, view, "2h 10m in total")
+	assert.Contains(t, view, "1h 00m")
+
+	sendKey(m, 'j')
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	runCmd(cmd)
+	assert.False(t, m.ListeningOpen)
+	assert.Equal(t, []string{"secretagent"}, backend(m).playIDs)
+}
+
+func TestListening_EmptyTable(t *testing.T) {
+	m := newTestModel(t)
+	sendKey(m, 'T')
+	assert.Contains(t, m.View(), "Nothing played yet")
+	sendKey(m, 'T')
+	assert.False(t, m.ListeningOpen)
+}
+
+func TestRenderDetails_ListeningTime(t *testing.T) {
+	m := listeningModel(t)
+	m.selectChannelByID("secretagent")
+
+	assert.Contains(t, m.renderDetails(), "You listened")
+	assert.Contains(t, m.renderDetails(), "1h 00m")
+
+	m.selectChannelByID("dronezone")
+	assert.NotContains(t, m.renderDetails(), "You listened")
+}
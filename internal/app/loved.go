@@ -0,0 +1,136 @@
+package app
+
+import (
+	"strings"
+
+	"somad/internal/protocol"
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// lovedMsg carries the server's loved tracks.
+type lovedMsg struct {
+	Tracks []protocol.TrackEntry
+	Err    error
+}
+
+// fetchLovedCmd asks the server for the loved tracks.
+func (m *Model) fetchLovedCmd() tea.Cmd {
+	b := m.Backend
+	return func() tea.Msg {
+		tracks, err := b.Loved()
+		return lovedMsg{Tracks: tracks, Err: err}
+	}
+}
+
+// toggleLoveCmd loves or unloves the track titled title on channelID, or
+// the playing track when title is empty. The playing track's heart follows
+// from the state event; the reply refreshes the loved list.
+func (m *Model) toggleLoveCmd(channelID, title string) tea.Cmd {
+	b := m.Backend
+	return func() tea.Msg {
+		result, err := b.ToggleLove(channelID, title)
+		if err != nil {
+			return requestErr("love", err)
+		}
+		return lovedMsg{Tracks: result.Tracks}
+	}
+}
+
+// LoveTrack loves the playing track, or unloves it when it is loved.
+func (m *Model) LoveTrack() tea.Cmd {
+	if m.Snapshot.TrackTitle == "" {
+		return nil
+	}
+	return m.toggleLoveCmd("", "")
+}
+
+// ToggleLoved opens or closes the loved tracks, fetching them on open.
+func (m *Model) ToggleLoved() tea.Cmd {
+	m.LovedOpen = !m.LovedOpen
+	if !m.LovedOpen {
+		return nil
+	}
+	m.lovedCursor = 0
+	return m.fetchLovedCmd()
+}
+
+// applyLoved records fetched loved tracks; a failed fetch keeps the last
+// ones.
+func (m *Model) applyLoved(msg lovedMsg) {
+	if msg.Err != nil {
+		m.RequestErr = "loved tracks failed: " + msg.Err.Error()
+		return
+	}
+	m.Loved = msg.Tracks
+	m.lovedCursor = min(m.lovedCursor, max(len(m.Loved)-1, 0))
+}
+
+// updateLoved handles keys while the loved tracks are open: j/k move, y
+// copies the highlighted title, x unloves it, esc or v closes.
+func (m *Model) updateLoved(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m.quitCmd()
+	case "esc", "v":
+		m.LovedOpen = false
+	case "up", "k":
+		if m.lovedCursor > 0 {
+			m.lovedCursor--
+		}
+	case "down", "j":
+		if m.lovedCursor < len(m.Loved)-1 {
+			m.lovedCursor++
+		}
+	case "y":
+		if m.lovedCursor < len(m.Loved) {
+			return copyCmd("track", m.Loved[m.lovedCursor].Title)
+		}
+	case "x":
+		if m.lovedCursor < len(m.Loved) {
+			t := m.Loved[m.lovedCursor]
+			return m.toggleLoveCmd(t.ChannelID, t.Title)
+		}
+	}
+	return nil
+}
+
+// renderLoved renders the loved tracks as a bordered table centered over
+// the list area, scrolled to keep the highlighted track in view.
+func (m *Model) renderLoved() string {
+	width := max(m.Width-8, 20)
+	// The box spends six rows on its border, header, footer and spacing.
+	visible := max(m.List.Height()-6, 1)
+	first := max(m.lovedCursor-visible+1, 0)
+
+	var lines []string
+	if len(m.Loved) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ui.SubtleColor).
+			Render("No loved tracks yet — press l while a track you like plays."))
+	}
+	for i := first; i < len(m.Loved) && i < first+visible; i++ {
+		t := m.Loved[i]
+		line := m.formatTime(t.Time) + "  " + t.Title + "  · " + t.Channel
+		line = ansi.Truncate(line, width, "…")
+
+		style := lipgloss.NewStyle().Foreground(ui.TextColor)
+		if i == m.lovedCursor {
+			style = lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true)
+		}
+		lines = append(lines, style.Render(line))
+	}
+
+	header := ui.TitleStyle.UnsetMarginLeft().Render("Loved tracks")
+	footer := lipgloss.NewStyle().Foreground(ui.SubtleColor).
+		Render("y copies the title · x unloves · esc closes · soma loved --json exports")
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.SubtleColor).
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, "", strings.Join(lines, "\n"), "", footer))
+
+	return lipgloss.Place(m.Width, m.List.Height(), lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"somad/internal/protocol"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoveTrack_TogglesThePlayingTrack(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 120
+
+	_, cmd := sendKey(m, 'l')
+	assert.Nil(t, cmd, "nothing to love while no track plays")
+
+	playingTrack(m, "Tycho - Awake", "Tycho", "Awake")
+	_, cmd = sendKey(m, 'l')
+	require.NotNil(t, cmd)
+	m.Update(runCmd(cmd))
+	assert.Equal(t, []string{"|"}, backend(m).loves, "an empty title loves the playing track")
+
+	m.Update(ServerStateMsg{State: protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "groovesalad", ChannelTitle: "Groove Salad",
+		TrackTitle: "Tycho - Awake", TrackLoved: true,
+	}})
+	assert.Contains(t, m.View(), "♥")
+}
+
+func TestLoved_ListsAndUnlovesTracks(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 120
+	m.UTC = true
+	at := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+	backend(m).loved = []protocol.TrackEntry{
+		{Time: at, ChannelID: "groovesalad", Channel: "Groove Salad", Title: "Tycho - Awake"},
+		{Time: at.Add(-time.Hour), ChannelID: "dronezone", Channel: "Drone Zone", Title: "Stars of the Lid - Requiem"},
+	}
+
+	_, cmd := sendKey(m, 'v')
+	require.True(t, m.LovedOpen)
+	m.Update(runCmd(cmd))
+
+	view := m.View()
+	assert.Contains(t, view, "Loved tracks")
+	assert.Contains(t, view, "2026-03-01 14:05 UTC  Tycho - Awake  · Groove Salad")
+	assert.Contains(t, view, "Stars of the Lid - Requiem")
+
+	sendKey(m, 'j')
+	_, cmd = sendKey(m, 'y')
+	msg, ok := runCmd(cmd).(ClipboardMsg)
+	require.True(t, ok)
+	assert.Equal(t, "Stars of the Lid - Requiem", msg.Text)
+
+	backend(m).loved = backend(m).loved[:1]
+	_, cmd = sendKey(m, 'x')
+	m.Update(runCmd(cmd))
+	assert.Equal(t, []string{"dronezone|Stars of the Lid - Requiem"}, backend(m).loves)
+	assert.Len(t, m.Loved, 1)
+	assert.Equal(t, 0, m.lovedCursor, "the cursor stays on the list")
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, m.LovedOpen)
+}
@@ -2,13 +2,29 @@ package app
 
 import (
 	"errors"
+	"image"
+	"slices"
+	"time"
 
+	"somad/internal/channels"
 	"somad/internal/protocol"
 	"somad/internal/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+)
+
+// SpaceAction selects what the space key does in the channel list.
+type SpaceAction string
+
+// Space key actions. Whatever the action, space still dismisses the about
+// footer first.
+const (
+	SpacePlay   SpaceAction = "play"   // play the selected channel
+	SpaceToggle SpaceAction = "toggle" // stop if playing, else play the selected channel
+	SpaceNone   SpaceAction = "none"   // do nothing
 )
 
 // AboutInfo holds version and metadata for the about screen.
@@ -26,16 +42,25 @@ type Model struct {
 
 	// Snapshot is the latest authoritative playback state from the server.
 	Snapshot protocol.PlaybackState
-	// Favorites mirrors the server-persisted favorite channel IDs.
+	// Favorites mirrors the server-persisted favorite channel IDs, in the
+	// pinned order they are listed in.
 	Favorites []string
 	// PlayingID is derived from Snapshot for the list delegate's playing marker.
 	PlayingID string
+	// FailedID is derived from Snapshot: the channel whose stream failed
+	// for good. While set, the status bar prompts to retry it (r), play the
+	// next channel (n), or stop (s).
+	FailedID string
 	// ServerLost is true while the server connection is being re-established.
 	ServerLost bool
 	// ServerVersion is the version the connected server reports. When it differs
 	// from About.Version the server is out of date and the next channel change
 	// or stop restarts it onto ours (see skewed).
 	ServerVersion string
+	// pendingChannels holds the latest catalog that arrived while the search
+	// query was being typed; it is applied once the input closes, so the
+	// list does not reorder under the user mid-search.
+	pendingChannels *protocol.ChannelsPayload
 	// pendingPlayID is a channel to play once the server has been restarted for
 	// a version upgrade and the reconnect has delivered a fresh backend.
 	pendingPlayID string
@@ -46,14 +71,187 @@ type Model struct {
 	// status bar until the server next answers successfully.
 	RequestErr string
 	ShowAbout  bool
-	About     AboutInfo
-	Width     int
-	Height    int
+	About      AboutInfo
+	Width      int
+	Height     int
+	// Notice is a transient confirmation (e.g. a clipboard copy), shown in
+	// the status bar until the next key press.
+	Notice string
 	// ShutdownOnExit asks the server to stop playback and exit when the TUI
 	// closes. OnExit is called before quitting so the reconnect bridge does not
 	// auto-spawn a replacement server.
 	ShutdownOnExit bool
 	OnExit         func()
+	// Splash shows the startup banner until it times out or a key is pressed;
+	// the catalog loads behind it.
+	Splash bool
+	// UTC shows timestamps in UTC instead of the local zone; t toggles it.
+	UTC bool
+	// Sort orders the SomaFM catalog below the favorites.
+	Sort SortOrder
+	// Sources names the server's station sources in catalog order; S
+	// switches Source between them, empty listing them all merged.
+	Sources []string
+	Source  string
+	// MinQuality hides catalog channels without an MP3 playlist of at least
+	// this quality ("low", "high" or "highest"); empty shows them all.
+	MinQuality string
+	// ShowGenre shows genres instead of descriptions under each channel; i
+	// toggles it. The list delegate reads it through a pointer.
+	ShowGenre bool
+	// Filters narrow the catalog interactively: F toggles favorites-only, e
+	// cycles genres, x clears both.
+	Filters Filters
+	// SpaceAction is what space does in the list; the zero value plays the
+	// selected channel like enter.
+	SpaceAction SpaceAction
+	// PreviewDelay is how long the cursor rests on a channel before it
+	// plays as a quiet preview while nothing else plays; 0 disables
+	// previews. hoverID is the channel under the cursor and hoverSeq
+	// counts cursor moves, so a preview timed for an earlier position is
+	// dropped.
+	PreviewDelay time.Duration
+	hoverID      string
+	hoverSeq     int
+	// Prebuffer has the server fetch the stream of the channel under the
+	// cursor ahead of time, so playing it starts at once; prebuffered is
+	// the channel it was last asked for.
+	Prebuffer   bool
+	prebuffered string
+	// TrackSearch is where o looks up the playing track: a name from
+	// TrackSearchURLs or a URL template with "{query}"; empty uses
+	// DefaultTrackSearch.
+	TrackSearch string
+	// NoAltScreen renders inline in the terminal's normal buffer instead of
+	// switching to the alternate screen, so the UI stays in the scrollback.
+	NoAltScreen bool
+	// CatalogUpdated is when SomaFM last changed the catalog; zero if
+	// unknown.
+	CatalogUpdated time.Time
+	// CatalogFetched is when the catalog was downloaded; CatalogStale is
+	// set while refreshing it fails, so the list shows the cached one.
+	CatalogFetched time.Time
+	CatalogStale   bool
+	// Offline is set while the server cannot reach SomaFM at all; a banner
+	// says the list is the cached one until a background retry succeeds.
+	Offline bool
+	// Refreshing is set while the server downloads the catalog; Probing
+	// while it checks the channels' streams. DeadChannels maps the channels
+	// whose streams did not answer to why, and the list dims them.
+	Refreshing   bool
+	Probing      bool
+	DeadChannels map[string]string
+	// ListenerTrends is each channel's listener change between the two
+	// latest catalog refreshes, drawn as ▲/▼ beside the channel.
+	ListenerTrends map[string]int
+	// RecentChannels mirrors the server's recently played channels, newest
+	// first.
+	RecentChannels []protocol.RecentChannel
+	// Listened mirrors the server's listening time per channel in seconds,
+	// without the running listen.
+	Listened map[string]int64
+	// FavoritesHintSeen hides the favorites onboarding hint for good; it
+	// mirrors the server's persisted flag.
+	FavoritesHintSeen bool
+	// Level is the latest polled audio level of the playing stream, drawn
+	// as a meter in the status bar; levelPolling is set while a poll loop
+	// runs.
+	Level        float64
+	levelPolling bool
+	// spinner turns in the status bar while a stream connects; spinning is
+	// set while its tick loop runs.
+	spinner  spinner.Model
+	spinning bool
+	// Visualizer polls the spectrum of the playing audio along with the
+	// level, and draws it in place of the meter and in the full-screen
+	// now-playing view; Spectrum is the latest poll.
+	Visualizer bool
+	Spectrum   []float64
+	// StatsOpen shows the stream statistics over the list. Stats is the
+	// latest poll, taken at statsAt, and Bitrate the rate derived from it
+	// in bits per second; statsPolling is set while a poll loop runs.
+	StatsOpen    bool
+	Stats        protocol.StatsResult
+	Bitrate      float64
+	statsAt      time.Time
+	statsPolling bool
+	// HistoryOpen shows the tracks the server has seen on the playing
+	// channel in a panel beside the list, newest first; historyCursor is
+	// the highlighted one.
+	HistoryOpen   bool
+	History       []protocol.TrackEntry
+	historyCursor int
+	// LovedOpen shows the loved tracks over the list, newest first;
+	// lovedCursor is the highlighted one.
+	LovedOpen   bool
+	Loved       []protocol.TrackEntry
+	lovedCursor int
+	// ArtworkOpen shows the now-playing pane over the list, with the
+	// track's cover drawn through ImageProtocol. artwork is the latest
+	// fetched cover (nil when none was found), for the track titled
+	// artworkTitle; artworkSeq caches its escape sequence for the size and
+	// cover named by artworkSeqKey.
+	ArtworkOpen    bool
+	ImageProtocol  ui.ImageProtocol
+	artwork        image.Image
+	artworkTitle   string
+	artworkLoading bool
+	artworkSeq     string
+	artworkSeqKey  string
+	// Grid shows the channels as rows of cards instead of the list, where
+	// the list area is wide enough; Delegate renders the cards like the
+	// list's rows.
+	Grid     bool
+	Delegate ui.StyledDelegate
+	// Compact collapses the UI to the highlighted channel and a one-line
+	// status for small terminals; overlays still open full size.
+	Compact bool
+	// NowPlayingOpen replaces the whole UI with the playing channel: its
+	// cover, track, elapsed time, buffer health and listener count.
+	NowPlayingOpen bool
+	// DetailsOpen shows the highlighted channel's details in a pane beside
+	// the list. channelArt holds the fetched logos by channel ID (nil for
+	// none, or while fetching); channelArtSeq caches the escape sequence of
+	// the logo of the channel named by channelArtSeqKey.
+	DetailsOpen      bool
+	channelArt       map[string]image.Image
+	channelArtSeq    string
+	channelArtSeqKey string
+	// DetailsWidth is the details pane's width in columns; 0 means
+	// DefaultDetailsWidth. [ and ] resize it.
+	DetailsWidth int
+	// RecentOpen shows the highlighted channel's recent songs in a panel
+	// below the list; recentSongs holds the fetched ones by channel ID.
+	RecentOpen  bool
+	recentSongs map[string]recentSongs
+	// GenresOpen shows the catalog grouped by genre over the list;
+	// genreCursor is the highlighted genre.
+	GenresOpen  bool
+	genreCursor int
+	// EqualizerOpen shows the equalizer presets over the list;
+	// equalizerCursor is the highlighted preset.
+	EqualizerOpen   bool
+	equalizerCursor int
+	// Dashboard shows what is on across the favorites in place of the
+	// list; dashboardCursor is its highlighted row.
+	Dashboard       bool
+	dashboardCursor int
+	// PlayedOpen shows the recently played channels in place of the list;
+	// playedCursor is the highlighted one.
+	PlayedOpen   bool
+	playedCursor int
+	// ListeningOpen shows the listening time per channel in place of the
+	// list; listeningCursor is the highlighted channel.
+	ListeningOpen   bool
+	listeningCursor int
+	// Station directory state. While Directory is set the list shows
+	// directory search results instead of the SomaFM catalog; the catalog
+	// is kept in catalog so leaving the directory can restore it.
+	Directory        bool   // Whether the list shows directory results
+	DirectoryTyping  bool   // Whether the directory query input is active
+	DirectoryQuery   string // Current directory query
+	DirectoryLoading bool   // Whether a directory search is in flight
+	catalog          []channels.Channel
 	// Search state
 	Searching     bool   // Whether search input is active
 	SearchQuery   string // Current search query
@@ -61,9 +259,18 @@ type Model struct {
 	CurrentMatch  int    // Current position in searchMatches (-1 if none)
 }
 
-// Init requests the initial catalog and playback state from the server.
+// Init requests the initial catalog and playback state from the server,
+// times the startup banner and, unless NoAltScreen is set, switches to the
+// alternate screen.
 func (m *Model) Init() tea.Cmd {
-	return tea.Batch(m.fetchChannels(), m.fetchStatus(), tea.EnterAltScreen)
+	cmds := []tea.Cmd{m.fetchChannels(), m.fetchStatus()}
+	if m.Splash {
+		cmds = append(cmds, splashTimeoutCmd())
+	}
+	if !m.NoAltScreen {
+		cmds = append(cmds, tea.EnterAltScreen)
+	}
+	return tea.Batch(cmds...)
 }
 
 // skewed reports whether the connected server runs a different version than the
@@ -77,7 +284,8 @@ func (m *Model) skewed() bool {
 func (m *Model) applySnapshot(st protocol.PlaybackState) {
 	m.Snapshot = st
 	m.RequestErr = ""
-	if st.Status == protocol.StatusPlaying {
+	m.FailedID = st.FailedChannelID
+	if st.Status == protocol.StatusPlaying || st.Status == protocol.StatusPaused {
 		m.PlayingID = st.ChannelID
 	} else {
 		m.PlayingID = ""
@@ -103,12 +311,44 @@ func (m *Model) applyChannels(payload protocol.ChannelsPayload) {
 	m.RequestErr = ""
 	m.Loading = false
 	m.Favorites = payload.Favorites
+	// The flag only ever gets set, so a stale payload must not bring a
+	// locally dismissed hint back.
+	m.FavoritesHintSeen = m.FavoritesHintSeen || payload.FavoritesHintSeen
+	// An order picked with O, in this client or another, replaces the
+	// configured default.
+	if payload.Sort != "" {
+		m.Sort = SortOrder(payload.Sort)
+	}
+	m.Sources = payload.Sources
+	m.Source = payload.Source
+	if !slices.Contains(m.Sources, m.Source) {
+		// A source dropped from the server's config lists everything.
+		m.Source = ""
+	}
+	m.catalog = payload.Channels
+	m.CatalogUpdated = payload.Updated
+	m.CatalogFetched = payload.Fetched
+	m.CatalogStale = payload.Stale
+	if m.Offline != payload.Offline {
+		m.Offline = payload.Offline
+		m.UpdateListSize() // the banner takes a row
+	}
+	m.Refreshing = payload.Refreshing
+	m.Probing = payload.Probing
+	m.DeadChannels = payload.Dead
+	m.ListenerTrends = payload.Trends
+	m.RecentChannels = payload.Recent
+	m.Listened = payload.Listened
+	if m.Directory {
+		// The catalog is restored when the directory is closed.
+		return
+	}
 
 	var selectedID string
 	if sel, ok := m.List.SelectedItem().(ui.Item); ok {
 		selectedID = sel.Channel.ID
 	}
-	m.List.SetItems(m.sortItemsWithFavorites(ChannelsToItems(payload.Channels)))
+	m.List.SetItems(m.catalogItems())
 
 	if firstLoad && selectedID == "" {
 		m.selectChannelByID(payload.LastChannelID)
@@ -117,8 +357,26 @@ func (m *Model) applyChannels(payload protocol.ChannelsPayload) {
 	}
 	// Update search matches since indices may have changed
 	if m.SearchQuery != "" {
-		m.UpdateSearchMatches()
+		m.refreshSearchMatches()
+	}
+}
+
+// catalogItems returns the SomaFM catalog as list items in Sort order,
+// favorites first, without the channels that fall short of MinQuality or
+// the active Filters.
+func (m *Model) catalogItems() []list.Item {
+	chs := m.Sort.apply(m.filterChannels(m.sourceChannels(channels.FilterByMP3Quality(m.catalog, m.MinQuality))), m.RecentChannels)
+	return m.sortItemsWithFavorites(ChannelsToItems(chs))
+}
+
+// applyPendingChannels installs a catalog deferred during search input.
+func (m *Model) applyPendingChannels() {
+	if m.pendingChannels == nil {
+		return
 	}
+	payload := *m.pendingChannels
+	m.pendingChannels = nil
+	m.applyChannels(payload)
 }
 
 // selectChannelByID moves the list cursor to the channel with the given ID,
@@ -135,5 +393,29 @@ func (m *Model) selectChannelByID(id string) {
 	}
 }
 
+// channelAfter returns the ID of the list item after the channel with the
+// given ID, wrapping around. A channel not in the list (e.g. a directory
+// station after returning to SomaFM) yields the first item.
+func (m *Model) channelAfter(id string) string {
+	items := m.List.Items()
+	if len(items) == 0 {
+		return ""
+	}
+	next := 0
+	for i, li := range items {
+		if it, ok := li.(ui.Item); ok && it.Channel.ID == id {
+			next = (i + 1) % len(items)
+			break
+		}
+	}
+	if it, ok := items[next].(ui.Item); ok {
+		return it.Channel.ID
+	}
+	return ""
+}
+
 // volumeStep is how much the +/- keys change the volume.
 const volumeStep = 0.05
+
+// timeShiftStep is how far the < and > keys move playback, in seconds.
+const timeShiftStep = 30
@@ -4,9 +4,12 @@ import (
 	"testing"
 
 	"somad/internal/channels"
+	"somad/internal/protocol"
 	"somad/internal/ui"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -68,6 +71,17 @@ func TestUpdateListSize_DoesNotPanic(t *testing.T) {
 	m.UpdateListSize()
 }
 
+func TestUpdateListSize_ClampsOnShortTerminal(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 80
+	m.Height = 3 // shorter than header, status bar, and margins combined
+
+	m.UpdateListSize()
+
+	assert.Equal(t, minListHeight, m.List.Height())
+	assert.NotPanics(t, func() { _ = m.View() })
+}
+
 func TestChannelsToItems_PreservesOrder(t *testing.T) {
 	chans := testChannels()
 	items := ChannelsToItems(chans)
@@ -90,5 +104,36 @@ func TestNewHelpKeys_ReturnsBindings(t *testing.T) {
 	assert.LessOrEqual(t, len(short), len(full))
 }
 
+func TestNewListKeyMap_LeavesAppKeysAlone(t *testing.T) {
+	km := NewListKeyMap()
+	for _, k := range []string{"g", "G", "h", "l", "d", "f"} {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		for _, b := range []key.Binding{km.PrevPage, km.NextPage, km.GoToStart, km.GoToEnd} {
+			assert.False(t, key.Matches(msg, b), "%q is an app key but pages the list (%s)", k, b.Help().Desc)
+		}
+	}
+	assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyPgDown}, km.NextPage))
+	assert.True(t, key.Matches(tea.KeyMsg{Type: tea.KeyHome}, km.GoToStart))
+}
+
 // Verify that list.Item interface is satisfied — compile-time check.
 var _ list.Item = ui.Item{}
+
+func TestApplyChannels_MinQualityHidesLowOnlyChannels(t *testing.T) {
+	m := newTestModel(t)
+	m.MinQuality = "high"
+	m.Favorites = []string{"lowonly"}
+
+	m.applyChannels(protocol.ChannelsPayload{
+		Channels: []channels.Channel{
+			{ID: "hifi", Title: "Hi-Fi", Playlists: []channels.Playlist{{URL: "http://somafm.com/hifi.pls", Format: "mp3", Quality: "highest"}}},
+			{ID: "lowonly", Title: "Low Only", Playlists: []channels.Playlist{
+				{URL: "http://somafm.com/lowonly64.pls", Format: "mp3", Quality: "low"},
+				{URL: "http://somafm.com/lowonly.aac", Format: "aac", Quality: "highest"},
+			}},
+		},
+		Favorites: []string{"lowonly"},
+	})
+
+	assert.Equal(t, []string{"hifi"}, listIDs(m), "a favorite without a high-quality mp3 stream is still hidden")
+}
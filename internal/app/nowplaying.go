@@ -0,0 +1,184 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"somad/internal/protocol"
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// maxNowPlayingRows caps the cover's height in the full-screen view; like
+// the pane's it is twice as many columns wide.
+const maxNowPlayingRows = 24
+
+// nowPlayingChromeRows is what the full-screen view spends besides the
+// cover: the channel, track, progress, buffer and footer lines and the
+// spacing between them.
+const nowPlayingChromeRows = 14
+
+// bufferBarWidth is the width of the buffer health bar in cells.
+const bufferBarWidth = 20
+
+// ToggleNowPlaying opens or closes the full-screen now-playing view. On
+// open it fetches the cover, as the pane does, and starts polling the
+// stream statistics for the buffer health unless a poll already runs.
+func (m *Model) ToggleNowPlaying() tea.Cmd {
+	m.NowPlayingOpen = !m.NowPlayingOpen
+	if !m.NowPlayingOpen {
+		return nil
+	}
+	var cmds []tea.Cmd
+	if m.ImageProtocol != ui.ImageNone && m.Snapshot.TrackTitle != "" && m.Snapshot.TrackTitle != m.artworkTitle {
+		cmds = append(cmds, m.fetchArtworkCmd())
+	}
+	if !m.statsPolling {
+		m.statsPolling = true
+		cmds = append(cmds, m.fetchStatsCmd(0))
+	}
+	return tea.Batch(cmds...)
+}
+
+// updateNowPlaying handles keys while the full-screen view is open: esc or
+// P closes it, and the playback keys keep working so the view can be left
+// up.
+func (m *Model) updateNowPlaying(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m.quitCmd()
+	case "esc", "P":
+		m.NowPlayingOpen = false
+	case "p":
+		return m.playPauseCmd()
+	case "s":
+		return m.stopPlaybackCmd()
+	case "l":
+		return m.LoveTrack()
+	case "+", "=":
+		return m.setVolumeCmd(m.Snapshot.Volume + volumeStep)
+	case "-", "_":
+		return m.setVolumeCmd(m.Snapshot.Volume - volumeStep)
+	case "m":
+		return m.toggleMuteCmd()
+	}
+	return nil
+}
+
+// nowPlayingCoverSize is the cover's size in cells for the current window,
+// or zero when the window leaves no room for it.
+func (m *Model) nowPlayingCoverSize() (cols, rows int) {
+	chrome := nowPlayingChromeRows
+	if m.Visualizer {
+		chrome += spectrumRows + 1
+	}
+	rows = min(m.Height-chrome, maxNowPlayingRows, (m.Width-4)/2)
+	if rows < 2 {
+		return 0, 0
+	}
+	return rows * 2, rows
+}
+
+// renderBufferBar draws how full the stream buffer is, e.g.
+// "██████░░░░ 60%", or a dash before the stream connects.
+func renderBufferBar(st protocol.StatsResult) string {
+	if st.ConnectedAt.IsZero() || st.BufferSize <= 0 {
+		return lipgloss.NewStyle().Foreground(ui.SubtleColor).Render("—")
+	}
+	pct := min(st.Buffered*100/st.BufferSize, 100)
+	filled := pct * bufferBarWidth / 100
+	return lipgloss.NewStyle().Foreground(ui.PlayingColor).Render(strings.Repeat("█", filled)) +
+		lipgloss.NewStyle().Foreground(ui.SubtleColor).Render(strings.Repeat("░", bufferBarWidth-filled)) +
+		lipgloss.NewStyle().Foreground(ui.TextColor).Render(fmt.Sprintf(" %d%%", pct))
+}
+
+// nowPlayingProgress renders the elapsed time of the track together with
+// what playback is doing: paused, behind live, or reconnecting.
+func (m *Model) nowPlayingProgress() string {
+	var parts []string
+	if !m.Snapshot.TrackStarted.IsZero() {
+		parts = append(parts, formatElapsed(time.Since(m.Snapshot.TrackStarted)))
+	}
+	switch m.Snapshot.Status {
+	case protocol.StatusPaused:
+		parts = append(parts, "paused")
+	case protocol.StatusConnecting:
+		parts = append(parts, "connecting")
+	case protocol.StatusReconnecting:
+		parts = append(parts, "reconnecting")
+	}
+	if behind := time.Duration(m.Snapshot.Behind * float64(time.Second)); behind >= time.Second {
+		parts = append(parts, formatBehind(behind))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// renderNowPlaying renders the full-screen now-playing view: the channel
+// above its cover, where the terminal can show one, the song, artist and
+// album, the elapsed time, the spectrum when the visualizer is on, the
+// buffer health and the listener count, centered in the window. The level
+// poll redraws it while playing, which keeps the elapsed time ticking.
+func (m *Model) renderNowPlaying() string {
+	width := max(m.Width-4, 20)
+	subtle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+	center := func(s string) string {
+		return lipgloss.PlaceHorizontal(width, lipgloss.Center, ansi.Truncate(s, width, "…"))
+	}
+
+	if m.Snapshot.Status == protocol.StatusStopped || m.Snapshot.ChannelTitle == "" {
+		msg := lipgloss.JoinVertical(lipgloss.Center, subtle.Render("Nothing is playing."), "", subtle.Render("esc closes"))
+		return ui.ClearImages(m.ImageProtocol) + lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, msg)
+	}
+
+	lines := []string{center(ui.TitleStyle.UnsetMarginLeft().Render(m.Snapshot.ChannelTitle)), ""}
+	clearImages := ui.ClearImages(m.ImageProtocol)
+	cols, rows := m.nowPlayingCoverSize()
+	if cols > 0 && m.ImageProtocol != ui.ImageNone && m.artwork != nil &&
+		!m.artworkLoading && m.artworkTitle == m.Snapshot.TrackTitle {
+		pad := strings.Repeat(" ", (width-cols)/2)
+		for _, line := range m.renderArtworkImage(cols, rows) {
+			lines = append(lines, pad+line)
+		}
+		lines = append(lines, "")
+		clearImages = ""
+	}
+
+	song := m.Snapshot.TrackSong
+	if song == "" {
+		song = m.Snapshot.TrackTitle
+	}
+	if song == "" {
+		song = m.placeholderTrack()
+	}
+	lines = append(lines, center(lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true).Render(song)))
+	if m.Snapshot.TrackArtist != "" {
+		lines = append(lines, center(lipgloss.NewStyle().Foreground(ui.TextColor).Render(m.Snapshot.TrackArtist)))
+	}
+	if m.Snapshot.TrackAlbum != "" {
+		lines = append(lines, center(subtle.Render(m.Snapshot.TrackAlbum)))
+	}
+	if progress := m.nowPlayingProgress(); progress != "" {
+		lines = append(lines, "", center(subtle.Render(progress)))
+	}
+	if m.Visualizer && m.Spectrum != nil {
+		lines = append(lines, "")
+		for _, line := range strings.Split(renderSpectrum(m.Spectrum), "\n") {
+			lines = append(lines, center(line))
+		}
+	}
+
+	lines = append(lines, "", center(subtle.Render("Buffer ")+renderBufferBar(m.Stats)))
+	if m.Snapshot.Listeners > 0 {
+		lines = append(lines, center(subtle.Render(ui.FormatCount(m.Snapshot.Listeners)+" listening")))
+	}
+	if m.Snapshot.StreamError != "" {
+		lines = append(lines, center(lipgloss.NewStyle().Foreground(ui.ErrorColor).Render(m.Snapshot.StreamError)))
+	}
+	lines = append(lines, "", center(subtle.Render("esc closes · p pause · +/- volume")))
+
+	return clearImages + lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"somad/internal/protocol"
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNowPlaying_ShowsPlayingChannelFullScreen(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 100, 50
+	m.ImageProtocol = ui.ImageKitty
+	m.Update(ServerStateMsg{State: protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "groovesalad", ChannelTitle: "Groove Salad",
+		TrackTitle: "Bonobo - Kerala", TrackArtist: "Bonobo", TrackSong: "Kerala", TrackAlbum: "Migration",
+		TrackStarted: time.Now().Add(-221 * time.Second), Listeners: 1042,
+	}})
+	backend(m).artwork = protocol.ArtworkResult{Title: "Bonobo - Kerala", Image: testCoverPNG(t)}
+	backend(m).stats = protocol.StatsResult{ConnectedAt: time.Now(), Buffered: 3 << 10, BufferSize: 4 << 10}
+
+	_, cmd := sendKey(m, 'P')
+	require.True(t, m.NowPlayingOpen)
+	updateWith(m, cmd)
+
+	view := m.View()
+	assert.Contains(t, view, "Groove Salad")
+	assert.Contains(t, view, "Kerala")
+	assert.Contains(t, view, "Bonobo")
+	assert.Contains(t, view, "Migration")
+	assert.Contains(t, view, "3:41")
+	assert.Contains(t, view, "75%", "buffer health")
+	assert.Contains(t, view, "1,042 listening")
+	assert.Contains(t, view, "\x1b_Ga=T", "the cover is drawn")
+	assert.NotContains(t, view, "Channel", "the list and its header are hidden")
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, cmd)
+	assert.False(t, m.NowPlayingOpen)
+	_, next := m.Update(statsMsg{Stats: backend(m).stats, At: time.Now()})
+	assert.Nil(t, next, "the statistics poll ends once closed")
+}
+
+func TestNowPlaying_PlaybackKeysStayLive(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 100, 40
+	m.ImageProtocol = ui.ImageNone
+	playingTrack(m, "Bonobo - Kerala", "Bonobo", "Kerala")
+	sendKey(m, 'P')
+
+	_, cmd := sendKey(m, 'p')
+	runCmd(cmd)
+	assert.Equal(t, protocol.StatusPaused, backend(m).status.Status, "p still pauses")
+
+	_, cmd = sendKey(m, 'd')
+	assert.Nil(t, cmd)
+	assert.False(t, m.Directory, "list keys do nothing while the view is up")
+	assert.True(t, m.NowPlayingOpen)
+
+	sendKey(m, 'P')
+	assert.False(t, m.NowPlayingOpen)
+}
+
+func TestNowPlaying_NothingPlaying(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 100, 40
+	sendKey(m, 'P')
+
+	assert.Contains(t, m.View(), "Nothing is playing.")
+}
+
+func TestRenderBufferBar(t *testing.T) {
+	assert.Contains(t, renderBufferBar(protocol.StatsResult{}), "—", "not connected")
+	bar := renderBufferBar(protocol.StatsResult{ConnectedAt: time.Now(), Buffered: 1, BufferSize: 2})
+	assert.Contains(t, bar, "██████████░░░░░░░░░░")
+	assert.Contains(t, bar, "50%")
+}
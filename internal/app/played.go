@@ -0,0 +1,111 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// playedRow is one recently played channel.
+type playedRow struct {
+	ID     string
+	Title  string
+	Played time.Time
+}
+
+// playedRows returns the recently played channels, newest first, with
+// their titles from the catalog. Channels no longer in the catalog are
+// skipped.
+func (m *Model) playedRows() []playedRow {
+	rows := make([]playedRow, 0, len(m.RecentChannels))
+	for _, r := range m.RecentChannels {
+		for _, ch := range m.catalog {
+			if ch.ID == r.ChannelID {
+				rows = append(rows, playedRow{ID: ch.ID, Title: ch.Title, Played: r.Played})
+				break
+			}
+		}
+	}
+	return rows
+}
+
+// OpenPlayed shows the recently played channels.
+func (m *Model) OpenPlayed() {
+	m.PlayedOpen = true
+	m.playedCursor = 0
+}
+
+// updatePlayed handles keys while the recently played channels are shown:
+// j/k move, enter plays the highlighted channel, esc or H closes.
+func (m *Model) updatePlayed(msg tea.KeyMsg) tea.Cmd {
+	rows := m.playedRows()
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m.quitCmd()
+	case "esc", "H":
+		m.PlayedOpen = false
+	case "up", "k":
+		if m.playedCursor > 0 {
+			m.playedCursor--
+		}
+	case "down", "j":
+		if m.playedCursor < len(rows)-1 {
+			m.playedCursor++
+		}
+	case "enter", " ":
+		if m.playedCursor < len(rows) {
+			id := rows[m.playedCursor].ID
+			m.PlayedOpen = false
+			m.selectChannelByID(id)
+			return m.switchChannelCmd(id)
+		}
+	}
+	return nil
+}
+
+// renderPlayed renders the recently played channels as a bordered table
+// centered over the list area, each with how long ago it was picked.
+func (m *Model) renderPlayed() string {
+	rows := m.playedRows()
+	width := max(m.Width-8, 20)
+
+	var lines []string
+	if len(rows) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ui.SubtleColor).
+			Render("Nothing played yet — the channels you play show up here."))
+	}
+	titleWidth := 0
+	for _, r := range rows {
+		titleWidth = max(titleWidth, lipgloss.Width(r.Title))
+	}
+	for i, r := range rows {
+		marker := "  "
+		if r.ID == m.PlayingID {
+			marker = "▶ "
+		}
+		line := marker + r.Title + strings.Repeat(" ", titleWidth-lipgloss.Width(r.Title)) +
+			"  " + formatAge(time.Since(r.Played)) + " ago"
+		line = ansi.Truncate(line, width, "…")
+
+		style := lipgloss.NewStyle().Foreground(ui.TextColor)
+		if i == m.playedCursor {
+			style = lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true)
+		}
+		lines = append(lines, style.Render(line))
+	}
+
+	header := ui.TitleStyle.UnsetMarginLeft().Render("Recently played")
+	footer := lipgloss.NewStyle().Foreground(ui.SubtleColor).Render("enter plays · esc closes")
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.SubtleColor).
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, "", strings.Join(lines, "\n"), "", footer))
+
+	return lipgloss.Place(m.Width, m.List.Height(), lipgloss.Center, lipgloss.Center, box)
+}
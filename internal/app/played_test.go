@@ -0,0 +1,74 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"somad/internal/protocol"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+// playedModel returns a test model that played two channels recently and
+// one that has since left the catalog.
+func playedModel(t *testing.T) *Model {
+	t.Helper()
+	m := newTestModel(t)
+	m.Width = 120
+	now := time.Now()
+	m.RecentChannels = []protocol.RecentChannel{
+		{ChannelID: "dronezone", Played: now.Add(-5 * time.Minute)},
+		{ChannelID: "gone", Played: now.Add(-time.Hour)},
+		{ChannelID: "secretagent", Played: now.Add(-26 * time.Hour)},
+	}
+	return m
+}
+
+func TestPlayedRows_NewestFirstWithTitles(t *testing.T) {
+	m := playedModel(t)
+
+	rows := m.playedRows()
+
+	assert.Len(t, rows, 2, "a channel missing from the catalog is skipped")
+	assert.Equal(t, "Drone Zone", rows[0].Title)
+	assert.Equal(t, "Secret Agent", rows[1].Title)
+}
+
+func TestPlayed_RendersTable(t *testing.T) {
+	m := playedModel(t)
+	sendKey(m, 'H')
+
+	view := m.View()
+
+	assert.True(t, m.PlayedOpen)
+	assert.Contains(t, view, "Recently played")
+	assert.Contains(t, view, "5m ago")
+	assert.Contains(t, view, "1d ago")
+}
+
+func TestPlayed_EnterPlaysHighlightedChannel(t *testing.T) {
+	m := playedModel(t)
+	sendKey(m, 'H')
+	sendKey(m, 'j')
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	runCmd(cmd)
+
+	assert.False(t, m.PlayedOpen)
+	assert.Equal(t, []string{"secretagent"}, backend(m).playIDs)
+}
+
+func TestPlayed_FromChannelsPayload(t *testing.T) {
+	m := newTestModel(t)
+	sendKey(m, 'H')
+	assert.Contains(t, m.View(), "Nothing played yet")
+	sendKey(m, 'H')
+	assert.False(t, m.PlayedOpen)
+
+	m.applyChannels(protocol.ChannelsPayload{
+		Channels: testChannels(),
+		Recent:   []protocol.RecentChannel{{ChannelID: "groovesalad", Played: time.Now()}},
+	})
+	assert.Equal(t, "Groove Salad", m.playedRows()[0].Title)
+}
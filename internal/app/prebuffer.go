@@ -0,0 +1,45 @@
+package app
+
+import (
+	"time"
+
+	"somad/internal/protocol"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prebufferDelay is how long the cursor rests on a channel before its
+// stream is prebuffered, so scrolling through the list does not open a
+// connection per row.
+const prebufferDelay = 300 * time.Millisecond
+
+// prebufferTickMsg fires once the cursor has rested on a channel for
+// prebufferDelay. Seq identifies the cursor position it was timed for.
+type prebufferTickMsg struct {
+	Seq int
+	ID  string
+}
+
+// prebufferCmd asks the server to fetch a channel's stream ahead of a
+// likely play, or to drop the prebuffer when id is empty. A failure only
+// costs the head start, so it is not shown.
+func (m *Model) prebufferCmd(id string) tea.Cmd {
+	b := m.Backend
+	return func() tea.Msg {
+		_ = b.Prebuffer(id)
+		return nil
+	}
+}
+
+// startPrebuffer prebuffers the channel the cursor rested on, unless the
+// cursor has moved on since or that channel is already playing.
+func (m *Model) startPrebuffer(msg prebufferTickMsg) tea.Cmd {
+	if msg.Seq != m.hoverSeq {
+		return nil
+	}
+	if m.Snapshot.Status != protocol.StatusStopped && m.Snapshot.ChannelID == msg.ID {
+		return nil
+	}
+	m.prebuffered = msg.ID
+	return m.prebufferCmd(msg.ID)
+}
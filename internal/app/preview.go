@@ -0,0 +1,84 @@
+package app
+
+import (
+	"time"
+
+	"somad/internal/protocol"
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// previewTickMsg fires once the cursor has rested on a channel for the
+// preview delay. Seq identifies the cursor position it was timed for.
+type previewTickMsg struct {
+	Seq int
+	ID  string
+}
+
+// previewCmd asks the server to preview a channel, or to end the preview
+// when id is empty.
+func (m *Model) previewCmd(id string) tea.Cmd {
+	b := m.Backend
+	return func() tea.Msg {
+		st, err := b.Preview(id)
+		if err != nil {
+			return requestErr("preview", err)
+		}
+		return ServerStateMsg{State: st}
+	}
+}
+
+// trackHover follows the cursor after the list handled a key: moving to
+// another channel ends a running preview and drops a prebuffer and, with
+// those enabled, times new ones for the channel now under the cursor.
+func (m *Model) trackHover() tea.Cmd {
+	id := ""
+	if i, ok := m.List.SelectedItem().(ui.Item); ok {
+		id = i.Channel.ID
+	}
+	if id == m.hoverID {
+		return nil
+	}
+	m.hoverID = id
+	m.hoverSeq++
+	var cmds []tea.Cmd
+	if m.Snapshot.Preview {
+		cmds = append(cmds, m.previewCmd(""))
+	}
+	if m.prebuffered != "" {
+		m.prebuffered = ""
+		cmds = append(cmds, m.prebufferCmd(""))
+	}
+	if m.PreviewDelay > 0 && id != "" {
+		seq := m.hoverSeq
+		cmds = append(cmds, tea.Tick(m.PreviewDelay, func(time.Time) tea.Msg {
+			return previewTickMsg{Seq: seq, ID: id}
+		}))
+	}
+	if cmd := m.scheduleRecentSongs(id); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.fetchChannelArt(id); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if m.Prebuffer && id != "" {
+		seq := m.hoverSeq
+		cmds = append(cmds, tea.Tick(prebufferDelay, func(time.Time) tea.Msg {
+			return prebufferTickMsg{Seq: seq, ID: id}
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// startPreview previews the channel the cursor rested on, unless the cursor
+// has moved on since or something other than a preview is playing.
+func (m *Model) startPreview(msg previewTickMsg) tea.Cmd {
+	if msg.Seq != m.hoverSeq {
+		return nil
+	}
+	if m.Snapshot.Status != protocol.StatusStopped && !m.Snapshot.Preview {
+		return nil
+	}
+	return m.previewCmd(msg.ID)
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"somad/internal/protocol"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreview_StartsAfterCursorRests(t *testing.T) {
+	m := newTestModel(t)
+	m.PreviewDelay = time.Millisecond
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	tick, ok := runCmd(cmd).(previewTickMsg)
+	require.True(t, ok, "moving the cursor times a preview")
+	assert.Equal(t, "dronezone", tick.ID)
+
+	_, cmd = m.Update(tick)
+	m.Update(runCmd(cmd))
+	assert.Equal(t, []string{"dronezone"}, backend(m).previews)
+	assert.True(t, m.Snapshot.Preview)
+	assert.Contains(t, m.RenderStatusBar(), "Preview")
+
+	// Moving on ends the preview and times the next one.
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	batch, ok := runCmd(cmd).(tea.BatchMsg)
+	require.True(t, ok)
+	for _, c := range batch {
+		if msg, ok := runCmd(c).(ServerStateMsg); ok {
+			m.Update(msg)
+		}
+	}
+	assert.Equal(t, []string{"dronezone", ""}, backend(m).previews)
+	assert.False(t, m.Snapshot.Preview)
+}
+
+func TestPreview_StaleOrBusyTicksDoNothing(t *testing.T) {
+	m := newTestModel(t)
+	m.PreviewDelay = time.Millisecond
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	stale := runCmd(cmd).(previewTickMsg)
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd = m.Update(stale)
+	assert.Nil(t, cmd, "the cursor moved on")
+
+	m.Snapshot = protocol.PlaybackState{Status: protocol.StatusPlaying, ChannelID: "groovesalad"}
+	_, cmd = m.Update(previewTickMsg{Seq: m.hoverSeq, ID: "secretagent"})
+	assert.Nil(t, cmd, "a preview never interrupts playback")
+}
+
+func TestPreview_DisabledByDefault(t *testing.T) {
+	m := newTestModel(t)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Nil(t, runCmd(cmd))
+}
+
+func TestPreview_QuitEndsIt(t *testing.T) {
+	m := newTestModel(t)
+	m.Snapshot = protocol.PlaybackState{Status: protocol.StatusPlaying, ChannelID: "dronezone", Preview: true}
+
+	_, cmd := sendKey(m, 'q')
+	runCmd(cmd)
+	assert.Equal(t, []string{""}, backend(m).previews)
+}
+
+func TestPrebuffer_FollowsTheCursor(t *testing.T) {
+	m := newTestModel(t)
+	m.Prebuffer = true
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	tick, ok := runCmd(cmd).(prebufferTickMsg)
+	require.True(t, ok, "moving the cursor times a prebuffer")
+	_, cmd = m.Update(tick)
+	runCmd(cmd)
+	assert.Equal(t, []string{"dronezone"}, backend(m).prebufs)
+
+	// Moving on drops it before timing the next one.
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	batch, ok := runCmd(cmd).(tea.BatchMsg)
+	require.True(t, ok)
+	for _, c := range batch {
+		if tick, ok := runCmd(c).(prebufferTickMsg); ok {
+			m.Update(tick)
+		}
+	}
+	assert.Equal(t, []string{"dronezone", ""}, backend(m).prebufs)
+
+	m.Snapshot = protocol.PlaybackState{Status: protocol.StatusPlaying, ChannelID: "secretagent"}
+	_, cmd = m.Update(prebufferTickMsg{Seq: m.hoverSeq, ID: "secretagent"})
+	assert.Nil(t, cmd, "the playing channel needs no prebuffer")
+}
@@ -0,0 +1,192 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	"somad/internal/protocol"
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// recentSongsRows is how many songs the recent songs panel shows, as many
+// as the server lists. The panel keeps this height whatever it holds, so
+// the list does not jump as the cursor moves.
+const recentSongsRows = 10
+
+// recentSongsDelay is how long the cursor rests on a channel before its
+// recent songs are fetched, so scrolling does not ask for every row.
+const recentSongsDelay = 300 * time.Millisecond
+
+// recentSongsMaxAge is how long fetched songs are shown without asking
+// again; SomaFM songs run minutes long.
+const recentSongsMaxAge = time.Minute
+
+// recentSongs is a channel's fetched song list, or why fetching it failed.
+type recentSongs struct {
+	Songs []protocol.SongEntry
+	Err   error
+	At    time.Time
+}
+
+// recentSongsMsg carries the songs a channel played last.
+type recentSongsMsg struct {
+	ChannelID string
+	Songs     []protocol.SongEntry
+	Err       error
+}
+
+// recentTickMsg fires once the cursor has rested on a channel for
+// recentSongsDelay. Seq identifies the cursor position it was timed for.
+type recentTickMsg struct {
+	Seq int
+	ID  string
+}
+
+// fetchRecentSongsCmd asks the server for the songs channelID played last.
+func (m *Model) fetchRecentSongsCmd(channelID string) tea.Cmd {
+	b := m.Backend
+	return func() tea.Msg {
+		result, err := b.RecentSongs(channelID)
+		return recentSongsMsg{ChannelID: channelID, Songs: result.Songs, Err: err}
+	}
+}
+
+// hasSongList reports whether the highlighted channel is a SomaFM channel,
+// the only kind the songs API lists, and returns its ID.
+func (m *Model) hasSongList() (string, bool) {
+	i, ok := m.List.SelectedItem().(ui.Item)
+	if !ok || i.Channel.StreamURL != "" {
+		return "", false
+	}
+	return i.Channel.ID, true
+}
+
+// refreshRecentSongs fetches the highlighted channel's songs unless they
+// were fetched lately.
+func (m *Model) refreshRecentSongs() tea.Cmd {
+	id, ok := m.hasSongList()
+	if !ok {
+		return nil
+	}
+	if r, ok := m.recentSongs[id]; ok && r.Err == nil && time.Since(r.At) < recentSongsMaxAge {
+		return nil
+	}
+	return m.fetchRecentSongsCmd(id)
+}
+
+// ToggleRecentSongs opens or closes the recent songs panel below the list,
+// fetching the highlighted channel's songs on open.
+func (m *Model) ToggleRecentSongs() tea.Cmd {
+	m.RecentOpen = !m.RecentOpen
+	m.UpdateListSize()
+	if !m.RecentOpen {
+		return nil
+	}
+	return m.refreshRecentSongs()
+}
+
+// scheduleRecentSongs times a fetch for the channel the cursor moved to,
+// while the panel is open.
+func (m *Model) scheduleRecentSongs(id string) tea.Cmd {
+	if !m.RecentOpen || id == "" {
+		return nil
+	}
+	seq := m.hoverSeq
+	return tea.Tick(recentSongsDelay, func(time.Time) tea.Msg {
+		return recentTickMsg{Seq: seq, ID: id}
+	})
+}
+
+// startRecentSongs fetches the songs of the channel the cursor rested on,
+// unless the cursor has moved on since or the panel was closed.
+func (m *Model) startRecentSongs(msg recentTickMsg) tea.Cmd {
+	if msg.Seq != m.hoverSeq || !m.RecentOpen {
+		return nil
+	}
+	return m.refreshRecentSongs()
+}
+
+// applyRecentSongs records a channel's fetched songs. A failure is shown in
+// the panel rather than the status bar, since the fetch was not asked for
+// by a key.
+func (m *Model) applyRecentSongs(msg recentSongsMsg) {
+	if m.recentSongs == nil {
+		m.recentSongs = make(map[string]recentSongs)
+	}
+	if msg.Err != nil {
+		// Keep songs fetched earlier; they are still a fair hint.
+		if r, ok := m.recentSongs[msg.ChannelID]; ok && r.Err == nil {
+			return
+		}
+	}
+	m.recentSongs[msg.ChannelID] = recentSongs{Songs: msg.Songs, Err: msg.Err, At: time.Now()}
+}
+
+// RenderRecentSongs renders the highlighted channel's recent songs as a
+// panel below the list, newest first. It returns an empty string unless
+// the panel is open.
+func (m *Model) RenderRecentSongs() string {
+	if !m.RecentOpen {
+		return ""
+	}
+
+	width := m.List.Width()
+	if width < 1 {
+		width = m.Width
+	}
+	if width < 1 {
+		width = 1
+	}
+
+	separator := lipgloss.NewStyle().
+		Foreground(ui.SubtleColor).
+		Render(strings.Repeat("─", width))
+
+	subtle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+	header := "Recent songs"
+	var lines []string
+	if i, ok := m.List.SelectedItem().(ui.Item); ok {
+		header = "Recent songs on " + i.Channel.Title
+	}
+	id, ok := m.hasSongList()
+	r, fetched := m.recentSongs[id]
+	switch {
+	case !ok:
+		lines = append(lines, subtle.Render("Only SomaFM channels list their songs."))
+	case !fetched:
+		lines = append(lines, subtle.Render("Looking up the recent songs…"))
+	case r.Err != nil:
+		lines = append(lines, subtle.Render("Recent songs unavailable: "+r.Err.Error()))
+	case len(r.Songs) == 0:
+		lines = append(lines, subtle.Render("No songs listed."))
+	}
+	if ok && fetched {
+		for _, song := range r.Songs[:min(len(r.Songs), recentSongsRows)] {
+			title := song.Title
+			if song.Artist != "" {
+				title = song.Artist + " - " + song.Title
+			}
+			line := m.formatClock(song.Time) + "  " + title
+			if song.Album != "" {
+				line += "  · " + song.Album
+			}
+			lines = append(lines, lipgloss.NewStyle().Foreground(ui.TextColor).Render(line))
+		}
+	}
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, max(width-2, 1), "…")
+	}
+
+	body := lipgloss.NewStyle().
+		Padding(0, 0, 0, 2).
+		Height(recentSongsRows + 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left,
+			lipgloss.NewStyle().Foreground(ui.PrimaryColor).Bold(true).Render(ansi.Truncate(header, max(width-2, 1), "…")),
+			strings.Join(lines, "\n")))
+
+	return lipgloss.JoinVertical(lipgloss.Left, separator, body)
+}
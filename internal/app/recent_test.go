@@ -0,0 +1,85 @@
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"somad/internal/channels"
+	"somad/internal/protocol"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentSongs_FollowTheCursor(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 120, 40
+	m.UTC = true
+	at := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+	backend(m).songs = map[string][]protocol.SongEntry{
+		"groovesalad": {
+			{Time: at, Title: "Awake", Artist: "Tycho", Album: "Awake"},
+			{Time: at.Add(-5 * time.Minute), Title: "Kerala", Artist: "Bonobo", Album: "Migration"},
+		},
+		"dronezone": {{Time: at, Title: "Requiem", Artist: "Stars of the Lid"}},
+	}
+	m.UpdateListSize()
+	listHeight := m.List.Height()
+
+	_, cmd := sendKey(m, 'R')
+	require.True(t, m.RecentOpen)
+	assert.Less(t, m.List.Height(), listHeight, "the panel takes rows from the list")
+	assert.Contains(t, m.View(), "Looking up the recent songs")
+	m.Update(runCmd(cmd))
+
+	view := m.View()
+	assert.Contains(t, view, "Recent songs on Groove Salad")
+	assert.Contains(t, view, "14:05  Tycho - Awake  · Awake")
+	assert.Contains(t, view, "14:00  Bonobo - Kerala  · Migration")
+	assert.Empty(t, backend(m).playIDs, "listing plays nothing")
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	tick, ok := runCmd(cmd).(recentTickMsg)
+	require.True(t, ok, "moving the cursor times a fetch")
+	assert.Equal(t, "dronezone", tick.ID)
+	_, cmd = m.Update(tick)
+	m.Update(runCmd(cmd))
+	assert.Contains(t, m.View(), "14:05  Stars of the Lid - Requiem")
+
+	m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	_, cmd = m.Update(recentTickMsg{Seq: m.hoverSeq, ID: "groovesalad"})
+	assert.Nil(t, cmd, "songs fetched lately are not asked for again")
+
+	sendKey(m, 'R')
+	assert.False(t, m.RecentOpen)
+	assert.Equal(t, listHeight, m.List.Height())
+	assert.NotContains(t, m.View(), "Recent songs")
+}
+
+func TestRecentSongs_StaleTicksAndFailures(t *testing.T) {
+	m := newTestModel(t)
+	m.RecentOpen = true
+
+	_, cmd := m.Update(recentTickMsg{Seq: m.hoverSeq + 1, ID: "groovesalad"})
+	assert.Nil(t, cmd, "the cursor moved on")
+
+	backend(m).callErr = errors.New("songs API down")
+	_, cmd = m.Update(recentTickMsg{Seq: m.hoverSeq, ID: "groovesalad"})
+	m.Update(runCmd(cmd))
+	assert.Contains(t, m.View(), "Recent songs unavailable: songs API down")
+	assert.Empty(t, m.RequestErr, "a fetch no key asked for stays out of the status bar")
+}
+
+func TestRecentSongs_NoneForDirectoryStations(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 120
+	m.List.SetItems(ChannelsToItems([]channels.Channel{
+		{ID: "rb:abc", Title: "Jazz One", StreamURL: "http://jazz.example.org/live"},
+	}))
+
+	_, cmd := sendKey(m, 'R')
+	assert.Nil(t, cmd)
+	assert.Contains(t, m.View(), "Only SomaFM channels list their songs.")
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"slices"
 	"strings"
 	"unicode"
 
@@ -43,6 +44,18 @@ func (m *Model) UpdateSearchMatches() {
 	}
 }
 
+// refreshSearchMatches recomputes the matches after the items were rebuilt
+// or re-sorted, leaving the cursor where the caller put it: a refresh must
+// not yank the selection back to the first match. When the selected item is
+// itself a match it stays the current one; otherwise n/N continue from the
+// ends of the match list.
+func (m *Model) refreshSearchMatches() {
+	selected := m.List.Index()
+	m.UpdateSearchMatches()
+	m.List.Select(selected)
+	m.CurrentMatch = slices.Index(m.SearchMatches, selected)
+}
+
 // NextMatch jumps to the next search match.
 func (m *Model) NextMatch() {
 	if len(m.SearchMatches) == 0 {
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"cmp"
+	"slices"
+
+	"somad/internal/channels"
+	"somad/internal/protocol"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SortOrder selects how the SomaFM catalog is ordered; favorites stay on
+// top either way.
+type SortOrder string
+
+// Catalog sort orders. The zero value is SortAPI.
+const (
+	SortAPI          SortOrder = "api"          // the order of SomaFM's channel list
+	SortAlphabetical SortOrder = "alphabetical" // by title, ignoring case
+	SortListeners    SortOrder = "listeners"    // most listeners first
+	SortGenre        SortOrder = "genre"        // by first genre, then title
+	SortRecent       SortOrder = "recent"       // last played first, then the rest in SomaFM's order
+)
+
+// nextSort is the order the O key switches to after the current one.
+var nextSort = map[SortOrder]SortOrder{
+	"":               SortAlphabetical,
+	SortAPI:          SortAlphabetical,
+	SortAlphabetical: SortListeners,
+	SortListeners:    SortGenre,
+	SortGenre:        SortRecent,
+	SortRecent:       SortAPI,
+}
+
+// apply returns chs in this order, SortRecent going by recent (newest
+// first). It never reorders chs in place: the catalog is shared with the
+// list items.
+func (o SortOrder) apply(chs []channels.Channel, recent []protocol.RecentChannel) []channels.Channel {
+	var compare func(a, b channels.Channel) int
+	switch o {
+	case SortAlphabetical:
+		compare = channels.CompareByTitle
+	case SortListeners:
+		compare = channels.CompareByListeners
+	case SortGenre:
+		compare = channels.CompareByGenre
+	case SortRecent:
+		compare = compareByRecent(recent)
+	default:
+		return chs
+	}
+	sorted := slices.Clone(chs)
+	slices.SortStableFunc(sorted, compare)
+	return sorted
+}
+
+// compareByRecent orders the channels in recent by when they were last
+// played, newest first, ahead of the channels never played, which keep
+// their order.
+func compareByRecent(recent []protocol.RecentChannel) func(a, b channels.Channel) int {
+	rank := make(map[string]int, len(recent))
+	for i, r := range recent {
+		rank[r.ChannelID] = i
+	}
+	return func(a, b channels.Channel) int {
+		ra, aPlayed := rank[a.ID]
+		rb, bPlayed := rank[b.ID]
+		switch {
+		case aPlayed && bPlayed:
+			return cmp.Compare(ra, rb)
+		case aPlayed:
+			return -1
+		case bPlayed:
+			return 1
+		}
+		return 0
+	}
+}
+
+// CycleSort switches the catalog to the next sort order, keeping the cursor
+// on the selected channel, and returns a command that persists the order on
+// the server for the next session. The station directory keeps its own
+// order.
+func (m *Model) CycleSort() tea.Cmd {
+	if m.Directory {
+		return nil
+	}
+	m.Sort = nextSort[m.Sort]
+	m.Notice = "Sort: " + string(m.Sort)
+	m.applyFilters()
+
+	b, sort := m.Backend, string(m.Sort)
+	return func() tea.Msg {
+		if err := b.SetChannelSort(sort); err != nil {
+			return requestErr("channel sort", err)
+		}
+		return nil
+	}
+}
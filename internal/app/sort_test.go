@@ -0,0 +1,101 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"somad/internal/protocol"
+	"somad/internal/ui"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyChannels_DefaultSortAlphabetical(t *testing.T) {
+	m := newTestModel(t)
+	m.Loading = true
+	m.Sort = SortAlphabetical
+
+	// The server delivers its own (API) order.
+	m.applyChannels(protocol.ChannelsPayload{Channels: testChannels()})
+
+	assert.Equal(t, []string{"dronezone", "groovesalad", "secretagent"}, listIDs(m))
+}
+
+func TestApplyChannels_SortKeepsFavoritesOnTop(t *testing.T) {
+	m := newTestModel(t)
+	m.Sort = SortAlphabetical
+
+	m.applyChannels(protocol.ChannelsPayload{Channels: testChannels(), Favorites: []string{"secretagent"}})
+
+	assert.Equal(t, []string{"secretagent", "dronezone", "groovesalad"}, listIDs(m))
+}
+
+func TestApplyChannels_SortListeners(t *testing.T) {
+	m := newTestModel(t)
+	m.Sort = SortListeners
+
+	m.applyChannels(protocol.ChannelsPayload{Channels: testChannels()})
+
+	assert.Equal(t, []string{"groovesalad", "secretagent", "dronezone"}, listIDs(m))
+}
+
+func TestApplyChannels_SortAPIKeepsServerOrder(t *testing.T) {
+	m := newTestModel(t)
+
+	m.applyChannels(protocol.ChannelsPayload{Channels: testChannels()})
+
+	assert.Equal(t, []string{"groovesalad", "dronezone", "secretagent"}, listIDs(m))
+}
+
+func TestCycleSort_PersistsEachOrder(t *testing.T) {
+	m := newTestModel(t)
+	m.applyChannels(protocol.ChannelsPayload{Channels: testChannels()})
+	m.List.Select(2) // secretagent
+
+	var orders [][]string
+	for range 5 {
+		_, cmd := sendKey(m, 'O')
+		runCmd(cmd)
+		orders = append(orders, listIDs(m))
+	}
+
+	assert.Equal(t, [][]string{
+		{"dronezone", "groovesalad", "secretagent"},
+		{"groovesalad", "secretagent", "dronezone"},
+		{"dronezone", "groovesalad", "secretagent"},
+		{"groovesalad", "dronezone", "secretagent"},
+		{"groovesalad", "dronezone", "secretagent"},
+	}, orders)
+	assert.Equal(t, []string{"alphabetical", "listeners", "genre", "recent", "api"}, backend(m).sorts)
+	assert.Equal(t, "secretagent", m.List.SelectedItem().(ui.Item).Channel.ID, "the cursor follows its channel")
+}
+
+func TestApplyChannels_PersistedSortReplacesDefault(t *testing.T) {
+	m := newTestModel(t)
+	m.Sort = SortAlphabetical
+
+	m.applyChannels(protocol.ChannelsPayload{Channels: testChannels(), Sort: "listeners"})
+
+	assert.Equal(t, SortListeners, m.Sort)
+	assert.Equal(t, []string{"groovesalad", "secretagent", "dronezone"}, listIDs(m))
+}
+
+func TestApplyChannels_SortRecent(t *testing.T) {
+	m := newTestModel(t)
+	m.Sort = SortRecent
+
+	m.applyChannels(protocol.ChannelsPayload{Channels: testChannels(), Recent: []protocol.RecentChannel{
+		{ChannelID: "secretagent", Played: time.Now()},
+		{ChannelID: "dronezone", Played: time.Now().Add(-time.Hour)},
+	}})
+
+	// Last played first; never played channels follow in SomaFM's order.
+	assert.Equal(t, []string{"secretagent", "dronezone", "groovesalad"}, listIDs(m))
+
+	// Playing a channel moves it up.
+	m.applyChannels(protocol.ChannelsPayload{Channels: testChannels(), Recent: []protocol.RecentChannel{
+		{ChannelID: "groovesalad", Played: time.Now()},
+		{ChannelID: "secretagent", Played: time.Now().Add(-time.Minute)},
+	}})
+	assert.Equal(t, []string{"groovesalad", "secretagent", "dronezone"}, listIDs(m))
+}
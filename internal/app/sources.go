@@ -0,0 +1,71 @@
+package app
+
+import (
+	"slices"
+
+	"somad/internal/channels"
+	"somad/internal/directory"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sourceHeaders titles the list while it shows a single station source
+// other than SomaFM.
+var sourceHeaders = map[string]string{
+	directory.RadioBrowserName: "Radio Browser Stations",
+	directory.StationsName:     "My Stations",
+}
+
+// nextSource returns the source the S key switches to after current: the
+// next of sources, and after the last one all of them merged ("").
+func nextSource(sources []string, current string) string {
+	i := slices.Index(sources, current)
+	if i+1 >= len(sources) {
+		return ""
+	}
+	return sources[i+1]
+}
+
+// sourceTitle names a source choice for the notice line.
+func sourceTitle(source string) string {
+	if source == "" {
+		return "all"
+	}
+	return directory.Title(source)
+}
+
+// sourceChannels returns the channels of the selected Source, or all of
+// them while no source is selected.
+func (m *Model) sourceChannels(chs []channels.Channel) []channels.Channel {
+	if m.Source == "" {
+		return chs
+	}
+	kept := make([]channels.Channel, 0, len(chs))
+	for _, ch := range chs {
+		if directory.Of(ch.ID) == m.Source {
+			kept = append(kept, ch)
+		}
+	}
+	return kept
+}
+
+// CycleSource switches the list to the next station source, keeping the
+// cursor on the selected channel, and returns a command that persists the
+// choice on the server for every client. With a single source there is
+// nothing to switch; the station directory lists its own results.
+func (m *Model) CycleSource() tea.Cmd {
+	if m.Directory || len(m.Sources) < 2 {
+		return nil
+	}
+	m.Source = nextSource(m.Sources, m.Source)
+	m.Notice = "Source: " + sourceTitle(m.Source)
+	m.applyFilters()
+
+	b, source := m.Backend, m.Source
+	return func() tea.Msg {
+		if err := b.SetChannelSource(source); err != nil {
+			return requestErr("channel source", err)
+		}
+		return nil
+	}
+}
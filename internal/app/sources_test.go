@@ -0,0 +1,78 @@
+package app
+
+import (
+	"testing"
+
+	"somad/internal/channels"
+	"somad/internal/directory"
+	"somad/internal/protocol"
+	"somad/internal/ui"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mixedChannels lists a SomaFM channel, a directory station and a station
+// of the user's own.
+func mixedChannels() []channels.Channel {
+	return []channels.Channel{
+		{ID: "groovesalad", Title: "Groove Salad"},
+		{ID: "rb:abc", Title: "Jazz FM", StreamURL: "http://jazz.example.org/live"},
+		channels.Custom("Talk", "http://talk.example.org/live", "", ""),
+	}
+}
+
+func TestCycleSource_SwitchesAndPersists(t *testing.T) {
+	m := newTestModel(t)
+	m.applyChannels(protocol.ChannelsPayload{
+		Channels: mixedChannels(),
+		Sources:  []string{directory.SomaFMName, directory.RadioBrowserName, directory.StationsName},
+	})
+	assert.Equal(t, []string{"groovesalad", "rb:abc", "custom:talk"}, listIDs(m), "all sources merged")
+
+	var lists [][]string
+	for range 4 {
+		_, cmd := sendKey(m, 'S')
+		runCmd(cmd)
+		lists = append(lists, listIDs(m))
+	}
+
+	assert.Equal(t, [][]string{
+		{"groovesalad"},
+		{"rb:abc"},
+		{"custom:talk"},
+		{"groovesalad", "rb:abc", "custom:talk"},
+	}, lists)
+	assert.Equal(t, []string{"somafm", "radiobrowser", "stations", ""}, backend(m).sources)
+	assert.Equal(t, "Source: all", m.Notice)
+}
+
+func TestCycleSource_SingleSourceIsNoOp(t *testing.T) {
+	m := newTestModel(t)
+	m.applyChannels(protocol.ChannelsPayload{Channels: testChannels(), Sources: []string{directory.SomaFMName}})
+
+	_, cmd := sendKey(m, 'S')
+
+	assert.Nil(t, cmd)
+	assert.Empty(t, m.Source)
+}
+
+func TestApplyChannels_PersistedSource(t *testing.T) {
+	m := newTestModel(t)
+	m.applyChannels(protocol.ChannelsPayload{
+		Channels: mixedChannels(),
+		Sources:  []string{directory.SomaFMName, directory.StationsName},
+		Source:   directory.StationsName,
+	})
+
+	assert.Equal(t, []string{"custom:talk"}, listIDs(m))
+	assert.Contains(t, m.RenderHeader(), "My Stations")
+
+	// A source the server no longer lists falls back to all of them.
+	m.applyChannels(protocol.ChannelsPayload{
+		Channels: mixedChannels(),
+		Sources:  []string{directory.SomaFMName},
+		Source:   directory.StationsName,
+	})
+	assert.Empty(t, m.Source)
+	assert.Equal(t, "groovesalad", m.List.Items()[0].(ui.Item).Channel.ID)
+}
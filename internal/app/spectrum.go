@@ -0,0 +1,72 @@
+package app
+
+import (
+	"math"
+	"strings"
+
+	"somad/internal/ui"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// spectrumBands is how many frequency bands the visualizer polls; the
+// full-screen view draws one column per band.
+const spectrumBands = 32
+
+// spectrumBarCells is the width of the status bar's spectrum, which pairs
+// up neighbouring bands.
+const spectrumBarCells = 16
+
+// spectrumRows is the height of the full-screen view's spectrum.
+const spectrumRows = 6
+
+// spectrumBlocks are the eighths of a cell a spectrum column rises by.
+var spectrumBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// groupBands folds bands into n cells, each the loudest of the bands it
+// covers.
+func groupBands(bands []float64, n int) []float64 {
+	cells := make([]float64, n)
+	for i, v := range bands {
+		c := i * n / len(bands)
+		cells[c] = max(cells[c], v)
+	}
+	return cells
+}
+
+// renderSpectrumBar draws the spectrum on one line for the status bar, in
+// place of the level meter: lit cells rise, silent ones are dimmed.
+func renderSpectrumBar(bands []float64) string {
+	litStyle := lipgloss.NewStyle().Foreground(ui.PlayingColor)
+	dimStyle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+	var b strings.Builder
+	for _, v := range groupBands(bands, spectrumBarCells) {
+		// The meter's cells start at the lowest block, as levelBars do.
+		i := int(math.Round(v * float64(len(spectrumBlocks)-2)))
+		if i == 0 {
+			b.WriteString(dimStyle.Render(string(spectrumBlocks[1])))
+			continue
+		}
+		b.WriteString(litStyle.Render(string(spectrumBlocks[i+1])))
+	}
+	return b.String()
+}
+
+// renderSpectrum draws the spectrum as spectrumRows rows of columns, one
+// per band, lowest frequency on the left.
+func renderSpectrum(bands []float64) string {
+	style := lipgloss.NewStyle().Foreground(ui.PlayingColor)
+	eighths := len(spectrumBlocks) - 1
+	lines := make([]string, spectrumRows)
+	for row := range lines {
+		// Rows count down from the top; floor is the eighths below this row.
+		floor := (spectrumRows - 1 - row) * eighths
+		var b strings.Builder
+		for _, v := range bands {
+			h := int(math.Round(v*float64(spectrumRows*eighths))) - floor
+			b.WriteRune(spectrumBlocks[max(0, min(h, eighths))])
+		}
+		lines[row] = style.Render(b.String())
+	}
+	return strings.Join(lines, "\n")
+}
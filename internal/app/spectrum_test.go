@@ -0,0 +1,57 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"somad/internal/protocol"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpectrum_PolledOnlyWithVisualizer(t *testing.T) {
+	m := newTestModel(t)
+	backend(m).spectrum = []float64{1, 0.5}
+
+	_, cmd := m.Update(ServerStateMsg{State: protocol.PlaybackState{Status: protocol.StatusPlaying}})
+	_, next := m.Update(runCmd(cmd))
+	assert.Nil(t, m.Spectrum, "the visualizer is off")
+
+	m.Visualizer = true
+	_, next = m.Update(runCmd(next))
+	m.Update(runCmd(next))
+	assert.Equal(t, []float64{1, 0.5}, m.Spectrum)
+
+	m.Update(ServerStateMsg{State: protocol.PlaybackState{Status: protocol.StatusStopped}})
+	m.Update(levelMsg{Spectrum: []float64{1}})
+	assert.Nil(t, m.Spectrum, "cleared once playback stops")
+}
+
+func TestRenderStatusBar_SpectrumReplacesLevelMeter(t *testing.T) {
+	m := newTestModel(t)
+	m.Visualizer = true
+	m.applySnapshot(protocol.PlaybackState{Status: protocol.StatusPlaying, ChannelTitle: "Groove Salad"})
+	m.Level = 1
+	assert.Contains(t, ansi.Strip(m.RenderStatusBar()), "▁▂▃▄▅▆", "the meter until the first spectrum")
+
+	bands := make([]float64, spectrumBands)
+	bands[0], bands[1] = 1, 0.2
+	m.Spectrum = bands
+	bar := ansi.Strip(m.RenderStatusBar())
+	assert.Contains(t, bar, "█"+strings.Repeat("▁", spectrumBarCells-1))
+	assert.NotContains(t, bar, "▁▂▃▄▅▆")
+}
+
+func TestGroupBands_KeepsTheLoudest(t *testing.T) {
+	assert.Equal(t, []float64{0.5, 0.9}, groupBands([]float64{0.1, 0.5, 0.9, 0.2}, 2))
+}
+
+func TestRenderSpectrum_ColumnsRise(t *testing.T) {
+	lines := strings.Split(ansi.Strip(renderSpectrum([]float64{0, 0.5, 1})), "\n")
+	require.Len(t, lines, spectrumRows)
+	assert.Equal(t, "  █", lines[0], "only the loudest band reaches the top")
+	assert.Equal(t, " ██", lines[spectrumRows/2], "half the rows for half the level")
+	assert.Equal(t, " ██", lines[spectrumRows-1])
+}
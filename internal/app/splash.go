@@ -0,0 +1,47 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// splashDuration is how long the startup banner stays up unless a key
+// dismisses it first.
+const splashDuration = 600 * time.Millisecond
+
+// splashBanner is the startup banner's ASCII art.
+var splashBanner = []string{
+	" ___  ___  _ __ ___   __ _ ",
+	"/ __|/ _ \\| '_ ` _ \\ / _` |",
+	"\\__ \\ (_) | | | | | | (_| |",
+	"|___/\\___/|_| |_| |_|\\__,_|",
+}
+
+// splashDoneMsg ends the startup banner.
+type splashDoneMsg struct{}
+
+// splashTimeoutCmd ends the banner after splashDuration.
+func splashTimeoutCmd() tea.Cmd {
+	return tea.Tick(splashDuration, func(time.Time) tea.Msg {
+		return splashDoneMsg{}
+	})
+}
+
+// renderSplash renders the startup banner, centered once the terminal size
+// is known.
+func (m *Model) renderSplash() string {
+	banner := lipgloss.JoinVertical(lipgloss.Center,
+		lipgloss.NewStyle().Foreground(ui.TitleColor).Bold(true).Render(strings.Join(splashBanner, "\n")),
+		"",
+		lipgloss.NewStyle().Foreground(ui.SubtleColor).Render("SomaFM in your terminal"),
+	)
+	if m.Width <= 0 || m.Height <= 0 {
+		return ui.LoadingStyle.Render(banner)
+	}
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, banner)
+}
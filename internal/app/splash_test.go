@@ -0,0 +1,61 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestView_SplashDisabledShowsLoading(t *testing.T) {
+	m := newTestModel(t)
+	m.Loading = true
+
+	result := m.View()
+
+	assert.Contains(t, result, "Loading")
+	assert.NotContains(t, result, "SomaFM in your terminal")
+}
+
+func TestView_SplashEnabledShowsBanner(t *testing.T) {
+	m := newTestModel(t)
+	m.Loading = true
+	m.Splash = true
+
+	result := m.View()
+
+	assert.Contains(t, result, "SomaFM in your terminal")
+	assert.Contains(t, result, splashBanner[3])
+}
+
+func TestUpdate_SplashDismissedByAnyKey(t *testing.T) {
+	m := newTestModel(t)
+	m.Splash = true
+
+	_, cmd := sendKey(m, 'j')
+
+	assert.False(t, m.Splash)
+	assert.Nil(t, cmd, "the dismissing key is not acted on")
+	assert.Empty(t, backend(m).playIDs)
+}
+
+func TestUpdate_SplashEndsOnTimeout(t *testing.T) {
+	m := newTestModel(t)
+	m.Splash = true
+
+	m.Update(splashDoneMsg{})
+
+	assert.False(t, m.Splash)
+}
+
+func TestInit_SchedulesSplashTimeout(t *testing.T) {
+	m := newTestModel(t)
+	m.NoAltScreen = true
+	m.Splash = true
+
+	msg := runCmd(m.Init())
+
+	batch, ok := msg.(tea.BatchMsg)
+	assert.True(t, ok)
+	assert.Len(t, batch, 3, "fetch channels, fetch status and the splash timeout")
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"somad/internal/channels"
+	"somad/internal/radiobrowser"
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// IsCustom reports whether the item at idx is a station from outside the
+// SomaFM catalog, which the delegate accents.
+func (m *Model) IsCustom(idx int) bool {
+	items := m.List.Items()
+	if idx < 0 || idx >= len(items) {
+		return false
+	}
+	if i, ok := items[idx].(ui.Item); ok {
+		return radiobrowser.IsStationID(i.Channel.ID) || channels.IsCustomID(i.Channel.ID)
+	}
+	return false
+}
+
+// OpenDirectory switches the list to the station directory and starts a new
+// directory query. The SomaFM catalog stays the home view: CloseDirectory
+// brings it back.
+func (m *Model) OpenDirectory() {
+	m.ClearSearch()
+	m.Directory = true
+	m.DirectoryTyping = true
+	m.DirectoryQuery = ""
+	m.UpdateListSize()
+}
+
+// CloseDirectory leaves the station directory and restores the SomaFM
+// catalog, keeping the cursor on the playing channel when there is one.
+func (m *Model) CloseDirectory() {
+	m.ClearSearch()
+	m.Directory = false
+	m.DirectoryTyping = false
+	m.DirectoryQuery = ""
+	m.DirectoryLoading = false
+	m.List.SetItems(m.catalogItems())
+	m.List.Select(0)
+	m.selectChannelByID(m.PlayingID)
+	m.UpdateListSize()
+}
+
+// updateDirectoryInput handles keys while the directory query is typed.
+func (m *Model) updateDirectoryInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c":
+		return m.quitCmd()
+	case "enter":
+		// An empty query browses the directory's most played stations.
+		m.DirectoryTyping = false
+		m.DirectoryLoading = true
+		m.UpdateListSize()
+		return m.searchStationsCmd(m.DirectoryQuery)
+	case "esc":
+		m.CloseDirectory()
+		return nil
+	case "backspace":
+		if len(m.DirectoryQuery) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.DirectoryQuery)
+			m.DirectoryQuery = m.DirectoryQuery[:len(m.DirectoryQuery)-size]
+		}
+		return nil
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.DirectoryQuery += PrintableRunes(msg.Runes)
+		}
+		return nil
+	}
+}
+
+// applyStations shows directory search results in the list. Results for a
+// query the user has since replaced or abandoned are dropped.
+func (m *Model) applyStations(msg StationsMsg) {
+	if !m.Directory || msg.Query != m.DirectoryQuery {
+		return
+	}
+	m.DirectoryLoading = false
+	m.RequestErr = ""
+	m.ClearSearch()
+	m.List.SetItems(ChannelsToItems(msg.Channels))
+	m.List.Select(0)
+	m.UpdateListSize()
+}
+
+// renderDirectoryBar renders the directory query input or, once a search
+// has run, the query with its result count.
+func (m *Model) renderDirectoryBar() string {
+	query := m.DirectoryQuery
+	if strings.TrimSpace(query) == "" {
+		query = "most played"
+	}
+	switch {
+	case m.DirectoryTyping && m.DirectoryQuery == "":
+		return ui.SearchBarStyle.Render("Radio Browser search: (enter alone lists the most played)")
+	case m.DirectoryTyping:
+		return ui.SearchBarStyle.Render("Radio Browser search: " + m.DirectoryQuery)
+	case m.DirectoryLoading:
+		return ui.SearchBarStyle.Render(fmt.Sprintf("Radio Browser: %s (searching…)", query))
+	default:
+		return ui.SearchBarStyle.Render(fmt.Sprintf("Radio Browser: %s [%d stations] (d new search, esc back to SomaFM)",
+			query, len(m.List.Items())))
+	}
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"somad/internal/channels"
+	"somad/internal/protocol"
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStations() []channels.Channel {
+	return []channels.Channel{
+		{ID: "rb:a", Title: "Jazz One", StreamURL: "http://a.example/jazz"},
+		{ID: "rb:b", Title: "Jazz Two", StreamURL: "http://b.example/jazz"},
+	}
+}
+
+// searchDirectory opens the directory, types query, and runs the search,
+// feeding its result back through Update.
+func searchDirectory(t *testing.T, m *Model, query string) {
+	t.Helper()
+	sendKey(m, 'd')
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(query)})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	m.Update(runCmd(cmd))
+}
+
+func TestDirectory_SearchShowsResults(t *testing.T) {
+	m := newTestModel(t)
+	m.catalog = testChannels()
+	backend(m).stations = testStations()
+
+	searchDirectory(t, m, "jazz")
+
+	assert.Equal(t, []string{"jazz"}, backend(m).queries)
+	assert.True(t, m.Directory)
+	assert.False(t, m.DirectoryTyping)
+	assert.False(t, m.DirectoryLoading)
+	require.Len(t, m.List.Items(), 2)
+	assert.Equal(t, "rb:a", m.List.SelectedItem().(ui.Item).Channel.ID)
+	assert.Contains(t, m.RenderSearchBar(), "[2 stations]")
+}
+
+func TestDirectory_EnterPlaysStation(t *testing.T) {
+	m := newTestModel(t)
+	backend(m).stations = testStations()
+	searchDirectory(t, m, "jazz")
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	runCmd(cmd)
+
+	assert.Equal(t, []string{"rb:a"}, backend(m).playIDs)
+}
+
+func TestDirectory_EmptyQueryListsMostPlayed(t *testing.T) {
+	m := newTestModel(t)
+	backend(m).stations = testStations()
+	sendKey(m, 'd')
+	assert.Contains(t, m.RenderSearchBar(), "enter alone lists the most played")
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	m.Update(runCmd(cmd))
+
+	assert.Equal(t, []string{""}, backend(m).queries)
+	assert.False(t, m.DirectoryTyping)
+	assert.Len(t, m.List.Items(), 2)
+	assert.Contains(t, m.RenderSearchBar(), "Radio Browser: most played [2 stations]")
+}
+
+func TestDirectory_EscRestoresCatalog(t *testing.T) {
+	m := newTestModel(t)
+	m.catalog = testChannels()
+	backend(m).stations = testStations()
+	searchDirectory(t, m, "jazz")
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	assert.False(t, m.Directory)
+	require.Len(t, m.List.Items(), len(testChannels()))
+	assert.Equal(t, "groovesalad", m.List.SelectedItem().(ui.Item).Channel.ID)
+}
+
+func TestDirectory_CatalogRefreshDoesNotReplaceResults(t *testing.T) {
+	m := newTestModel(t)
+	backend(m).stations = testStations()
+	searchDirectory(t, m, "jazz")
+
+	m.Update(ServerChannelsMsg{Payload: protocol.ChannelsPayload{Channels: testChannels()}})
+
+	require.Len(t, m.List.Items(), 2, "results stay until the directory is closed")
+	m.CloseDirectory()
+	assert.Len(t, m.List.Items(), len(testChannels()))
+}
+
+func TestDirectory_FavoriteKeyIgnored(t *testing.T) {
+	m := newTestModel(t)
+	backend(m).stations = testStations()
+	searchDirectory(t, m, "jazz")
+
+	_, cmd := sendKey(m, 'f')
+
+	assert.Nil(t, cmd)
+	assert.Empty(t, m.Favorites)
+}
+
+func TestDirectory_SearchErrorClearsLoading(t *testing.T) {
+	m := newTestModel(t)
+	backend(m).callErr = errors.New("directory down")
+
+	searchDirectory(t, m, "jazz")
+
+	assert.False(t, m.DirectoryLoading)
+	assert.Contains(t, m.RequestErr, "station search failed")
+}
+
+func TestDirectory_StaleResultsDropped(t *testing.T) {
+	m := newTestModel(t)
+	sendKey(m, 'd')
+	m.DirectoryQuery = "rock"
+
+	m.Update(StationsMsg{Query: "jazz", Channels: testStations()})
+
+	assert.Len(t, m.List.Items(), len(testChannels()))
+}
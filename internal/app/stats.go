@@ -0,0 +1,136 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"somad/internal/protocol"
+	"somad/internal/ui"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// statsInterval is how often the statistics overlay refreshes.
+const statsInterval = time.Second
+
+// statsMsg carries polled stream statistics and when they were taken.
+type statsMsg struct {
+	Stats protocol.StatsResult
+	At    time.Time
+	Err   error
+}
+
+// fetchStatsCmd asks the server for stream statistics after delay.
+func (m *Model) fetchStatsCmd(delay time.Duration) tea.Cmd {
+	b := m.Backend
+	fetch := func(time.Time) tea.Msg {
+		st, err := b.Stats()
+		return statsMsg{Stats: st, At: time.Now(), Err: err}
+	}
+	if delay == 0 {
+		return func() tea.Msg { return fetch(time.Now()) }
+	}
+	return tea.Tick(delay, fetch)
+}
+
+// ToggleStats opens or closes the statistics overlay, starting its poll
+// loop unless one still runs from an earlier opening.
+func (m *Model) ToggleStats() tea.Cmd {
+	m.StatsOpen = !m.StatsOpen
+	if !m.StatsOpen || m.statsPolling {
+		return nil
+	}
+	m.statsPolling = true
+	return m.fetchStatsCmd(0)
+}
+
+// applyStats records polled statistics, deriving the current bitrate from
+// the bytes received since the previous poll, and schedules the next poll
+// while the overlay or the full-screen now-playing view is open.
+func (m *Model) applyStats(msg statsMsg) tea.Cmd {
+	if msg.Err == nil {
+		prev, prevAt := m.Stats, m.statsAt
+		m.Bitrate = 0
+		switch {
+		case msg.Stats.ConnectedAt.IsZero():
+		case prev.ConnectedAt.Equal(msg.Stats.ConnectedAt) && msg.At.After(prevAt):
+			m.Bitrate = float64(msg.Stats.BytesReceived-prev.BytesReceived) * 8 / msg.At.Sub(prevAt).Seconds()
+		case msg.At.After(msg.Stats.ConnectedAt):
+			// A fresh connection: average over its lifetime so far.
+			m.Bitrate = float64(msg.Stats.BytesReceived) * 8 / msg.At.Sub(msg.Stats.ConnectedAt).Seconds()
+		}
+		m.Stats, m.statsAt = msg.Stats, msg.At
+	}
+	if !m.StatsOpen && !m.NowPlayingOpen {
+		m.statsPolling = false
+		return nil
+	}
+	return m.fetchStatsCmd(statsInterval)
+}
+
+// updateStats handles keys while the statistics overlay is open: esc or I
+// closes it.
+func (m *Model) updateStats(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m.quitCmd()
+	case "esc", "I":
+		m.StatsOpen = false
+	}
+	return nil
+}
+
+// renderStats renders the stream statistics as a bordered box centered over
+// the list area.
+func (m *Model) renderStats() string {
+	st := m.Stats
+	rows := [][2]string{
+		{"Bitrate", "—"},
+		{"Downloaded", "—"},
+		{"Connected for", "—"},
+		{"Reconnects", fmt.Sprint(st.Reconnects)},
+		{"Buffer", "—"},
+	}
+	if !st.ConnectedAt.IsZero() {
+		rows[0][1] = fmt.Sprintf("%.0f kbps", m.Bitrate/1000)
+		rows[1][1] = formatBytes(st.BytesReceived)
+		rows[2][1] = m.statsAt.Sub(st.ConnectedAt).Truncate(time.Second).String()
+		if st.BufferSize > 0 {
+			rows[4][1] = fmt.Sprintf("%d%% (%s of %s)", st.Buffered*100/st.BufferSize,
+				formatBytes(int64(st.Buffered)), formatBytes(int64(st.BufferSize)))
+		}
+	}
+
+	labelStyle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+	valueStyle := lipgloss.NewStyle().Foreground(ui.TextColor)
+	var lines []string
+	for _, r := range rows {
+		lines = append(lines, labelStyle.Render(fmt.Sprintf("%-14s", r[0]))+valueStyle.Render(r[1]))
+	}
+
+	header := ui.TitleStyle.UnsetMarginLeft().Render("Stream statistics")
+	footer := labelStyle.Render("esc closes")
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.SubtleColor).
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, "", strings.Join(lines, "\n"), "", footer))
+
+	return lipgloss.Place(m.Width, m.List.Height(), lipgloss.Center, lipgloss.Center, box)
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. "3.4 MiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"somad/internal/protocol"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats_PollsWhileOpen(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 120
+	connected := time.Now().Add(-90 * time.Second)
+	backend(m).stats = protocol.StatsResult{
+		BytesReceived: 3 << 20, ConnectedAt: connected, Reconnects: 2, Buffered: 1 << 20, BufferSize: 4 << 20,
+	}
+
+	_, cmd := sendKey(m, 'I')
+	require.True(t, m.StatsOpen)
+	_, next := m.Update(runCmd(cmd))
+	require.NotNil(t, next, "the next poll is scheduled while open")
+
+	view := m.View()
+	assert.Contains(t, view, "Stream statistics")
+	assert.Contains(t, view, "3.0 MiB")
+	assert.Contains(t, view, "1m30s")
+	assert.Contains(t, view, "25% (1.0 MiB of 4.0 MiB)")
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, cmd)
+	assert.False(t, m.StatsOpen)
+	_, next = m.Update(statsMsg{Stats: backend(m).stats, At: time.Now()})
+	assert.Nil(t, next, "the poll loop ends once closed")
+
+	assert.NotNil(t, m.ToggleStats(), "reopening starts a new loop")
+	m.ToggleStats()
+	assert.Nil(t, m.ToggleStats(), "reopening while a loop runs does not start another")
+}
+
+func TestStats_BitrateFromSuccessivePolls(t *testing.T) {
+	m := newTestModel(t)
+	m.StatsOpen = true
+	connected := time.Now().Add(-time.Minute)
+	at := time.Now()
+
+	m.applyStats(statsMsg{Stats: protocol.StatsResult{BytesReceived: 960_000, ConnectedAt: connected}, At: at})
+	assert.InDelta(t, 128_000, m.Bitrate, 1, "first poll averages over the connection")
+
+	m.applyStats(statsMsg{Stats: protocol.StatsResult{BytesReceived: 960_000 + 32_000, ConnectedAt: connected}, At: at.Add(time.Second)})
+	assert.InDelta(t, 256_000, m.Bitrate, 1, "later polls use the bytes since the last one")
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "4.0 MiB", formatBytes(4<<20))
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"fmt"
+	"time"
+)
+
+// timestampLayout is how the UI shows a point in time; the zone
+// abbreviation makes local and UTC renderings distinguishable.
+const timestampLayout = "2006-01-02 15:04 MST"
+
+// clockLayout is how the UI shows a time of day within the last hours,
+// where the date and zone would only crowd the line.
+const clockLayout = "15:04"
+
+// FormatTime renders t in loc for display. Every timestamp the UI shows goes
+// through it (via Model.formatTime) or Model.formatClock, so the local/UTC
+// toggle applies to all of them alike.
+func FormatTime(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(timestampLayout)
+}
+
+// location is the zone timestamps are shown in, per the UTC toggle.
+func (m *Model) location() *time.Location {
+	if m.UTC {
+		return time.UTC
+	}
+	return time.Local
+}
+
+// formatTime renders t in UTC or the local zone, per the UTC toggle.
+func (m *Model) formatTime(t time.Time) string {
+	return FormatTime(t, m.location())
+}
+
+// formatClock renders t's time of day in UTC or the local zone, per the UTC
+// toggle.
+func (m *Model) formatClock(t time.Time) string {
+	return t.In(m.location()).Format(clockLayout)
+}
+
+// formatBehind renders how far playback trails the live stream, e.g.
+// "-1:30 behind live".
+func formatBehind(d time.Duration) string {
+	secs := int(d / time.Second)
+	return fmt.Sprintf("-%d:%02d behind live", secs/60, secs%60)
+}
+
+// formatElapsed renders how long a track has been playing, e.g. "3:41",
+// with hours once it runs that long.
+func formatElapsed(d time.Duration) string {
+	secs := max(int(d/time.Second), 0)
+	if secs >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+	}
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
+
+// formatAge renders how old something is in its largest whole unit, e.g.
+// "45m", "2h" or "3d".
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	default:
+		return fmt.Sprintf("%dm", max(int(d/time.Minute), 0))
+	}
+}
+
+// formatListened renders a listening time in hours and minutes, e.g.
+// "45m" or "12h 05m"; under a minute reads "<1m".
+func formatListened(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	}
+	return fmt.Sprintf("%dh %02dm", int(d/time.Hour), int(d%time.Hour/time.Minute))
+}
+
+// buildDate renders the build timestamp for the about footer. Release builds
+// stamp it in RFC 3339; anything else (e.g. "unknown" in dev builds) is
+// shown as is.
+func (m *Model) buildDate() string {
+	t, err := time.Parse(time.RFC3339, m.About.Date)
+	if err != nil {
+		return m.About.Date
+	}
+	return m.formatTime(t)
+}
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"somad/internal/protocol"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatTime_LocalVersusUTC(t *testing.T) {
+	ts := time.Date(2024, 6, 19, 22, 30, 0, 0, time.UTC)
+	cest := time.FixedZone("CEST", 2*60*60)
+
+	assert.Equal(t, "2024-06-19 22:30 UTC", FormatTime(ts, time.UTC))
+	assert.Equal(t, "2024-06-20 00:30 CEST", FormatTime(ts, cest))
+}
+
+func TestFormatBehind(t *testing.T) {
+	assert.Equal(t, "-0:45 behind live", formatBehind(45*time.Second))
+	assert.Equal(t, "-12:05 behind live", formatBehind(12*time.Minute+5500*time.Millisecond))
+}
+
+func TestFormatElapsed(t *testing.T) {
+	assert.Equal(t, "0:00", formatElapsed(-time.Second), "a server clock ahead of ours reads as just started")
+	assert.Equal(t, "3:41", formatElapsed(3*time.Minute+41500*time.Millisecond))
+	assert.Equal(t, "1:02:03", formatElapsed(time.Hour+2*time.Minute+3*time.Second))
+}
+
+func TestFormatAge(t *testing.T) {
+	assert.Equal(t, "0m", formatAge(-time.Second))
+	assert.Equal(t, "45m", formatAge(45*time.Minute+30*time.Second))
+	assert.Equal(t, "2h", formatAge(2*time.Hour+59*time.Minute))
+	assert.Equal(t, "3d", formatAge(3*24*time.Hour+5*time.Hour))
+}
+
+func TestRenderStatusBar_ShowsCacheAgeWhileStale(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 160
+	m.CatalogFetched = time.Now().Add(-2*time.Hour - time.Minute)
+	assert.NotContains(t, m.RenderStatusBar(), "data from cache", "a cache the server could refresh is not worth a mention")
+
+	m.CatalogStale = true
+	assert.Contains(t, m.RenderStatusBar(), "data from cache (2h old)")
+}
+
+func TestRenderStatusBar_ShowsTrackElapsed(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 160
+	m.Snapshot = protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelTitle: "Groove Salad", TrackTitle: "Tycho - Awake",
+		TrackStarted: time.Now().Add(-(3*time.Minute + 41*time.Second + 500*time.Millisecond)),
+	}
+	assert.Contains(t, m.RenderStatusBar(), "3:41")
+
+	m.Snapshot.TrackStarted = time.Time{}
+	assert.NotRegexp(t, `\d:\d\d`, m.RenderStatusBar(), "no timer without a start time")
+}
+
+func TestUpdate_TKeyTogglesUTC(t *testing.T) {
+	m := newTestModel(t)
+
+	sendKey(m, 't')
+	assert.True(t, m.UTC)
+
+	sendKey(m, 't')
+	assert.False(t, m.UTC)
+}
+
+func TestRenderAboutFooter_BuildDateFollowsUTCToggle(t *testing.T) {
+	m := newTestModel(t)
+	m.ShowAbout = true
+	m.Width = 120
+	m.About = AboutInfo{Version: "1.0.0", Commit: "abc", Date: "2024-06-19T22:30:00Z"}
+
+	m.UTC = true
+	assert.Contains(t, m.RenderAboutFooter(), "built 2024-06-19 22:30 UTC")
+
+	m.UTC = false
+	assert.Contains(t, m.RenderAboutFooter(), "built "+FormatTime(time.Date(2024, 6, 19, 22, 30, 0, 0, time.UTC), time.Local))
+}
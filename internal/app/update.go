@@ -8,6 +8,8 @@ import (
 	"somad/internal/ui"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -15,6 +17,42 @@ import (
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// Any key dismisses the startup banner; ctrl+c still quits.
+		if m.Splash && msg.String() != "ctrl+c" {
+			m.Splash = false
+			return m, nil
+		}
+		m.Notice = ""
+		if m.NowPlayingOpen {
+			return m, m.updateNowPlaying(msg)
+		}
+		if m.DirectoryTyping {
+			return m, m.updateDirectoryInput(msg)
+		}
+		if m.Dashboard {
+			return m, m.updateDashboard(msg)
+		}
+		if m.PlayedOpen {
+			return m, m.updatePlayed(msg)
+		}
+		if m.ListeningOpen {
+			return m, m.updateListening(msg)
+		}
+		if m.EqualizerOpen {
+			return m, m.updateEqualizer(msg)
+		}
+		if m.GenresOpen {
+			return m, m.updateGenres(msg)
+		}
+		if m.StatsOpen {
+			return m, m.updateStats(msg)
+		}
+		if m.LovedOpen {
+			return m, m.updateLoved(msg)
+		}
+		if m.ArtworkOpen {
+			return m, m.updateArtwork(msg)
+		}
 		// Handle search input mode
 		if m.Searching {
 			switch msg.String() {
@@ -23,11 +61,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "enter":
 				// Exit search mode, keep at current match
 				m.Searching = false
+				m.applyPendingChannels()
 				m.UpdateListSize()
 				return m, nil
 			case "esc":
 				// Cancel search, clear query
 				m.ClearSearch()
+				m.applyPendingChannels()
 				m.UpdateListSize()
 				return m, nil
 			case "backspace":
@@ -39,6 +79,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			default:
 				// Append printable characters (including non-ASCII) to the query.
+				// A paste (bracketed or not) arrives as one KeyRunes message
+				// carrying every rune, so the whole text is filtered and
+				// appended at once; pasted newlines and tabs are dropped.
 				if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
 					if input := PrintableRunes(msg.Runes); input != "" {
 						m.SearchQuery += input
@@ -49,27 +92,69 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// The history panel takes the keys it acts on; the rest work the
+		// list beside it.
+		if m.HistoryOpen {
+			if cmd, ok := m.updateHistory(msg); ok {
+				return m, cmd
+			}
+		}
+
+		// A failed stream prompts for what to do next; r and n answer it
+		// (s falls through to the regular stop, which clears the failure).
+		// While a search has matches, n keeps stepping through them.
+		if m.FailedID != "" {
+			switch msg.String() {
+			case "r":
+				return m, m.switchChannelCmd(m.FailedID)
+			case "n":
+				if len(m.SearchMatches) > 0 {
+					break
+				}
+				if id := m.channelAfter(m.FailedID); id != "" {
+					return m, m.switchChannelCmd(id)
+				}
+				return m, nil
+			}
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "q":
 			return m, m.quitCmd()
-		case "enter", " ":
+		case "enter":
 			if i, ok := m.List.SelectedItem().(ui.Item); ok {
-				// Changing channel interrupts the stream anyway, so an
-				// out-of-date server is restarted first and the channel is
-				// played once the reconnect delivers a fresh backend.
-				if m.skewed() {
-					m.pendingPlayID = i.Channel.ID
-					return m, m.restartCmd()
+				return m, m.switchChannelCmd(i.Channel.ID)
+			}
+		case " ":
+			// Space dismisses the about footer whatever it is bound to.
+			if m.ShowAbout {
+				m.ShowAbout = false
+				m.UpdateListSize()
+				return m, nil
+			}
+			switch m.SpaceAction {
+			case SpaceNone:
+				return m, nil
+			case SpaceToggle:
+				if m.Snapshot.Status != protocol.StatusStopped {
+					return m, m.stopPlaybackCmd()
 				}
-				return m, m.playCmd(i.Channel.ID)
 			}
-		case "s":
-			// Stopping interrupts the stream anyway; upgrade an out-of-date
-			// server while we're at it (the fresh one comes up stopped).
-			if m.skewed() && m.Snapshot.Status != protocol.StatusStopped {
-				return m, m.restartCmd()
+			if i, ok := m.List.SelectedItem().(ui.Item); ok {
+				return m, m.switchChannelCmd(i.Channel.ID)
 			}
-			return m, m.stopCmd()
+		case "s":
+			return m, m.stopPlaybackCmd()
+		case "p":
+			return m, m.playPauseCmd()
+		case "t":
+			// Switch timestamps between local time and UTC.
+			m.UTC = !m.UTC
+			return m, nil
+		case "i":
+			// Switch the second line between description and genres.
+			m.ShowGenre = !m.ShowGenre
+			return m, nil
 		case "a":
 			// Toggle the inline about footer.
 			m.ShowAbout = !m.ShowAbout
@@ -82,6 +167,93 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.UpdateListSize()
 				return m, nil
 			}
+			// Leave the station directory for the SomaFM catalog.
+			if m.Directory {
+				m.CloseDirectory()
+				return m, nil
+			}
+			if m.showFavoritesHint() {
+				cmd := m.dismissFavoritesHint()
+				m.UpdateListSize()
+				return m, cmd
+			}
+		case "d":
+			m.OpenDirectory()
+			return m, nil
+		case "w":
+			// What's on across the favorites.
+			m.OpenDashboard()
+			return m, nil
+		case "H":
+			// The channels played last, for a quick return.
+			if m.Directory {
+				return m, nil
+			}
+			m.OpenPlayed()
+			return m, nil
+		case "T":
+			// Which channels were played the longest.
+			if m.Directory {
+				return m, nil
+			}
+			m.OpenListening()
+			return m, nil
+		case "h":
+			// What played earlier, on any channel.
+			return m, m.ToggleHistory()
+		case "l":
+			// Love the playing track, or unlove it.
+			return m, m.LoveTrack()
+		case "v":
+			return m, m.ToggleLoved()
+		case "o":
+			// Look the playing track up on the web.
+			return m, m.SearchTrack()
+		case "A":
+			// The playing track with its cover.
+			return m, m.ToggleArtwork()
+		case "P":
+			// The playing channel full screen, e.g. for a spare monitor.
+			return m, m.ToggleNowPlaying()
+		case "G":
+			// Channels as a grid of cards, for wide terminals.
+			m.Grid = !m.Grid
+			return m, nil
+		case "up", "down", "k", "j", "left", "right":
+			// The grid moves in two dimensions; the list takes them otherwise.
+			if m.gridActive() {
+				m.moveGrid(msg.String())
+				return m, m.trackHover()
+			}
+		case "z":
+			// Collapse to a channel selector and the status line.
+			m.Compact = !m.Compact
+			return m, nil
+		case "D":
+			// Everything the catalog says about the highlighted channel.
+			return m, m.ToggleDetails()
+		case "[":
+			m.ResizeDetails(-detailsWidthStep)
+			return m, nil
+		case "]":
+			m.ResizeDetails(detailsWidthStep)
+			return m, nil
+		case "R":
+			// What the highlighted channel played lately.
+			return m, m.ToggleRecentSongs()
+		case "ctrl+r":
+			// Fresh listener counts and songs without waiting for the
+			// periodic refresh.
+			if m.Directory || m.Refreshing {
+				return m, nil
+			}
+			return m, m.refreshChannelsCmd()
+		case "y":
+			// Copy the selected channel's ID, e.g. for `soma play <id>` scripts.
+			if i, ok := m.List.SelectedItem().(ui.Item); ok {
+				return m, copyCmd("station ID", i.Channel.ID)
+			}
+			return m, nil
 		case "/":
 			// Enter search mode
 			m.Searching = true
@@ -103,12 +275,78 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 		case "f", "*":
-			// Toggle favorite on selected channel
+			// Toggle favorite on selected channel; directory stations
+			// cannot be favorited.
+			if m.Directory {
+				return m, nil
+			}
 			return m, m.ToggleFavorite()
+		case "shift+up", "K", "shift+down", "J":
+			// Move the selected favorite within the pinned order.
+			if m.Directory {
+				return m, nil
+			}
+			if s := msg.String(); s == "shift+up" || s == "K" {
+				return m, m.MoveFavorite(-1)
+			}
+			return m, m.MoveFavorite(1)
+		case "F":
+			// Show only favorites; combines with the genre filter.
+			if m.Directory {
+				return m, nil
+			}
+			m.Filters.FavoritesOnly = !m.Filters.FavoritesOnly
+			m.applyFilters()
+			return m, nil
+		case "e":
+			// Cycle the genre filter through the catalog's genres, then off.
+			if m.Directory {
+				return m, nil
+			}
+			m.Filters.Genre = m.nextGenre()
+			m.applyFilters()
+			return m, nil
+		case "g":
+			// Pick a genre from the catalog grouped by genre.
+			m.OpenGenres()
+			return m, nil
+		case "x":
+			// Clear every filter at once.
+			if m.Filters.Active() {
+				m.Filters = Filters{}
+				m.applyFilters()
+				return m, nil
+			}
+		case "Q":
+			// Cycle the stream quality: highest, high, low.
+			quality := nextQuality[m.Snapshot.StreamQuality]
+			m.Notice = "Stream quality: " + quality
+			return m, m.setQualityCmd(quality)
+		case "O":
+			// Cycle the channel order: api, alphabetical, listeners, genre,
+			// recent.
+			return m, m.CycleSort()
+		case "S":
+			// Cycle the station source: each one, then all merged.
+			return m, m.CycleSource()
+		case "E":
+			m.OpenEqualizer()
+			return m, nil
+		case "I":
+			// Stream statistics: bitrate, bytes, uptime, reconnects, buffer.
+			return m, m.ToggleStats()
 		case "+", "=":
 			return m, m.setVolumeCmd(m.Snapshot.Volume + volumeStep)
 		case "-", "_":
 			return m, m.setVolumeCmd(m.Snapshot.Volume - volumeStep)
+		case "m":
+			return m, m.toggleMuteCmd()
+		case "<":
+			return m, m.timeShiftCmd(timeShiftStep, false)
+		case ">":
+			return m, m.timeShiftCmd(-timeShiftStep, false)
+		case "L":
+			return m, m.timeShiftCmd(0, true)
 		case "c":
 			// Clear search
 			if m.SearchQuery != "" {
@@ -123,12 +361,82 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.UpdateListSize()
 		return m, nil
 
+	case ClipboardMsg:
+		if msg.Err != nil {
+			m.RequestErr = fmt.Sprintf("copy failed: %v", msg.Err)
+			return m, nil
+		}
+		m.Notice = fmt.Sprintf("Copied %s: %s", msg.What, msg.Text)
+		return m, nil
+
+	case BrowserMsg:
+		if msg.Err != nil {
+			m.RequestErr = fmt.Sprintf("opening the browser failed: %v", msg.Err)
+			return m, nil
+		}
+		m.Notice = "Searching the web for " + msg.Query
+		return m, nil
+
+	case splashDoneMsg:
+		m.Splash = false
+		return m, nil
+
 	case ServerStateMsg:
+		prevTrack := m.Snapshot.TrackTitle
 		m.applySnapshot(msg.State)
+		return m, tea.Batch(m.spin(), m.pollLevel(), m.refreshHistory(prevTrack), m.refreshArtwork(prevTrack))
+
+	case levelMsg:
+		return m, m.applyLevel(msg)
+
+	case spinner.TickMsg:
+		return m, m.applySpinnerTick(msg)
+
+	case statsMsg:
+		return m, m.applyStats(msg)
+
+	case historyMsg:
+		m.applyHistory(msg)
+		return m, nil
+
+	case lovedMsg:
+		m.applyLoved(msg)
+		return m, nil
+
+	case artworkMsg:
+		m.applyArtwork(msg)
+		return m, nil
+
+	case channelArtMsg:
+		m.applyChannelArt(msg)
+		return m, nil
+
+	case previewTickMsg:
+		return m, m.startPreview(msg)
+
+	case prebufferTickMsg:
+		return m, m.startPrebuffer(msg)
+
+	case recentTickMsg:
+		return m, m.startRecentSongs(msg)
+
+	case recentSongsMsg:
+		m.applyRecentSongs(msg)
 		return m, nil
 
 	case ServerChannelsMsg:
+		// Hold background refreshes while the query is being typed; the
+		// newest one is applied when the input closes.
+		if m.Searching && !m.Loading {
+			payload := msg.Payload
+			m.pendingChannels = &payload
+			return m, nil
+		}
 		m.applyChannels(msg.Payload)
+		return m, m.fetchDetails()
+
+	case StationsMsg:
+		m.applyStations(msg)
 		return m, nil
 
 	case FavoritesMsg:
@@ -143,6 +451,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Err = msg.Err
 			return m, nil
 		}
+		if msg.Op == opSearchStations {
+			m.DirectoryLoading = false
+		}
 		m.RequestErr = fmt.Sprintf("%s failed: %v", msg.Op, msg.Err)
 		return m, nil
 
@@ -180,7 +491,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Update the list component and return its command
 	var cmd tea.Cmd
 	m.List, cmd = m.List.Update(msg)
-	return m, cmd
+	return m, tea.Batch(cmd, m.trackHover())
 }
 
 // NewHelpKeys returns additional help keys for the list.
@@ -191,10 +502,43 @@ func NewHelpKeys(shutdownOnExit bool) ([]key.Binding, []key.Binding) {
 	}
 	fullHelp := []key.Binding{
 		key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "stop")),
+		key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pause/resume")),
 		key.NewBinding(key.WithKeys("f"), key.WithHelp("f/*", "toggle favorite")),
+		key.NewBinding(key.WithKeys("K"), key.WithHelp("⇧↑/⇧↓", "move favorite (also K/J)")),
 		key.NewBinding(key.WithKeys("+"), key.WithHelp("+/-", "volume")),
+		key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "mute")),
+		key.NewBinding(key.WithKeys("<"), key.WithHelp("</>", "rewind/forward 30s")),
+		key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "back to live")),
+		key.NewBinding(key.WithKeys("Q"), key.WithHelp("Q", "stream quality")),
+		key.NewBinding(key.WithKeys("E"), key.WithHelp("E", "equalizer")),
+		key.NewBinding(key.WithKeys("I"), key.WithHelp("I", "stream statistics")),
 		key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
 		key.NewBinding(key.WithKeys("n"), key.WithHelp("n/N", "next/prev match")),
+		key.NewBinding(key.WithKeys("F"), key.WithHelp("F", "favorites only")),
+		key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "cycle genre filter")),
+		key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "browse genres")),
+		key.NewBinding(key.WithKeys("O"), key.WithHelp("O", "cycle sort order")),
+		key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "switch station source")),
+		key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "refresh channels")),
+		key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "clear filters")),
+		key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "station directory")),
+		key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "what's on (favorites)")),
+		key.NewBinding(key.WithKeys("h"), key.WithHelp("h", "track history panel")),
+		key.NewBinding(key.WithKeys("H"), key.WithHelp("H", "recently played channels")),
+		key.NewBinding(key.WithKeys("T"), key.WithHelp("T", "listening time")),
+		key.NewBinding(key.WithKeys("l"), key.WithHelp("l", "love track")),
+		key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "loved tracks")),
+		key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "search track on the web")),
+		key.NewBinding(key.WithKeys("A"), key.WithHelp("A", "now playing + cover")),
+		key.NewBinding(key.WithKeys("P"), key.WithHelp("P", "full-screen now playing")),
+		key.NewBinding(key.WithKeys("G"), key.WithHelp("G", "grid of cards")),
+		key.NewBinding(key.WithKeys("z"), key.WithHelp("z", "compact mode")),
+		key.NewBinding(key.WithKeys("R"), key.WithHelp("R", "recent songs on channel")),
+		key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "channel details")),
+		key.NewBinding(key.WithKeys("[", "]"), key.WithHelp("[/]", "narrow/widen details")),
+		key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy station ID")),
+		key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "local time / UTC")),
+		key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "description / genres")),
 		key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "about")),
 		key.NewBinding(key.WithKeys("q"), key.WithHelp("q", quitHelp)),
 	}
@@ -208,3 +552,15 @@ func NewHelpKeys(shutdownOnExit bool) ([]key.Binding, []key.Binding) {
 
 	return fullHelp, shortHelp
 }
+
+// NewListKeyMap returns the channel list's key bindings: the list's
+// defaults without the letters Update claims for itself (g, G, h, l, d and
+// f), which would otherwise still be offered in the help for paging.
+func NewListKeyMap() list.KeyMap {
+	km := list.DefaultKeyMap()
+	km.PrevPage = key.NewBinding(key.WithKeys("left", "pgup", "b", "u"), key.WithHelp("←/pgup", "prev page"))
+	km.NextPage = key.NewBinding(key.WithKeys("right", "pgdown"), key.WithHelp("→/pgdn", "next page"))
+	km.GoToStart = key.NewBinding(key.WithKeys("home"), key.WithHelp("home", "go to start"))
+	km.GoToEnd = key.NewBinding(key.WithKeys("end"), key.WithHelp("end", "go to end"))
+	return km
+}
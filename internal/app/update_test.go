@@ -2,8 +2,10 @@ package app
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
+	"somad/internal/channels"
 	"somad/internal/protocol"
 	"somad/internal/ui"
 
@@ -132,6 +134,27 @@ func TestUpdate_SearchMode_TypeSpace(t *testing.T) {
 	assert.Equal(t, "groove ", m.SearchQuery)
 }
 
+func TestUpdate_SearchMode_PasteMultipleRunes(t *testing.T) {
+	m := newTestModel(t)
+	m.Searching = true
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("groove")})
+
+	assert.Equal(t, "groove", m.SearchQuery)
+	require.NotEmpty(t, m.SearchMatches)
+	assert.Equal(t, 0, m.List.Index(), "the first match is selected")
+}
+
+func TestUpdate_SearchMode_BracketedPasteDropsControlChars(t *testing.T) {
+	m := newTestModel(t)
+	m.Searching = true
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("drone\tzone\n"), Paste: true})
+
+	assert.True(t, m.Searching, "a pasted newline must not end the search")
+	assert.Equal(t, "dronezone", m.SearchQuery)
+}
+
 func TestUpdate_SearchMode_BackspaceDeletesFullRune(t *testing.T) {
 	m := newTestModel(t)
 	m.Searching = true
@@ -215,6 +238,49 @@ func TestUpdate_StopKey_StopsPlayback(t *testing.T) {
 	assert.Empty(t, m.PlayingID)
 }
 
+func TestUpdate_SpaceKey_Actions(t *testing.T) {
+	playing := protocol.PlaybackState{Status: protocol.StatusPlaying, ChannelID: "dronezone", Volume: 1}
+	tests := []struct {
+		name      string
+		action    SpaceAction
+		state     protocol.PlaybackState
+		wantPlays []string
+		wantStops int
+	}{
+		{"default plays", "", playing, []string{"groovesalad"}, 0},
+		{"play plays", SpacePlay, playing, []string{"groovesalad"}, 0},
+		{"toggle stops while playing", SpaceToggle, playing, nil, 1},
+		{"toggle plays while stopped", SpaceToggle, protocol.PlaybackState{Status: protocol.StatusStopped}, []string{"groovesalad"}, 0},
+		{"none ignores", SpaceNone, playing, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t)
+			m.SpaceAction = tt.action
+			m.applySnapshot(tt.state)
+
+			_, cmd := sendKey(m, ' ')
+			runCmd(cmd)
+
+			assert.Equal(t, tt.wantPlays, backend(m).playIDs)
+			assert.Equal(t, tt.wantStops, backend(m).stops)
+		})
+	}
+}
+
+func TestUpdate_SpaceKey_DismissesAbout(t *testing.T) {
+	for _, action := range []SpaceAction{SpacePlay, SpaceToggle, SpaceNone} {
+		m := newTestModel(t)
+		m.SpaceAction = action
+		m.ShowAbout = true
+
+		_, cmd := sendKey(m, ' ')
+
+		assert.False(t, m.ShowAbout, action)
+		assert.Nil(t, cmd, action)
+	}
+}
+
 func TestUpdate_PlayKey_RestartsSkewedServerThenPlays(t *testing.T) {
 	m := newTestModel(t)
 	m.About.Version = "new"
@@ -298,6 +364,65 @@ func TestUpdate_VolumeKeys_ClampAtBounds(t *testing.T) {
 	assert.InDelta(t, 0.0, m.Snapshot.Volume, 1e-9)
 }
 
+func TestUpdate_PauseKey_TogglesPause(t *testing.T) {
+	m := newTestModel(t)
+	m.applySnapshot(protocol.PlaybackState{Status: protocol.StatusPlaying, ChannelID: "groovesalad"})
+	backend(m).status = m.Snapshot
+
+	_, cmd := sendKey(m, 'p')
+	m.Update(runCmd(cmd))
+	assert.Equal(t, protocol.StatusPaused, m.Snapshot.Status)
+	assert.Equal(t, "groovesalad", m.PlayingID, "a paused channel keeps its marker")
+
+	_, cmd = sendKey(m, 'p')
+	m.Update(runCmd(cmd))
+	assert.Equal(t, protocol.StatusPlaying, m.Snapshot.Status)
+}
+
+func TestUpdate_MuteKey_TogglesMute(t *testing.T) {
+	m := newTestModel(t)
+
+	_, cmd := sendKey(m, 'm')
+	m.Update(runCmd(cmd))
+	assert.True(t, m.Snapshot.Muted)
+
+	_, cmd = sendKey(m, 'm')
+	m.Update(runCmd(cmd))
+	assert.False(t, m.Snapshot.Muted)
+}
+
+func TestUpdate_TimeShiftKeys(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 120
+
+	for range 2 {
+		_, cmd := sendKey(m, '<')
+		m.Update(runCmd(cmd))
+	}
+	assert.InDelta(t, 60, m.Snapshot.Behind, 0.001)
+	assert.Contains(t, m.RenderStatusBar(), "-1:00 behind live")
+
+	_, cmd := sendKey(m, '>')
+	m.Update(runCmd(cmd))
+	assert.InDelta(t, 30, m.Snapshot.Behind, 0.001)
+
+	_, cmd = sendKey(m, 'L')
+	m.Update(runCmd(cmd))
+	assert.Zero(t, m.Snapshot.Behind)
+	assert.NotContains(t, m.RenderStatusBar(), "behind live")
+}
+
+func TestUpdate_QualityKey_CyclesQuality(t *testing.T) {
+	m := newTestModel(t)
+
+	for _, want := range []string{"high", "low", "highest"} {
+		_, cmd := sendKey(m, 'Q')
+		m.Update(runCmd(cmd))
+		assert.Equal(t, want, m.Snapshot.StreamQuality)
+	}
+	assert.Equal(t, []string{"high", "low", "highest"}, backend(m).qualities)
+}
+
 func TestUpdate_FavoriteKey_TogglesSelected(t *testing.T) {
 	m := newTestModel(t)
 	m.List.Select(1) // dronezone
@@ -391,6 +516,26 @@ func TestUpdate_ServerChannelsMsg_SortsFavoritesFirst(t *testing.T) {
 	assert.Equal(t, "secretagent", first.Channel.ID)
 }
 
+func TestUpdate_ServerChannelsMsg_RecomputesSearchMatches(t *testing.T) {
+	m := newTestModel(t)
+	m.SearchQuery = "ambient" // Groove Salad and Drone Zone
+	m.UpdateSearchMatches()
+	m.NextMatch() // on Drone Zone, the second match
+
+	// A refresh re-sorts the list: Secret Agent moves to the top.
+	m.Update(ServerChannelsMsg{Payload: protocol.ChannelsPayload{
+		Channels:  testChannels(),
+		Favorites: []string{"secretagent"},
+	}})
+
+	assert.Equal(t, []int{1, 2}, m.SearchMatches)
+	sel, ok := m.List.SelectedItem().(ui.Item)
+	require.True(t, ok)
+	assert.Equal(t, "dronezone", sel.Channel.ID, "the refresh must not jump back to the first match")
+	assert.Equal(t, 1, m.CurrentMatch)
+	assert.Contains(t, m.RenderSearchBar(), "[2/2]")
+}
+
 func TestUpdate_ServerChannelsMsg_KeepsSelection(t *testing.T) {
 	m := newTestModel(t)
 	m.Loading = false
@@ -406,6 +551,61 @@ func TestUpdate_ServerChannelsMsg_KeepsSelection(t *testing.T) {
 	assert.Equal(t, "secretagent", sel.Channel.ID)
 }
 
+// qualityChannels returns testChannels with playlist qualities set: only
+// Drone Zone and Secret Agent offer a high-quality MP3 stream.
+func qualityChannels() []channels.Channel {
+	chs := testChannels()
+	for i := range chs {
+		quality := "high"
+		if chs[i].ID == "groovesalad" {
+			quality = "low"
+		}
+		chs[i].Playlists[0].Quality = quality
+	}
+	return chs
+}
+
+func listIDs(m *Model) []string {
+	var ids []string
+	for _, item := range m.List.Items() {
+		ids = append(ids, item.(ui.Item).Channel.ID)
+	}
+	return ids
+}
+
+func TestUpdate_ServerChannelsMsg_KeepsQualityFilter(t *testing.T) {
+	m := newTestModel(t)
+	m.Loading = false
+	m.MinQuality = "high"
+	m.applyChannels(protocol.ChannelsPayload{Channels: qualityChannels()})
+	require.Equal(t, []string{"dronezone", "secretagent"}, listIDs(m))
+	m.selectChannelByID("dronezone")
+
+	// A background refresh re-sorts (Secret Agent becomes a favorite).
+	m.Update(ServerChannelsMsg{Payload: protocol.ChannelsPayload{
+		Channels:  qualityChannels(),
+		Favorites: []string{"secretagent"},
+	}})
+
+	assert.Equal(t, []string{"secretagent", "dronezone"}, listIDs(m), "the refresh must not bring back filtered channels")
+	sel, ok := m.List.SelectedItem().(ui.Item)
+	require.True(t, ok)
+	assert.Equal(t, "dronezone", sel.Channel.ID)
+}
+
+func TestCloseDirectory_KeepsQualityFilter(t *testing.T) {
+	m := newTestModel(t)
+	m.Loading = false
+	m.MinQuality = "high"
+	m.applyChannels(protocol.ChannelsPayload{Channels: qualityChannels()})
+
+	m.OpenDirectory()
+	m.applyChannels(protocol.ChannelsPayload{Channels: qualityChannels()}) // refresh while away
+	m.CloseDirectory()
+
+	assert.Equal(t, []string{"dronezone", "secretagent"}, listIDs(m))
+}
+
 func TestUpdate_ServerChannelsMsg_EmptyWithErrorShowsError(t *testing.T) {
 	m := newTestModel(t)
 	m.Loading = true
@@ -468,7 +668,7 @@ func TestUpdate_EnterPlaysAfterStartupFlow(t *testing.T) {
 		Loading:      true,
 		CurrentMatch: -1,
 	}
-	delegate := ui.NewStyledDelegate(&m.PlayingID, m.IsMatch, m.IsFavorite)
+	delegate := ui.NewStyledDelegate(&m.PlayingID, m.IsMatch, m.IsFavorite, m.IsCustom)
 	l := list.New([]list.Item{}, delegate, 0, 0)
 	l.SetShowTitle(false)
 	l.SetFilteringEnabled(false)
@@ -496,3 +696,190 @@ func TestInit_FetchesChannelsAndStatus(t *testing.T) {
 	cmd := m.Init()
 	require.NotNil(t, cmd)
 }
+
+// initMsgs runs Init's batch and returns the message of every command in it.
+func initMsgs(t *testing.T, m *Model) []tea.Msg {
+	t.Helper()
+	batch, ok := runCmd(m.Init()).(tea.BatchMsg)
+	require.True(t, ok, "Init returns a batch")
+	var msgs []tea.Msg
+	for _, cmd := range batch {
+		msgs = append(msgs, runCmd(cmd))
+	}
+	return msgs
+}
+
+func TestInit_EntersAltScreenByDefault(t *testing.T) {
+	m := newTestModel(t)
+
+	assert.Contains(t, initMsgs(t, m), tea.EnterAltScreen())
+}
+
+func TestInit_NoAltScreenRendersInline(t *testing.T) {
+	m := newTestModel(t)
+	m.NoAltScreen = true
+
+	msgs := initMsgs(t, m)
+
+	assert.NotContains(t, msgs, tea.EnterAltScreen())
+	assert.Len(t, msgs, 2, "the catalog and status fetches still run")
+}
+
+func TestUpdate_IKeyTogglesGenreLine(t *testing.T) {
+	m := newTestModel(t)
+	showGenre := &m.ShowGenre // what the delegate reads
+
+	sendKey(m, 'i')
+	assert.True(t, *showGenre)
+
+	sendKey(m, 'i')
+	assert.False(t, *showGenre)
+}
+
+func failedSnapshot(id string) ServerStateMsg {
+	return ServerStateMsg{State: protocol.PlaybackState{
+		Status:          protocol.StatusStopped,
+		StreamError:     "connection refused",
+		FailedChannelID: id,
+		Volume:          1,
+	}}
+}
+
+func TestUpdate_StreamFailure_ShowsPrompt(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 200 // keep the status bar on one line
+
+	m.Update(failedSnapshot("dronezone"))
+
+	assert.Equal(t, "dronezone", m.FailedID)
+	assert.Contains(t, m.RenderStatusBar(), "press r to retry")
+}
+
+// With server.reconnect_attempts set, the server stops after the last failed
+// attempt instead of retrying silently; the model must turn that into the
+// actionable prompt rather than a plain "Stopped".
+func TestUpdate_StreamFailure_AfterReconnectsGiveUp(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 200
+	m.Update(ServerStateMsg{State: protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "dronezone", ChannelTitle: "Drone Zone", Volume: 1,
+	}})
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		m.Update(ServerStateMsg{State: protocol.PlaybackState{
+			Status: protocol.StatusReconnecting, ChannelID: "dronezone", ChannelTitle: "Drone Zone",
+			StreamError:      "failed to fetch stream: dial tcp: lookup ice.somafm.com: no such host",
+			ReconnectAttempt: attempt, Volume: 1,
+		}})
+		assert.Empty(t, m.FailedID, "still retrying")
+		assert.Contains(t, m.RenderStatusBar(), fmt.Sprintf("Reconnecting #%d", attempt))
+	}
+
+	m.Update(ServerStateMsg{State: protocol.PlaybackState{
+		Status: protocol.StatusStopped, FailedChannelID: "dronezone", Volume: 1,
+		StreamError: "failed to fetch stream: dial tcp: lookup ice.somafm.com: no such host",
+	}})
+
+	assert.Equal(t, "dronezone", m.FailedID)
+	assert.Empty(t, m.PlayingID)
+	bar := m.RenderStatusBar()
+	assert.Contains(t, bar, "Stopped")
+	assert.Contains(t, bar, "no such host")
+	assert.Contains(t, bar, "press r to retry")
+}
+
+func TestUpdate_StreamFailure_RetryReplaysSameChannel(t *testing.T) {
+	m := newTestModel(t)
+	m.List.Select(0) // the cursor is elsewhere; r must not follow it
+	m.Update(failedSnapshot("dronezone"))
+
+	_, cmd := sendKey(m, 'r')
+	m.Update(runCmd(cmd))
+
+	assert.Equal(t, []string{"dronezone"}, backend(m).playIDs)
+	assert.Empty(t, m.FailedID, "a successful play clears the prompt")
+}
+
+func TestUpdate_StreamFailure_NextPlaysFollowingChannel(t *testing.T) {
+	m := newTestModel(t)
+	m.Update(failedSnapshot("secretagent"))
+
+	_, cmd := sendKey(m, 'n')
+	runCmd(cmd)
+
+	assert.Equal(t, []string{"groovesalad"}, backend(m).playIDs, "next wraps around the list")
+}
+
+func TestUpdate_StreamFailure_NextKeepsSearchMatches(t *testing.T) {
+	m := newTestModel(t)
+	m.SearchQuery = "a"
+	m.UpdateSearchMatches()
+	require.Greater(t, len(m.SearchMatches), 1)
+	first := m.CurrentMatch
+	m.Update(failedSnapshot("secretagent"))
+
+	_, cmd := sendKey(m, 'n')
+
+	assert.Nil(t, runCmd(cmd))
+	assert.Empty(t, backend(m).playIDs, "n steps through the matches instead of playing")
+	assert.NotEqual(t, first, m.CurrentMatch)
+	assert.Equal(t, "secretagent", m.FailedID, "the prompt stays up")
+}
+
+func TestUpdate_StreamFailure_StopClearsPrompt(t *testing.T) {
+	m := newTestModel(t)
+	m.Update(failedSnapshot("dronezone"))
+
+	_, cmd := sendKey(m, 's')
+	m.Update(runCmd(cmd))
+
+	assert.Equal(t, 1, backend(m).stops)
+	assert.Empty(t, m.FailedID)
+}
+
+func TestUpdate_RetryKeyIgnoredWithoutFailure(t *testing.T) {
+	m := newTestModel(t)
+
+	_, cmd := sendKey(m, 'r')
+
+	assert.Nil(t, runCmd(cmd))
+	assert.Empty(t, backend(m).playIDs)
+}
+
+func TestUpdate_ServerChannelsMsg_DeferredWhileTypingSearch(t *testing.T) {
+	m := newTestModel(t)
+	sendKey(m, '/')
+	sendKey(m, 'z')
+
+	m.Update(ServerChannelsMsg{Payload: protocol.ChannelsPayload{
+		Channels:  testChannels(),
+		Favorites: []string{"secretagent"},
+	}})
+
+	first := m.List.Items()[0].(ui.Item)
+	assert.Equal(t, "groovesalad", first.Channel.ID, "the list must not reorder mid-search")
+	assert.Empty(t, m.Favorites)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	first = m.List.Items()[0].(ui.Item)
+	assert.Equal(t, "secretagent", first.Channel.ID, "the deferred refresh applies once the input closes")
+	assert.Equal(t, []string{"secretagent"}, m.Favorites)
+	assert.Equal(t, []int{2}, m.SearchMatches, "matches follow the reordered list")
+	sel := m.List.SelectedItem().(ui.Item)
+	assert.Equal(t, "dronezone", sel.Channel.ID)
+}
+
+func TestUpdate_ServerChannelsMsg_DeferredRefreshAppliedOnCancel(t *testing.T) {
+	m := newTestModel(t)
+	sendKey(m, '/')
+
+	m.Update(ServerChannelsMsg{Payload: protocol.ChannelsPayload{
+		Channels: testChannels()[:2],
+	}})
+	require.Len(t, m.List.Items(), 3)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	assert.Len(t, m.List.Items(), 2)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	"somad/internal/channels"
 	"somad/internal/protocol"
@@ -11,24 +12,76 @@ import (
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // RenderHeader renders the list header with column titles.
 func (m *Model) RenderHeader() string {
-	leftColWidth, listenerColWidth := ui.CalculateColumnWidths(m.List.Width())
+	layout := ui.LayoutColumns(m.List.Width(), m.Delegate.Columns)
 
-	title := ui.TitleStyle.Width(leftColWidth).Render("SomaFM Stations")
-	listenerHeader := lipgloss.NewStyle().
-		Foreground(ui.SubtleColor).
-		Width(listenerColWidth).
-		Align(lipgloss.Right).
-		Render("Listeners")
+	titleText := "SomaFM Stations"
+	if h, ok := sourceHeaders[m.Source]; ok {
+		titleText = h
+	}
+	var blank []string
+	switch {
+	case m.Directory:
+		titleText = "Radio Browser Stations"
+		blank = append(blank, ui.ColumnListeners)
+	case m.Refreshing:
+		titleText += " ↻ refreshing…"
+	case m.Probing:
+		titleText += " ↻ checking streams…"
+	}
+	title := ui.TitleStyle.Width(layout.Title).Render(titleText)
+	columns := layout.Header(lipgloss.NewStyle().Foreground(ui.SubtleColor), blank...)
+	if m.gridActive() {
+		// Each card carries its own details.
+		columns = ""
+	}
+
+	header := lipgloss.JoinHorizontal(lipgloss.Bottom, title, columns)
+	if banner := m.renderOfflineBanner(); banner != "" {
+		header = lipgloss.JoinVertical(lipgloss.Left, header, banner)
+	}
+	return header
+}
 
-	return lipgloss.JoinHorizontal(lipgloss.Bottom, title, listenerHeader)
+// renderOfflineBanner tells the user, while the server cannot reach
+// SomaFM, that the list is the cached one and a retry is underway.
+func (m *Model) renderOfflineBanner() string {
+	if !m.Offline || m.Directory {
+		return ""
+	}
+	text := "⚠ Offline — showing the cached channel list, retrying in the background"
+	if !m.CatalogFetched.IsZero() {
+		text = fmt.Sprintf("⚠ Offline — showing the channel list from %s ago, retrying in the background",
+			formatAge(time.Since(m.CatalogFetched)))
+	}
+	text = ansi.Truncate(text, max(m.List.Width()-2, 1), "…")
+	return lipgloss.NewStyle().Foreground(ui.ErrorColor).Padding(0, 0, 0, 2).Render(text)
 }
 
-// RenderSearchBar renders the search input bar.
+// RenderSearchBar renders the search input bar, followed by a summary of
+// the active catalog filters. In the station directory the directory query
+// takes the bar unless an in-list search is active.
 func (m *Model) RenderSearchBar() string {
+	bar := m.renderQueryBar()
+	if m.Directory || !m.Filters.Active() {
+		return bar
+	}
+	filters := ui.SearchBarStyle.Render("Filter: " + m.Filters.String() + " (x clears)")
+	if bar == "" {
+		return filters
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, bar, filters)
+}
+
+// renderQueryBar renders the in-list search or directory query, if any.
+func (m *Model) renderQueryBar() string {
+	if m.Directory && !m.Searching && m.SearchQuery == "" {
+		return m.renderDirectoryBar()
+	}
 	if m.Searching {
 		matchInfo := ""
 		if len(m.SearchMatches) > 0 {
@@ -51,22 +104,45 @@ func (m *Model) RenderSearchBar() string {
 // RenderStatusBar renders the styled status bar from the latest server
 // playback snapshot.
 func (m *Model) RenderStatusBar() string {
+	style := ui.StatusBarStyle
+	if m.Width > 0 {
+		// Wrap on narrow terminals: the renderer truncates overlong lines,
+		// which would clip exactly the errors this bar exists to show.
+		// UpdateListSize measures the rendered height, so the list shrinks
+		// to make room for the extra lines.
+		style = style.Width(m.Width)
+	}
+	return style.Render(strings.Join(m.statusParts(), "  │  "))
+}
+
+// statusParts returns the status bar's fields, most important first.
+func (m *Model) statusParts() []string {
 	var icon, stateText string
 	var stateStyle lipgloss.Style
 
 	switch m.Snapshot.Status {
 	case protocol.StatusConnecting:
-		icon = "◌"
+		icon = m.connectingIcon("◌")
 		stateText = "Connecting"
+		if m.Snapshot.ChannelTitle != "" {
+			stateText = "Connecting to " + m.Snapshot.ChannelTitle + "…"
+		}
 		stateStyle = ui.StatusConnectingStyle
 	case protocol.StatusReconnecting:
-		icon = "↻"
+		icon = m.connectingIcon("↻")
 		stateText = fmt.Sprintf("Reconnecting #%d", m.Snapshot.ReconnectAttempt)
 		stateStyle = ui.StatusConnectingStyle
 	case protocol.StatusPlaying:
 		icon = "▶"
 		stateText = "Playing"
+		if m.Snapshot.Preview {
+			stateText = "Preview"
+		}
 		stateStyle = ui.StatusPlayingStyle
+	case protocol.StatusPaused:
+		icon = "⏸"
+		stateText = "Paused"
+		stateStyle = ui.StatusConnectingStyle
 	default:
 		icon = "■"
 		stateText = "Stopped"
@@ -76,27 +152,97 @@ func (m *Model) RenderStatusBar() string {
 	// Build the status line
 	parts := []string{stateStyle.Render(icon + " " + stateText)}
 
-	// Add the channel name if playing, connecting, or awaiting a reconnect
-	if m.Snapshot.ChannelTitle != "" {
-		channelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
+	// Show that audio is actually flowing
+	switch {
+	case m.Snapshot.Status != protocol.StatusPlaying:
+	case m.Visualizer && m.Spectrum != nil:
+		parts = append(parts, renderSpectrumBar(m.Spectrum))
+	default:
+		parts = append(parts, renderLevelMeter(m.Level))
+	}
+
+	// Show how far a rewind or a pause left playback behind the live stream
+	if behind := time.Duration(m.Snapshot.Behind * float64(time.Second)); behind >= time.Second {
+		behindStyle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+		parts = append(parts, behindStyle.Render(formatBehind(behind)))
+	}
+
+	// Add the channel name if playing or awaiting a reconnect; connecting
+	// names it already.
+	if m.Snapshot.ChannelTitle != "" && m.Snapshot.Status != protocol.StatusConnecting {
+		channelStyle := lipgloss.NewStyle().Foreground(ui.BrightColor)
 		parts = append(parts, channelStyle.Render(m.Snapshot.ChannelTitle))
+		// The server polls the playing channel's count every minute.
+		if m.Snapshot.Listeners > 0 {
+			listenersStyle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+			parts = append(parts, listenersStyle.Render(ui.FormatCount(m.Snapshot.Listeners)+" listening"))
+		}
 	}
 
 	// Add track info with music note
 	if m.Snapshot.TrackTitle != "" {
 		trackStr := "♫ " + m.Snapshot.TrackTitle
 		parts = append(parts, ui.TrackInfoStyle.Render(trackStr))
+		// The level poll redraws the bar while playing, which keeps the
+		// timer ticking.
+		if !m.Snapshot.TrackStarted.IsZero() {
+			elapsedStyle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
+			parts = append(parts, elapsedStyle.Render(formatElapsed(time.Since(m.Snapshot.TrackStarted))))
+		}
+		if m.Snapshot.TrackLoved {
+			parts = append(parts, lipgloss.NewStyle().Foreground(ui.ErrorColor).Render("♥"))
+		}
+	} else if last := m.placeholderTrack(); last != "" {
+		// Until the stream names its track, the catalog's last known one
+		// stands in, dimmed.
+		parts = append(parts, lipgloss.NewStyle().Foreground(ui.SubtleColor).Render("♫ "+last))
 	}
 
 	// Add stream error if present
 	if m.Snapshot.StreamError != "" {
 		errorStyle := lipgloss.NewStyle().Foreground(ui.ErrorColor)
-		parts = append(parts, errorStyle.Render("Stream error: "+m.Snapshot.StreamError))
+		label := "Stream error: "
+		switch m.Snapshot.StreamErrorKind {
+		case protocol.StreamErrorOffline:
+			label = "Station offline: "
+		case protocol.StreamErrorUnsupported:
+			label = "Stream format not supported: "
+		}
+		parts = append(parts, errorStyle.Render(label+m.Snapshot.StreamError))
+	}
+
+	// Offer a way out of a stream that failed for good.
+	if m.FailedID != "" {
+		parts = append(parts, ui.StatusConnectingStyle.Render("Stream failed — press r to retry, n for next, s to stop"))
 	}
 
 	// Add the volume level
 	volumeStyle := lipgloss.NewStyle().Foreground(ui.SubtleColor)
-	parts = append(parts, volumeStyle.Render(fmt.Sprintf("♪ %d%%", int(math.Round(m.Snapshot.Volume*100)))))
+	volume := fmt.Sprintf("♪ %d%%", int(math.Round(m.Snapshot.Volume*100)))
+	if m.Snapshot.Muted {
+		volume = "🔇 muted"
+	}
+	if m.Snapshot.StreamQuality != "" {
+		volume += " · " + m.Snapshot.StreamQuality
+	}
+	if eq := m.Snapshot.Equalizer; eq != "" && eq != "flat" {
+		volume += " · eq " + eq
+	}
+	parts = append(parts, volumeStyle.Render(volume))
+
+	if m.Notice != "" {
+		parts = append(parts, ui.StatusPlayingStyle.Render(m.Notice))
+	}
+
+	// The list is only as current as the cache while SomaFM is unreachable.
+	if m.CatalogStale && !m.Offline && !m.CatalogFetched.IsZero() && !m.Directory {
+		parts = append(parts, volumeStyle.Render(fmt.Sprintf("data from cache (%s old)", formatAge(time.Since(m.CatalogFetched)))))
+	}
+
+	// Point new users at favorites, unless something more pressing is shown.
+	if m.showFavoritesHint() && m.FailedID == "" && m.RequestErr == "" && m.Notice == "" {
+		parts = append(parts, volumeStyle.Render(favoritesHint))
+	}
 
 	// Surface the last failed request until the server answers successfully.
 	if m.RequestErr != "" {
@@ -109,16 +255,21 @@ func (m *Model) RenderStatusBar() string {
 		warnStyle := lipgloss.NewStyle().Foreground(ui.ErrorColor)
 		parts = append(parts, warnStyle.Render("server connection lost — reconnecting…"))
 	}
+	return parts
+}
 
-	style := ui.StatusBarStyle
-	if m.Width > 0 {
-		// Wrap on narrow terminals: the renderer truncates overlong lines,
-		// which would clip exactly the errors this bar exists to show.
-		// UpdateListSize measures the rendered height, so the list shrinks
-		// to make room for the extra lines.
-		style = style.Width(m.Width)
+// placeholderTrack returns the catalog's last known track of the active
+// channel while the server has not named one yet, or "".
+func (m *Model) placeholderTrack() string {
+	if m.Snapshot.Status == protocol.StatusStopped || m.Snapshot.ChannelID == "" {
+		return ""
 	}
-	return style.Render(strings.Join(parts, "  │  "))
+	for _, ch := range m.catalog {
+		if ch.ID == m.Snapshot.ChannelID {
+			return ch.LastPlaying
+		}
+	}
+	return ""
 }
 
 // RenderAboutFooter renders the about information as an inline footer, styled
@@ -141,12 +292,17 @@ func (m *Model) RenderAboutFooter() string {
 		Render(strings.Repeat("─", width))
 
 	lines := []string{
-		fmt.Sprintf("Soma %s · commit %s · built %s", m.About.Version, m.About.Commit, m.About.Date),
+		fmt.Sprintf("Soma %s · commit %s · built %s", m.About.Version, m.About.Commit, m.buildDate()),
 		"A terminal UI for SomaFM internet radio · MIT License",
+	}
+	if !m.CatalogUpdated.IsZero() {
+		lines = append(lines, "Catalog updated: "+m.formatTime(m.CatalogUpdated))
+	}
+	lines = append(lines,
 		"Author: Samuel Barabas · https://github.com/samuelb/somad",
 		"Not affiliated with SomaFM. Streams provided by somafm.com.",
-		"press a or esc to close",
-	}
+		"press a or esc to close · t toggles local time / UTC",
+	)
 
 	body := lipgloss.NewStyle().
 		Foreground(ui.SubtleColor).
@@ -158,6 +314,16 @@ func (m *Model) RenderAboutFooter() string {
 
 // View renders the application's UI.
 func (m *Model) View() string {
+	if m.Splash {
+		return m.renderSplash()
+	}
+	if m.NowPlayingOpen {
+		return m.renderNowPlaying()
+	}
+	if m.Compact && !m.Loading && m.Err == nil && !m.overlayOpen() {
+		return m.renderCompact()
+	}
+
 	// Display loading message if channels are still being fetched
 	if m.Loading {
 		return ui.LoadingStyle.Render("◌ Loading SomaFM channels...")
@@ -177,7 +343,59 @@ func (m *Model) View() string {
 	if searchBar := m.RenderSearchBar(); searchBar != "" {
 		components = append(components, searchBar)
 	}
-	components = append(components, m.List.View(), m.RenderStatusBar())
+	body := m.List.View()
+	if m.gridActive() {
+		body = m.renderGrid()
+	}
+	switch {
+	case m.historyPaneWidth() > 0:
+		body = lipgloss.JoinHorizontal(lipgloss.Top, body, m.renderHistory(historyWidth))
+	case m.HistoryOpen:
+		body = m.renderHistory(m.Width)
+	}
+	if m.detailsPaneWidth() > 0 {
+		body = lipgloss.JoinHorizontal(lipgloss.Top, body, m.renderDetails())
+	}
+	listBody := body
+	if m.Dashboard {
+		body = m.renderDashboard()
+	}
+	if m.PlayedOpen {
+		body = m.renderPlayed()
+	}
+	if m.ListeningOpen {
+		body = m.renderListening()
+	}
+	if m.EqualizerOpen {
+		body = m.renderEqualizer()
+	}
+	if m.GenresOpen {
+		body = m.renderGenres()
+	}
+	if m.StatsOpen {
+		body = m.renderStats()
+	}
+	if m.LovedOpen {
+		body = m.renderLoved()
+	}
+	_, logo := m.detailsArt()
+	switch {
+	case m.ArtworkOpen:
+		body = m.renderArtwork()
+	case logo != nil && body == listBody:
+		// The channel logo replaces any image drawn before it; clearing
+		// here would remove it whenever a line above it is redrawn.
+	default:
+		// Kitty keeps images apart from the text, so the cover stays up
+		// after the pane closes unless it is cleared.
+		body = ui.ClearImages(m.ImageProtocol) + body
+	}
+	components = append(components, body)
+	// The recent songs follow the list cursor, so they sit right below it.
+	if recent := m.RenderRecentSongs(); recent != "" {
+		components = append(components, recent)
+	}
+	components = append(components, m.RenderStatusBar())
 
 	// Show the about information as an inline footer when active.
 	if about := m.RenderAboutFooter(); about != "" {
@@ -200,12 +418,52 @@ func (m *Model) UpdateListSize() {
 	if about := m.RenderAboutFooter(); about != "" {
 		aboutHeight = lipgloss.Height(about)
 	}
+	recentHeight := 0
+	if recent := m.RenderRecentSongs(); recent != "" {
+		recentHeight = lipgloss.Height(recent)
+	}
 
 	// Total height occupied by elements other than the list itself
-	totalFixedUIHeight := 1 + headerHeight + searchBarHeight + statusBarHeight + aboutHeight + 1
+	totalFixedUIHeight := 1 + headerHeight + searchBarHeight + statusBarHeight + aboutHeight + recentHeight + 1
+
+	// On a terminal shorter than the fixed UI (or before the first size
+	// message) the remainder goes negative; keep at least one row so the
+	// list's pagination math never sees a negative height.
+	listHeight := max(m.Height-totalFixedUIHeight, minListHeight)
 
 	// Update the list's dimensions
-	m.List.SetSize(m.Width, m.Height-totalFixedUIHeight)
+	m.List.SetSize(m.Width-m.detailsPaneWidth()-m.historyPaneWidth(), listHeight)
+}
+
+// minListHeight is the smallest height UpdateListSize gives the list.
+const minListHeight = 1
+
+// ListenerTrend returns how many listeners the channel at idx gained or
+// lost between the two latest catalog refreshes; 0 when it held steady or
+// is not a SomaFM channel.
+func (m *Model) ListenerTrend(idx int) int {
+	items := m.List.Items()
+	if idx < 0 || idx >= len(items) {
+		return 0
+	}
+	if i, ok := items[idx].(ui.Item); ok {
+		return m.ListenerTrends[i.Channel.ID]
+	}
+	return 0
+}
+
+// IsDead reports whether the stream of the channel at idx did not answer
+// the server's latest probe, which the delegate marks.
+func (m *Model) IsDead(idx int) bool {
+	items := m.List.Items()
+	if idx < 0 || idx >= len(items) {
+		return false
+	}
+	if i, ok := items[idx].(ui.Item); ok {
+		_, dead := m.DeadChannels[i.Channel.ID]
+		return dead
+	}
+	return false
 }
 
 // ChannelsToItems converts channels to list items.
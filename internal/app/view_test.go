@@ -3,11 +3,15 @@ package app
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"somad/internal/protocol"
+	"somad/internal/ui"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRenderSearchBar_Active(t *testing.T) {
@@ -82,6 +86,42 @@ func TestRenderStatusBar_Connecting(t *testing.T) {
 	assert.Contains(t, result, "Groove Salad")
 }
 
+func TestRenderStatusBar_ShowsLiveListeners(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 200
+	m.applySnapshot(protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "groovesalad", ChannelTitle: "Groove Salad", Listeners: 1042, Volume: 1,
+	})
+	assert.Contains(t, m.RenderStatusBar(), "1,042 listening")
+
+	m.applySnapshot(protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "custom-x", ChannelTitle: "My Station", Volume: 1,
+	})
+	assert.NotContains(t, m.RenderStatusBar(), "listening", "no count is known")
+}
+
+func TestRenderStatusBar_LastPlayingUntilTheTrackIsKnown(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 200
+	m.catalog[0].LastPlaying = "Tycho - Awake"
+
+	m.applySnapshot(protocol.PlaybackState{
+		Status: protocol.StatusConnecting, ChannelID: "groovesalad", ChannelTitle: "Groove Salad", Volume: 1,
+	})
+	assert.Contains(t, m.RenderStatusBar(), "♫ Tycho - Awake")
+
+	m.applySnapshot(protocol.PlaybackState{
+		Status: protocol.StatusPlaying, ChannelID: "groovesalad", ChannelTitle: "Groove Salad",
+		TrackTitle: "Bonobo - Kerala", Volume: 1,
+	})
+	bar := m.RenderStatusBar()
+	assert.Contains(t, bar, "♫ Bonobo - Kerala")
+	assert.NotContains(t, bar, "Tycho")
+
+	m.applySnapshot(protocol.PlaybackState{Status: protocol.StatusStopped, ChannelID: "groovesalad", Volume: 1})
+	assert.NotContains(t, m.RenderStatusBar(), "Tycho")
+}
+
 func TestRenderStatusBar_ShowsVolume(t *testing.T) {
 	m := newTestModel(t)
 	m.applySnapshot(protocol.PlaybackState{Status: protocol.StatusStopped, Volume: 0.85})
@@ -91,6 +131,26 @@ func TestRenderStatusBar_ShowsVolume(t *testing.T) {
 	assert.Contains(t, result, "♪ 85%")
 }
 
+func TestRenderStatusBar_Paused(t *testing.T) {
+	m := newTestModel(t)
+	m.applySnapshot(protocol.PlaybackState{Status: protocol.StatusPaused, ChannelTitle: "Groove Salad"})
+
+	result := m.RenderStatusBar()
+
+	assert.Contains(t, result, "Paused")
+	assert.Contains(t, result, "Groove Salad")
+}
+
+func TestRenderStatusBar_ShowsMuted(t *testing.T) {
+	m := newTestModel(t)
+	m.applySnapshot(protocol.PlaybackState{Status: protocol.StatusStopped, Volume: 0.85, Muted: true})
+
+	result := m.RenderStatusBar()
+
+	assert.Contains(t, result, "🔇")
+	assert.NotContains(t, result, "♪ 85%")
+}
+
 func TestRenderStatusBar_Reconnecting(t *testing.T) {
 	m := newTestModel(t)
 	m.applySnapshot(protocol.PlaybackState{
@@ -141,6 +201,19 @@ func TestRenderStatusBar_WithStreamError(t *testing.T) {
 	assert.Contains(t, result, "Stream error")
 }
 
+func TestRenderStatusBar_NamesClassifiedStreamErrors(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 200
+
+	m.applySnapshot(protocol.PlaybackState{Status: protocol.StatusStopped, Volume: 1,
+		StreamError: "unexpected status code: 503", StreamErrorKind: protocol.StreamErrorOffline})
+	assert.Contains(t, m.RenderStatusBar(), "Station offline: unexpected status code: 503")
+
+	m.applySnapshot(protocol.PlaybackState{Status: protocol.StatusStopped, Volume: 1,
+		StreamError: "failed to decode mp3", StreamErrorKind: protocol.StreamErrorUnsupported})
+	assert.Contains(t, m.RenderStatusBar(), "Stream format not supported: failed to decode mp3")
+}
+
 func TestRenderStatusBar_WrapsOnNarrowTerminals(t *testing.T) {
 	m := newTestModel(t)
 	m.Width = 30
@@ -179,6 +252,22 @@ func TestRenderHeader_ContainsTitles(t *testing.T) {
 	assert.Contains(t, result, "Listeners")
 }
 
+func TestRenderHeader_ConfiguredColumns(t *testing.T) {
+	m := newTestModel(t)
+	m.Delegate.Columns = []ui.Column{{Name: ui.ColumnGenre}, {Name: ui.ColumnListeners}}
+	m.List.SetDelegate(m.Delegate)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	view := m.View()
+	assert.Contains(t, view, "Genre")
+	assert.Contains(t, view, "Listeners")
+	assert.Contains(t, view, "ambient, space", "the rows follow the header")
+
+	m.Update(tea.WindowSizeMsg{Width: 50, Height: 30})
+	assert.NotContains(t, m.RenderHeader(), "Listeners", "the last column is left out when narrow")
+	assert.Contains(t, m.RenderHeader(), "Genre")
+}
+
 func TestView_Loading(t *testing.T) {
 	m := newTestModel(t)
 	m.Loading = true
@@ -249,3 +338,66 @@ func TestRenderAboutFooter_ContainsVersionInfo(t *testing.T) {
 	assert.Contains(t, result, "MIT")
 	assert.Contains(t, result, "close")
 }
+
+func TestRenderAboutFooter_CatalogUpdated(t *testing.T) {
+	m := newTestModel(t)
+	m.ShowAbout = true
+	m.UTC = true
+
+	assert.NotContains(t, m.RenderAboutFooter(), "Catalog updated", "unknown update time is not shown")
+
+	m.applyChannels(protocol.ChannelsPayload{
+		Channels: testChannels(),
+		Updated:  time.Date(2026, 10, 13, 8, 30, 0, 0, time.UTC),
+	})
+
+	assert.Contains(t, m.RenderAboutFooter(), "Catalog updated: 2026-10-13 08:30 UTC")
+}
+
+func TestListenerTrend_FromChannelsPayload(t *testing.T) {
+	m := newTestModel(t)
+	m.Sort = SortAlphabetical
+	m.applyChannels(protocol.ChannelsPayload{Channels: testChannels(), Trends: map[string]int{"groovesalad": 12}})
+
+	// Alphabetical: dronezone, groovesalad, secretagent.
+	assert.Equal(t, 0, m.ListenerTrend(0))
+	assert.Equal(t, 12, m.ListenerTrend(1))
+	assert.Equal(t, 0, m.ListenerTrend(5), "out of range")
+}
+
+func TestRenderHeader_OfflineBanner(t *testing.T) {
+	m := newTestModel(t)
+	m.Width, m.Height = 120, 30
+	m.UpdateListSize()
+	height := m.List.Height()
+	assert.NotContains(t, m.RenderHeader(), "Offline")
+
+	m.applyChannels(protocol.ChannelsPayload{
+		Channels: testChannels(), Stale: true, Offline: true,
+		Fetched: time.Now().Add(-3 * time.Hour),
+	})
+	assert.Contains(t, m.RenderHeader(), "Offline — showing the channel list from 3h ago")
+	assert.Equal(t, height-1, m.List.Height(), "the banner takes a row from the list")
+	assert.NotContains(t, m.RenderStatusBar(), "data from cache", "the banner already says so")
+
+	m.applyChannels(protocol.ChannelsPayload{Channels: testChannels()})
+	assert.NotContains(t, m.RenderHeader(), "Offline")
+	assert.Equal(t, height, m.List.Height())
+}
+
+func TestRefreshKey_ShowsRefreshingUntilTheCatalogArrives(t *testing.T) {
+	m := newTestModel(t)
+	m.Width = 120
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	require.NotNil(t, cmd)
+	assert.Contains(t, m.RenderHeader(), "refreshing…")
+	runCmd(cmd)
+	assert.Equal(t, 1, backend(m).refreshes)
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	assert.Nil(t, cmd, "one refresh at a time")
+
+	m.applyChannels(protocol.ChannelsPayload{Channels: testChannels()})
+	assert.NotContains(t, m.RenderHeader(), "refreshing…")
+}
@@ -0,0 +1,316 @@
+// Package artwork finds cover art for the playing track. It asks the iTunes
+// Search API first and falls back to MusicBrainz and the Cover Art Archive,
+// keeping what it finds (and what it did not) in the user cache directory so
+// a track is looked up once.
+package artwork
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"somad/internal/atomicfile"
+	"somad/internal/musicbrainz"
+	"somad/internal/security"
+)
+
+const (
+	cacheDirName    = "artwork"
+	appCacheDirName = "somad"
+
+	// maxImageBytes caps a downloaded cover; a 600px JPEG is around 100 KB.
+	maxImageBytes = 1 << 20 // 1 MiB
+
+	// maxResponseBytes caps a search response.
+	maxResponseBytes = 1 << 20 // 1 MiB
+
+	// missTTL is how long a track without art is remembered as such before
+	// it is looked up again, in case a cover has been added since.
+	missTTL = 7 * 24 * time.Hour
+
+	// lookupTimeout bounds the whole lookup, across both sources.
+	lookupTimeout = 20 * time.Second
+)
+
+// maxCacheBytes caps the covers kept on disk; writing past it prunes the
+// least recently used. A variable so tests can shrink it.
+var maxCacheBytes int64 = 50 << 20 // 50 MiB
+
+// Endpoints - exported for testing.
+var (
+	ITunesSearchURL    = "https://itunes.apple.com/search"
+	CoverArtReleaseURL = "https://coverartarchive.org/release"
+)
+
+// ErrNotFound reports that neither source has art for the track.
+var ErrNotFound = errors.New("no artwork found")
+
+// Lookup returns the cover of artist's song as JPEG or PNG bytes, from the
+// cache when it was looked up before. A track no source knows returns
+// ErrNotFound; network failures are not cached, so they are retried on the
+// next lookup.
+func Lookup(artist, song, userAgent string) ([]byte, error) {
+	path, err := cacheFilePath(artist, song)
+	if err != nil {
+		return nil, err
+	}
+	if data, ok := readCache(path); ok {
+		if len(data) == 0 {
+			return nil, ErrNotFound
+		}
+		return data, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+	data, err := fetchITunes(ctx, artist, song, userAgent)
+	if errors.Is(err, ErrNotFound) {
+		data, err = fetchCoverArtArchive(ctx, artist, song, userAgent)
+	}
+	switch {
+	case errors.Is(err, ErrNotFound):
+		writeCache(path, nil)
+		return nil, err
+	case err != nil:
+		return nil, err
+	}
+	writeCache(path, data)
+	return data, nil
+}
+
+// ChannelImage returns the channel logo at imageURL as JPEG or PNG bytes,
+// cached like covers are: a URL that serves no image returns ErrNotFound,
+// and network failures are retried on the next call.
+func ChannelImage(imageURL, userAgent string) ([]byte, error) {
+	path, err := cachePath("channel\x00" + imageURL)
+	if err != nil {
+		return nil, err
+	}
+	if data, ok := readCache(path); ok {
+		if len(data) == 0 {
+			return nil, ErrNotFound
+		}
+		return data, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+	data, err := getImage(ctx, imageURL, userAgent)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		writeCache(path, nil)
+		return nil, err
+	case err != nil:
+		return nil, err
+	}
+	writeCache(path, data)
+	return data, nil
+}
+
+// cacheFilePath names the cache entry for a track. Case and surrounding
+// space do not make a different track.
+func cacheFilePath(artist, song string) (string, error) {
+	return cachePath(strings.ToLower(strings.TrimSpace(artist)) + "\x00" + strings.ToLower(strings.TrimSpace(song)))
+}
+
+// cachePath names the cache entry for key.
+func cachePath(key string) (string, error) {
+	// Check XDG override first (works on all platforms, enables testing)
+	cacheDir := os.Getenv("XDG_CACHE_HOME")
+	if cacheDir == "" {
+		var err error
+		cacheDir, err = os.UserCacheDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user cache directory: %w", err)
+		}
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(cacheDir, appCacheDirName, cacheDirName, hex.EncodeToString(sum[:])), nil
+}
+
+// readCache returns a cached cover, or an empty one for a remembered miss.
+// A miss older than missTTL counts as not cached.
+func readCache(path string) ([]byte, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	if info.Size() == 0 {
+		return nil, time.Since(info.ModTime()) < missTTL
+	}
+	data, err := os.ReadFile(path) // #nosec G304 -- path derived from os.UserCacheDir, not user input
+	if err != nil {
+		return nil, false
+	}
+	// Mark the cover as used, so pruning takes the ones not seen lately.
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return data, true
+}
+
+// writeCache stores a cover, or an empty file for a miss. The cache is an
+// optimization, so a failed write only means looking the track up again.
+func writeCache(path string, data []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil { // #nosec G703 -- path derived from os.UserCacheDir, not user input
+		return
+	}
+	if atomicfile.WriteFile(path, data, 0600) == nil && len(data) > 0 {
+		pruneCache(filepath.Dir(path))
+	}
+}
+
+// pruneCache deletes expired misses from dir, then the least recently used
+// covers until the rest fit in maxCacheBytes.
+func pruneCache(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var (
+		covers []os.FileInfo
+		total  int64
+	)
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if info.Size() == 0 {
+			if time.Since(info.ModTime()) >= missTTL {
+				_ = os.Remove(filepath.Join(dir, info.Name()))
+			}
+			continue
+		}
+		covers = append(covers, info)
+		total += info.Size()
+	}
+	slices.SortFunc(covers, func(a, b os.FileInfo) int { return a.ModTime().Compare(b.ModTime()) })
+	for _, info := range covers {
+		if total <= maxCacheBytes {
+			break
+		}
+		if os.Remove(filepath.Join(dir, info.Name())) == nil {
+			total -= info.Size()
+		}
+	}
+}
+
+// fetchITunes looks the track up in the iTunes Search API and downloads the
+// cover of the first result by the same artist.
+func fetchITunes(ctx context.Context, artist, song, userAgent string) ([]byte, error) {
+	params := url.Values{}
+	params.Set("term", artist+" "+song)
+	params.Set("media", "music")
+	params.Set("entity", "song")
+	params.Set("limit", "5")
+
+	var result struct {
+		Results []struct {
+			ArtistName    string `json:"artistName"`
+			ArtworkURL100 string `json:"artworkUrl100"`
+		} `json:"results"`
+	}
+	if err := getJSON(ctx, ITunesSearchURL+"?"+params.Encode(), userAgent, &result); err != nil {
+		return nil, err
+	}
+	for _, r := range result.Results {
+		if r.ArtworkURL100 == "" || !sameArtist(r.ArtistName, artist) {
+			continue
+		}
+		// The URL names its size; asking for a larger one is the documented
+		// way to get more than 100px.
+		return getImage(ctx, strings.Replace(r.ArtworkURL100, "100x100", "600x600", 1), userAgent)
+	}
+	return nil, ErrNotFound
+}
+
+// fetchCoverArtArchive finds the track's releases on MusicBrainz and
+// downloads the first front cover the Cover Art Archive has for one.
+func fetchCoverArtArchive(ctx context.Context, artist, song, userAgent string) ([]byte, error) {
+	releases, err := musicbrainz.ReleaseIDs(ctx, artist, song, userAgent)
+	if errors.Is(err, musicbrainz.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range releases {
+		data, err := getImage(ctx, CoverArtReleaseURL+"/"+url.PathEscape(id)+"/front-500", userAgent)
+		if errors.Is(err, ErrNotFound) {
+			continue // a release without a cover; try the next
+		}
+		return data, err
+	}
+	return nil, ErrNotFound
+}
+
+// sameArtist reports whether a search result's artist is the one asked for,
+// allowing for "feat." credits on either side.
+func sameArtist(got, want string) bool {
+	got, want = strings.ToLower(got), strings.ToLower(want)
+	return got != "" && (strings.Contains(got, want) || strings.Contains(want, got))
+}
+
+// getJSON fetches rawURL and decodes its JSON body into v.
+func getJSON(ctx context.Context, rawURL, userAgent string, v any) error {
+	body, err := get(ctx, rawURL, userAgent, maxResponseBytes)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode artwork search response: %w", err)
+	}
+	return nil
+}
+
+// getImage downloads a cover, insisting that it is a JPEG or PNG.
+func getImage(ctx context.Context, rawURL, userAgent string) ([]byte, error) {
+	data, err := get(ctx, rawURL, userAgent, maxImageBytes)
+	if err != nil {
+		return nil, err
+	}
+	switch http.DetectContentType(data) {
+	case "image/jpeg", "image/png":
+		return data, nil
+	}
+	return nil, ErrNotFound
+}
+
+// get fetches rawURL, reading at most limit bytes. A 404 is ErrNotFound.
+func get(ctx context.Context, rawURL, userAgent string, limit int64) ([]byte, error) {
+	req, err := security.NewRequest(ctx, rawURL, userAgent)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artwork URL: %w", err)
+	}
+	resp, err := security.HTTPClient.Do(req) // #nosec G704 -- URL validated by security.NewRequest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch artwork: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unexpected status code from %s: %d", req.URL.Host, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artwork response: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("artwork response from %s is too large", req.URL.Host)
+	}
+	return data, nil
+}
@@ -0,0 +1,161 @@
+package artwork
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"somad/internal/musicbrainz"
+	"somad/internal/security/securitytest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCover is a tiny PNG, standing in for a cover download.
+func testCover(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))))
+	return buf.Bytes()
+}
+
+// stubSources points all three endpoints at one test server, which serves
+// the given iTunes and MusicBrainz replies (with "URL" replaced by its own
+// URL) and a cover at /cover/600x600bb.png and /release/rel-1/front-500.
+// It counts requests.
+func stubSources(t *testing.T, itunes, recordings string) *atomic.Int32 {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	securitytest.AllowTestHosts(t)
+	cover := testCover(t)
+	var requests atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/itunes":
+			_, _ = w.Write([]byte(strings.ReplaceAll(itunes, "URL", server.URL)))
+		case "/musicbrainz":
+			_, _ = w.Write([]byte(recordings))
+		case "/cover/600x600bb.png", "/release/rel-1/front-500":
+			_, _ = w.Write(cover)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	prev := [3]string{ITunesSearchURL, musicbrainz.RecordingURL, CoverArtReleaseURL}
+	ITunesSearchURL = server.URL + "/itunes"
+	musicbrainz.RecordingURL = server.URL + "/musicbrainz"
+	CoverArtReleaseURL = server.URL + "/release"
+	t.Cleanup(func() { ITunesSearchURL, musicbrainz.RecordingURL, CoverArtReleaseURL = prev[0], prev[1], prev[2] })
+	return &requests
+}
+
+func TestLookup_ITunesThenCache(t *testing.T) {
+	itunes := `{"results":[
+		{"artistName":"Someone Else","artworkUrl100":"http://127.0.0.1/wrong.png"},
+		{"artistName":"Boards of Canada","artworkUrl100":"URL/cover/100x100bb.png"}]}`
+	requests := stubSources(t, itunes, "")
+
+	data, err := Lookup("Boards of Canada", "Roygbiv", "test")
+	require.NoError(t, err)
+	assert.Equal(t, testCover(t), data)
+	assert.Equal(t, int32(2), requests.Load(), "only the matching artist's cover is downloaded")
+
+	data, err = Lookup("boards of canada ", "ROYGBIV", "test")
+	require.NoError(t, err)
+	assert.Equal(t, testCover(t), data)
+	assert.Equal(t, int32(2), requests.Load(), "the second lookup is served from the cache")
+}
+
+func TestLookup_FallsBackToCoverArtArchive(t *testing.T) {
+	requests := stubSources(t, `{"results":[]}`,
+		`{"recordings":[{"score":100,"releases":[{"id":"rel-0"},{"id":"rel-1"}]}]}`)
+
+	data, err := Lookup("Artist", "Song", "test")
+	require.NoError(t, err)
+	assert.Equal(t, testCover(t), data)
+	// iTunes, MusicBrainz, the coverless rel-0, then rel-1.
+	assert.Equal(t, int32(4), requests.Load())
+}
+
+func TestLookup_RemembersMisses(t *testing.T) {
+	requests := stubSources(t, `{"results":[]}`, `{"recordings":[]}`)
+
+	_, err := Lookup("Artist", "Song", "test")
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = Lookup("Artist", "Song", "test")
+	require.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, int32(2), requests.Load(), "the miss is not looked up again")
+}
+
+func TestLookup_NetworkErrorsAreNotCached(t *testing.T) {
+	requests := stubSources(t, `not json`, "")
+
+	_, err := Lookup("Artist", "Song", "test")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrNotFound)
+	_, err = Lookup("Artist", "Song", "test")
+	require.Error(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestChannelImage_CachesHitsAndMisses(t *testing.T) {
+	requests := stubSources(t, "", "")
+	base := strings.TrimSuffix(ITunesSearchURL, "/itunes")
+
+	for range 2 {
+		data, err := ChannelImage(base+"/cover/600x600bb.png", "test")
+		require.NoError(t, err)
+		assert.Equal(t, testCover(t), data)
+	}
+	for range 2 {
+		_, err := ChannelImage(base+"/img/missing.png", "test")
+		require.ErrorIs(t, err, ErrNotFound)
+	}
+	assert.Equal(t, int32(2), requests.Load(), "each URL is downloaded once")
+}
+
+func TestWriteCache_PrunesLeastRecentlyUsed(t *testing.T) {
+	prev := maxCacheBytes
+	maxCacheBytes = 25
+	t.Cleanup(func() { maxCacheBytes = prev })
+	dir := t.TempDir()
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	old := time.Now().Add(-time.Hour)
+	for i, name := range []string{"a", "b"} {
+		writeCache(path(name), bytes.Repeat([]byte{'x'}, 10))
+		at := old.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, os.Chtimes(path(name), at, at))
+	}
+	writeCache(path("stale-miss"), nil)
+	stale := time.Now().Add(-missTTL - time.Hour)
+	require.NoError(t, os.Chtimes(path("stale-miss"), stale, stale))
+	_, ok := readCache(path("a")) // a is now the most recently used
+	require.True(t, ok)
+
+	writeCache(path("c"), bytes.Repeat([]byte{'x'}, 10))
+	assert.FileExists(t, path("a"))
+	assert.NoFileExists(t, path("b"), "the least recently used cover goes first")
+	assert.FileExists(t, path("c"))
+	assert.NoFileExists(t, path("stale-miss"), "expired misses are dropped")
+}
+
+func TestSameArtist(t *testing.T) {
+	assert.True(t, sameArtist("Boards of Canada", "boards of canada"))
+	assert.True(t, sameArtist("Bonobo feat. Andreya Triana", "Bonobo"))
+	assert.False(t, sameArtist("Bonobo", "Tycho"))
+	assert.False(t, sameArtist("", "Tycho"))
+}
@@ -0,0 +1,125 @@
+package audio
+
+import (
+	"io"
+	"sync"
+)
+
+// streamWindowSize caps how much of the stream is kept, played or not: the
+// time-shift window to rewind into, and the backlog while paused. About
+// eight minutes of a 128 kbps stream, four at 256 kbps.
+const streamWindowSize = 8 << 20
+
+// streamBuffer carries the stream from the network fetch to the decoder in
+// place of a pipe. Writes never block, so the fetch keeps draining the
+// connection while the output is paused and neither the stall watchdog nor
+// the station drops it. Bytes already read stay in the buffer for rewinding
+// (see move). Once more than capacity bytes are kept the oldest are
+// discarded, played ones first, so a long pause resumes at most capacity
+// behind the live stream; the decoder resyncs on the next frame after a gap
+// or a jump.
+type streamBuffer struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	data     []byte
+	start    int // oldest byte kept; data[start:off] was read already
+	off      int // read cursor: start of the unread bytes in data
+	capacity int
+	written  int64 // total bytes ever written, for Stats
+	err      error // set by the first close; reads return it once drained
+}
+
+func newStreamBuffer(capacity int) *streamBuffer {
+	b := &streamBuffer{capacity: capacity}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// Write appends p, discarding the oldest bytes beyond capacity. It fails
+// with io.ErrClosedPipe once the buffer is closed.
+func (b *streamBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err != nil {
+		return 0, io.ErrClosedPipe
+	}
+	b.data = append(b.data, p...)
+	b.written += int64(len(p))
+	if len(b.data)-b.start > b.capacity {
+		b.start = len(b.data) - b.capacity
+		b.off = max(b.off, b.start)
+	}
+	// Reclaim the discarded prefix once it dominates, so the backing array
+	// stays bounded by about twice the capacity.
+	if b.start > len(b.data)/2 {
+		n := copy(b.data, b.data[b.start:])
+		b.data = b.data[:n]
+		b.off -= b.start
+		b.start = 0
+	}
+	b.cond.Broadcast()
+	return len(p), nil
+}
+
+// Read blocks until data is available or the buffer is closed; buffered
+// data is still delivered after a close.
+func (b *streamBuffer) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.off == len(b.data) && b.err == nil {
+		b.cond.Wait()
+	}
+	if b.off == len(b.data) {
+		return 0, b.err
+	}
+	n := copy(p, b.data[b.off:])
+	b.off += n
+	return n, nil
+}
+
+// move shifts the read cursor n bytes back into the played bytes, or -n
+// bytes forward over unread ones, within what the buffer holds. It returns
+// how far the cursor actually moved, back being positive.
+func (b *streamBuffer) move(n int) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n = max(min(n, b.off-b.start), b.off-len(b.data))
+	b.off -= n
+	b.cond.Broadcast()
+	return n
+}
+
+// stats returns the total bytes written, the bytes waiting to be read, and
+// the bytes kept in all.
+func (b *streamBuffer) stats() (written int64, buffered, kept int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.written, len(b.data) - b.off, len(b.data) - b.start
+}
+
+// closed reports whether the stream has ended.
+func (b *streamBuffer) closed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.err != nil
+}
+
+// CloseWithError ends the stream: reads return err (io.EOF when nil) after
+// the buffered data. Only the first close has an effect.
+func (b *streamBuffer) CloseWithError(err error) error {
+	if err == nil {
+		err = io.EOF
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err == nil {
+		b.err = err
+	}
+	b.cond.Broadcast()
+	return nil
+}
+
+// Close ends the stream with io.EOF.
+func (b *streamBuffer) Close() error {
+	return b.CloseWithError(nil)
+}
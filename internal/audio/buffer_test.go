@@ -0,0 +1,111 @@
+package audio
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamBuffer_DeliversInOrder(t *testing.T) {
+	b := newStreamBuffer(16)
+	_, _ = b.Write([]byte("abc"))
+	_, _ = b.Write([]byte("def"))
+	require.NoError(t, b.Close())
+
+	data, err := io.ReadAll(b)
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef", string(data))
+}
+
+func TestStreamBuffer_DropsOldestBeyondCapacity(t *testing.T) {
+	b := newStreamBuffer(4)
+	for _, chunk := range []string{"ab", "cd", "ef", "g"} {
+		n, err := b.Write([]byte(chunk))
+		require.NoError(t, err, "writes never block or fail while open")
+		assert.Equal(t, len(chunk), n)
+	}
+	_ = b.Close()
+
+	data, err := io.ReadAll(b)
+	require.NoError(t, err)
+	assert.Equal(t, "defg", string(data))
+}
+
+func TestStreamBuffer_ReadBlocksUntilWrite(t *testing.T) {
+	b := newStreamBuffer(16)
+	got := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 8)
+		n, _ := b.Read(buf)
+		got <- string(buf[:n])
+	}()
+
+	select {
+	case <-got:
+		t.Fatal("read returned before any data arrived")
+	case <-time.After(20 * time.Millisecond):
+	}
+	_, _ = b.Write([]byte("hi"))
+	assert.Equal(t, "hi", <-got)
+}
+
+func TestStreamBuffer_CloseWithErrorAfterData(t *testing.T) {
+	b := newStreamBuffer(16)
+	_, _ = b.Write([]byte("abc"))
+	boom := errors.New("boom")
+	_ = b.CloseWithError(boom)
+	_ = b.Close() // only the first close counts
+
+	data, err := io.ReadAll(b)
+	assert.Equal(t, "abc", string(data))
+	assert.ErrorIs(t, err, boom)
+
+	_, err = b.Write([]byte("x"))
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+}
+
+func TestStreamBuffer_StatsCountWrittenAndWaiting(t *testing.T) {
+	b := newStreamBuffer(4)
+	_, _ = b.Write([]byte("abc"))
+	_, _ = b.Write([]byte("def"))
+	_, _ = b.Read(make([]byte, 1))
+
+	written, buffered, kept := b.stats()
+	assert.Equal(t, int64(6), written, "dropped bytes still count as downloaded")
+	assert.Equal(t, 3, buffered)
+	assert.Equal(t, 4, kept)
+}
+
+func TestStreamBuffer_MoveRewindsIntoPlayedBytes(t *testing.T) {
+	b := newStreamBuffer(16)
+	_, _ = b.Write([]byte("abcdef"))
+	buf := make([]byte, 4)
+	n, _ := b.Read(buf)
+	require.Equal(t, "abcd", string(buf[:n]))
+
+	assert.Equal(t, 2, b.move(2))
+	n, _ = b.Read(buf)
+	assert.Equal(t, "cdef", string(buf[:n]), "rewound bytes play again")
+
+	assert.Equal(t, 6, b.move(10), "cannot rewind past the oldest byte kept")
+	assert.Equal(t, -6, b.move(-10), "cannot skip past the newest byte")
+	_, buffered, _ := b.stats()
+	assert.Equal(t, 0, buffered)
+}
+
+func TestStreamBuffer_PlayedBytesCountTowardCapacity(t *testing.T) {
+	b := newStreamBuffer(4)
+	_, _ = b.Write([]byte("abc"))
+	_, _ = b.Read(make([]byte, 3))
+	_, _ = b.Write([]byte("de"))
+
+	assert.Equal(t, 2, b.move(5), "only the newest capacity bytes are kept")
+	_ = b.Close()
+	data, err := io.ReadAll(b)
+	require.NoError(t, err)
+	assert.Equal(t, "bcde", string(data))
+}
@@ -0,0 +1,138 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"slices"
+	"sync/atomic"
+)
+
+// eqBands are the equalizer's center frequencies in Hz.
+var eqBands = []float64{60, 230, 910, 3600, 14000}
+
+// eqQ is the bandwidth of each band's peaking filter; at 1 neighbouring
+// bands overlap enough to shape the spectrum smoothly.
+const eqQ = 1.0
+
+// eqPreset is a named set of gains in dB, one per band.
+type eqPreset struct {
+	name  string
+	gains []float64
+}
+
+// eqPresets are the built-in equalizer presets. "flat" bypasses the filters
+// entirely, so it is bit-exact.
+var eqPresets = []*eqPreset{
+	{name: "flat", gains: []float64{0, 0, 0, 0, 0}},
+	{name: "bass", gains: []float64{6, 4, 0, 0, 0}},
+	{name: "speech", gains: []float64{-6, -2, 2, 4, 0}},
+}
+
+// EqualizerPresets returns the names of the built-in equalizer presets.
+func EqualizerPresets() []string {
+	names := make([]string, len(eqPresets))
+	for i, p := range eqPresets {
+		names[i] = p.name
+	}
+	return names
+}
+
+// ValidEqualizer reports whether name is a built-in equalizer preset.
+func ValidEqualizer(name string) bool {
+	return slices.Contains(EqualizerPresets(), name)
+}
+
+// findPreset returns the preset called name, falling back to flat.
+func findPreset(name string) *eqPreset {
+	for _, p := range eqPresets {
+		if p.name == name {
+			return p
+		}
+	}
+	return eqPresets[0]
+}
+
+// biquad is one channel's peaking filter for one band (RBJ audio EQ
+// cookbook), in direct form I.
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+func newPeaking(freq, gainDB float64) biquad {
+	a := math.Pow(10, gainDB/40)
+	w0 := 2 * math.Pi * freq / sampleRate
+	alpha := math.Sin(w0) / (2 * eqQ)
+	a0 := 1 + alpha/a
+	return biquad{
+		b0: (1 + alpha*a) / a0,
+		b1: -2 * math.Cos(w0) / a0,
+		b2: (1 - alpha*a) / a0,
+		a1: -2 * math.Cos(w0) / a0,
+		a2: (1 - alpha/a) / a0,
+	}
+}
+
+func (f *biquad) process(x float64) float64 {
+	y := f.b0*x + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+	f.x2, f.x1 = f.x1, x
+	f.y2, f.y1 = f.y1, y
+	return y
+}
+
+// equalizer applies the equalizer preset to stereo 16-bit PCM on its way
+// to the output player. It follows preset, which the player swaps when the
+// preset changes, so a running stream switches without a reconnect. Only the
+// output player's goroutine runs it.
+type equalizer struct {
+	preset *atomic.Pointer[eqPreset]
+
+	active  *eqPreset
+	filters [2][]biquad // per channel, one filter per band
+	preamp  float64     // attenuation that keeps boosted bands from clipping
+}
+
+// newEqReader returns r equalized with the preset the pointer holds.
+func newEqReader(r io.Reader, preset *atomic.Pointer[eqPreset]) io.Reader {
+	return &frameReader{r: r, f: &equalizer{preset: preset}}
+}
+
+// process equalizes b in place, rebuilding the filters if the preset changed.
+func (e *equalizer) process(b []byte) {
+	preset := e.preset.Load()
+	if preset != e.active {
+		e.use(preset)
+	}
+	if preset == nil || preset.name == "flat" {
+		return
+	}
+	for i := 0; i+pcmFrame <= len(b); i += pcmFrame {
+		for ch := range 2 {
+			off := i + 2*ch
+			x := float64(int16(binary.LittleEndian.Uint16(b[off:]))) * e.preamp
+			for k := range e.filters[ch] {
+				x = e.filters[ch][k].process(x)
+			}
+			y := int16(math.Max(math.MinInt16, math.Min(math.MaxInt16, math.Round(x))))
+			binary.LittleEndian.PutUint16(b[off:], uint16(y))
+		}
+	}
+}
+
+// use builds fresh filters for preset.
+func (e *equalizer) use(preset *eqPreset) {
+	e.active = preset
+	if preset == nil {
+		return
+	}
+	peak := 0.0
+	for ch := range 2 {
+		e.filters[ch] = e.filters[ch][:0]
+		for k, gain := range preset.gains {
+			e.filters[ch] = append(e.filters[ch], newPeaking(eqBands[k], gain))
+			peak = math.Max(peak, gain)
+		}
+	}
+	e.preamp = math.Pow(10, -peak/20)
+}
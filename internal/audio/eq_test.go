@@ -0,0 +1,82 @@
+package audio
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sine returns a second of a stereo sine at freq Hz and the given amplitude.
+func sine(freq, amp float64) []byte {
+	samples := make([]int16, 0, 2*sampleRate)
+	for i := range sampleRate {
+		v := int16(amp * math.Sin(2*math.Pi*freq*float64(i)/sampleRate))
+		samples = append(samples, v, v)
+	}
+	return pcm(samples...)
+}
+
+// equalize runs b through the equalizer on the named preset and returns the
+// RMS level of the second half, past the filters' settling.
+func equalize(t *testing.T, name string, b []byte) float64 {
+	t.Helper()
+	var preset atomic.Pointer[eqPreset]
+	preset.Store(findPreset(name))
+	out, err := io.ReadAll(newEqReader(bytes.NewReader(b), &preset))
+	require.NoError(t, err)
+	require.Len(t, out, len(b))
+
+	l := &levelReader{r: bytes.NewReader(out[len(out)/2:])}
+	_, err = io.ReadAll(l)
+	require.NoError(t, err)
+	return l.Level()
+}
+
+func TestEqualizer_FlatIsBitExact(t *testing.T) {
+	in := sine(440, 10000)
+	var preset atomic.Pointer[eqPreset]
+	preset.Store(findPreset("flat"))
+
+	out, err := io.ReadAll(newEqReader(bytes.NewReader(in), &preset))
+	require.NoError(t, err)
+	assert.Equal(t, in, out)
+}
+
+func TestEqualizer_BassBoostsLowsOverHighs(t *testing.T) {
+	low, high := sine(60, 8000), sine(8000, 8000)
+
+	assert.Greater(t, equalize(t, "bass", low)/equalize(t, "flat", low),
+		1.5*equalize(t, "bass", high)/equalize(t, "flat", high))
+}
+
+func TestEqualizer_SpeechCutsRumble(t *testing.T) {
+	low, mid := sine(60, 8000), sine(3000, 8000)
+
+	assert.Less(t, equalize(t, "speech", low), equalize(t, "flat", low))
+	assert.Greater(t, equalize(t, "speech", mid), equalize(t, "speech", low))
+}
+
+func TestEqualizer_CarriesSplitFrames(t *testing.T) {
+	in := sine(440, 10000)
+	var preset atomic.Pointer[eqPreset]
+	preset.Store(findPreset("bass"))
+	want, err := io.ReadAll(newEqReader(bytes.NewReader(in), &preset))
+	require.NoError(t, err)
+
+	// One byte per read splits every frame; the output must not change.
+	got, err := io.ReadAll(newEqReader(iotest.OneByteReader(bytes.NewReader(in)), &preset))
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestFindPreset_UnknownIsFlat(t *testing.T) {
+	assert.Equal(t, "flat", findPreset("loudness").name)
+	assert.True(t, ValidEqualizer("speech"))
+	assert.False(t, ValidEqualizer("loudness"))
+}
@@ -0,0 +1,71 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// levelHold is how long a measured level stays current. The output player
+// pulls continuously while playing, so an older reading means it stopped
+// (paused, stalled, or torn down) and the level reads as silence.
+const levelHold = 250 * time.Millisecond
+
+// levelReader taps the decoded PCM on its way to the output player and keeps
+// the RMS level of the latest read, before volume is applied: it shows that
+// audio is flowing even when muted or turned down.
+type levelReader struct {
+	r     io.Reader
+	level atomic.Uint64 // math.Float64bits of the RMS level in [0, 1]
+	at    atomic.Int64  // UnixNano of the latest reading
+	// carry holds the odd byte of a sample split across reads. Only the
+	// output player's goroutine reads, so it needs no locking.
+	carry    byte
+	hasCarry bool
+}
+
+func (l *levelReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	if n > 0 {
+		l.measure(p[:n])
+	}
+	return n, err
+}
+
+// measure records the RMS level of the 16-bit little-endian samples in b.
+func (l *levelReader) measure(b []byte) {
+	var sum float64
+	count := 0
+	if l.hasCarry {
+		v := float64(int16(binary.LittleEndian.Uint16([]byte{l.carry, b[0]})))
+		sum += v * v
+		count++
+		b = b[1:]
+		l.hasCarry = false
+	}
+	for ; len(b) >= 2; b = b[2:] {
+		v := float64(int16(binary.LittleEndian.Uint16(b)))
+		sum += v * v
+		count++
+	}
+	if len(b) == 1 {
+		l.carry, l.hasCarry = b[0], true
+	}
+	if count == 0 {
+		return
+	}
+	rms := math.Sqrt(sum/float64(count)) / math.MaxInt16
+	l.level.Store(math.Float64bits(math.Min(rms, 1)))
+	l.at.Store(time.Now().UnixNano())
+}
+
+// Level returns the latest RMS level in [0, 1], or 0 once it is older than
+// levelHold.
+func (l *levelReader) Level() float64 {
+	if time.Since(time.Unix(0, l.at.Load())) > levelHold {
+		return 0
+	}
+	return math.Float64frombits(l.level.Load())
+}
@@ -0,0 +1,60 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pcm encodes 16-bit samples as little-endian bytes.
+func pcm(samples ...int16) []byte {
+	var b bytes.Buffer
+	_ = binary.Write(&b, binary.LittleEndian, samples)
+	return b.Bytes()
+}
+
+func TestLevelReader_MeasuresRMS(t *testing.T) {
+	l := &levelReader{r: bytes.NewReader(pcm(16384, -16384, 16384, -16384))}
+	_, err := io.ReadAll(l)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 0.5, l.Level(), 0.001)
+}
+
+func TestLevelReader_SilenceIsZero(t *testing.T) {
+	l := &levelReader{r: bytes.NewReader(pcm(0, 0, 0, 0))}
+	_, _ = io.ReadAll(l)
+
+	assert.Zero(t, l.Level())
+}
+
+func TestLevelReader_JoinsSamplesSplitAcrossReads(t *testing.T) {
+	data := pcm(-32767, -32767)
+	l := &levelReader{r: bytes.NewReader(data)}
+	buf := make([]byte, 3)
+	n, _ := l.Read(buf)
+	require.Equal(t, 3, n)
+	n, _ = l.Read(buf)
+	require.Equal(t, 1, n)
+
+	// The second read is the tail of a full-scale sample, not a byte of
+	// noise.
+	assert.InDelta(t, 1.0, l.Level(), 0.001)
+}
+
+func TestLevelReader_StaleLevelReadsAsSilence(t *testing.T) {
+	l := &levelReader{r: bytes.NewReader(pcm(16384, 16384))}
+	_, _ = io.ReadAll(l)
+	l.at.Store(time.Now().Add(-2 * levelHold).UnixNano())
+
+	assert.Zero(t, l.Level())
+}
+
+func TestLevel_IdlePlayerIsZero(t *testing.T) {
+	assert.Zero(t, newTestPlayer().Level())
+}
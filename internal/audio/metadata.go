@@ -3,13 +3,47 @@ package audio
 import (
 	"bufio"
 	"fmt"
+	"html"
 	"io"
 	"strings"
 )
 
 // TrackInfo represents the current track information from ICY metadata.
+// Title is the StreamTitle as sent; Artist and Song are split out of it
+// (see newTrackInfo).
 type TrackInfo struct {
-	Title string
+	Title  string
+	Artist string // empty when the title names no artist
+	Song   string // the whole title when it names no artist
+	Album  string // only known from the SomaFM songs API; ICY titles name none
+}
+
+// titleSeparators divide artist from song in a StreamTitle. Stations
+// overwhelmingly use " - "; a few typeset a dash.
+var titleSeparators = []string{" - ", " – ", " — "}
+
+// newTrackInfo splits a StreamTitle of the usual "Artist - Song" form at
+// its first separator, so a song title containing a dash stays whole. A
+// title without one, or with nothing on either side of it, is all song.
+func newTrackInfo(title string) TrackInfo {
+	info := TrackInfo{Title: title, Song: title}
+	cut := -1
+	sep := ""
+	for _, s := range titleSeparators {
+		if i := strings.Index(title, s); i >= 0 && (cut < 0 || i < cut) {
+			cut, sep = i, s
+		}
+	}
+	if cut < 0 {
+		return info
+	}
+	artist := strings.TrimSpace(title[:cut])
+	song := strings.TrimSpace(title[cut+len(sep):])
+	if artist == "" || song == "" {
+		return info
+	}
+	info.Artist, info.Song = artist, song
+	return info
 }
 
 // icyDemuxer strips the ICY metadata blocks that Shoutcast/Icecast servers
@@ -108,7 +142,13 @@ func parseICYMetadata(metaStr string) (TrackInfo, error) {
 		title = strings.TrimSuffix(title, "'")
 	}
 
-	return TrackInfo{
-		Title: strings.TrimSpace(title),
-	}, nil
+	return newTrackInfo(cleanTitle(title)), nil
+}
+
+// cleanTitle tidies a StreamTitle for display: some stations send HTML
+// entities such as "&amp;" or "&#39;", and runs of spaces, tabs or line
+// breaks, so entities are decoded and whitespace collapsed to single
+// spaces.
+func cleanTitle(title string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(title)), " ")
 }
@@ -76,6 +76,18 @@ func TestParseICYMetadata(t *testing.T) {
 			want:    "Sepalcure - Me; Only Us",
 			wantErr: false,
 		},
+		{
+			name:    "HTML entities",
+			input:   "StreamTitle='Simon &amp; Garfunkel - Mrs. Robinson&#39;s Song';",
+			want:    "Simon & Garfunkel - Mrs. Robinson's Song",
+			wantErr: false,
+		},
+		{
+			name:    "inner whitespace collapsed",
+			input:   "StreamTitle='Artist  -\tSong\r\nEdit';",
+			want:    "Artist - Song Edit",
+			wantErr: false,
+		},
 		{
 			name:    "semicolon inside title as final field",
 			input:   "StreamTitle='Artist - A; B';",
@@ -100,6 +112,29 @@ func TestParseICYMetadata(t *testing.T) {
 	}
 }
 
+func TestNewTrackInfo(t *testing.T) {
+	tests := []struct {
+		name, title, artist, song string
+	}{
+		{"artist and song", "Artist - Song", "Artist", "Song"},
+		{"en dash", "Café del Mar – Música", "Café del Mar", "Música"},
+		{"em dash", "Artist — Song", "Artist", "Song"},
+		{"no separator", "Station ID", "", "Station ID"},
+		{"first separator wins", "Artist - Song - Remix", "Artist", "Song - Remix"},
+		{"earliest separator wins", "Artist – Song - Edit", "Artist", "Song - Edit"},
+		{"hyphenated name", "Jay-Z - Song", "Jay-Z", "Song"},
+		{"empty artist", " - Song", "", " - Song"},
+		{"empty song", "Artist - ", "", "Artist - "},
+		{"empty", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newTrackInfo(tt.title)
+			assert.Equal(t, TrackInfo{Title: tt.title, Artist: tt.artist, Song: tt.song}, got)
+		})
+	}
+}
+
 // icyStreamBuilder assembles a synthetic Shoutcast body: audio segments of
 // exactly icyInt bytes, each followed by a metadata block.
 type icyStreamBuilder struct {
@@ -0,0 +1,424 @@
+package audio
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"somad/internal/security"
+)
+
+// mpvProtocols are the only protocols mpv's FFmpeg may open, so a stream
+// cannot send it to a local file or another of FFmpeg's protocols.
+const mpvProtocols = "protocol_whitelist=[http,https,tcp,tls]"
+
+// mpvStartTimeout bounds how long Play waits for mpv to start playing: to
+// bring up its IPC socket, connect, and begin audio. A variable so tests can
+// shrink it.
+var mpvStartTimeout = 30 * time.Second
+
+// MPVPlayer plays streams through an external mpv process instead of the
+// built-in decoder and audio output, for systems where oto cannot drive the
+// sound device. It speaks mpv's JSON IPC protocol over a Unix socket:
+// pause, volume and now-playing titles work as with AudioPlayer, and a
+// stream mpv gives up on is reported on Errors so the server reconnects.
+// mpv owns the decoded audio, so there is no level, equalizer, time shift,
+// prebuffering or loudness normalization, and Stats reports only the
+// connect time.
+type MPVPlayer struct {
+	path      string // the mpv executable
+	userAgent string
+	errChan   chan error
+	trackChan chan TrackInfo
+
+	mu      sync.Mutex
+	current *mpvSession // the active session, guarded by mu
+	playGen uint64      // bumped by every Play/Stop so stale starts never commit
+	volume  float64     // target volume in [0, 1], guarded by mu
+}
+
+// mpvSession is one mpv process playing one stream.
+type mpvSession struct {
+	ctx       context.Context
+	cancel    context.CancelFunc // kills the process
+	connected time.Time
+	title     string // the latest stream title, guarded by the player's mu
+
+	mu   sync.Mutex // serializes commands on conn
+	conn net.Conn
+}
+
+// mpvMessage is an event or a command reply from mpv; replies carry no
+// Event and are ignored.
+type mpvMessage struct {
+	Event     string          `json:"event"`
+	Reason    string          `json:"reason"`     // end-file: eof, error, quit…
+	FileError string          `json:"file_error"` // end-file with reason error
+	Name      string          `json:"name"`       // property-change
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewMPVPlayer finds mpv on the PATH. It fails when mpv is not installed.
+func NewMPVPlayer(userAgent string) (*MPVPlayer, error) {
+	path, err := exec.LookPath("mpv")
+	if err != nil {
+		return nil, fmt.Errorf("mpv not found: %w", err)
+	}
+	return newMPVPlayer(path, userAgent), nil
+}
+
+func newMPVPlayer(path, userAgent string) *MPVPlayer {
+	return &MPVPlayer{
+		path:      path,
+		userAgent: userAgent,
+		errChan:   make(chan error, 2),
+		trackChan: make(chan TrackInfo, 1),
+		volume:    1,
+	}
+}
+
+// Play starts an mpv process for url and blocks until it plays audio; the
+// previous process is stopped once the new one has taken over. Like
+// AudioPlayer.Play, a Play or Stop arriving meanwhile wins and this one
+// returns ErrSuperseded. mpv only gets url once followStream has checked
+// it.
+func (p *MPVPlayer) Play(url string) error {
+	p.mu.Lock()
+	p.playGen++
+	gen := p.playGen
+	volume := p.volume
+	p.mu.Unlock()
+
+	url, err := followStream(url, p.userAgent)
+	if err != nil {
+		return err
+	}
+	dir, err := os.MkdirTemp("", "soma-mpv-")
+	if err != nil {
+		return fmt.Errorf("failed to create the mpv socket directory: %w", err)
+	}
+	socket := filepath.Join(dir, "ipc.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, p.path, // #nosec G204 -- mpv from PATH; the URL comes after "--"
+		"--no-config", "--ytdl=no",
+		"--no-video", "--no-terminal", "--idle=no", "--cache=yes",
+		"--stream-lavf-o="+mpvProtocols, "--demuxer-lavf-o="+mpvProtocols,
+		"--input-ipc-server="+socket,
+		"--user-agent="+p.userAgent,
+		"--volume="+strconv.FormatFloat(volume*100, 'f', 0, 64),
+		"--", url)
+	if err := cmd.Start(); err != nil {
+		cancel()
+		_ = os.RemoveAll(dir)
+		return fmt.Errorf("failed to start mpv: %w", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		_ = os.RemoveAll(dir)
+		close(exited)
+	}()
+
+	s := &mpvSession{ctx: ctx, cancel: cancel}
+	started := make(chan error, 1)
+	if err := s.connect(socket, exited); err != nil {
+		cancel()
+		return err
+	}
+	go p.watch(s, started)
+	// A failed write means mpv is going away; watch reports why.
+	_ = s.command("observe_property", 1, "metadata")
+
+	select {
+	case err = <-started:
+	case <-time.After(mpvStartTimeout):
+		err = &StreamError{Kind: StreamOffline, Err: fmt.Errorf("mpv did not start playing within %s", mpvStartTimeout)}
+	}
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	p.mu.Lock()
+	if gen != p.playGen {
+		p.mu.Unlock()
+		cancel()
+		return ErrSuperseded
+	}
+	s.connected = time.Now()
+	old := p.current
+	p.current = s
+	// Titles buffered from the previous channel must not leak into this
+	// one; one mpv read while starting is published now.
+	p.drainTrackUpdates()
+	if s.title != "" {
+		p.reportTrack(newTrackInfo(s.title))
+	}
+	p.mu.Unlock()
+
+	if old != nil {
+		old.cancel()
+	}
+	return nil
+}
+
+// followStream opens url as AudioPlayer would, through the client
+// NewStreamRequest picks for it, which checks the URL and every redirect
+// (and for a station every address it dials), and returns where the stream
+// ended up. mpv is handed that URL, as it would follow a redirect anywhere,
+// the local network included. A variable so tests can avoid the network.
+var followStream = func(url, userAgent string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mpvStartTimeout)
+	defer cancel()
+	req, client, err := security.NewStreamRequest(ctx, url, userAgent)
+	if err != nil {
+		return "", fmt.Errorf("invalid stream URL: %w", err)
+	}
+	resp, err := client.Do(req) // #nosec G704 -- URL validated by security.NewStreamRequest()
+	if err != nil {
+		return "", &StreamError{Kind: StreamOffline, Err: fmt.Errorf("failed to fetch stream: %w", err)}
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", &StreamError{Kind: StreamOffline, Err: fmt.Errorf("unexpected status code: %d", resp.StatusCode)}
+	}
+	return resp.Request.URL.String(), nil
+}
+
+// connect dials mpv's IPC socket, retrying until mpv has created it. It
+// gives up when mpv exits first or takes longer than mpvStartTimeout.
+func (s *mpvSession) connect(socket string, exited <-chan struct{}) error {
+	deadline := time.Now().Add(mpvStartTimeout)
+	for {
+		conn, err := net.Dial("unix", socket)
+		if err == nil {
+			s.conn = conn
+			go func() {
+				<-exited
+				_ = conn.Close()
+			}()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("failed to connect to mpv: %w", err)
+		}
+		select {
+		case <-exited:
+			return &StreamError{Kind: StreamOffline, Err: errors.New("mpv exited before playing")}
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// command sends an mpv IPC command; mpv's reply is read (and ignored) by
+// watch.
+func (s *mpvSession) command(args ...any) error {
+	b, err := json.Marshal(map[string][]any{"command": args})
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.conn.Write(append(b, '\n'))
+	return err
+}
+
+// watch reads mpv's events until the process goes away. It reports the
+// start of playback, or why it failed, on started; once playing, a stream
+// that ends is reported on Errors unless the session was stopped.
+func (p *MPVPlayer) watch(s *mpvSession, started chan<- error) {
+	playing := false
+	fail := func(err error) {
+		if playing {
+			p.reportError(s.ctx, err)
+		} else {
+			started <- err
+		}
+	}
+	sc := bufio.NewScanner(s.conn)
+	for sc.Scan() {
+		var msg mpvMessage
+		if err := json.Unmarshal(sc.Bytes(), &msg); err != nil {
+			continue
+		}
+		switch msg.Event {
+		case "playback-restart":
+			if !playing {
+				playing = true
+				started <- nil
+			}
+		case "end-file":
+			fail(mpvEndFileError(msg))
+			s.cancel()
+			return
+		case "property-change":
+			if msg.Name != "metadata" {
+				continue
+			}
+			var meta map[string]string
+			if err := json.Unmarshal(msg.Data, &meta); err != nil {
+				continue
+			}
+			p.mu.Lock()
+			if title := cleanTitle(meta["icy-title"]); title != s.title {
+				s.title = title
+				if p.current == s {
+					p.reportTrack(newTrackInfo(title))
+				}
+			}
+			p.mu.Unlock()
+		}
+	}
+	fail(&StreamError{Kind: StreamOffline, Err: errors.New("mpv exited")})
+}
+
+// mpvEndFileError explains an end-file event: an unreadable stream, a
+// failed connection, or a station that ended the stream.
+func mpvEndFileError(msg mpvMessage) error {
+	if msg.Reason != "error" {
+		return &StreamError{Kind: StreamOffline, Err: errors.New("stream ended")}
+	}
+	reason := msg.FileError
+	if reason == "" {
+		reason = "playback failed"
+	}
+	err := fmt.Errorf("mpv: %s", reason)
+	if strings.Contains(reason, "unrecognized file format") || strings.Contains(reason, "no audio") {
+		return &StreamError{Kind: StreamUnsupported, Err: err}
+	}
+	return &StreamError{Kind: StreamOffline, Err: err}
+}
+
+// Stop kills the mpv process and cancels any Play still starting.
+func (p *MPVPlayer) Stop() {
+	p.mu.Lock()
+	p.playGen++
+	old := p.current
+	p.current = nil
+	p.mu.Unlock()
+
+	p.drainTrackUpdates()
+	if old != nil {
+		old.cancel()
+	}
+}
+
+// Pause pauses mpv, which keeps caching the stream so Resume continues
+// where it left off.
+func (p *MPVPlayer) Pause() { p.setPaused(true) }
+
+// Resume continues a paused stream.
+func (p *MPVPlayer) Resume() { p.setPaused(false) }
+
+func (p *MPVPlayer) setPaused(paused bool) {
+	p.mu.Lock()
+	s := p.current
+	p.mu.Unlock()
+	if s != nil {
+		_ = s.command("set_property", "pause", paused)
+	}
+}
+
+// SetVolume sets the volume in [0, 1], live and for later streams.
+func (p *MPVPlayer) SetVolume(v float64) {
+	v = max(0, min(v, 1))
+	p.mu.Lock()
+	p.volume = v
+	s := p.current
+	p.mu.Unlock()
+	if s != nil {
+		_ = s.command("set_property", "volume", v*100)
+	}
+}
+
+// Volume returns the target volume in [0, 1].
+func (p *MPVPlayer) Volume() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.volume
+}
+
+// Stats reports when the active stream started; mpv does not expose the
+// byte counts.
+func (p *MPVPlayer) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.current == nil {
+		return Stats{}
+	}
+	return Stats{Connected: p.current.connected}
+}
+
+// Level is always 0: mpv does not share the decoded audio.
+func (p *MPVPlayer) Level() float64 { return 0 }
+
+// Spectrum is always nil, for the same reason.
+func (p *MPVPlayer) Spectrum(int) []float64 { return nil }
+
+// TimeShift does nothing with mpv.
+func (p *MPVPlayer) TimeShift(time.Duration) {}
+
+// Behind is always 0 with mpv.
+func (p *MPVPlayer) Behind() time.Duration { return 0 }
+
+// SetEqualizer does nothing with mpv, which plays flat.
+func (p *MPVPlayer) SetEqualizer(string) {}
+
+// Prebuffer does nothing with mpv: each Play starts a new process.
+func (p *MPVPlayer) Prebuffer(string) {}
+
+// Errors returns a channel for async stream errors, with the same
+// guarantees as AudioPlayer.Errors.
+func (p *MPVPlayer) Errors() <-chan error {
+	return p.errChan
+}
+
+// TrackUpdates returns a channel carrying now-playing title changes for the
+// active stream.
+func (p *MPVPlayer) TrackUpdates() <-chan TrackInfo {
+	return p.trackChan
+}
+
+// reportTrack publishes a track update, newest wins. Callers hold mu and
+// only report for the active session.
+func (p *MPVPlayer) reportTrack(info TrackInfo) {
+	select {
+	case <-p.trackChan:
+	default:
+	}
+	select {
+	case p.trackChan <- info:
+	default:
+	}
+}
+
+// drainTrackUpdates discards any pending track update.
+func (p *MPVPlayer) drainTrackUpdates() {
+	select {
+	case <-p.trackChan:
+	default:
+	}
+}
+
+// reportError publishes an async stream error unless the session was
+// stopped; it drops the error when the buffer is full.
+func (p *MPVPlayer) reportError(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	select {
+	case p.errChan <- err:
+	default:
+	}
+}
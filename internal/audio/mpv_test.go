@@ -0,0 +1,187 @@
+package audio
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"somad/internal/security/securitytest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain lets the test binary stand in for mpv: with SOMA_FAKE_MPV set it
+// runs fakeMPV instead of the tests.
+func TestMain(m *testing.M) {
+	if os.Getenv("SOMA_FAKE_MPV") != "" {
+		fakeMPV(os.Args[1:])
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeMPV speaks enough of mpv's IPC protocol for the tests, scripted by the
+// URL's host: "ok" plays with a title, "unsupported" fails to decode, and
+// "ends" plays briefly then ends. Its arguments, then the commands it
+// receives, are appended to the file in SOMA_FAKE_MPV_LOG.
+func fakeMPV(args []string) {
+	var socket, url string
+	for i, a := range args {
+		if v, ok := strings.CutPrefix(a, "--input-ipc-server="); ok {
+			socket = v
+		}
+		if a == "--" && i+1 < len(args) {
+			url = args[i+1]
+		}
+	}
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		os.Exit(1)
+	}
+	conn, err := ln.Accept()
+	if err != nil {
+		os.Exit(1)
+	}
+	send := func(msg string) { _, _ = fmt.Fprintln(conn, msg) }
+
+	switch strings.TrimPrefix(url, "http://") {
+	case "ok":
+		send(`{"event":"playback-restart"}`)
+		send(`{"event":"property-change","id":1,"name":"metadata","data":{"icy-title":"Artist &amp; Friend -  Song"}}`)
+	case "unsupported":
+		send(`{"event":"end-file","reason":"error","file_error":"unrecognized file format"}`)
+		return
+	case "ends":
+		send(`{"event":"playback-restart"}`)
+		time.Sleep(50 * time.Millisecond)
+		send(`{"event":"end-file","reason":"eof"}`)
+		return
+	}
+	log, _ := os.OpenFile(os.Getenv("SOMA_FAKE_MPV_LOG"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	_, _ = fmt.Fprintln(log, strings.Join(args, " "))
+	sc := bufio.NewScanner(conn)
+	for sc.Scan() {
+		_, _ = fmt.Fprintln(log, sc.Text())
+		send(`{"error":"success"}`)
+	}
+}
+
+// newFakeMPVPlayer returns an MPVPlayer running fakeMPV, which plays URLs
+// unchecked, and the file its arguments and commands are logged to.
+func newFakeMPVPlayer(t *testing.T) (*MPVPlayer, string) {
+	t.Helper()
+	logPath := filepath.Join(t.TempDir(), "commands")
+	t.Setenv("SOMA_FAKE_MPV", "1")
+	t.Setenv("SOMA_FAKE_MPV_LOG", logPath)
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	prev := followStream
+	followStream = func(url, _ string) (string, error) { return url, nil }
+	t.Cleanup(func() { followStream = prev })
+	p := newMPVPlayer(exe, "soma-test")
+	t.Cleanup(p.Stop)
+	return p, logPath
+}
+
+func TestMPVPlayer_PlaysAndReportsTitles(t *testing.T) {
+	p, logPath := newFakeMPVPlayer(t)
+
+	require.NoError(t, p.Play("http://ok"))
+	select {
+	case ti := <-p.TrackUpdates():
+		assert.Equal(t, "Artist & Friend - Song", ti.Title, "titles are tidied as from ICY metadata")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no title reported")
+	}
+	assert.False(t, p.Stats().Connected.IsZero())
+
+	p.SetVolume(0.5)
+	p.Pause()
+	require.Eventually(t, func() bool {
+		b, _ := os.ReadFile(logPath)
+		return strings.Contains(string(b), `["set_property","pause",true]`)
+	}, 5*time.Second, 10*time.Millisecond)
+	b, _ := os.ReadFile(logPath)
+	lines := strings.SplitN(string(b), "\n", 3)
+	for _, flag := range []string{"--no-config", "--ytdl=no", "--stream-lavf-o=" + mpvProtocols, "--demuxer-lavf-o=" + mpvProtocols} {
+		assert.Contains(t, strings.Fields(lines[0]), flag)
+	}
+	var first struct{ Command []any }
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &first))
+	assert.Equal(t, []any{"observe_property", float64(1), "metadata"}, first.Command)
+	assert.Contains(t, string(b), `["set_property","volume",50]`)
+	assert.InDelta(t, 0.5, p.Volume(), 0.001)
+
+	p.Stop()
+	assert.True(t, p.Stats().Connected.IsZero())
+	select {
+	case err := <-p.Errors():
+		t.Fatalf("stopping reported an error: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestFollowStream_RefusesUnsafeURLs(t *testing.T) {
+	for _, u := range []string{"file:///etc/passwd", "http://10.0.0.5/live", "rtmp://stream.example.org/live"} {
+		_, err := followStream(u, "soma-test")
+		require.Error(t, err, u)
+		assert.Contains(t, err.Error(), "invalid stream URL", u)
+	}
+}
+
+func TestFollowStream_ChecksRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/live", http.StatusFound)
+		case "/metadata":
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+		default:
+			_, _ = w.Write([]byte("audio"))
+		}
+	}))
+	defer srv.Close()
+	securitytest.AllowTestHosts(t)
+
+	got, err := followStream(srv.URL+"/moved", "soma-test")
+	require.NoError(t, err)
+	assert.Equal(t, srv.URL+"/live", got, "mpv gets where the stream ended up")
+
+	_, err = followStream(srv.URL+"/metadata", "soma-test")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "disallowed URL")
+}
+
+func TestMPVPlayer_ClassifiesStartFailures(t *testing.T) {
+	p, _ := newFakeMPVPlayer(t)
+
+	err := p.Play("http://unsupported")
+	var se *StreamError
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, StreamUnsupported, se.Kind)
+	assert.Contains(t, err.Error(), "unrecognized file format")
+}
+
+func TestMPVPlayer_ReportsStreamEnd(t *testing.T) {
+	p, _ := newFakeMPVPlayer(t)
+
+	require.NoError(t, p.Play("http://ends"))
+	select {
+	case err := <-p.Errors():
+		var se *StreamError
+		require.True(t, errors.As(err, &se))
+		assert.Equal(t, StreamOffline, se.Kind)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the end of the stream was not reported")
+	}
+}
@@ -0,0 +1,86 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"sync/atomic"
+)
+
+const (
+	// normTarget is the RMS level loudness normalization aims for, about
+	// -18 dBFS: SomaFM's quieter channels come up, the loud ones go down.
+	normTarget = 0.125
+	// normMinGain and normMaxGain bound the applied gain to ±12 dB, so a
+	// quiet intro or a fade is not pumped up to full level.
+	normMinGain = 0.25
+	normMaxGain = 4
+	// normSilence is the RMS level below which the analysis holds its
+	// estimate rather than treating the gap between tracks as a quiet song.
+	normSilence = 0.003
+	// normWindow is how many seconds of audio the loudness estimate averages
+	// over; normGlide is how quickly the gain follows it.
+	normWindow = 3.0
+	normGlide  = 0.5
+	// normCeiling is the peak the limiter keeps boosted audio under, about
+	// -1 dBFS.
+	normCeiling = 0.89 * math.MaxInt16
+)
+
+// normalizer evens out loudness across channels: it estimates the stream's
+// recent loudness, glides a gain toward normTarget, and limits the peaks so
+// the boost never clips. It runs while enabled is set and passes the audio
+// through untouched otherwise. Only the output player's goroutine runs it.
+type normalizer struct {
+	enabled *atomic.Bool
+
+	meanSquare float64 // loudness estimate; 0 until the first analysis
+	gain       float64
+}
+
+// newNormReader returns r with loudness normalization while enabled is set.
+func newNormReader(r io.Reader, enabled *atomic.Bool) io.Reader {
+	return &frameReader{r: r, f: &normalizer{enabled: enabled, gain: 1}}
+}
+
+func (n *normalizer) process(b []byte) {
+	samples := len(b) / 2
+	if !n.enabled.Load() || samples == 0 {
+		return
+	}
+
+	var sum float64
+	for i := 0; i < len(b); i += 2 {
+		v := float64(int16(binary.LittleEndian.Uint16(b[i:]))) / math.MaxInt16
+		sum += v * v
+	}
+	if ms := sum / float64(samples); ms > normSilence*normSilence {
+		seconds := float64(samples/2) / sampleRate
+		if n.meanSquare == 0 {
+			// A fresh stream starts from its own level, not from silence.
+			n.meanSquare = ms
+			n.gain = n.targetGain()
+		}
+		n.meanSquare += (1 - math.Exp(-seconds/normWindow)) * (ms - n.meanSquare)
+		n.gain += (1 - math.Exp(-seconds/normGlide)) * (n.targetGain() - n.gain)
+	}
+
+	gain := n.gain
+	peak := 0.0
+	for i := 0; i < len(b); i += 2 {
+		peak = math.Max(peak, math.Abs(float64(int16(binary.LittleEndian.Uint16(b[i:])))))
+	}
+	if peak*gain > normCeiling {
+		gain = normCeiling / peak
+	}
+	for i := 0; i < len(b); i += 2 {
+		v := float64(int16(binary.LittleEndian.Uint16(b[i:]))) * gain
+		y := int16(math.Max(math.MinInt16, math.Min(math.MaxInt16, math.Round(v))))
+		binary.LittleEndian.PutUint16(b[i:], uint16(y))
+	}
+}
+
+// targetGain is the gain that brings the loudness estimate to normTarget.
+func (n *normalizer) targetGain() float64 {
+	return math.Max(normMinGain, math.Min(normMaxGain, normTarget/math.Sqrt(n.meanSquare)))
+}
@@ -0,0 +1,68 @@
+package audio
+
+import (
+	"bytes"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// normalize runs b through the normalizer and returns the RMS level of the
+// last quarter, once the gain has settled.
+func normalize(t *testing.T, on bool, b []byte) float64 {
+	t.Helper()
+	var enabled atomic.Bool
+	enabled.Store(on)
+	out, err := io.ReadAll(newNormReader(bytes.NewReader(b), &enabled))
+	require.NoError(t, err)
+	require.Len(t, out, len(b))
+
+	l := &levelReader{r: bytes.NewReader(out[len(out)*3/4:])}
+	_, err = io.ReadAll(l)
+	require.NoError(t, err)
+	return l.Level()
+}
+
+func TestNormalizer_EvensOutLoudness(t *testing.T) {
+	quiet, loud := sine(440, 2000), sine(440, 20000)
+
+	q, l := normalize(t, true, quiet), normalize(t, true, loud)
+
+	assert.InDelta(t, normTarget, q, 0.02, "quiet stream comes up")
+	assert.InDelta(t, normTarget, l, 0.02, "loud stream comes down")
+}
+
+func TestNormalizer_BoostIsBounded(t *testing.T) {
+	// A near-silent stream is lifted by at most normMaxGain.
+	faint := sine(440, 300)
+
+	assert.InDelta(t, normMaxGain*normalize(t, false, faint), normalize(t, true, faint), 0.002)
+}
+
+func TestNormalizer_LimiterKeepsPeaksUnderCeiling(t *testing.T) {
+	var enabled atomic.Bool
+	enabled.Store(true)
+	// Quiet audio with one loud burst: the gain for the quiet part must not
+	// push the burst into clipping.
+	in := append(sine(440, 1500), sine(440, 30000)[:4096]...)
+
+	out, err := io.ReadAll(newNormReader(bytes.NewReader(in), &enabled))
+	require.NoError(t, err)
+	for i := 0; i+1 < len(out); i += 2 {
+		v := int16(uint16(out[i]) | uint16(out[i+1])<<8)
+		require.LessOrEqual(t, float64(v), normCeiling+1)
+		require.GreaterOrEqual(t, float64(v), -normCeiling-1)
+	}
+}
+
+func TestNormalizer_DisabledPassesThrough(t *testing.T) {
+	in := sine(440, 2000)
+	var enabled atomic.Bool
+
+	out, err := io.ReadAll(newNormReader(bytes.NewReader(in), &enabled))
+	require.NoError(t, err)
+	assert.Equal(t, in, out)
+}
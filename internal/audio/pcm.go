@@ -0,0 +1,38 @@
+package audio
+
+import "io"
+
+// pcmFrame is the size of one stereo frame of the 16-bit PCM the decoder
+// produces.
+const pcmFrame = 4
+
+// pcmFilter transforms whole PCM frames in place.
+type pcmFilter interface {
+	process(b []byte)
+}
+
+// frameReader runs the PCM from r through f on its way to the output
+// player. A frame split across reads is held back until the rest arrives,
+// so f only ever sees whole frames; p must hold at least one.
+type frameReader struct {
+	r     io.Reader
+	f     pcmFilter
+	carry []byte // the head of a frame split across reads
+}
+
+func (fr *frameReader) Read(p []byte) (int, error) {
+	if len(p) < pcmFrame {
+		return 0, io.ErrShortBuffer
+	}
+	for {
+		n := copy(p, fr.carry)
+		m, err := fr.r.Read(p[n:])
+		n += m
+		whole := n - n%pcmFrame
+		fr.carry = append(fr.carry[:0], p[whole:n]...)
+		fr.f.process(p[:whole])
+		if whole > 0 || err != nil {
+			return whole, err
+		}
+	}
+}
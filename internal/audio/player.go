@@ -34,15 +34,72 @@ var streamStallTimeout = 30 * time.Second
 // while this one was still connecting; the newer request owns the audio state.
 var ErrSuperseded = errors.New("playback superseded by a newer request")
 
+// StreamErrorKind classifies why a stream could not be played.
+type StreamErrorKind int
+
+const (
+	// StreamOffline means the station could not be reached or refused the
+	// stream: a connect failure or a non-200 response.
+	StreamOffline StreamErrorKind = iota + 1
+	// StreamUnsupported means the station answered, but not with MP3 audio
+	// the decoder can play.
+	StreamUnsupported
+)
+
+// StreamError is a classified stream failure, so callers can tell a station
+// that is down from one whose stream cannot be played. Use errors.As to
+// find it in a wrapped error.
+type StreamError struct {
+	Kind StreamErrorKind
+	Err  error
+}
+
+func (e *StreamError) Error() string { return e.Err.Error() }
+
+func (e *StreamError) Unwrap() error { return e.Err }
+
+// Stats describes the active stream's connection.
+type Stats struct {
+	BytesReceived int64     // stream bytes downloaded so far
+	Connected     time.Time // when playback began; zero while idle
+	Buffered      int       // bytes downloaded but not yet decoded
+	BufferSize    int       // how many bytes the buffer holds at most
+}
+
 // Player is the interface for audio playback operations.
 // This allows mocking the player in tests.
 type Player interface {
 	Play(url string) error
 	Stop()
+	// Pause silences the output while the stream keeps buffering; Resume
+	// continues from the buffered position. Both are no-ops when idle.
+	Pause()
+	Resume()
 	Errors() <-chan error
 	TrackUpdates() <-chan TrackInfo
 	SetVolume(v float64)
 	Volume() float64
+	// Level is the decoded audio level in [0, 1] right now, before volume;
+	// 0 when nothing is playing.
+	Level() float64
+	// Spectrum is the decoded audio's level in bands frequency bands, each
+	// in [0, 1], lowest first, before volume; nil when nothing is playing
+	// or the backend does not share its audio. Recording starts with the
+	// first call, so that one may return nil too.
+	Spectrum(bands int) []float64
+	// Stats reports on the active stream; the zero Stats while idle.
+	Stats() Stats
+	// TimeShift moves playback d further behind the live stream, or toward
+	// it when d is negative, within what the stream buffer still holds.
+	// Behind reports how far behind live playback is.
+	TimeShift(d time.Duration)
+	Behind() time.Duration
+	// SetEqualizer switches the equalizer preset (see EqualizerPresets),
+	// live on a running stream; an unknown name plays flat.
+	SetEqualizer(name string)
+	// Prebuffer starts fetching url without playing it, so a following
+	// Play of url starts at once; an empty url drops the prebuffer.
+	Prebuffer(url string)
 }
 
 // outputPlayer and audioContext are the parts of oto used by AudioPlayer.
@@ -74,12 +131,47 @@ func (c *otoContext) NewPlayer(r io.Reader) outputPlayer {
 // one oto player. After creation, only its managing goroutine (runSession)
 // touches the oto player, which keeps volume changes free of data races.
 type session struct {
-	player   outputPlayer
-	stream   io.Closer
-	cancel   context.CancelFunc // aborts the HTTP fetch goroutine
-	stop     chan struct{}      // closed to request fade-out and teardown
-	stopOnce sync.Once
-	volumeCh chan float64 // volume targets for the session goroutine to apply
+	player    outputPlayer
+	stream    *streamBuffer
+	connected time.Time
+	level     *levelReader       // taps the PCM the player pulls
+	spectrum  *spectrumTap       // keeps that PCM for Spectrum while asked
+	cancel    context.CancelFunc // aborts the HTTP fetch goroutine
+	stop      chan struct{}      // closed to request fade-out and teardown
+	stopOnce  sync.Once
+	volumeCh  chan float64 // volume targets for the session goroutine to apply
+	pauseCh   chan bool    // pause (true) or resume requests, newest wins
+
+	// behind is how far playback trails the live stream, not counting a
+	// pause in progress since pausedAt. Both are guarded by the player's mu.
+	behind   time.Duration
+	pausedAt time.Time
+}
+
+// byteRate estimates the stream's bytes per second from what arrived since
+// connecting; 0 until anything has.
+func (s *session) byteRate() float64 {
+	written, _, _ := s.stream.stats()
+	elapsed := time.Since(s.connected).Seconds()
+	if written == 0 || elapsed <= 0 {
+		return 0
+	}
+	return float64(written) / elapsed
+}
+
+// settleLocked folds a pause in progress into behind and clamps it to the
+// stream the buffer still holds, which a long pause overflows. The player's
+// mu must be held.
+func (s *session) settleLocked() {
+	if !s.pausedAt.IsZero() {
+		now := time.Now()
+		s.behind += now.Sub(s.pausedAt)
+		s.pausedAt = now
+	}
+	if rate := s.byteRate(); rate > 0 {
+		_, _, kept := s.stream.stats()
+		s.behind = min(s.behind, time.Duration(float64(kept)/rate*float64(time.Second)))
+	}
 }
 
 // requestStop signals the session to fade out and release resources.
@@ -101,6 +193,19 @@ func (s *session) setVolume(v float64) {
 	}
 }
 
+// setPaused hands a pause or resume request to the session goroutine,
+// replacing any pending one so the newest request wins.
+func (s *session) setPaused(paused bool) {
+	select {
+	case <-s.pauseCh:
+	default:
+	}
+	select {
+	case s.pauseCh <- paused:
+	default:
+	}
+}
+
 // AudioPlayer manages the audio playback for SomaFM streams.
 type AudioPlayer struct {
 	userAgent string
@@ -123,6 +228,14 @@ type AudioPlayer struct {
 	sessions int      // committed sessions still fading or playing, guarded by mu
 	playGen  uint64   // bumped by every Play/Stop so stale connects never commit
 	volume   float64  // target volume in [0, 1], guarded by mu
+	standby  *standby // a stream prebuffered for a later Play, guarded by mu
+	// eq is the equalizer preset every session's equalizer follows.
+	eq atomic.Pointer[eqPreset]
+	// normalize enables loudness normalization in every session.
+	normalize atomic.Bool
+	// silenceTimeout is how long a stream may play only silence before it
+	// is reported as dead, in nanoseconds; 0 disables the check.
+	silenceTimeout atomic.Int64
 }
 
 // audioReadyTimeout bounds how long the first Play waits for the audio device.
@@ -203,32 +316,49 @@ func (p *AudioPlayer) Play(url string) error {
 	p.mu.Lock()
 	p.playGen++
 	gen := p.playGen
+	sb := p.takeStandbyLocked(url)
 	p.mu.Unlock()
 
-	// Create a pipe to connect the HTTP stream to the MP3 decoder.
-	pr, pw := io.Pipe()
-	ctx, cancel := context.WithCancel(context.Background())
+	// Buffer the HTTP stream on its way to the MP3 decoder, so it keeps
+	// arriving while playback is paused. A prebuffered stream already is.
+	var buf *streamBuffer
+	var ctx context.Context
+	var cancel context.CancelFunc
+	connected := time.Now()
+	if sb != nil {
+		buf, ctx, cancel, connected = sb.stream, sb.ctx, sb.cancel, sb.started
+	} else {
+		buf = newStreamBuffer(streamWindowSize)
+		ctx, cancel = context.WithCancel(context.Background())
+		go p.fetchStream(ctx, url, buf)
+	}
 
 	discard := func() {
 		cancel()
-		_ = pr.Close()
-		_ = pw.Close()
+		_ = buf.Close()
 	}
 
-	go p.fetchStream(ctx, url, pw)
-
-	// Decode the MP3 stream from the pipe reader. This is the only synchronous
+	// Decode the MP3 stream from the buffer. This is the only synchronous
 	// failure mode, so the new session is not committed until decoding succeeds.
-	decoder, err := mp3.NewDecoder(pr)
+	src := &sourceReader{r: buf}
+	decoder, err := mp3.NewDecoder(src)
 	if err != nil {
 		discard()
-		return fmt.Errorf("failed to decode mp3: %w", err)
+		// A fetch failure closes the pipe with its own (classified) error;
+		// only bytes that arrived and did not decode are a format problem.
+		if src.err != nil && !errors.Is(src.err, io.EOF) {
+			return src.err
+		}
+		return &StreamError{Kind: StreamUnsupported, Err: fmt.Errorf("failed to decode mp3: %w", err)}
 	}
 
-	// The oto context runs at a fixed rate; resample if the stream differs.
-	var decodedStream io.Reader = decoder
+	// Skip corrupt frames instead of stopping, and the oto context runs at a
+	// fixed rate; resample if the stream differs.
+	var decodedStream io.Reader = newResyncReader(decoder, src, func(err error) {
+		p.reportError(ctx, err)
+	})
 	if decoder.SampleRate() != sampleRate {
-		decodedStream = newResampler(decoder, decoder.SampleRate(), sampleRate)
+		decodedStream = newResampler(decodedStream, decoder.SampleRate(), sampleRate)
 	}
 	p.mu.Lock()
 	superseded := gen != p.playGen
@@ -264,26 +394,49 @@ func (p *AudioPlayer) Play(url string) error {
 		p.deviceSuspended = false
 	}
 
-	player := p.ctx.NewPlayer(decodedStream)
+	// Dead air is reported like a dropped connection, so the server
+	// reconnects and shows why.
+	decodedStream = newSilenceReader(decodedStream, &p.silenceTimeout, func(silent time.Duration) {
+		p.reportError(ctx, fmt.Errorf("stream appears dead: only silence for %s", silent.Round(time.Second)))
+	})
+	// Equalize, then normalize the result, then measure, so the meter shows
+	// what is heard.
+	shaped := newNormReader(newEqReader(decodedStream, &p.eq), &p.normalize)
+	level := &levelReader{r: shaped}
+	spectrum := &spectrumTap{}
+	player := p.ctx.NewPlayer(&frameReader{r: level, f: spectrum})
 	player.SetVolume(0)
 	player.Play()
 
 	s := &session{
-		player:   player,
-		stream:   pr,
-		cancel:   cancel,
-		stop:     make(chan struct{}),
-		volumeCh: make(chan float64, 1),
+		player:    player,
+		stream:    buf,
+		connected: connected,
+		level:     level,
+		spectrum:  spectrum,
+		cancel:    cancel,
+		stop:      make(chan struct{}),
+		volumeCh:  make(chan float64, 1),
+		pauseCh:   make(chan bool, 1),
 	}
 	old := p.current
 	p.current = s
 	p.sessions++
+	// Titles buffered from the previous channel must not leak into this one;
+	// a prebuffered stream's own title is published now.
+	p.drainTrackUpdates()
+	var heldErr error
+	if sb != nil {
+		var title string
+		title, heldErr = sb.releaseLocked()
+		if title != "" {
+			p.sendTrack(newTrackInfo(title))
+		}
+	}
 	p.mu.Unlock()
 	p.deviceMu.Unlock()
 
-	// Titles buffered from the previous channel must not leak into this one.
-	p.drainTrackUpdates()
-
+	p.reportError(ctx, heldErr)
 	if old != nil {
 		old.requestStop()
 	}
@@ -292,6 +445,13 @@ func (p *AudioPlayer) Play(url string) error {
 	return nil
 }
 
+// streamWriter is the writing end fetchStream feeds: a streamBuffer, or a
+// pipe in tests.
+type streamWriter interface {
+	io.WriteCloser
+	CloseWithError(err error) error
+}
+
 // fetchStream fetches the stream over HTTP and pipes it to the decoder. It
 // requests interleaved ICY metadata so the same connection carries the
 // now-playing titles, which are demuxed out and reported via TrackUpdates.
@@ -302,7 +462,7 @@ func (p *AudioPlayer) Play(url string) error {
 // reporting it here too would leave a stale error queued that could kill a
 // later, healthy session. Once the stream is established, errors are
 // reported asynchronously via the errors channel.
-func (p *AudioPlayer) fetchStream(ctx context.Context, url string, pw *io.PipeWriter) {
+func (p *AudioPlayer) fetchStream(ctx context.Context, url string, pw streamWriter) {
 	defer func() { _ = pw.Close() }()
 
 	// The watchdog aborts the request when the connection goes silent for
@@ -325,22 +485,22 @@ func (p *AudioPlayer) fetchStream(ctx context.Context, url string, pw *io.PipeWr
 		return err
 	}
 
-	req, err := security.NewRequest(reqCtx, url, p.userAgent)
+	req, client, err := security.NewStreamRequest(reqCtx, url, p.userAgent)
 	if err != nil {
 		pw.CloseWithError(fmt.Errorf("invalid stream URL: %w", err))
 		return
 	}
 	req.Header.Set("Icy-MetaData", "1") // Request interleaved ICY metadata
 
-	resp, err := security.HTTPClient.Do(req) // #nosec G704 -- URL validated by security.NewRequest()
+	resp, err := client.Do(req) // #nosec G704 -- URL validated by security.NewStreamRequest()
 	if err != nil {
-		pw.CloseWithError(stallErr(fmt.Errorf("failed to fetch stream: %w", err)))
+		pw.CloseWithError(&StreamError{Kind: StreamOffline, Err: stallErr(fmt.Errorf("failed to fetch stream: %w", err))})
 		return
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		pw.CloseWithError(fmt.Errorf("unexpected status code: %d", resp.StatusCode))
+		pw.CloseWithError(&StreamError{Kind: StreamOffline, Err: fmt.Errorf("unexpected status code: %d", resp.StatusCode)})
 		return
 	}
 
@@ -349,7 +509,7 @@ func (p *AudioPlayer) fetchStream(ctx context.Context, url string, pw *io.PipeWr
 	var body io.Reader = &watchdogReader{r: resp.Body, timer: watchdog, timeout: streamStallTimeout}
 	if icyInt, err := strconv.Atoi(resp.Header.Get("icy-metaint")); err == nil && icyInt > 0 {
 		body = newICYDemuxer(body, icyInt, func(title string) {
-			p.reportTrack(ctx, TrackInfo{Title: title})
+			p.reportTrack(ctx, newTrackInfo(title))
 		})
 	}
 
@@ -393,10 +553,20 @@ func (p *AudioPlayer) TrackUpdates() <-chan TrackInfo {
 
 // reportTrack publishes a track update, replacing any pending one so the
 // newest title wins. Updates from cancelled (superseded) sessions are dropped.
+// It never blocks, so a consumer that stops draining TrackUpdates cannot pin
+// the fetch goroutine (and its connection) past the end of the session.
 func (p *AudioPlayer) reportTrack(ctx context.Context, info TrackInfo) {
 	if ctx != nil && ctx.Err() != nil {
 		return
 	}
+	if p.holdReport(ctx, &info, nil) {
+		return
+	}
+	p.sendTrack(info)
+}
+
+// sendTrack publishes a track update, newest wins, without blocking.
+func (p *AudioPlayer) sendTrack(info TrackInfo) {
 	select {
 	case <-p.trackChan:
 	default:
@@ -425,24 +595,49 @@ func (p *AudioPlayer) Errors() <-chan error {
 }
 
 // runSession owns the session's oto player for its entire lifetime: it fades
-// the volume in, holds (applying volume changes) until a stop is requested,
-// then fades out and releases resources. Because only this goroutine touches
-// s.player after Play, volume changes and teardown never race.
+// the volume in, holds (applying volume changes and pauses) until a stop is
+// requested, then fades out and releases resources. Because only this
+// goroutine touches s.player after Play, volume changes and teardown never
+// race.
 func (p *AudioPlayer) runSession(s *session) {
+	paused := false
 	if p.fadeIn(s) {
-		p.holdSession(s)
+		paused = p.holdSession(s)
 	}
-	p.fadeOutAndClose(s)
+	if !paused {
+		p.fadeOut(s)
+	}
+	p.closeSession(s, paused)
 }
 
-// holdSession applies volume changes until a stop is requested.
-func (p *AudioPlayer) holdSession(s *session) {
+// holdSession applies volume changes and pause requests until a stop is
+// requested, reporting whether the player was paused at that point. While
+// paused the player pulls nothing, so the stream accumulates in its buffer.
+func (p *AudioPlayer) holdSession(s *session) bool {
+	paused := false
 	for {
 		select {
 		case <-s.stop:
-			return
+			return paused
 		case v := <-s.volumeCh:
-			s.player.SetVolume(v)
+			// A resume fades in to the latest target anyway.
+			if !paused {
+				s.player.SetVolume(v)
+			}
+		case pause := <-s.pauseCh:
+			if pause == paused {
+				continue
+			}
+			paused = pause
+			if paused {
+				p.fadeOut(s)
+				s.player.Pause()
+				continue
+			}
+			s.player.Play()
+			if !p.fadeIn(s) {
+				return false
+			}
 		}
 	}
 }
@@ -483,6 +678,131 @@ func (p *AudioPlayer) SetVolume(v float64) {
 	}
 }
 
+// Pause silences the active session without dropping its stream, which
+// keeps buffering (up to streamWindowSize) until Resume.
+func (p *AudioPlayer) Pause() {
+	p.mu.Lock()
+	s := p.current
+	if s != nil && s.pausedAt.IsZero() {
+		s.pausedAt = time.Now()
+	}
+	p.mu.Unlock()
+	if s != nil {
+		s.setPaused(true)
+	}
+}
+
+// Resume continues a paused session from its buffered position.
+func (p *AudioPlayer) Resume() {
+	p.mu.Lock()
+	s := p.current
+	if s != nil {
+		s.settleLocked()
+		s.pausedAt = time.Time{}
+	}
+	p.mu.Unlock()
+	if s != nil {
+		s.setPaused(false)
+	}
+}
+
+// TimeShift moves the active session d further behind the live stream, or
+// toward it when d is negative: the stream buffer's read cursor jumps by
+// d's worth of bytes at the stream's average rate, and the decoder resyncs
+// on the next frame. Audio already handed to the output plays out first.
+func (p *AudioPlayer) TimeShift(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.current
+	if s == nil {
+		return
+	}
+	s.settleLocked()
+	rate := s.byteRate()
+	if rate <= 0 {
+		return
+	}
+	target := max(s.behind+d, 0)
+	moved := s.stream.move(int((target - s.behind).Seconds() * rate))
+	if target == 0 {
+		s.behind = 0 // back at live, whatever the rounding
+		return
+	}
+	s.behind += time.Duration(float64(moved) / rate * float64(time.Second))
+}
+
+// Behind reports how far the active session trails the live stream,
+// including a pause in progress; 0 while idle.
+func (p *AudioPlayer) Behind() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.current
+	if s == nil {
+		return 0
+	}
+	s.settleLocked()
+	return s.behind
+}
+
+// Level returns the audio level of the active session in [0, 1]; 0 while
+// idle or paused.
+func (p *AudioPlayer) Level() float64 {
+	p.mu.Lock()
+	s := p.current
+	p.mu.Unlock()
+	if s == nil {
+		return 0
+	}
+	return s.level.Level()
+}
+
+// Spectrum returns the frequency bands of the active session's audio; nil
+// while idle or paused.
+func (p *AudioPlayer) Spectrum(bands int) []float64 {
+	p.mu.Lock()
+	s := p.current
+	p.mu.Unlock()
+	if s == nil {
+		return nil
+	}
+	return s.spectrum.Spectrum(bands)
+}
+
+// Stats reports on the active session's stream; the zero Stats while idle.
+func (p *AudioPlayer) Stats() Stats {
+	p.mu.Lock()
+	s := p.current
+	p.mu.Unlock()
+	if s == nil {
+		return Stats{}
+	}
+	received, buffered, _ := s.stream.stats()
+	return Stats{
+		BytesReceived: received,
+		Connected:     s.connected,
+		Buffered:      buffered,
+		BufferSize:    s.stream.capacity,
+	}
+}
+
+// SetEqualizer switches the equalizer preset for the active session and all
+// future ones; an unknown name plays flat.
+func (p *AudioPlayer) SetEqualizer(name string) {
+	p.eq.Store(findPreset(name))
+}
+
+// SetNormalize turns loudness normalization on or off, live on a running
+// stream, so switching channels does not jump in volume.
+func (p *AudioPlayer) SetNormalize(on bool) {
+	p.normalize.Store(on)
+}
+
+// SetSilenceTimeout reports a stream that has played only silence for d as
+// failed, so the server reconnects it; 0 disables the check.
+func (p *AudioPlayer) SetSilenceTimeout(d time.Duration) {
+	p.silenceTimeout.Store(int64(d))
+}
+
 // Volume returns the current target volume in [0, 1].
 func (p *AudioPlayer) Volume() float64 {
 	p.mu.Lock()
@@ -490,21 +810,27 @@ func (p *AudioPlayer) Volume() float64 {
 	return p.volume
 }
 
-// fadeOutAndClose gradually lowers the session volume to 0, then pauses the
-// player, closes the stream, and cancels the HTTP fetch.
-func (p *AudioPlayer) fadeOutAndClose(s *session) {
+// fadeOut gradually lowers the session volume to 0.
+func (p *AudioPlayer) fadeOut(s *session) {
 	step := fadeOutDuration / fadeSteps
 	startVolume := s.player.Volume()
 	for i := fadeSteps - 1; i >= 0; i-- {
 		s.player.SetVolume(startVolume * float64(i) / fadeSteps)
 		time.Sleep(step)
 	}
-	s.player.Pause()
-	// Cancel before closing the pipe: with the context already cancelled,
-	// fetchStream suppresses the resulting pipe/read error instead of
+}
+
+// closeSession pauses the player (unless it already is), closes the stream,
+// and cancels the HTTP fetch.
+func (p *AudioPlayer) closeSession(s *session, paused bool) {
+	if !paused {
+		s.player.Pause()
+	}
+	// Cancel before closing the buffer: with the context already cancelled,
+	// fetchStream suppresses the resulting write/read error instead of
 	// reporting a spurious "stream read error" (and triggering an unwanted
-	// reconnect) on a clean stop. Closing second still unblocks a writer
-	// stuck in a pipe write.
+	// reconnect) on a clean stop. Closing second also fails the fetch's next
+	// write, so it stops copying even if the request lingers.
 	s.cancel()
 	_ = s.stream.Close()
 
@@ -551,6 +877,9 @@ func (p *AudioPlayer) reportError(ctx context.Context, err error) {
 	if ctx != nil && ctx.Err() != nil {
 		return
 	}
+	if p.holdReport(ctx, nil, err) {
+		return
+	}
 	// Non-blocking send: if the buffer is full the error is dropped rather than
 	// stalling the session goroutine. See Errors for what a reader can rely on.
 	select {
@@ -29,14 +29,23 @@ func newTestPlayer() *AudioPlayer {
 }
 
 type fakeOutputPlayer struct {
-	mu     sync.Mutex
-	volume float64
-	paused func()
+	mu       sync.Mutex
+	volume   float64
+	stopped  bool // set by Pause until the next Play; no SetVolume meanwhile
+	paused   func()
+	lateCall func() // called for a SetVolume after Pause
 }
 
-func (p *fakeOutputPlayer) Play() {}
+func (p *fakeOutputPlayer) Play() {
+	p.mu.Lock()
+	p.stopped = false
+	p.mu.Unlock()
+}
 
 func (p *fakeOutputPlayer) Pause() {
+	p.mu.Lock()
+	p.stopped = true
+	p.mu.Unlock()
 	if p.paused != nil {
 		p.paused()
 	}
@@ -44,8 +53,12 @@ func (p *fakeOutputPlayer) Pause() {
 
 func (p *fakeOutputPlayer) SetVolume(v float64) {
 	p.mu.Lock()
+	late := p.stopped
 	p.volume = v
 	p.mu.Unlock()
+	if late && p.lateCall != nil {
+		p.lateCall()
+	}
 }
 
 func (p *fakeOutputPlayer) Volume() float64 {
@@ -55,10 +68,11 @@ func (p *fakeOutputPlayer) Volume() float64 {
 }
 
 type fakeAudioContext struct {
-	suspends atomic.Int32
-	resumes  atomic.Int32
-	players  atomic.Int32
-	pauses   atomic.Int32
+	suspends  atomic.Int32
+	resumes   atomic.Int32
+	players   atomic.Int32
+	pauses    atomic.Int32
+	lateCalls atomic.Int32 // player calls after the player was paused
 
 	mu        sync.Mutex
 	resumeErr error
@@ -67,8 +81,9 @@ type fakeAudioContext struct {
 func (c *fakeAudioContext) NewPlayer(io.Reader) outputPlayer {
 	c.players.Add(1)
 	return &fakeOutputPlayer{
-		volume: 1,
-		paused: func() { c.pauses.Add(1) },
+		volume:   1,
+		paused:   func() { c.pauses.Add(1) },
+		lateCall: func() { c.lateCalls.Add(1) },
 	}
 }
 
@@ -241,6 +256,71 @@ func TestStopDuringCrossfade_WaitsForBothSessionsBeforeSuspend(t *testing.T) {
 	}, time.Second, 10*time.Millisecond)
 }
 
+// Run with -race: Play and Stop racing each other must never let a fade
+// touch a player after its session tore it down, and every session must end.
+func TestPlayStop_RapidAlternationIsRaceFree(t *testing.T) {
+	p, ctx, _ := newLifecycleTestPlayer(t)
+	server := newStreamingTestServer(t)
+	t.Cleanup(p.Stop)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 5 {
+				err := p.Play(server.URL)
+				if err != nil && !errors.Is(err, ErrSuperseded) {
+					t.Errorf("Play: %v", err)
+				}
+				p.SetVolume(0.5)
+				p.Stop()
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Eventually(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.current == nil && p.sessions == 0
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, ctx.players.Load(), ctx.pauses.Load(), "every session paused its player")
+	assert.Zero(t, ctx.lateCalls.Load())
+}
+
+func TestPauseResume_KeepsSessionAndStopsCleanly(t *testing.T) {
+	p, ctx, _ := newLifecycleTestPlayer(t)
+	server := newStreamingTestServer(t)
+	require.NoError(t, p.Play(server.URL))
+
+	p.Pause()
+	require.Eventually(t, func() bool { return ctx.pauses.Load() == 1 }, 2*time.Second, 5*time.Millisecond)
+	p.mu.Lock()
+	assert.NotNil(t, p.current, "pausing keeps the session")
+	p.mu.Unlock()
+
+	p.Resume()
+	p.Pause()
+	require.Eventually(t, func() bool { return ctx.pauses.Load() == 2 }, 2*time.Second, 5*time.Millisecond)
+
+	// Stopping a paused session releases it without touching the player.
+	p.Stop()
+	require.Eventually(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.sessions == 0 && ctx.suspends.Load() == 1
+	}, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(2), ctx.pauses.Load())
+	assert.Zero(t, ctx.lateCalls.Load())
+}
+
+func TestPauseResume_IdleIsNoop(t *testing.T) {
+	p := newTestPlayer()
+	p.Pause()
+	p.Resume()
+}
+
 func TestErrors_ReturnsChannel(t *testing.T) {
 	p := newTestPlayer()
 	assert.NotNil(t, p.Errors())
@@ -505,6 +585,46 @@ func TestFetchStream_RequestsAndDemuxesICYMetadata(t *testing.T) {
 	}
 }
 
+func TestFetchStream_UndrainedTrackUpdatesDoNotBlockStop(t *testing.T) {
+	securitytest.AllowTestHosts(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b := &icyStreamBuilder{icyInt: 8}
+		for _, title := range []string{"One", "Two", "Three"} {
+			b.segment(0xAA, "StreamTitle='"+title+"';")
+		}
+		w.Header().Set("icy-metaint", "8")
+		_, _ = w.Write(b.buf.Bytes())
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		<-r.Context().Done() // keep the stream open until the session stops
+	}))
+	defer server.Close()
+
+	// Nobody drains the title updates: publishing must never block the
+	// fetch goroutine, or it would outlive the session.
+	p := newTestPlayer()
+	p.trackChan <- TrackInfo{Title: "Undrained"}
+	ctx, cancel := context.WithCancel(context.Background())
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		p.fetchStream(ctx, server.URL, pw)
+		close(done)
+	}()
+
+	_, err := io.ReadFull(pr, make([]byte, 3*8))
+	require.NoError(t, err)
+
+	cancel()
+	_ = pr.Close()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fetchStream did not exit after stop with undrained track updates")
+	}
+}
+
 func TestFetchStream_NoICYHeaderPassesThrough(t *testing.T) {
 	securitytest.AllowTestHosts(t)
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -579,7 +699,7 @@ func TestFetchStream_InvalidURL(t *testing.T) {
 	p := newTestPlayer()
 	pr, pw := io.Pipe()
 
-	go p.fetchStream(context.Background(), "http://evil.example.com/stream", pw)
+	go p.fetchStream(context.Background(), "http://169.254.169.254/stream", pw)
 
 	// The pipe reader should observe the error propagated via CloseWithError.
 	_, err := drainPipe(pr)
@@ -609,6 +729,7 @@ func TestFetchStream_BadStatusCode(t *testing.T) {
 	_, err := drainPipe(pr)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "unexpected status code")
+	assertStreamErrorKind(t, err, StreamOffline)
 
 	// Synchronous failures own their error exclusively; see above.
 	select {
@@ -654,3 +775,63 @@ func TestFetchStream_CancelledContextSuppressesReadError(t *testing.T) {
 	default:
 	}
 }
+
+// assertStreamErrorKind checks that err carries a StreamError of kind.
+func assertStreamErrorKind(t *testing.T, err error, kind StreamErrorKind) {
+	t.Helper()
+	var se *StreamError
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, kind, se.Kind)
+}
+
+func TestPlay_ClassifiesStreamFailures(t *testing.T) {
+	securitytest.AllowTestHosts(t)
+	refused := httptest.NewServer(http.NotFoundHandler())
+	refusedURL := refused.URL
+	refused.Close() // nothing listens here any more
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		url     string
+		kind    StreamErrorKind
+		message string
+	}{
+		{
+			name: "connect failure",
+			url:  refusedURL,
+			kind: StreamOffline, message: "failed to fetch stream",
+		},
+		{
+			name: "non-200 status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			kind: StreamOffline, message: "unexpected status code: 503",
+		},
+		{
+			name: "undecodable body",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("<html><body>Station moved</body></html>"))
+			},
+			kind: StreamUnsupported, message: "failed to decode mp3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ctx, _ := newLifecycleTestPlayer(t)
+			url := tt.url
+			if tt.handler != nil {
+				server := httptest.NewServer(tt.handler)
+				t.Cleanup(server.Close)
+				url = server.URL
+			}
+
+			err := p.Play(url)
+			require.Error(t, err)
+			assertStreamErrorKind(t, err, tt.kind)
+			assert.Contains(t, err.Error(), tt.message)
+			assert.Zero(t, ctx.players.Load(), "a failed connect must not start a session")
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package audio
+
+import (
+	"context"
+	"time"
+)
+
+// prebufferLead is how far behind live a prebuffered stream starts once it
+// plays: enough for the decoder to start at once, little enough that the
+// wait on the cursor is not heard as lag. About four seconds at 128 kbps.
+const prebufferLead = 64 << 10
+
+// prebufferTimeout drops a prebuffered stream that never played, so a
+// cursor left resting does not hold a connection open for good. A variable
+// so tests can shrink it.
+var prebufferTimeout = 2 * time.Minute
+
+// standby is a stream fetched ahead of the Play that will want it (see
+// Prebuffer). Until that Play commits it, its titles are kept back and an
+// error just drops it.
+type standby struct {
+	url     string
+	stream  *streamBuffer
+	ctx     context.Context // the fetch's, marked with standbyKey
+	cancel  context.CancelFunc
+	expiry  *time.Timer
+	started time.Time
+
+	// held, title and err are guarded by the player's mu.
+	held  bool   // not playing yet: reports wait
+	title string // the latest title while held
+	err   error  // the error the fetch reported while held
+}
+
+// standbyKey marks a fetch context as a standby's, so its reports can be
+// held back.
+type standbyKey struct{}
+
+// Prebuffer starts fetching url in the background without playing it, so a
+// later Play of the same url starts from the buffered stream at once instead
+// of connecting. It replaces an earlier prebuffer; an empty url just drops
+// it.
+func (p *AudioPlayer) Prebuffer(url string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.standby != nil && p.standby.url == url {
+		return
+	}
+	p.dropStandbyLocked()
+	if url == "" {
+		return
+	}
+	sb := &standby{url: url, stream: newStreamBuffer(streamWindowSize), started: time.Now(), held: true}
+	sb.ctx, sb.cancel = context.WithCancel(context.WithValue(context.Background(), standbyKey{}, sb))
+	sb.expiry = time.AfterFunc(prebufferTimeout, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.standby == sb {
+			p.dropStandbyLocked()
+		}
+	})
+	p.standby = sb
+	go p.fetchStream(sb.ctx, url, sb.stream)
+}
+
+// dropStandbyLocked abandons the prebuffered stream, if any. The player's
+// mu must be held.
+func (p *AudioPlayer) dropStandbyLocked() {
+	sb := p.standby
+	if sb == nil {
+		return
+	}
+	p.standby = nil
+	sb.expiry.Stop()
+	sb.cancel()
+	_ = sb.stream.Close()
+}
+
+// takeStandbyLocked hands Play the prebuffered stream for url, skipped ahead
+// to prebufferLead behind live. It returns nil when there is none for url or
+// the fetch already failed. The player's mu must be held.
+func (p *AudioPlayer) takeStandbyLocked(url string) *standby {
+	sb := p.standby
+	if sb == nil || sb.url != url {
+		return nil
+	}
+	if sb.err != nil || sb.stream.closed() {
+		p.dropStandbyLocked()
+		return nil
+	}
+	p.standby = nil
+	sb.expiry.Stop()
+	if _, buffered, _ := sb.stream.stats(); buffered > prebufferLead {
+		sb.stream.move(prebufferLead - buffered)
+	}
+	return sb
+}
+
+// holdReport keeps a report from a held standby's fetch back: a title is
+// remembered for when it plays, and an error drops the standby, or is
+// reported once it plays when Play already took it. It reports whether the
+// report was held.
+func (p *AudioPlayer) holdReport(ctx context.Context, info *TrackInfo, err error) bool {
+	if ctx == nil {
+		return false
+	}
+	sb, ok := ctx.Value(standbyKey{}).(*standby)
+	if !ok {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !sb.held {
+		return false
+	}
+	if info != nil {
+		sb.title = info.Title
+	}
+	if err != nil {
+		sb.err = err
+		if p.standby == sb {
+			p.dropStandbyLocked()
+		}
+	}
+	return true
+}
+
+// releaseLocked ends the hold on a standby that Play committed, returning
+// the reports held back. The player's mu must be held.
+func (sb *standby) releaseLocked() (title string, err error) {
+	sb.held = false
+	return sb.title, sb.err
+}
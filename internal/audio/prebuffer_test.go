@@ -0,0 +1,115 @@
+package audio
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"somad/internal/security/securitytest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCountingStreamServer serves an MP3 stream carrying the ICY title
+// "Held" and keeps it open. The first failFirst requests get a 404
+// instead. It counts requests and closed connections.
+func newCountingStreamServer(t *testing.T, failFirst int32) (url string, requests, closed *atomic.Int32) {
+	t.Helper()
+	securitytest.AllowTestHosts(t)
+	requests, closed = &atomic.Int32{}, &atomic.Int32{}
+	frames := silentMP3Frames(30)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failFirst {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		meta := "StreamTitle='Held';"
+		var body bytes.Buffer
+		body.Write(frames)
+		body.WriteByte(byte((len(meta) + 15) / 16))
+		body.WriteString(meta)
+		body.Write(make([]byte, (len(meta)+15)/16*16-len(meta)))
+		body.Write(frames)
+		w.Header().Set("icy-metaint", strconv.Itoa(len(frames)))
+		_, _ = w.Write(body.Bytes())
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		<-r.Context().Done()
+		closed.Add(1)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, requests, closed
+}
+
+func TestPrebuffer_PlayTakesOverTheStream(t *testing.T) {
+	url, requests, _ := newCountingStreamServer(t, 0)
+	p, _, _ := newLifecycleTestPlayer(t)
+	t.Cleanup(p.Stop)
+
+	p.Prebuffer(url)
+	require.Eventually(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.standby != nil && p.standby.title == "Held"
+	}, 5*time.Second, 5*time.Millisecond)
+	select {
+	case info := <-p.TrackUpdates():
+		t.Fatalf("a prebuffered title leaked: %q", info.Title)
+	default:
+	}
+
+	require.NoError(t, p.Play(url))
+	assert.Equal(t, int32(1), requests.Load(), "play reuses the prebuffered connection")
+	select {
+	case info := <-p.TrackUpdates():
+		assert.Equal(t, "Held", info.Title)
+	default:
+		t.Fatal("the held title was not published on play")
+	}
+}
+
+func TestPrebuffer_DroppedWhenReplacedOrExpired(t *testing.T) {
+	url, requests, closed := newCountingStreamServer(t, 0)
+	p, _, _ := newLifecycleTestPlayer(t)
+
+	p.Prebuffer(url)
+	require.Eventually(t, func() bool { return requests.Load() == 1 }, 5*time.Second, 5*time.Millisecond)
+	p.Prebuffer("")
+	require.Eventually(t, func() bool { return closed.Load() == 1 }, 5*time.Second, 5*time.Millisecond)
+
+	prev := prebufferTimeout
+	prebufferTimeout = 20 * time.Millisecond
+	t.Cleanup(func() { prebufferTimeout = prev })
+	p.Prebuffer(url)
+	require.Eventually(t, func() bool { return closed.Load() == 2 }, 5*time.Second, 5*time.Millisecond)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	assert.Nil(t, p.standby)
+}
+
+func TestPrebuffer_FailedFetchConnectsAfresh(t *testing.T) {
+	url, requests, _ := newCountingStreamServer(t, 1)
+	p, _, _ := newLifecycleTestPlayer(t)
+	t.Cleanup(p.Stop)
+
+	p.Prebuffer(url)
+	require.Eventually(t, func() bool {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.standby != nil && p.standby.stream.closed()
+	}, 5*time.Second, 5*time.Millisecond)
+
+	require.NoError(t, p.Play(url))
+	assert.Equal(t, int32(2), requests.Load())
+	select {
+	case err := <-p.Errors():
+		t.Fatalf("the failed prebuffer was reported: %v", err)
+	default:
+	}
+}
@@ -0,0 +1,78 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxDecodeResyncs bounds how many corrupt frames in a row are skipped before
+// the stream is given up on. A flaky mirror glitches for a frame or two; a
+// stream that never decodes again is better served by a reconnect.
+const maxDecodeResyncs = 8
+
+// sourceReader records the first error of the stream feeding the decoder, so
+// a decode error can be told apart from the source failing or closing.
+type sourceReader struct {
+	r   io.Reader
+	err error
+}
+
+func (s *sourceReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+	return n, err
+}
+
+// resyncReader wraps the MP3 decoder so a corrupt frame does not end playback.
+// go-mp3 drops the broken frame on error and scans for the next sync word on
+// the following read, so retrying is all it takes to resync. Without this the
+// first bad frame stops the output player for good, leaving silence until the
+// stall watchdog notices the stream.
+type resyncReader struct {
+	dec io.Reader
+	src *sourceReader
+	// onGiveUp reports the error once maxDecodeResyncs is exceeded, so the
+	// stream can be reconnected rather than sitting silent.
+	onGiveUp func(error)
+	failures int
+}
+
+func newResyncReader(dec io.Reader, src *sourceReader, onGiveUp func(error)) *resyncReader {
+	return &resyncReader{dec: dec, src: src, onGiveUp: onGiveUp}
+}
+
+func (r *resyncReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.decode(p)
+		if n > 0 {
+			r.failures = 0
+		}
+		// Errors from the source itself (hang-up, stall, stop) are not
+		// corruption; pass them through untouched like end of stream.
+		if err == nil || n > 0 || errors.Is(err, io.EOF) || r.src.err != nil {
+			return n, err
+		}
+		r.failures++
+		if r.failures > maxDecodeResyncs {
+			err = fmt.Errorf("mp3 decode failed after skipping %d corrupt frames: %w", maxDecodeResyncs, err)
+			if r.onGiveUp != nil {
+				r.onGiveUp(err)
+			}
+			return 0, err
+		}
+	}
+}
+
+// decode reads from the decoder, turning a panic on malformed frame data into
+// an error: it would otherwise crash the daemon from the output goroutine.
+func (r *resyncReader) decode(p []byte) (n int, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			n, err = 0, fmt.Errorf("mp3 decode panic: %v", v)
+		}
+	}()
+	return r.dec.Read(p)
+}
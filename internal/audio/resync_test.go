@@ -0,0 +1,145 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"somad/internal/security/securitytest"
+
+	mp3 "github.com/hajimehoshi/go-mp3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedDecoder replays a fixed sequence of reads, standing in for a
+// decoder that panics or passes on a source error.
+type scriptedDecoder struct {
+	steps []func(p []byte) (int, error)
+}
+
+func (d *scriptedDecoder) Read(p []byte) (int, error) {
+	if len(d.steps) == 0 {
+		return 0, io.EOF
+	}
+	step := d.steps[0]
+	d.steps = d.steps[1:]
+	return step(p)
+}
+
+func scriptData(s string) func([]byte) (int, error) {
+	return func(p []byte) (int, error) { return copy(p, s), nil }
+}
+
+func scriptFail(err error) func([]byte) (int, error) {
+	return func([]byte) (int, error) { return 0, err }
+}
+
+// decodedFrameSize is how many PCM bytes one silentMP3Frames frame decodes
+// to: 1152 stereo 16-bit samples.
+const decodedFrameSize = 1152 * 2 * 2
+
+// corruptMP3Frames returns n frames with a valid header but garbage side
+// info and main data, which go-mp3 fails to decode.
+func corruptMP3Frames(n int) []byte {
+	frame := silentMP3Frames(1)
+	for i := 4; i < len(frame); i++ {
+		frame[i] = byte(i*37 + 11)
+	}
+	return bytes.Repeat(frame, n)
+}
+
+// decodeServed serves stream over HTTP and reads it back the way Play does:
+// fetched into a pipe, decoded by go-mp3 and resynced past corrupt frames.
+func decodeServed(t *testing.T, stream []byte, onGiveUp func(error)) ([]byte, error) {
+	t.Helper()
+	securitytest.AllowTestHosts(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(stream)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	pr, pw := io.Pipe()
+	t.Cleanup(func() { _ = pr.Close() })
+	go newTestPlayer().fetchStream(ctx, server.URL, pw)
+
+	src := &sourceReader{r: pr}
+	dec, err := mp3.NewDecoder(src)
+	require.NoError(t, err)
+	return io.ReadAll(newResyncReader(dec, src, onGiveUp))
+}
+
+func TestResyncReader_SkipsCorruptFrames(t *testing.T) {
+	stream := slices.Concat(silentMP3Frames(5), corruptMP3Frames(3), silentMP3Frames(5))
+	var gaveUp error
+
+	out, err := decodeServed(t, stream, func(err error) { gaveUp = err })
+	require.NoError(t, err)
+	assert.Len(t, out, 10*decodedFrameSize, "playback should continue past the corrupt region")
+	assert.NoError(t, gaveUp)
+}
+
+func TestResyncReader_GivesUpAfterLimit(t *testing.T) {
+	stream := slices.Concat(silentMP3Frames(2), corruptMP3Frames(maxDecodeResyncs+1), silentMP3Frames(5))
+	var gaveUp error
+
+	out, err := decodeServed(t, stream, func(err error) { gaveUp = err })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "corrupt frames")
+	assert.Len(t, out, 2*decodedFrameSize)
+	assert.Equal(t, err, gaveUp, "giving up must be reported so the stream reconnects")
+}
+
+func TestResyncReader_GoodDataResetsBudget(t *testing.T) {
+	stream := silentMP3Frames(1)
+	for range 3 {
+		stream = slices.Concat(stream, corruptMP3Frames(maxDecodeResyncs), silentMP3Frames(1))
+	}
+
+	out, err := decodeServed(t, stream, nil)
+	require.NoError(t, err)
+	assert.Len(t, out, 4*decodedFrameSize)
+}
+
+func TestResyncReader_PassesThroughSourceErrors(t *testing.T) {
+	srcErr := errors.New("stream read error")
+	src := &sourceReader{r: &scriptedDecoder{steps: []func([]byte) (int, error){scriptFail(srcErr)}}}
+	// The decoder surfaces the source failure; it must not be retried.
+	dec := &scriptedDecoder{steps: []func([]byte) (int, error){
+		func(p []byte) (int, error) { return src.Read(p) },
+		scriptData("unreachable"),
+	}}
+	r := newResyncReader(dec, src, func(error) { t.Fatal("source errors are not corruption") })
+
+	_, err := r.Read(make([]byte, 8))
+	assert.Equal(t, srcErr, err)
+}
+
+func TestResyncReader_RecoversDecoderPanic(t *testing.T) {
+	dec := &scriptedDecoder{steps: []func([]byte) (int, error){
+		func([]byte) (int, error) { panic("index out of range") },
+		scriptData("ok"),
+	}}
+	r := newResyncReader(dec, &sourceReader{}, nil)
+
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(out))
+}
+
+func TestResyncReader_RealDecoderPassesCleanStream(t *testing.T) {
+	src := &sourceReader{r: bytes.NewReader(silentMP3Frames(10))}
+	dec, err := mp3.NewDecoder(src)
+	require.NoError(t, err)
+
+	out, err := io.ReadAll(newResyncReader(dec, src, nil))
+	require.NoError(t, err)
+	assert.NotEmpty(t, out)
+}
@@ -0,0 +1,59 @@
+package audio
+
+import (
+	"encoding/binary"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// silenceThreshold is the loudest sample that still counts as dead air,
+// about -60 dBFS: below anything a channel plays on purpose, but above the
+// dither some encoders leave in a silent stream.
+const silenceThreshold = 32
+
+// silenceDetector reports a stream that keeps delivering audio that is
+// nothing but silence, which the stall watchdog cannot see: the connection
+// is healthy, the station just broadcasts dead air. It counts audio time,
+// not wall time, so a pause never looks like silence. Only the output
+// player's goroutine runs it.
+type silenceDetector struct {
+	timeout  *atomic.Int64 // nanoseconds of silence to report; 0 disables
+	onSilent func(silent time.Duration)
+	frames   int  // consecutive silent frames so far
+	reported bool // already reported this stretch of silence
+}
+
+// newSilenceReader returns r watched for dead air: onSilent is called once
+// the audio has been silent for the timeout, and again only after sound
+// returns and falls silent anew.
+func newSilenceReader(r io.Reader, timeout *atomic.Int64, onSilent func(time.Duration)) io.Reader {
+	return &frameReader{r: r, f: &silenceDetector{timeout: timeout, onSilent: onSilent}}
+}
+
+func (d *silenceDetector) process(b []byte) {
+	timeout := time.Duration(d.timeout.Load())
+	if timeout <= 0 {
+		d.frames, d.reported = 0, false
+		return
+	}
+	limit := int(timeout * sampleRate / time.Second)
+	for i := 0; i+pcmFrame <= len(b); i += pcmFrame {
+		l := int16(binary.LittleEndian.Uint16(b[i:]))
+		r := int16(binary.LittleEndian.Uint16(b[i+2:]))
+		if loud(l) || loud(r) {
+			d.frames, d.reported = 0, false
+			continue
+		}
+		d.frames++
+		if d.frames >= limit && !d.reported {
+			d.reported = true
+			d.onSilent(time.Duration(d.frames) * time.Second / sampleRate)
+		}
+	}
+}
+
+// loud reports whether a sample is above the dead-air threshold.
+func loud(v int16) bool {
+	return v > silenceThreshold || v < -silenceThreshold
+}
@@ -0,0 +1,62 @@
+package audio
+
+import (
+	"bytes"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// silent returns d of digital silence with a little dither.
+func silent(d time.Duration) []byte {
+	frames := int(d * sampleRate / time.Second)
+	samples := make([]int16, 0, 2*frames)
+	for i := range frames {
+		v := int16(i%5 - 2)
+		samples = append(samples, v, v)
+	}
+	return pcm(samples...)
+}
+
+// detect feeds b through a silence detector and returns how often it fired.
+func detect(t *testing.T, timeout time.Duration, b []byte) []time.Duration {
+	t.Helper()
+	var limit atomic.Int64
+	limit.Store(int64(timeout))
+	var fired []time.Duration
+	_, err := io.ReadAll(newSilenceReader(bytes.NewReader(b), &limit, func(d time.Duration) {
+		fired = append(fired, d)
+	}))
+	require.NoError(t, err)
+	return fired
+}
+
+func TestSilenceDetector_ReportsDeadAirOnce(t *testing.T) {
+	fired := detect(t, time.Second, silent(3*time.Second))
+
+	require.Len(t, fired, 1, "a single stretch of silence is reported once")
+	assert.InDelta(t, time.Second, fired[0], float64(50*time.Millisecond))
+}
+
+func TestSilenceDetector_SoundResets(t *testing.T) {
+	var b []byte
+	b = append(b, silent(700*time.Millisecond)...)
+	b = append(b, sine(440, 8000)[:4096]...)
+	b = append(b, silent(700*time.Millisecond)...)
+
+	assert.Empty(t, detect(t, time.Second, b), "sound in between restarts the count")
+
+	b = append(b, sine(440, 8000)[:4096]...)
+	b = append(b, silent(1500*time.Millisecond)...)
+	b = append(b, sine(440, 8000)[:4096]...)
+	b = append(b, silent(1500*time.Millisecond)...)
+	assert.Len(t, detect(t, time.Second, b), 2, "each new stretch of silence is reported")
+}
+
+func TestSilenceDetector_DisabledByZero(t *testing.T) {
+	assert.Empty(t, detect(t, 0, silent(2*time.Second)))
+}
@@ -0,0 +1,136 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+	"math/cmplx"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// spectrumWindow is how many of the latest mono samples the spectrum is
+// taken over: about 46 ms at sampleRate, a power of two for the FFT.
+const spectrumWindow = 2048
+
+// spectrumWant is how long a Spectrum call keeps the tap recording. With
+// no one asking, the samples are not kept, so the visualizer costs nothing
+// while no client shows it.
+const spectrumWant = 2 * time.Second
+
+// spectrumLow and spectrumHigh bound the analyzed frequencies in Hz; the
+// bands are spaced logarithmically between them, like the ear hears.
+const (
+	spectrumLow  = 50.0
+	spectrumHigh = 16000.0
+)
+
+// spectrumFloorDB is the quietest band level the spectrum shows; anything
+// below reads as 0.
+const spectrumFloorDB = -60
+
+// MaxSpectrumBands caps the bands a Spectrum call can ask for.
+const MaxSpectrumBands = 64
+
+// spectrumTap keeps the latest spectrumWindow samples of the decoded PCM
+// on its way to the output player, mixed down to mono, for Spectrum to
+// analyze on demand. It is a pcmFilter that leaves the audio untouched.
+type spectrumTap struct {
+	wanted atomic.Int64 // UnixNano of the latest Spectrum call
+
+	mu      sync.Mutex
+	samples [spectrumWindow]float64
+	next    int       // where the next sample goes
+	at      time.Time // when the latest sample arrived
+}
+
+func (t *spectrumTap) process(b []byte) {
+	if time.Since(time.Unix(0, t.wanted.Load())) > spectrumWant {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for ; len(b) >= pcmFrame; b = b[pcmFrame:] {
+		l := float64(int16(binary.LittleEndian.Uint16(b)))
+		r := float64(int16(binary.LittleEndian.Uint16(b[2:])))
+		t.samples[t.next] = (l + r) / 2 / math.MaxInt16
+		t.next = (t.next + 1) % spectrumWindow
+	}
+	t.at = time.Now()
+}
+
+// Spectrum returns the level of each of bands frequency bands in [0, 1],
+// on a decibel scale from spectrumFloorDB, lowest band first. It returns
+// nil once the latest samples are older than levelHold, and for the first
+// call after a while, which only starts the recording.
+func (t *spectrumTap) Spectrum(bands int) []float64 {
+	t.wanted.Store(time.Now().UnixNano())
+	bands = min(bands, MaxSpectrumBands)
+	t.mu.Lock()
+	if bands <= 0 || time.Since(t.at) > levelHold {
+		t.mu.Unlock()
+		return nil
+	}
+	// Oldest first, shaped by a Hann window against spectral leakage.
+	x := make([]complex128, spectrumWindow)
+	for i := range x {
+		w := 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/(spectrumWindow-1))
+		x[i] = complex(t.samples[(t.next+i)%spectrumWindow]*w, 0)
+	}
+	t.mu.Unlock()
+
+	fft(x)
+	return spectrumBands(x[:spectrumWindow/2], bands)
+}
+
+// spectrumBands folds the FFT bins into bands logarithmically spaced bands,
+// each the loudest bin within it. A full-scale sine reads as 0 dB: the
+// Hann window leaves a quarter of the window length as its bin's magnitude.
+func spectrumBands(bins []complex128, bands int) []float64 {
+	binHz := float64(sampleRate) / spectrumWindow
+	ratio := math.Pow(spectrumHigh/spectrumLow, 1/float64(bands))
+	levels := make([]float64, bands)
+	lo := spectrumLow
+	for i := range levels {
+		hi := lo * ratio
+		first := max(int(lo/binHz), 1)
+		last := max(min(int(hi/binHz), len(bins)-1), first)
+		var peak float64
+		for _, c := range bins[first : last+1] {
+			peak = max(peak, cmplx.Abs(c))
+		}
+		if peak > 0 {
+			db := 20 * math.Log10(peak/(spectrumWindow/4))
+			levels[i] = math.Max(0, math.Min(1, (db-spectrumFloorDB)/-spectrumFloorDB))
+		}
+		lo = hi
+	}
+	return levels
+}
+
+// fft transforms x in place: an iterative radix-2 Cooley-Tukey FFT. The
+// length of x must be a power of two.
+func fft(x []complex128) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j |= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := range size / 2 {
+				even, odd := x[start+k], x[start+k+size/2]*w
+				x[start+k], x[start+k+size/2] = even+odd, even-odd
+				w *= step
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package audio
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sinePCM encodes frames of a stereo sine at freq Hz and amplitude amp
+// (of full scale) as 16-bit PCM.
+func sinePCM(freq, amp float64, frames int) []byte {
+	samples := make([]int16, 0, frames*2)
+	for i := range frames {
+		v := int16(amp * math.MaxInt16 * math.Sin(2*math.Pi*freq*float64(i)/sampleRate))
+		samples = append(samples, v, v)
+	}
+	return pcm(samples...)
+}
+
+func TestSpectrumTap_RecordsOnlyOnceAsked(t *testing.T) {
+	tap := &spectrumTap{}
+	tap.process(sinePCM(1000, 0.5, spectrumWindow))
+	assert.Nil(t, tap.Spectrum(16), "nothing was recorded before the first call")
+
+	tap.process(sinePCM(1000, 0.5, spectrumWindow))
+	assert.Len(t, tap.Spectrum(16), 16)
+}
+
+func TestSpectrumTap_FindsTheTone(t *testing.T) {
+	tap := &spectrumTap{}
+	tap.Spectrum(24)
+	tap.process(sinePCM(1000, 1, spectrumWindow))
+
+	bands := tap.Spectrum(24)
+	require.Len(t, bands, 24)
+	loudest := 0
+	for i, v := range bands {
+		if v > bands[loudest] {
+			loudest = i
+		}
+	}
+	// The band holding 1 kHz on the log scale from spectrumLow to
+	// spectrumHigh.
+	want := int(math.Log(1000/spectrumLow) / math.Log(spectrumHigh/spectrumLow) * 24)
+	assert.InDelta(t, want, loudest, 1)
+	assert.InDelta(t, 1, bands[loudest], 0.05, "a full-scale tone reads near 0 dB")
+	assert.Less(t, bands[0], 0.2, "the bass stays quiet")
+	assert.Less(t, bands[23], 0.2, "the treble stays quiet")
+}
+
+func TestSpectrumTap_SilenceAndStaleness(t *testing.T) {
+	tap := &spectrumTap{}
+	tap.Spectrum(8)
+	tap.process(make([]byte, spectrumWindow*pcmFrame))
+	assert.Equal(t, make([]float64, 8), tap.Spectrum(8))
+
+	tap.mu.Lock()
+	tap.at = time.Now().Add(-time.Second)
+	tap.mu.Unlock()
+	assert.Nil(t, tap.Spectrum(8), "a stalled output reads as nothing")
+	assert.Len(t, tap.Spectrum(MaxSpectrumBands+10), 0, "still stale")
+}
+
+func TestFFT_MatchesDFT(t *testing.T) {
+	x := make([]complex128, 16)
+	for i := range x {
+		x[i] = complex(math.Sin(float64(i))+0.5*math.Cos(3*float64(i)), 0)
+	}
+	want := make([]complex128, len(x))
+	for k := range want {
+		for n, v := range x {
+			want[k] += v * cmplx.Exp(complex(0, -2*math.Pi*float64(k*n)/float64(len(x))))
+		}
+	}
+
+	fft(x)
+	for k := range x {
+		assert.InDelta(t, real(want[k]), real(x[k]), 1e-9, "bin %d", k)
+		assert.InDelta(t, imag(want[k]), imag(x[k]), 1e-9, "bin %d", k)
+	}
+}
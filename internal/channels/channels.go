@@ -3,12 +3,17 @@ package channels
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"somad/internal/atomicfile"
@@ -32,14 +37,77 @@ type Channel struct {
 	LargeImage  string     `json:"largeimage"`
 	XLImage     string     `json:"xlimage"`
 	Twitter     string     `json:"twitter"`
-	Listeners   string     `json:"listeners"`
+	DJ          string     `json:"dj"`
+	Listeners   int        `json:"listeners"` // 0 when the catalog gives no count
 	LastPlaying string     `json:"lastPlaying"`
 	Playlists   []Playlist `json:"playlists"`
+	// StreamURL is a direct stream that bypasses playlist resolution. SomaFM
+	// channels never set it; stations from the directory search do.
+	StreamURL string `json:"streamUrl,omitempty"`
+}
+
+// UnmarshalJSON decodes a channel, parsing its listener count once at load
+// time: SomaFM sends it as a string, so a number is accepted too. A
+// malformed count is dropped rather than failing the whole catalog.
+func (c *Channel) UnmarshalJSON(data []byte) error {
+	type plain Channel
+	var raw struct {
+		plain
+		Listeners json.RawMessage `json:"listeners"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*c = Channel(raw.plain)
+	c.Listeners = parseListeners(raw.Listeners)
+	return nil
+}
+
+// MarshalJSON encodes a channel with its listener count as a decimal
+// string, "" for none, the way SomaFM sends it; clients and caches from
+// before the count was parsed read it as one.
+func (c Channel) MarshalJSON() ([]byte, error) {
+	type plain Channel
+	raw := struct {
+		plain
+		Listeners string `json:"listeners"`
+	}{plain: plain(c)}
+	if c.Listeners > 0 {
+		raw.Listeners = strconv.Itoa(c.Listeners)
+	}
+	return json.Marshal(raw)
+}
+
+// parseListeners returns a raw JSON listener count, string or number, or 0
+// when it is missing or not a count.
+func parseListeners(raw json.RawMessage) int {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		s = string(raw)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
 }
 
 // Channels is a wrapper for the list of SomaFM channels.
 type Channels struct {
 	Channels []Channel `json:"channels"`
+	// Updated is when SomaFM last changed the catalog: the feed's own
+	// "updated" field if it has one, else the response's Last-Modified
+	// header. Zero when neither was given.
+	Updated time.Time `json:"updated,omitzero"`
+	// Fetched is when the catalog was downloaded from SomaFM; zero in
+	// caches written before it was recorded.
+	Fetched time.Time `json:"fetched,omitzero"`
+}
+
+// Stale reports whether the catalog was downloaded more than ttl ago, or
+// at an unknown time. A ttl of 0 makes every catalog stale.
+func (c *Channels) Stale(ttl time.Duration) bool {
+	return c.Fetched.IsZero() || time.Since(c.Fetched) >= ttl
 }
 
 const (
@@ -57,6 +125,12 @@ var SomaFMChannelsURL = "https://somafm.com/channels.json"
 // cacheFilePath resolves the absolute path of the cache file without
 // touching the filesystem.
 func cacheFilePath() (string, error) {
+	return namedCacheFilePath(cacheFileName)
+}
+
+// namedCacheFilePath resolves the absolute path of the cache file name in
+// the app's cache directory without touching the filesystem.
+func namedCacheFilePath(name string) (string, error) {
 	// Check XDG override first (works on all platforms, enables testing)
 	cacheDir := os.Getenv("XDG_CACHE_HOME")
 	if cacheDir == "" {
@@ -66,13 +140,19 @@ func cacheFilePath() (string, error) {
 			return "", fmt.Errorf("failed to get user cache directory: %w", err)
 		}
 	}
-	return filepath.Join(cacheDir, appCacheDirName, cacheFileName), nil
+	return filepath.Join(cacheDir, appCacheDirName, name), nil
 }
 
 // GetCacheFilePath returns the absolute path to the cache file, creating its
 // directory so the caller can write to it.
 func GetCacheFilePath() (string, error) {
-	path, err := cacheFilePath()
+	return namedCacheFile(cacheFileName)
+}
+
+// namedCacheFile returns the absolute path to the cache file name, creating
+// its directory so the caller can write to it.
+func namedCacheFile(name string) (string, error) {
+	path, err := namedCacheFilePath(name)
 	if err != nil {
 		return "", err
 	}
@@ -98,12 +178,19 @@ func PeekChannelsFromCache() (*Channels, error) {
 	if err := json.Unmarshal(data, &channels); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal cached data: %w", err)
 	}
+	channels.dropUnidentified() // silently: a completion must not print warnings
 	return &channels, nil
 }
 
 // ReadChannelsFromCache attempts to read channel data from the local cache file.
 func ReadChannelsFromCache() (*Channels, error) {
-	cachePath, err := GetCacheFilePath()
+	return ReadCache(cacheFileName)
+}
+
+// ReadCache reads a catalog from the cache file name in the app's cache
+// directory; station sources other than SomaFM keep their own file there.
+func ReadCache(name string) (*Channels, error) {
+	cachePath, err := namedCacheFile(name)
 	if err != nil {
 		return nil, err
 	}
@@ -126,13 +213,20 @@ func ReadChannelsFromCache() (*Channels, error) {
 		}
 		return nil, fmt.Errorf("failed to unmarshal cached data: %w", err)
 	}
+	warnUnidentified(channels.dropUnidentified())
 
 	return &channels, nil
 }
 
 // WriteChannelsToCache writes the given channel data to the local cache file.
 func WriteChannelsToCache(channels *Channels) error {
-	cachePath, err := GetCacheFilePath()
+	return WriteCache(cacheFileName, channels)
+}
+
+// WriteCache writes a catalog to the cache file name in the app's cache
+// directory.
+func WriteCache(name string, channels *Channels) error {
+	cachePath, err := namedCacheFile(name)
 	if err != nil {
 		return err
 	}
@@ -150,8 +244,49 @@ func WriteChannelsToCache(channels *Channels) error {
 	return nil
 }
 
+// IsUnreachable reports whether err from FetchChannelsFromNetwork means
+// SomaFM could not be reached at all (no network, DNS failure, timeout),
+// as opposed to an error response or a malformed catalog.
+func IsUnreachable(err error) bool {
+	var ue *url.Error
+	return errors.As(err, &ue) && ue.Op != "parse"
+}
+
 // FetchChannelsFromNetwork fetches channel data from the SomaFM API.
 func FetchChannelsFromNetwork(userAgent string) (*Channels, error) {
+	fetchedChannels, err := downloadChannels(userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Write to cache for future use
+	if err := WriteChannelsToCache(fetchedChannels); err != nil {
+		// Log error but don't fail
+		fmt.Fprintf(os.Stderr, "Warning: Failed to write channels to cache: %v\n", err)
+	}
+
+	return fetchedChannels, nil
+}
+
+// FetchListeners returns how many listeners channelID has right now. It
+// downloads the whole catalog, as SomaFM has no per-channel API, but
+// leaves the cache alone.
+func FetchListeners(channelID, userAgent string) (int, error) {
+	chs, err := downloadChannels(userAgent)
+	if err != nil {
+		return 0, err
+	}
+	for _, ch := range chs.Channels {
+		if ch.ID == channelID {
+			return ch.Listeners, nil
+		}
+	}
+	return 0, fmt.Errorf("channel %s is not in the catalog", channelID)
+}
+
+// downloadChannels fetches and decodes the catalog, stamping when it was
+// fetched.
+func downloadChannels(userAgent string) (*Channels, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -177,12 +312,36 @@ func FetchChannelsFromNetwork(userAgent string) (*Channels, error) {
 	if err := json.NewDecoder(io.LimitReader(resp.Body, maxCatalogBytes)).Decode(&fetchedChannels); err != nil {
 		return nil, fmt.Errorf("failed to decode network response: %w", err)
 	}
-
-	// Write to cache for future use
-	if err := WriteChannelsToCache(&fetchedChannels); err != nil {
-		// Log error but don't fail
-		fmt.Fprintf(os.Stderr, "Warning: Failed to write channels to cache: %v\n", err)
+	warnUnidentified(fetchedChannels.dropUnidentified())
+	if fetchedChannels.Updated.IsZero() {
+		if updated, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+			fetchedChannels.Updated = updated
+		}
 	}
 
+	fetchedChannels.Fetched = time.Now()
 	return &fetchedChannels, nil
 }
+
+// dropUnidentified removes channels without an ID and returns their titles.
+// Favorites, the playing marker and selection restoring all key on the ID,
+// so an empty one would match every other ID-less channel (and the "no
+// channel" state) at once.
+func (c *Channels) dropUnidentified() []string {
+	var dropped []string
+	c.Channels = slices.DeleteFunc(c.Channels, func(ch Channel) bool {
+		if strings.TrimSpace(ch.ID) != "" {
+			return false
+		}
+		dropped = append(dropped, ch.Title)
+		return true
+	})
+	return dropped
+}
+
+// warnUnidentified logs the channels dropped by dropUnidentified.
+func warnUnidentified(titles []string) {
+	for _, title := range titles {
+		log.Printf("warning: ignoring channel %q without an ID", title)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"somad/internal/security/securitytest"
 
@@ -30,7 +31,7 @@ var testChannelData = Channels{
 			Title:       "Groove Salad",
 			Description: "A nicely chilled plate of ambient/downtempo beats and grooves.",
 			Genre:       "ambient|electronica|chillout",
-			Listeners:   "1234",
+			Listeners:   1234,
 			Playlists: []Playlist{
 				{URL: "http://somafm.com/groovesalad130.pls", Format: "mp3", Quality: "high"},
 			},
@@ -40,7 +41,7 @@ var testChannelData = Channels{
 			Title:       "Drone Zone",
 			Description: "Served best chilled, safe with most medications.",
 			Genre:       "ambient|space",
-			Listeners:   "567",
+			Listeners:   567,
 			Playlists: []Playlist{
 				{URL: "http://somafm.com/dronezone130.pls", Format: "mp3", Quality: "high"},
 				{URL: "http://somafm.com/dronezone64.pls", Format: "aac", Quality: "low"},
@@ -151,6 +152,127 @@ func TestFetchChannelsFromNetwork(t *testing.T) {
 	assert.Equal(t, len(channels.Channels), len(cached.Channels))
 }
 
+func TestFetchListeners(t *testing.T) {
+	securitytest.AllowTestHosts(t)
+	SetCacheDir(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := json.Marshal(testChannelData)
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+	originalURL := SomaFMChannelsURL
+	SomaFMChannelsURL = server.URL
+	t.Cleanup(func() { SomaFMChannelsURL = originalURL })
+
+	n, err := FetchListeners("dronezone", "soma/test")
+	require.NoError(t, err)
+	assert.Equal(t, 567, n)
+
+	_, err = FetchListeners("nosuchchannel", "soma/test")
+	require.Error(t, err)
+
+	_, err = ReadChannelsFromCache()
+	assert.Error(t, err, "the cache is left alone")
+}
+
+func TestFetchChannelsFromNetwork_RecordsFetchTime(t *testing.T) {
+	securitytest.AllowTestHosts(t)
+	SetCacheDir(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := json.Marshal(testChannelData)
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	originalURL := SomaFMChannelsURL
+	SomaFMChannelsURL = server.URL
+	t.Cleanup(func() { SomaFMChannelsURL = originalURL })
+
+	before := time.Now()
+	_, err := FetchChannelsFromNetwork("soma/test")
+	require.NoError(t, err)
+
+	cached, err := ReadChannelsFromCache()
+	require.NoError(t, err)
+	assert.False(t, cached.Fetched.Before(before.Truncate(time.Second)), "got %v", cached.Fetched)
+	assert.False(t, cached.Stale(time.Hour))
+	assert.True(t, cached.Stale(0), "a zero TTL makes every cache stale")
+}
+
+func TestChannelsStale(t *testing.T) {
+	assert.True(t, (&Channels{}).Stale(time.Hour), "a cache without a fetch time predates the TTL")
+	assert.False(t, (&Channels{Fetched: time.Now().Add(-59 * time.Minute)}).Stale(time.Hour))
+	assert.True(t, (&Channels{Fetched: time.Now().Add(-61 * time.Minute)}).Stale(time.Hour))
+}
+
+func TestFetchChannelsFromNetwork_UpdatedFromLastModified(t *testing.T) {
+	securitytest.AllowTestHosts(t)
+	SetCacheDir(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", "Tue, 13 Oct 2026 08:30:00 GMT")
+		data, _ := json.Marshal(testChannelData)
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	originalURL := SomaFMChannelsURL
+	SomaFMChannelsURL = server.URL
+	t.Cleanup(func() { SomaFMChannelsURL = originalURL })
+
+	channels, err := FetchChannelsFromNetwork("soma/test")
+	require.NoError(t, err)
+	want := time.Date(2026, 10, 13, 8, 30, 0, 0, time.UTC)
+	assert.True(t, want.Equal(channels.Updated), "got %v", channels.Updated)
+
+	// The timestamp is cached along with the catalog.
+	cached, err := ReadChannelsFromCache()
+	require.NoError(t, err)
+	assert.True(t, want.Equal(cached.Updated), "got %v", cached.Updated)
+}
+
+func TestFetchChannelsFromNetwork_DropsChannelsWithoutID(t *testing.T) {
+	securitytest.AllowTestHosts(t)
+	SetCacheDir(t)
+
+	withBlank := Channels{Channels: append([]Channel{{ID: "", Title: "Nameless"}, {ID: "  ", Title: "Blank"}},
+		testChannelData.Channels...)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := json.Marshal(withBlank)
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	originalURL := SomaFMChannelsURL
+	SomaFMChannelsURL = server.URL
+	t.Cleanup(func() { SomaFMChannelsURL = originalURL })
+
+	channels, err := FetchChannelsFromNetwork("soma/test")
+	require.NoError(t, err)
+	var ids []string
+	for _, ch := range channels.Channels {
+		ids = append(ids, ch.ID)
+	}
+	// An empty ID would match an empty favorite or playing ID; none survive.
+	assert.Equal(t, []string{"groovesalad", "dronezone"}, ids)
+}
+
+func TestReadChannelsFromCache_DropsChannelsWithoutID(t *testing.T) {
+	SetCacheDir(t)
+	withBlank := Channels{Channels: append([]Channel{{Title: "Nameless"}}, testChannelData.Channels...)}
+	require.NoError(t, WriteChannelsToCache(&withBlank))
+
+	loaded, err := ReadChannelsFromCache()
+	require.NoError(t, err)
+	assert.Len(t, loaded.Channels, 2)
+
+	peeked, err := PeekChannelsFromCache()
+	require.NoError(t, err)
+	assert.Len(t, peeked.Channels, 2)
+}
+
 func TestFetchChannelsFromNetwork_ServerError(t *testing.T) {
 	securitytest.AllowTestHosts(t)
 	SetCacheDir(t)
@@ -222,3 +344,61 @@ func TestGetCacheFilePath(t *testing.T) {
 	assert.Contains(t, path, appCacheDirName)
 	assert.Contains(t, path, cacheFileName)
 }
+
+func TestFetchChannelsFromNetwork_Unreachable(t *testing.T) {
+	securitytest.AllowTestHosts(t)
+	SetCacheDir(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	originalURL := SomaFMChannelsURL
+	SomaFMChannelsURL = server.URL
+	t.Cleanup(func() { SomaFMChannelsURL = originalURL })
+
+	_, err := FetchChannelsFromNetwork("soma/test")
+	require.Error(t, err)
+	assert.False(t, IsUnreachable(err), "an error response means SomaFM was reached")
+
+	server.Close()
+	_, err = FetchChannelsFromNetwork("soma/test")
+	require.Error(t, err)
+	assert.True(t, IsUnreachable(err), "got %v", err)
+}
+
+func TestChannelUnmarshal_ParsesListeners(t *testing.T) {
+	for raw, want := range map[string]int{
+		`"1234"`: 1234,
+		`" 42 "`: 42,
+		`"0012"`: 12,
+		`987`:    987,
+		`"many"`: 0,
+		`"-3"`:   0,
+		`null`:   0,
+	} {
+		var ch Channel
+		require.NoError(t, json.Unmarshal([]byte(`{"id":"x","title":"X","listeners":`+raw+`}`), &ch), raw)
+		assert.Equal(t, want, ch.Listeners, raw)
+		assert.Equal(t, "X", ch.Title, raw)
+	}
+
+	var ch Channel
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"x"}`), &ch))
+	assert.Zero(t, ch.Listeners)
+}
+
+func TestChannelMarshal_KeepsListenersAString(t *testing.T) {
+	b, err := json.Marshal(Channel{ID: "x", Listeners: 1234})
+	require.NoError(t, err)
+	var wire struct{ Listeners string }
+	require.NoError(t, json.Unmarshal(b, &wire))
+	assert.Equal(t, "1234", wire.Listeners, "older clients decode the count as a string")
+
+	var ch Channel
+	require.NoError(t, json.Unmarshal(b, &ch))
+	assert.Equal(t, 1234, ch.Listeners)
+
+	b, err = json.Marshal(Channel{ID: "x"})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"listeners":""`)
+}
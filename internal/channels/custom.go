@@ -0,0 +1,47 @@
+package channels
+
+import (
+	"strings"
+	"unicode"
+)
+
+// CustomIDPrefix starts the IDs of the stations listed in the config file,
+// keeping them apart from SomaFM's channel IDs.
+const CustomIDPrefix = "custom:"
+
+// IsCustomID reports whether a channel ID names a station from the config
+// file.
+func IsCustomID(id string) bool {
+	return strings.HasPrefix(id, CustomIDPrefix)
+}
+
+// Custom returns a station from the config file as a channel. Its ID is
+// derived from the title, so favorites and the last played channel survive
+// edits to the rest of its entry. The URL, a stream or a .pls playlist of
+// one, goes into StreamURL: playback skips SomaFM's playlists and songs API
+// for it.
+func Custom(title, streamURL, description, genre string) Channel {
+	return Channel{
+		ID:          CustomID(title),
+		Title:       strings.TrimSpace(title),
+		Description: description,
+		Genre:       genre,
+		StreamURL:   streamURL,
+	}
+}
+
+// CustomID returns the ID of the station from the config file titled
+// title. Titles that differ only in case, spacing or punctuation share one,
+// and a title without letters or digits gets the bare CustomIDPrefix.
+func CustomID(title string) string {
+	return CustomIDPrefix + slug(title)
+}
+
+// slug lowercases title and joins its runs of letters and digits with "-",
+// e.g. "Jazz & Blues 24/7" becomes "jazz-blues-24-7".
+func slug(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
@@ -0,0 +1,34 @@
+package channels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustom_IDFromTitle(t *testing.T) {
+	ch := Custom(" Jazz & Blues 24/7 ", "http://jazz.example.org/live", "Late night", "jazz|blues")
+
+	assert.Equal(t, "custom:jazz-blues-24-7", ch.ID)
+	assert.True(t, IsCustomID(ch.ID))
+	assert.Equal(t, "Jazz & Blues 24/7", ch.Title)
+	assert.Equal(t, "http://jazz.example.org/live", ch.StreamURL)
+	assert.Equal(t, []string{"jazz", "blues"}, ch.Genres())
+	assert.False(t, IsCustomID("groovesalad"))
+}
+
+func TestCustomID_SharedByNearTitles(t *testing.T) {
+	assert.Equal(t, CustomID("Jazz & Blues"), CustomID("jazz blues"))
+	assert.Equal(t, CustomIDPrefix, CustomID("★"), "nothing to make an ID from")
+}
+
+func TestFilterByMP3Quality_KeepsDirectStreams(t *testing.T) {
+	chs := []Channel{
+		{ID: "lowonly", Playlists: []Playlist{{Format: "mp3", Quality: "low"}}},
+		Custom("Jazz", "http://jazz.example.org/live", "", ""),
+	}
+
+	kept := FilterByMP3Quality(chs, "highest")
+
+	assert.Equal(t, []string{"Jazz"}, titles(kept))
+}
@@ -1,28 +1,125 @@
 package channels
 
+import (
+	"slices"
+	"strings"
+)
+
 // mp3QualityRank orders SomaFM playlist quality levels, best first.
 var mp3QualityRank = map[string]int{"highest": 0, "high": 1, "low": 2}
 
 // SelectMP3PlaylistURL returns the best-quality MP3 playlist URL from a
 // channel's playlists (highest > high > low > unknown), or "" if none.
 func SelectMP3PlaylistURL(playlists []Playlist) string {
+	return SelectPlaylist(playlists, "mp3", "")
+}
+
+// SelectPlaylist returns the URL of the playlist in format closest to the
+// preferred quality ("low", "high" or "highest"), or "" if the channel has
+// no playlist in that format. An exact match wins; otherwise the nearest
+// quality does, the better one on a tie, and unrecognized quality labels
+// rank below "low". An empty or unknown quality picks the best available.
+func SelectPlaylist(playlists []Playlist, format, quality string) string {
+	want, ok := mp3QualityRank[quality]
+	if !ok {
+		want = 0
+	}
 	bestURL := ""
-	// The seed must exceed the unknown-quality rank below, or a channel
-	// whose playlists all have unrecognized quality labels would select
-	// nothing at all instead of falling back to its first entry.
-	bestRank := len(mp3QualityRank) + 1
+	bestDist, bestRank := 0, 0
 	for _, playlist := range playlists {
-		if playlist.Format != "mp3" {
+		if playlist.Format != format {
 			continue
 		}
 		rank, ok := mp3QualityRank[playlist.Quality]
 		if !ok {
 			rank = len(mp3QualityRank)
 		}
-		if rank < bestRank {
+		dist := rank - want
+		if dist < 0 {
+			dist = -dist
+		}
+		if bestURL == "" || dist < bestDist || (dist == bestDist && rank < bestRank) {
 			bestURL = playlist.URL
-			bestRank = rank
+			bestDist, bestRank = dist, rank
 		}
 	}
 	return bestURL
 }
+
+// ValidQuality reports whether quality is a SomaFM playlist quality level.
+func ValidQuality(quality string) bool {
+	_, ok := mp3QualityRank[quality]
+	return ok
+}
+
+// OffersMP3 reports whether the channel has an MP3 playlist of at least
+// minQuality ("low", "high" or "highest"). Any other minQuality accepts an MP3
+// playlist of any quality, including unrecognized labels.
+func (c Channel) OffersMP3(minQuality string) bool {
+	maxRank, ok := mp3QualityRank[minQuality]
+	if !ok {
+		maxRank = len(mp3QualityRank)
+	}
+	for _, playlist := range c.Playlists {
+		if playlist.Format != "mp3" {
+			continue
+		}
+		rank, ok := mp3QualityRank[playlist.Quality]
+		if !ok {
+			rank = len(mp3QualityRank)
+		}
+		if rank <= maxRank {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByMP3Quality returns the channels offering an MP3 playlist of at
+// least minQuality. Channels with a direct stream have no playlists to
+// judge and are kept. An empty minQuality disables the filter and returns
+// chs unchanged.
+func FilterByMP3Quality(chs []Channel, minQuality string) []Channel {
+	if minQuality == "" {
+		return chs
+	}
+	kept := make([]Channel, 0, len(chs))
+	for _, ch := range chs {
+		if ch.StreamURL != "" || ch.OffersMP3(minQuality) {
+			kept = append(kept, ch)
+		}
+	}
+	return kept
+}
+
+// Genres returns the channel's genre tags. SomaFM separates them with "|",
+// e.g. "ambient|space".
+func (c Channel) Genres() []string {
+	var genres []string
+	for g := range strings.SplitSeq(c.Genre, "|") {
+		if g = strings.TrimSpace(g); g != "" {
+			genres = append(genres, g)
+		}
+	}
+	return genres
+}
+
+// HasGenre reports whether the channel is tagged with genre, ignoring case.
+func (c Channel) HasGenre(genre string) bool {
+	return slices.ContainsFunc(c.Genres(), func(g string) bool {
+		return strings.EqualFold(g, genre)
+	})
+}
+
+// Genres returns every genre tag used by chs, lowercased, sorted and without
+// duplicates.
+func Genres(chs []Channel) []string {
+	var genres []string
+	for _, ch := range chs {
+		for _, g := range ch.Genres() {
+			genres = append(genres, strings.ToLower(g))
+		}
+	}
+	slices.Sort(genres)
+	return slices.Compact(genres)
+}
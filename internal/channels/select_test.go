@@ -65,3 +65,72 @@ func TestSelectMP3PlaylistURL_Empty(t *testing.T) {
 	assert.Empty(t, SelectMP3PlaylistURL(nil))
 	assert.Empty(t, SelectMP3PlaylistURL([]Playlist{}))
 }
+
+func TestSelectPlaylist_PreferredQuality(t *testing.T) {
+	playlists := []Playlist{
+		{URL: "http://somafm.com/groovesalad130.pls", Format: "mp3", Quality: "highest"},
+		{URL: "http://somafm.com/groovesalad.pls", Format: "mp3", Quality: "high"},
+		{URL: "http://somafm.com/groovesalad64.pls", Format: "mp3", Quality: "low"},
+		{URL: "http://somafm.com/groovesalad32.pls", Format: "aac", Quality: "low"},
+	}
+
+	assert.Equal(t, "http://somafm.com/groovesalad64.pls", SelectPlaylist(playlists, "mp3", "low"))
+	assert.Equal(t, "http://somafm.com/groovesalad.pls", SelectPlaylist(playlists, "mp3", "high"))
+	assert.Equal(t, "http://somafm.com/groovesalad130.pls", SelectPlaylist(playlists, "mp3", ""))
+	assert.Equal(t, "http://somafm.com/groovesalad32.pls", SelectPlaylist(playlists, "aac", "highest"))
+}
+
+func TestSelectPlaylist_FallsBackToNearestQuality(t *testing.T) {
+	highestAndLow := []Playlist{
+		{URL: "http://somafm.com/highest.pls", Format: "mp3", Quality: "highest"},
+		{URL: "http://somafm.com/low.pls", Format: "mp3", Quality: "low"},
+	}
+	onlyHighest := []Playlist{{URL: "http://somafm.com/highest.pls", Format: "mp3", Quality: "highest"}}
+
+	assert.Equal(t, "http://somafm.com/highest.pls", SelectPlaylist(highestAndLow, "mp3", "high"),
+		"equally near: the better quality wins")
+	assert.Equal(t, "http://somafm.com/highest.pls", SelectPlaylist(onlyHighest, "mp3", "low"),
+		"a channel without the preferred quality still plays")
+	assert.Empty(t, SelectPlaylist(highestAndLow, "aac", "low"))
+}
+
+func TestOffersMP3_MinimumQuality(t *testing.T) {
+	ch := Channel{Playlists: []Playlist{
+		{URL: "http://somafm.com/groovesalad64.pls", Format: "mp3", Quality: "low"},
+		{URL: "http://somafm.com/groovesalad256.pls", Format: "aac", Quality: "highest"},
+	}}
+
+	assert.True(t, ch.OffersMP3(""))
+	assert.True(t, ch.OffersMP3("low"))
+	assert.False(t, ch.OffersMP3("high"), "a high-quality aac stream does not count")
+	assert.False(t, ch.OffersMP3("highest"))
+}
+
+func TestFilterByMP3Quality(t *testing.T) {
+	chs := []Channel{
+		{ID: "high", Playlists: []Playlist{{Format: "mp3", Quality: "highest"}}},
+		{ID: "lowonly", Playlists: []Playlist{{Format: "mp3", Quality: "low"}, {Format: "aac", Quality: "highest"}}},
+		{ID: "aaconly", Playlists: []Playlist{{Format: "aac", Quality: "high"}}},
+	}
+
+	var ids []string
+	for _, ch := range FilterByMP3Quality(chs, "high") {
+		ids = append(ids, ch.ID)
+	}
+	assert.Equal(t, []string{"high"}, ids)
+	assert.Equal(t, chs, FilterByMP3Quality(chs, ""), "no minimum keeps every channel")
+}
+
+func TestGenres_SplitsSortsAndDeduplicates(t *testing.T) {
+	chs := []Channel{
+		{Genre: "ambient|space"},
+		{Genre: "Lounge| spy"},
+		{Genre: "Ambient"},
+		{Genre: ""},
+	}
+
+	assert.Equal(t, []string{"ambient", "lounge", "space", "spy"}, Genres(chs))
+	assert.True(t, chs[1].HasGenre("lounge"), "matching ignores case")
+	assert.True(t, chs[1].HasGenre("spy"), "tags are trimmed")
+	assert.False(t, chs[0].HasGenre("amb"), "tags match whole")
+}
@@ -0,0 +1,77 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"somad/internal/security"
+)
+
+// SomaFMSongsURL is where a channel's recently played songs are listed, as
+// <SomaFMSongsURL><channel ID>.json - exported for testing.
+var SomaFMSongsURL = "https://somafm.com/songs/"
+
+// maxSongsBytes caps the song-list download; the real list is a few KB.
+const maxSongsBytes = 1 << 20 // 1 MiB
+
+// Song is one song a SomaFM channel played.
+type Song struct {
+	Title  string
+	Artist string
+	Album  string
+	// Played is when the song started; zero if SomaFM did not say.
+	Played time.Time
+}
+
+// songsResponse is the songs API's reply. Dates are Unix seconds, usually
+// sent as strings.
+type songsResponse struct {
+	Songs []struct {
+		Title  string          `json:"title"`
+		Artist string          `json:"artist"`
+		Album  string          `json:"album"`
+		Date   json.RawMessage `json:"date"`
+	} `json:"songs"`
+}
+
+// FetchSongs returns the songs a SomaFM channel played recently, the one
+// playing now first. It is cheaper than reading the stream's metadata and
+// names the album as well.
+func FetchSongs(channelID, userAgent string) ([]Song, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	req, err := security.NewRequest(ctx, SomaFMSongsURL+url.PathEscape(channelID)+".json", userAgent)
+	if err != nil {
+		return nil, fmt.Errorf("invalid songs URL: %w", err)
+	}
+	resp, err := security.HTTPClient.Do(req) // #nosec G704 -- URL validated by security.NewRequest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch songs: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code fetching songs: %d", resp.StatusCode)
+	}
+
+	var decoded songsResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxSongsBytes)).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode songs: %w", err)
+	}
+	songs := make([]Song, 0, len(decoded.Songs))
+	for _, s := range decoded.Songs {
+		song := Song{Title: s.Title, Artist: s.Artist, Album: s.Album}
+		if secs, err := strconv.ParseInt(strings.Trim(string(s.Date), `"`), 10, 64); err == nil && secs > 0 {
+			song.Played = time.Unix(secs, 0)
+		}
+		songs = append(songs, song)
+	}
+	return songs, nil
+}
@@ -0,0 +1,58 @@
+package channels
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"somad/internal/security/securitytest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSongsServer serves body for /groovesalad.json and 404 for anything
+// else.
+func stubSongsServer(t *testing.T, body string) {
+	t.Helper()
+	securitytest.AllowTestHosts(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/groovesalad.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	originalURL := SomaFMSongsURL
+	SomaFMSongsURL = server.URL + "/"
+	t.Cleanup(func() { SomaFMSongsURL = originalURL })
+}
+
+func TestFetchSongs(t *testing.T) {
+	stubSongsServer(t, `{"id":"groovesalad","songs":[
+		{"title":"Awake","artist":"Tycho","album":"Awake","albumart":"","date":"1772373900"},
+		{"title":"Kerala","artist":"Bonobo","album":"Migration","date":1772373600},
+		{"title":"Station ID","artist":"SomaFM","album":"","date":""}]}`)
+
+	songs, err := FetchSongs("groovesalad", "soma/test")
+	require.NoError(t, err)
+	require.Len(t, songs, 3)
+	assert.Equal(t, Song{Title: "Awake", Artist: "Tycho", Album: "Awake", Played: time.Unix(1772373900, 0)}, songs[0])
+	assert.Equal(t, time.Unix(1772373600, 0), songs[1].Played, "numeric dates are read too")
+	assert.True(t, songs[2].Played.IsZero())
+}
+
+func TestFetchSongs_Errors(t *testing.T) {
+	stubSongsServer(t, `not json`)
+
+	_, err := FetchSongs("groovesalad", "soma/test")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "decode")
+
+	_, err = FetchSongs("dronezone", "soma/test")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
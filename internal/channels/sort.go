@@ -0,0 +1,42 @@
+package channels
+
+import (
+	"cmp"
+	"strings"
+)
+
+// CompareByListeners orders channels by listener count, most listeners
+// first. Equal counts fall back to a case-insensitive title comparison, so
+// the order is deterministic rather than whatever the API returned.
+// Suitable for slices.SortFunc.
+func CompareByListeners(a, b Channel) int {
+	if c := cmp.Compare(b.Listeners, a.Listeners); c != 0 {
+		return c
+	}
+	return CompareByTitle(a, b)
+}
+
+// CompareByGenre orders channels by their first genre, channels without a
+// genre last. Channels of the same genre fall back to a case-insensitive
+// title comparison. Suitable for slices.SortFunc.
+func CompareByGenre(a, b Channel) int {
+	ga, gb := a.Genres(), b.Genres()
+	switch {
+	case len(ga) == 0 && len(gb) == 0:
+	case len(ga) == 0:
+		return 1
+	case len(gb) == 0:
+		return -1
+	default:
+		if c := cmp.Compare(strings.ToLower(ga[0]), strings.ToLower(gb[0])); c != 0 {
+			return c
+		}
+	}
+	return CompareByTitle(a, b)
+}
+
+// CompareByTitle orders channels alphabetically by title, ignoring case.
+// Suitable for slices.SortFunc.
+func CompareByTitle(a, b Channel) int {
+	return cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+}
@@ -0,0 +1,68 @@
+package channels
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func titles(chs []Channel) []string {
+	out := make([]string, len(chs))
+	for i, ch := range chs {
+		out[i] = ch.Title
+	}
+	return out
+}
+
+func TestCompareByListeners_MostListenersFirst(t *testing.T) {
+	chs := []Channel{
+		{Title: "Drone Zone", Listeners: 500},
+		{Title: "Groove Salad", Listeners: 1000},
+		{Title: "Secret Agent", Listeners: 750},
+	}
+
+	slices.SortFunc(chs, CompareByListeners)
+
+	assert.Equal(t, []string{"Groove Salad", "Secret Agent", "Drone Zone"}, titles(chs))
+}
+
+func TestCompareByListeners_TiesBreakAlphabetically(t *testing.T) {
+	chs := []Channel{
+		{Title: "Space Station Soma", Listeners: 300},
+		{Title: "beat blender", Listeners: 300},
+		{Title: "Lush", Listeners: 900},
+		{Title: "Deep Space One", Listeners: 300},
+	}
+
+	slices.SortFunc(chs, CompareByListeners)
+
+	assert.Equal(t, []string{"Lush", "beat blender", "Deep Space One", "Space Station Soma"}, titles(chs))
+}
+
+func TestCompareByListeners_ComparesNumbers(t *testing.T) {
+	chs := []Channel{{Title: "Nine", Listeners: 9}, {Title: "Ten", Listeners: 10}}
+	slices.SortFunc(chs, CompareByListeners)
+	assert.Equal(t, []string{"Ten", "Nine"}, titles(chs))
+}
+
+func TestCompareByTitle_IgnoresCase(t *testing.T) {
+	chs := []Channel{{Title: "Lush"}, {Title: "beat blender"}, {Title: "Drone Zone"}}
+
+	slices.SortFunc(chs, CompareByTitle)
+
+	assert.Equal(t, []string{"beat blender", "Drone Zone", "Lush"}, titles(chs))
+}
+
+func TestCompareByGenre_FirstGenreThenTitle(t *testing.T) {
+	chs := []Channel{
+		{Title: "Secret Agent", Genre: "lounge|spy"},
+		{Title: "Mystery Channel"},
+		{Title: "Groove Salad", Genre: "ambient"},
+		{Title: "Drone Zone", Genre: "ambient|space"},
+	}
+
+	slices.SortFunc(chs, CompareByGenre)
+
+	assert.Equal(t, []string{"Drone Zone", "Groove Salad", "Secret Agent", "Mystery Channel"}, titles(chs))
+}
@@ -0,0 +1,28 @@
+package channels
+
+// ListenerTrends maps the IDs of channels in next to how many listeners
+// they gained (positive) or lost (negative) since prev. Channels that held
+// steady, are new, or lack a count in either catalog are left out; nil
+// when none changed.
+func ListenerTrends(prev, next []Channel) map[string]int {
+	before := make(map[string]int, len(prev))
+	for _, ch := range prev {
+		if ch.Listeners > 0 {
+			before[ch.ID] = ch.Listeners
+		}
+	}
+	var trends map[string]int
+	for _, ch := range next {
+		old, ok := before[ch.ID]
+		if !ok || ch.Listeners == 0 {
+			continue
+		}
+		if d := ch.Listeners - old; d != 0 {
+			if trends == nil {
+				trends = make(map[string]int)
+			}
+			trends[ch.ID] = d
+		}
+	}
+	return trends
+}
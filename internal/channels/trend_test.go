@@ -0,0 +1,27 @@
+package channels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListenerTrends(t *testing.T) {
+	prev := []Channel{
+		{ID: "groovesalad", Listeners: 1000},
+		{ID: "dronezone", Listeners: 500},
+		{ID: "secretagent", Listeners: 750},
+		{ID: "custom:mine"},
+	}
+	next := []Channel{
+		{ID: "groovesalad", Listeners: 1012},
+		{ID: "dronezone", Listeners: 497},
+		{ID: "secretagent", Listeners: 750},
+		{ID: "custom:mine"},
+		{ID: "newchannel", Listeners: 40},
+	}
+
+	assert.Equal(t, map[string]int{"groovesalad": 12, "dronezone": -3}, ListenerTrends(prev, next))
+	assert.Nil(t, ListenerTrends(nil, next), "a first catalog has nothing to compare against")
+	assert.Nil(t, ListenerTrends(next, next))
+}
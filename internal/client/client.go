@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"somad/internal/channels"
 	"somad/internal/protocol"
 )
 
@@ -299,6 +300,72 @@ func (c *Client) Status() (protocol.PlaybackState, error) {
 	return st, err
 }
 
+// Level returns the audio level of the playing stream in [0, 1].
+func (c *Client) Level() (float64, error) {
+	var result protocol.LevelResult
+	err := c.call(protocol.MethodLevel, nil, &result)
+	return result.Level, err
+}
+
+// Spectrum returns the level of each of bands frequency bands of the
+// playing audio in [0, 1], lowest first; empty while nothing plays.
+func (c *Client) Spectrum(bands int) ([]float64, error) {
+	var result protocol.SpectrumResult
+	err := c.call(protocol.MethodSpectrum, protocol.SpectrumParams{Bands: bands}, &result)
+	return result.Bands, err
+}
+
+// Stats reports on the playing stream's connection.
+func (c *Client) Stats() (protocol.StatsResult, error) {
+	var result protocol.StatsResult
+	err := c.call(protocol.MethodStats, nil, &result)
+	return result, err
+}
+
+// History returns the tracks played since the server started, newest
+// first.
+func (c *Client) History() ([]protocol.TrackEntry, error) {
+	var result protocol.HistoryResult
+	err := c.call(protocol.MethodHistory, nil, &result)
+	return result.Tracks, err
+}
+
+// Loved returns the loved tracks, newest first.
+func (c *Client) Loved() ([]protocol.TrackEntry, error) {
+	var result protocol.LovedResult
+	err := c.call(protocol.MethodLoved, nil, &result)
+	return result.Tracks, err
+}
+
+// ToggleLove loves or unloves the track titled title on channelID, or the
+// playing track when title is empty.
+func (c *Client) ToggleLove(channelID, title string) (protocol.LovedResult, error) {
+	var result protocol.LovedResult
+	err := c.call(protocol.MethodToggleLove, protocol.ToggleLoveParams{ChannelID: channelID, Title: title}, &result)
+	return result, err
+}
+
+// Artwork returns the cover of the playing track.
+func (c *Client) Artwork() (protocol.ArtworkResult, error) {
+	var result protocol.ArtworkResult
+	err := c.call(protocol.MethodArtwork, nil, &result)
+	return result, err
+}
+
+// ChannelArtwork returns the logo of channelID.
+func (c *Client) ChannelArtwork(channelID string) (protocol.ArtworkResult, error) {
+	var result protocol.ArtworkResult
+	err := c.call(protocol.MethodChannelArtwork, protocol.ChannelArtworkParams{ChannelID: channelID}, &result)
+	return result, err
+}
+
+// RecentSongs returns the songs a SomaFM channel played last, newest first.
+func (c *Client) RecentSongs(channelID string) (protocol.RecentSongsResult, error) {
+	var result protocol.RecentSongsResult
+	err := c.call(protocol.MethodRecentSongs, protocol.RecentSongsParams{ChannelID: channelID}, &result)
+	return result, err
+}
+
 // Channels returns the catalog with favorites and the last-played channel.
 func (c *Client) Channels() (protocol.ChannelsPayload, error) {
 	var payload protocol.ChannelsPayload
@@ -306,6 +373,12 @@ func (c *Client) Channels() (protocol.ChannelsPayload, error) {
 	return payload, err
 }
 
+// RefreshChannels asks the server to download the catalog now; the result
+// arrives as a channels event.
+func (c *Client) RefreshChannels() error {
+	return c.call(protocol.MethodRefresh, nil, nil)
+}
+
 // Play starts a channel, blocking until it is connected or has failed.
 func (c *Client) Play(channelID string) (protocol.PlaybackState, error) {
 	var st protocol.PlaybackState
@@ -313,8 +386,8 @@ func (c *Client) Play(channelID string) (protocol.PlaybackState, error) {
 	return st, err
 }
 
-// PlayPause toggles between stopped and playing (live radio has no real
-// pause: unpausing reconnects to the live stream).
+// PlayPause pauses a playing stream (which keeps buffering) and resumes a
+// paused one; stopped playback starts the current channel.
 func (c *Client) PlayPause() (protocol.PlaybackState, error) {
 	var st protocol.PlaybackState
 	err := c.call(protocol.MethodPlayPause, nil, &st)
@@ -343,6 +416,52 @@ func (c *Client) SetVolume(v float64) (protocol.PlaybackState, error) {
 	return st, err
 }
 
+// ToggleMute silences or restores playback without dropping the stream.
+func (c *Client) ToggleMute() (protocol.PlaybackState, error) {
+	var st protocol.PlaybackState
+	err := c.call(protocol.MethodToggleMute, nil, &st)
+	return st, err
+}
+
+// SetStreamQuality picks the preferred playlist quality ("low", "high" or
+// "highest"); the server persists it and reconnects a playing channel.
+func (c *Client) SetStreamQuality(quality string) (protocol.PlaybackState, error) {
+	var st protocol.PlaybackState
+	err := c.call(protocol.MethodSetQuality, protocol.SetQualityParams{Quality: quality}, &st)
+	return st, err
+}
+
+// SetEqualizer picks the equalizer preset ("flat", "bass" or "speech"); the
+// server persists it and applies it to the playing stream.
+func (c *Client) SetEqualizer(preset string) (protocol.PlaybackState, error) {
+	var st protocol.PlaybackState
+	err := c.call(protocol.MethodSetEqualizer, protocol.SetEqualizerParams{Preset: preset}, &st)
+	return st, err
+}
+
+// Preview plays a channel quietly without picking it, while nothing else
+// plays; an empty channelID ends the preview.
+func (c *Client) Preview(channelID string) (protocol.PlaybackState, error) {
+	var st protocol.PlaybackState
+	err := c.call(protocol.MethodPreview, protocol.PreviewParams{ChannelID: channelID}, &st)
+	return st, err
+}
+
+// Prebuffer has the server fetch a channel's stream ahead of a likely
+// play, so playing it starts at once; an empty channelID drops it.
+func (c *Client) Prebuffer(channelID string) error {
+	return c.call(protocol.MethodPrebuffer, protocol.PrebufferParams{ChannelID: channelID}, nil)
+}
+
+// TimeShift moves playback seconds further behind the live stream, or
+// toward it when negative, within the server's stream buffer; live returns
+// to the live stream.
+func (c *Client) TimeShift(seconds float64, live bool) (protocol.PlaybackState, error) {
+	var st protocol.PlaybackState
+	err := c.call(protocol.MethodTimeShift, protocol.TimeShiftParams{Seconds: seconds, Live: live}, &st)
+	return st, err
+}
+
 // ToggleFavorite flips a channel's favorite flag and returns the new list.
 func (c *Client) ToggleFavorite(channelID string) ([]string, error) {
 	var result protocol.FavoritesResult
@@ -350,6 +469,41 @@ func (c *Client) ToggleFavorite(channelID string) ([]string, error) {
 	return result.Favorites, err
 }
 
+// MoveFavorite moves a favorite delta places up (negative) or down
+// (positive) among the favorites and returns the new list.
+func (c *Client) MoveFavorite(channelID string, delta int) ([]string, error) {
+	var result protocol.FavoritesResult
+	err := c.call(protocol.MethodMoveFavorite, protocol.MoveFavoriteParams{ChannelID: channelID, Delta: delta}, &result)
+	return result.Favorites, err
+}
+
+// SetChannelSort persists the channel list order ("api", "alphabetical",
+// "listeners", "genre" or "recent") for every client.
+func (c *Client) SetChannelSort(sort string) error {
+	return c.call(protocol.MethodSetSort, protocol.SetSortParams{Sort: sort}, nil)
+}
+
+// SetChannelSource persists the station source the channel list shows
+// ("somafm", "radiobrowser", "stations", or "" for all merged) for every
+// client.
+func (c *Client) SetChannelSource(source string) error {
+	return c.call(protocol.MethodSetSource, protocol.SetSourceParams{Source: source}, nil)
+}
+
+// DismissFavoritesHint tells the server the favorites onboarding hint was
+// seen, so no client shows it again.
+func (c *Client) DismissFavoritesHint() error {
+	return c.call(protocol.MethodDismissHint, nil, nil)
+}
+
+// SearchStations searches the station directory; the returned channels can
+// be played by ID until the next search.
+func (c *Client) SearchStations(query string) ([]channels.Channel, error) {
+	var result protocol.SearchStationsResult
+	err := c.call(protocol.MethodSearchStations, protocol.SearchStationsParams{Query: query}, &result)
+	return result.Channels, err
+}
+
 // Shutdown asks the server to stop playback and exit.
 func (c *Client) Shutdown() error {
 	return c.call(protocol.MethodShutdown, nil, nil)
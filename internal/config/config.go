@@ -11,9 +11,14 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"runtime"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"somad/internal/channels"
+
 	"gopkg.in/yaml.v3"
 )
 
@@ -22,6 +27,11 @@ const (
 	appDirName     = "somad"
 )
 
+// EnvPath names the environment variable that points at a config file in a
+// non-standard location. The --config flag sets it, so an auto-spawned
+// server, which inherits the environment, reads the same file.
+const EnvPath = "SOMAD_CONFIG"
+
 // Config is the parsed configuration file. Fields are pointers so an
 // explicit zero value ("tray: false", "idle_timeout: 0") is distinguishable
 // from an absent key, which falls back to the built-in default.
@@ -29,6 +39,19 @@ type Config struct {
 	Server ServerConfig `yaml:"server"`
 	Client ClientConfig `yaml:"client"`
 	TUI    TUIConfig    `yaml:"tui"`
+	// Stations are streams of the user's own, listed after the SomaFM
+	// channels. They have no daemon flag.
+	Stations []Station `yaml:"stations"`
+}
+
+// Station is a stream the user lists in the config file. URL is the stream
+// itself or a .pls playlist of it; Description and Genre (genres separated
+// by "|", as SomaFM's are) are optional.
+type Station struct {
+	Title       string `yaml:"title"`
+	URL         string `yaml:"url"`
+	Description string `yaml:"description"`
+	Genre       string `yaml:"genre"`
 }
 
 // ServerConfig configures the playback server, mirroring the flags of
@@ -40,6 +63,55 @@ type ServerConfig struct {
 	// Tray controls the system tray / menu-bar icon (the inverse of the
 	// --no-tray flag, so the file reads positively).
 	Tray *bool `yaml:"tray"`
+	// ReconnectAttempts caps how many times in a row the server retries a
+	// dropped stream before giving up and stopping; 0 retries forever.
+	ReconnectAttempts *int `yaml:"reconnect_attempts"`
+	// MaxHTTPRequests bounds how many background HTTP requests (catalog,
+	// playlist, and directory fetches) the server runs at once.
+	MaxHTTPRequests *int `yaml:"max_http_requests"`
+	// CatalogTTL is how long after downloading it the cached SomaFM channel
+	// list is used at startup without downloading it again; 0 downloads it
+	// at every start.
+	CatalogTTL *Duration `yaml:"catalog_ttl"`
+	// RefreshInterval is how often the server downloads the SomaFM channel
+	// list again while it runs; 0 disables the periodic refresh.
+	RefreshInterval *Duration `yaml:"refresh_interval"`
+	// ProbeInterval is how often the server checks that every channel's
+	// stream still answers, marking the ones that do not; 0 (the default)
+	// never checks.
+	ProbeInterval *Duration `yaml:"probe_interval"`
+	// Sources are where the channel list's stations come from, merged in
+	// this order: "somafm", "radiobrowser" (the Radio Browser directory's
+	// most played stations) and "stations" (Config.Stations). Unset lists
+	// SomaFM followed by the stations. It has no daemon flag.
+	Sources []string `yaml:"sources"`
+	// StreamQuality is the SomaFM playlist quality to play until a client
+	// picks one: "low", "high" or "highest". Unset or empty plays the best
+	// available.
+	StreamQuality *string `yaml:"stream_quality"`
+	// Player selects the audio backend: "internal" decodes and plays in
+	// process; "mpv" hands streams to an external mpv. Unset or empty is
+	// internal.
+	Player *string `yaml:"player"`
+	// Equalizer is the equalizer preset to play with until a client picks
+	// one: "flat", "bass" or "speech". Unset or empty plays flat.
+	Equalizer *string `yaml:"equalizer"`
+	// Normalize evens out loudness across channels, so switching from a
+	// quiet channel to a loud one does not jump in volume.
+	Normalize *bool `yaml:"normalize"`
+	// SilenceTimeout is how long a stream may play nothing but silence
+	// before the server treats it as dead and reconnects; 0 disables the
+	// check.
+	SilenceTimeout *Duration `yaml:"silence_timeout"`
+	// Notify shows a desktop notification for each new track.
+	Notify *bool `yaml:"notify"`
+	// NowPlayingFile is a file the server keeps holding "Channel — Artist
+	// — Song" for the playing track, e.g. for an OBS text source. Unset or
+	// empty writes no file.
+	NowPlayingFile *string `yaml:"now_playing_file"`
+	// MusicBrainz looks each new track up on MusicBrainz for the album,
+	// release year and MusicBrainz IDs its title lacks.
+	MusicBrainz *bool `yaml:"musicbrainz"`
 	// Listen is a host:port the server additionally listens on over TCP,
 	// for frontends on other machines. Empty keeps the server local-only
 	// (Unix socket).
@@ -87,6 +159,78 @@ type ClientConfig struct {
 type TUIConfig struct {
 	// ShutdownOnExit stops playback and shuts down the server when the TUI exits.
 	ShutdownOnExit *bool `yaml:"shutdown_on_exit"`
+	// AltScreen controls whether the TUI takes over the alternate screen
+	// (the inverse of the --no-altscreen flag, so the file reads positively).
+	AltScreen *bool `yaml:"alt_screen"`
+	// Compact starts the TUI in compact mode: the highlighted channel and a
+	// status line, for small tmux panes. Same as the --compact flag.
+	Compact *bool `yaml:"compact"`
+	// Grid starts the TUI with the channels as a grid of cards, on
+	// terminals wide enough for two side by side.
+	Grid *bool `yaml:"grid"`
+	// Splash shows a brief banner on launch; any key dismisses it.
+	Splash *bool `yaml:"splash"`
+	// UTCTimes shows timestamps in UTC instead of the local zone by default;
+	// the TUI's t key toggles it for the session.
+	UTCTimes *bool `yaml:"utc_times"`
+	// SecondaryLine picks what is shown under each channel title:
+	// "description" (the default) or "genre". The i key toggles it.
+	SecondaryLine *string `yaml:"secondary_line"`
+	// DefaultSort orders the channel list: "api" (SomaFM's own order, the
+	// default), "alphabetical", "listeners", "genre" or "recent" (last
+	// played first). Favorites stay on top. An order picked with the O key
+	// replaces it.
+	DefaultSort *string `yaml:"default_sort"`
+	// MinQuality hides SomaFM channels that offer no MP3 stream of at
+	// least this quality: "low", "high" or "highest". Unset or empty shows
+	// them all.
+	MinQuality *string `yaml:"min_quality"`
+	// SpaceKey is what space does in the channel list: "play" (the
+	// default) plays the selected channel, "toggle" stops playback if
+	// anything is playing and plays otherwise, and "none" ignores it.
+	SpaceKey *string `yaml:"space_key"`
+	// PreviewDelay is how long the cursor rests on a channel before it
+	// plays as a quiet preview, while nothing else plays; 0 (the default)
+	// disables previews.
+	PreviewDelay *Duration `yaml:"preview_delay"`
+	// Prebuffer fetches the stream of the channel under the cursor in the
+	// background, so playing it starts at once, at the cost of the
+	// bandwidth for streams that are never played.
+	Prebuffer *bool `yaml:"prebuffer"`
+	// Visualizer draws a spectrum analyzer of the playing audio in place of
+	// the level meter and in the full-screen now-playing view. The server
+	// analyzes the audio ten times a second while it shows, so it is off by
+	// default.
+	Visualizer *bool `yaml:"visualizer"`
+	// Details opens the channel details pane beside the list at start, on
+	// terminals wide enough for both. DetailsWidth is its width in columns,
+	// at least 28; [ and ] resize it for the session.
+	Details      *bool `yaml:"details"`
+	DetailsWidth *int  `yaml:"details_width"`
+	// Columns are the list's columns right of the channel title, in order,
+	// from listColumns; unset shows the listeners alone. ColumnWidths sets
+	// the width of any of them in cells, at least 4.
+	Columns      []string       `yaml:"columns"`
+	ColumnWidths map[string]int `yaml:"column_widths"`
+	// Theme is the built-in color palette: "somafm" (the default), "nord",
+	// "gruvbox" or "light". Colors overrides single colors of it, keyed by
+	// themeColors, each "#rrggbb", "#rgb", or an ANSI 0-255 index.
+	Theme  *string           `yaml:"theme"`
+	Colors map[string]string `yaml:"colors"`
+	// CustomAccent is the color ("#rrggbb", "#rgb", or an ANSI 0-255 index)
+	// and CustomGlyph the title prefix marking stations that are not SomaFM
+	// channels, the color in place of the theme's custom color. An empty
+	// glyph leaves only the color.
+	CustomAccent *string `yaml:"custom_accent"`
+	CustomGlyph  *string `yaml:"custom_glyph"`
+	// ImageProtocol is how the now-playing pane draws album art: "auto"
+	// (the default) guesses from the terminal, "kitty", "sixel" and
+	// "iterm" force a protocol, and "none" shows no images.
+	ImageProtocol *string `yaml:"image_protocol"`
+	// TrackSearch is where o looks up the playing track on the web:
+	// "youtube" (the default), "bandcamp", "musicbrainz", or an http(s)
+	// URL template in which "{query}" stands for the track.
+	TrackSearch *string `yaml:"track_search"`
 }
 
 // Duration wraps time.Duration so the YAML file can use Go duration syntax
@@ -108,30 +252,51 @@ func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
-// Path returns the configuration file path without requiring it to exist.
-// On Linux: $XDG_CONFIG_HOME/somad/config.yaml or ~/.config/somad/config.yaml
-// On macOS: ~/Library/Application Support/somad/config.yaml
+// Path returns the configuration file path without requiring it to exist:
+// $SOMAD_CONFIG when set, otherwise the first of the standard locations
+// that holds a file, or the preferred one when none does.
 func Path() (string, error) {
-	var baseDir string
+	if p := os.Getenv(EnvPath); p != "" {
+		return p, nil
+	}
+	candidates, err := standardPaths()
+	if err != nil {
+		return "", err
+	}
+	for _, p := range candidates {
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	return candidates[0], nil
+}
+
+// userConfigDir is the platform's config directory. A variable so tests
+// can stand in for the macOS one.
+var userConfigDir = os.UserConfigDir
 
-	// Check XDG override first (works on all platforms, enables testing)
+// standardPaths lists where the config file may live, preferred first:
+// $XDG_CONFIG_HOME/somad/config.yaml when XDG_CONFIG_HOME is set, then
+// ~/.config/somad/config.yaml, then the legacy location in the platform's
+// config directory, ~/Library/Application Support/somad/config.yaml on
+// macOS. On Linux the legacy location is one of the first two and is not
+// listed again.
+func standardPaths() ([]string, error) {
+	var paths []string
 	if xdgConfig := os.Getenv("XDG_CONFIG_HOME"); xdgConfig != "" {
-		baseDir = xdgConfig
-	} else if runtime.GOOS == "darwin" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("failed to get home directory: %w", err)
-		}
-		baseDir = filepath.Join(homeDir, "Library", "Application Support")
-	} else {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("failed to get home directory: %w", err)
+		paths = append(paths, filepath.Join(xdgConfig, appDirName, configFileName))
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	paths = append(paths, filepath.Join(homeDir, ".config", appDirName, configFileName))
+	if dir, err := userConfigDir(); err == nil {
+		if legacy := filepath.Join(dir, appDirName, configFileName); !slices.Contains(paths, legacy) {
+			paths = append(paths, legacy)
 		}
-		baseDir = filepath.Join(homeDir, ".config")
 	}
-
-	return filepath.Join(baseDir, appDirName, configFileName), nil
+	return paths, nil
 }
 
 // Load reads the configuration file. A missing file is not an error and
@@ -144,9 +309,11 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
-	data, err := os.ReadFile(path) // #nosec G304 -- path derived from the user config dir, not user input
+	data, err := os.ReadFile(path) // #nosec G304 -- the user config dir or a path the user named explicitly
 	if err != nil {
-		if os.IsNotExist(err) {
+		// A file the user named explicitly must exist; only the standard
+		// locations are optional.
+		if os.IsNotExist(err) && os.Getenv(EnvPath) == "" {
 			return &Config{}, nil
 		}
 		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
@@ -167,6 +334,30 @@ func Load() (*Config, error) {
 	if cfg.Server.IdleTimeout != nil && *cfg.Server.IdleTimeout < 0 {
 		return nil, fmt.Errorf("invalid config file %s: server.idle_timeout must not be negative", path)
 	}
+	if cfg.Server.SilenceTimeout != nil && *cfg.Server.SilenceTimeout < 0 {
+		return nil, fmt.Errorf("invalid config file %s: server.silence_timeout must not be negative", path)
+	}
+	if cfg.Server.CatalogTTL != nil && *cfg.Server.CatalogTTL < 0 {
+		return nil, fmt.Errorf("invalid config file %s: server.catalog_ttl must not be negative", path)
+	}
+	if cfg.Server.RefreshInterval != nil && *cfg.Server.RefreshInterval < 0 {
+		return nil, fmt.Errorf("invalid config file %s: server.refresh_interval must not be negative", path)
+	}
+	if cfg.Server.ProbeInterval != nil && *cfg.Server.ProbeInterval < 0 {
+		return nil, fmt.Errorf("invalid config file %s: server.probe_interval must not be negative", path)
+	}
+	if cfg.TUI.PreviewDelay != nil && *cfg.TUI.PreviewDelay < 0 {
+		return nil, fmt.Errorf("invalid config file %s: tui.preview_delay must not be negative", path)
+	}
+	if cfg.TUI.DetailsWidth != nil && *cfg.TUI.DetailsWidth < 28 {
+		return nil, fmt.Errorf("invalid config file %s: tui.details_width must be at least 28", path)
+	}
+	if cfg.Server.ReconnectAttempts != nil && *cfg.Server.ReconnectAttempts < 0 {
+		return nil, fmt.Errorf("invalid config file %s: server.reconnect_attempts must not be negative", path)
+	}
+	if cfg.Server.MaxHTTPRequests != nil && *cfg.Server.MaxHTTPRequests < 1 {
+		return nil, fmt.Errorf("invalid config file %s: server.max_http_requests must be at least 1", path)
+	}
 	if err := cfg.validate(); err != nil {
 		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
 	}
@@ -189,9 +380,156 @@ func (c *Config) validate() error {
 	if set(c.Client.TLSCA) && set(c.Client.TLSFingerprint) {
 		return errors.New("client.tls_ca and client.tls_fingerprint are mutually exclusive")
 	}
+	if c.Server.StreamQuality != nil {
+		switch *c.Server.StreamQuality {
+		case "", "low", "high", "highest":
+		default:
+			return fmt.Errorf("server.stream_quality %q is not one of low, high, highest", *c.Server.StreamQuality)
+		}
+	}
+	if c.Server.Player != nil {
+		switch *c.Server.Player {
+		case "", "internal", "mpv":
+		default:
+			return fmt.Errorf("server.player %q is not one of internal, mpv", *c.Server.Player)
+		}
+	}
+	if c.Server.Equalizer != nil {
+		switch *c.Server.Equalizer {
+		case "", "flat", "bass", "speech":
+		default:
+			return fmt.Errorf("server.equalizer %q is not one of flat, bass, speech", *c.Server.Equalizer)
+		}
+	}
+	if c.TUI.SecondaryLine != nil {
+		switch *c.TUI.SecondaryLine {
+		case "description", "genre":
+		default:
+			return fmt.Errorf("tui.secondary_line %q is not one of description, genre", *c.TUI.SecondaryLine)
+		}
+	}
+	if c.TUI.DefaultSort != nil {
+		switch *c.TUI.DefaultSort {
+		case "api", "alphabetical", "listeners", "genre", "recent":
+		default:
+			return fmt.Errorf("tui.default_sort %q is not one of api, alphabetical, listeners, genre, recent", *c.TUI.DefaultSort)
+		}
+	}
+	if c.TUI.MinQuality != nil {
+		switch *c.TUI.MinQuality {
+		case "", "low", "high", "highest":
+		default:
+			return fmt.Errorf("tui.min_quality %q is not one of low, high, highest", *c.TUI.MinQuality)
+		}
+	}
+	if c.TUI.SpaceKey != nil {
+		switch *c.TUI.SpaceKey {
+		case "play", "toggle", "none":
+		default:
+			return fmt.Errorf("tui.space_key %q is not one of play, toggle, none", *c.TUI.SpaceKey)
+		}
+	}
+	if c.TUI.Theme != nil && !slices.Contains(themes, *c.TUI.Theme) {
+		return fmt.Errorf("tui.theme %q is not one of %s", *c.TUI.Theme, strings.Join(themes, ", "))
+	}
+	for name, color := range c.TUI.Colors {
+		switch {
+		case !slices.Contains(themeColors, name):
+			return fmt.Errorf("tui.colors: %q is not one of %s", name, strings.Join(themeColors, ", "))
+		case !validColor(color):
+			return fmt.Errorf("tui.colors.%s %q is not a color (use \"#rrggbb\", \"#rgb\", or an ANSI index 0-255)", name, color)
+		}
+	}
+	shown := make(map[string]bool, len(c.TUI.Columns))
+	for _, name := range c.TUI.Columns {
+		switch {
+		case !slices.Contains(listColumns, name):
+			return fmt.Errorf("tui.columns: %q is not one of %s", name, strings.Join(listColumns, ", "))
+		case shown[name]:
+			return fmt.Errorf("tui.columns lists %q twice", name)
+		}
+		shown[name] = true
+	}
+	for name, width := range c.TUI.ColumnWidths {
+		switch {
+		case !slices.Contains(listColumns, name):
+			return fmt.Errorf("tui.column_widths: %q is not one of %s", name, strings.Join(listColumns, ", "))
+		case width < 4:
+			return fmt.Errorf("tui.column_widths.%s must be at least 4", name)
+		}
+	}
+	if c.TUI.CustomAccent != nil && !validColor(*c.TUI.CustomAccent) {
+		return fmt.Errorf("tui.custom_accent %q is not a color (use \"#rrggbb\", \"#rgb\", or an ANSI index 0-255)", *c.TUI.CustomAccent)
+	}
+	if c.TUI.ImageProtocol != nil {
+		switch *c.TUI.ImageProtocol {
+		case "auto", "kitty", "sixel", "iterm", "none":
+		default:
+			return fmt.Errorf("tui.image_protocol %q is not one of auto, kitty, sixel, iterm, none", *c.TUI.ImageProtocol)
+		}
+	}
+	if c.Server.Sources != nil && len(c.Server.Sources) == 0 {
+		return errors.New("server.sources must name at least one source")
+	}
+	sources := make(map[string]bool, len(c.Server.Sources))
+	for _, src := range c.Server.Sources {
+		switch {
+		case src != "somafm" && src != "radiobrowser" && src != "stations":
+			return fmt.Errorf("server.sources: %q is not one of somafm, radiobrowser, stations", src)
+		case sources[src]:
+			return fmt.Errorf("server.sources lists %q twice", src)
+		}
+		sources[src] = true
+	}
+	// A station's ID is made from its title, so two titles that make the
+	// same ID would be one station to favorites and playback.
+	titles := make(map[string]string, len(c.Stations)) // by station ID
+	for i, st := range c.Stations {
+		id := channels.CustomID(st.Title)
+		switch {
+		case strings.TrimSpace(st.Title) == "":
+			return fmt.Errorf("stations[%d] has no title", i)
+		case id == channels.CustomIDPrefix:
+			return fmt.Errorf("stations[%d]: title %q needs a letter or digit to make the station's ID from", i, st.Title)
+		case titles[id] != "":
+			return fmt.Errorf("stations[%d]: title %q is too close to %q; both make the station ID %q", i, st.Title, titles[id], id)
+		case !strings.HasPrefix(st.URL, "https://") && !strings.HasPrefix(st.URL, "http://"):
+			return fmt.Errorf("stations[%d] (%s): url %q is not an http(s) URL", i, st.Title, st.URL)
+		}
+		titles[id] = st.Title
+	}
+	if c.TUI.TrackSearch != nil {
+		switch s := *c.TUI.TrackSearch; {
+		case s == "youtube", s == "bandcamp", s == "musicbrainz":
+		case (strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")) && strings.Contains(s, "{query}"):
+		default:
+			return fmt.Errorf("tui.track_search %q is not youtube, bandcamp, musicbrainz, or an http(s) URL containing {query}", s)
+		}
+	}
 	return nil
 }
 
+// themes and themeColors mirror the ui package's built-in themes and the
+// colors a theme is made of; listColumns mirrors the columns its channel
+// list can show.
+var (
+	themes      = []string{"somafm", "nord", "gruvbox", "light"}
+	themeColors = []string{"title", "primary", "playing", "error", "subtle", "search_match", "custom", "text", "bright"}
+	listColumns = []string{"listeners", "genre", "quality", "last_playing"}
+)
+
+var hexColorRe = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor reports whether s is a color the terminal renderer accepts: a
+// hex RGB value or an ANSI 256-color index.
+func validColor(s string) bool {
+	if hexColorRe.MatchString(s) {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
+
 // templateFormat is the generated default config file. Every setting is
 // commented out, so parsing it yields the built-in defaults even when those
 // change in a later release.
@@ -214,6 +552,75 @@ const templateFormat = `# Soma configuration file.
 #  # "tray: false" is the same as the --no-tray flag.
 #  tray: true
 #
+#  # Give up reconnecting a dropped stream after this many failed attempts
+#  # in a row and stop, so clients can offer to retry or move on. 0 keeps
+#  # retrying until stopped (the default). Same as --reconnect-attempts.
+#  reconnect_attempts: 0
+#
+#  # How many background HTTP requests (catalog, playlists, station
+#  # directory) may run at once. Same as --max-http-requests.
+#  max_http_requests: %d
+#
+#  # Start from the cached SomaFM channel list for this long after it was
+#  # downloaded, instead of downloading it again. "0" downloads it at every
+#  # start. Same as --catalog-ttl.
+#  catalog_ttl: %s
+#
+#  # Download the SomaFM channel list again this often while the server
+#  # runs, for fresh listener counts and songs. "0" only downloads it at
+#  # start and on Ctrl+R in the TUI. Same as --refresh-interval.
+#  refresh_interval: %s
+#
+#  # Check this often that every channel's stream still answers, dimming
+#  # the ones that do not; Ctrl+R in the TUI checks at once too. "0" never
+#  # checks (the default). Same as --probe-interval.
+#  probe_interval: 0s
+#
+#  # Where the channel list's stations come from, merged in this order:
+#  # somafm, radiobrowser (the Radio Browser directory's most played
+#  # stations) and stations (the streams at the end of this file). S in the
+#  # TUI switches between the merge and a single source. No daemon flag.
+#  sources: [somafm, stations]
+#
+#  # Which SomaFM stream quality to play: low, high or highest ("" picks
+#  # the best available). A channel without it plays the nearest one; Q
+#  # in the TUI switches and remembers it. Same as --stream-quality.
+#  stream_quality: ""
+#
+#  # Audio backend: internal, or mpv to play through an external mpv
+#  # (which must be installed) where the built-in output has trouble with
+#  # the sound system. mpv has no level meter, equalizer, time shift or
+#  # normalization. Same as --player.
+#  player: internal
+#
+#  # Equalizer preset: flat, bass (boosts the lows) or speech (cuts the
+#  # rumble, lifts voices). E in the TUI switches and remembers it. Same as
+#  # --equalizer.
+#  equalizer: flat
+#
+#  # Even out loudness across channels, so switching from a quiet channel
+#  # to a loud one does not blast your ears. Same as --normalize.
+#  normalize: false
+#
+#  # Treat a stream that plays only silence for this long as dead and
+#  # reconnect it, showing why in the status bar. "0" disables the check
+#  # (the default). Same as --silence-timeout.
+#  silence_timeout: "0"
+#
+#  # Show a desktop notification with the song, artist and channel when the
+#  # track changes (at most one every few seconds). Same as --notify.
+#  notify: false
+#
+#  # Keep this file holding "Channel — Artist — Song" for the playing
+#  # track, e.g. for a streaming overlay; it is empty while nothing plays.
+#  # "" writes no file (the default). Same as --now-playing-file.
+#  now_playing_file: ""
+#
+#  # Look each new track up on MusicBrainz for its album, release year and
+#  # MusicBrainz IDs, shown in the now-playing pane and passed to MPRIS.
+#  # Same as --musicbrainz.
+#  musicbrainz: false
+#
 #  # Also listen for frontends on TCP (host:port), e.g. to control this
 #  # machine's playback from a laptop. Same as the --listen flag. The Unix
 #  # socket stays available either way; empty disables TCP (the default).
@@ -261,17 +668,117 @@ const templateFormat = `# Soma configuration file.
 #  # Stop playback and shut down the server when closing the TUI.
 #  # Same as the --shutdown-on-exit flag.
 #  shutdown_on_exit: false
+#
+#  # Draw the TUI on the terminal's alternate screen. "alt_screen: false"
+#  # renders inline, keeping it in the scrollback; same as --no-altscreen.
+#  alt_screen: true
+#
+#  # Start collapsed to the highlighted channel and a status line, for
+#  # small tmux panes; z toggles it. Same as the --compact flag.
+#  compact: false
+#
+#  # Show the channels as a grid of cards (title, genres, listeners)
+#  # instead of the list, on terminals wide enough for it; G toggles it.
+#  grid: false
+#
+#  # Show a brief banner on launch (any key dismisses it).
+#  splash: false
+#
+#  # Show timestamps in UTC instead of local time (t toggles it in the
+#  # TUI).
+#  utc_times: false
+#
+#  # What to show under each channel title: "description" or "genre" (i
+#  # toggles it in the TUI).
+#  secondary_line: description
+#
+#  # Order of the channel list: "api" (SomaFM's own order),
+#  # "alphabetical", "listeners" (most first), "genre" or "recent" (last
+#  # played first). Favorites stay on top. Picking an order with O
+#  # replaces this.
+#  default_sort: api
+#
+#  # Hide channels without an MP3 stream of at least this quality: low,
+#  # high or highest ("" lists every channel).
+#  min_quality: ""
+#
+#  # What space does in the channel list: "play" plays the selected
+#  # channel, "toggle" stops playback if anything is playing (and plays
+#  # otherwise), "none" ignores it. Space always closes the about footer.
+#  space_key: play
+#
+#  # Preview a channel at low volume once the cursor rests on it this
+#  # long while nothing is playing; Enter keeps it playing, moving on
+#  # stops it. "0" disables previews (the default).
+#  preview_delay: "0"
+#
+#  # Buffer the stream of the channel under the cursor in the background,
+#  # so Enter starts it at once. Costs bandwidth while browsing.
+#  prebuffer: false
+#
+#  # Draw a spectrum analyzer of the playing audio instead of the level
+#  # meter, and in the full-screen now-playing view (P). Costs some CPU
+#  # while it shows.
+#  visualizer: false
+#
+#  # Open the details pane (D) beside the list at start, where the terminal
+#  # is wide enough, and how many columns it takes; [ and ] resize it.
+#  details: false
+#  details_width: 40
+#
+#  # The list's columns right of the channel title, in order: listeners,
+#  # genre, quality (the best stream) and last_playing (the latest track).
+#  # column_widths sets the width of any of them, e.g. {last_playing: 40}.
+#  # Columns that do not fit a narrow terminal are left out, last first.
+#  columns: [listeners]
+#  column_widths: {}
+#
+#  # Color theme: somafm, nord, gruvbox, or light (for light terminal
+#  # backgrounds). colors overrides single colors of it ("#rrggbb",
+#  # "#rgb", or an ANSI index 0-255): title, primary (the accent), playing,
+#  # error, subtle, search_match, custom, text and bright (channel titles).
+#  theme: somafm
+#  colors: {}
+#
+#  # Accent for stations that are not SomaFM channels: a title color
+#  # ("#rrggbb", "#rgb", or an ANSI index 0-255; replaces the theme's
+#  # custom color) and a glyph before the title ("" for none).
+#  custom_accent: "#AE81FF"
+#  custom_glyph: "◆"
+#
+#  # How the now-playing pane (A) draws album art: auto guesses from the
+#  # terminal; kitty, sixel or iterm force a protocol; none shows no art.
+#  image_protocol: auto
+#
+#  # Where o looks up the playing track: youtube, bandcamp, musicbrainz,
+#  # or a URL in which {query} stands for the track, e.g.
+#  # "https://www.discogs.com/search?q={query}".
+#  track_search: youtube
+
+# Streams of your own, listed after the SomaFM channels. url is the stream
+# itself or a .pls playlist of it; description and genre (several separated
+# by "|") are optional. There is no daemon flag for these.
+#stations:
+#  - title: My Station
+#    url: "https://radio.example.org/live.mp3"
+#    description: ""
+#    genre: "jazz|blues"
 `
 
 // EnsureTemplate writes the commented-out default template to Path() when no
 // config file exists yet, so the settings are discoverable without the docs.
-// It never touches an existing file. It reports the path it considered and
-// whether it created the file.
-func EnsureTemplate(defaultIdleTimeout time.Duration) (path string, created bool, err error) {
+// It never touches an existing file, nor a path named through $SOMAD_CONFIG:
+// that one is the user's to create. It reports the path it considered and
+// whether it created the file. The defaults owned by other packages are
+// passed in, keeping this package free of their dependencies.
+func EnsureTemplate(defaultIdleTimeout time.Duration, defaultMaxHTTPRequests int, defaultCatalogTTL, defaultRefreshInterval time.Duration) (path string, created bool, err error) {
 	path, err = Path()
 	if err != nil {
 		return "", false, err
 	}
+	if os.Getenv(EnvPath) != "" {
+		return path, false, nil
+	}
 	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
 		return path, false, fmt.Errorf("failed to create config directory: %w", err)
 	}
@@ -284,7 +791,7 @@ func EnsureTemplate(defaultIdleTimeout time.Duration) (path string, created bool
 		}
 		return path, false, fmt.Errorf("failed to create config file: %w", err)
 	}
-	_, werr := fmt.Fprintf(f, templateFormat, defaultIdleTimeout)
+	_, werr := fmt.Fprintf(f, templateFormat, defaultIdleTimeout, defaultMaxHTTPRequests, defaultCatalogTTL, defaultRefreshInterval)
 	cerr := f.Close()
 	if werr == nil {
 		werr = cerr
@@ -16,20 +16,127 @@ import (
 // override to point at a temp directory.
 func writeConfig(t *testing.T, content string) {
 	t.Helper()
+	isolateHome(t)
 	dir := t.TempDir()
 	t.Setenv("XDG_CONFIG_HOME", dir)
 	require.NoError(t, os.MkdirAll(filepath.Join(dir, appDirName), 0o750))
 	require.NoError(t, os.WriteFile(filepath.Join(dir, appDirName, configFileName), []byte(content), 0o600))
 }
 
+// isolateHome points the home and legacy config directories at temp
+// directories, so the real ones never leak into a test, and returns them.
+func isolateHome(t *testing.T) (home, legacy string) {
+	t.Helper()
+	home, legacy = t.TempDir(), t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(EnvPath, "")
+	prev := userConfigDir
+	userConfigDir = func() (string, error) { return legacy, nil }
+	t.Cleanup(func() { userConfigDir = prev })
+	return home, legacy
+}
+
+// placeConfig writes an empty config file under dir's somad directory and
+// returns its path.
+func placeConfig(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, appDirName, configFileName)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+	return path
+}
+
 func TestPathUsesXDGOverride(t *testing.T) {
+	isolateHome(t)
 	t.Setenv("XDG_CONFIG_HOME", "/custom/config")
 	path, err := Path()
 	require.NoError(t, err)
-	assert.Equal(t, filepath.Join("/custom/config", appDirName, configFileName), path)
+	assert.Equal(t, filepath.Join("/custom/config", appDirName, configFileName), path, "with no file anywhere, the first location")
+}
+
+func TestPathUsesEnvOverride(t *testing.T) {
+	home, legacy := isolateHome(t)
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	placeConfig(t, xdg)
+	placeConfig(t, filepath.Join(home, ".config"))
+	placeConfig(t, legacy)
+	t.Setenv(EnvPath, "/elsewhere/soma.yaml")
+	path, err := Path()
+	require.NoError(t, err)
+	assert.Equal(t, "/elsewhere/soma.yaml", path, "an explicit path beats the standard locations")
+}
+
+func TestPathPrefersXDG(t *testing.T) {
+	home, legacy := isolateHome(t)
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	want := placeConfig(t, xdg)
+	placeConfig(t, filepath.Join(home, ".config"))
+	placeConfig(t, legacy)
+
+	path, err := Path()
+	require.NoError(t, err)
+	assert.Equal(t, want, path)
+}
+
+func TestPathFallsBackToDotConfig(t *testing.T) {
+	home, legacy := isolateHome(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dotConfig := placeConfig(t, filepath.Join(home, ".config"))
+	placeConfig(t, legacy)
+
+	path, err := Path()
+	require.NoError(t, err)
+	assert.Equal(t, dotConfig, path, "~/.config is tried when $XDG_CONFIG_HOME holds no file")
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+	path, err = Path()
+	require.NoError(t, err)
+	assert.Equal(t, dotConfig, path)
+}
+
+func TestPathFallsBackToLegacyDir(t *testing.T) {
+	_, legacy := isolateHome(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	want := placeConfig(t, legacy)
+
+	path, err := Path()
+	require.NoError(t, err)
+	assert.Equal(t, want, path)
+}
+
+func TestLoadUsesEnvOverride(t *testing.T) {
+	writeConfig(t, "tui:\n  alt_screen: true\n")
+	override := filepath.Join(t.TempDir(), "other.yaml")
+	require.NoError(t, os.WriteFile(override, []byte("tui:\n  alt_screen: false\n"), 0o600))
+	t.Setenv(EnvPath, override)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.TUI.AltScreen)
+	assert.False(t, *cfg.TUI.AltScreen)
+}
+
+func TestLoadMissingExplicitFileIsError(t *testing.T) {
+	t.Setenv(EnvPath, filepath.Join(t.TempDir(), "missing.yaml"))
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing.yaml")
+}
+
+func TestEnsureTemplateLeavesExplicitPathAlone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "soma.yaml")
+	t.Setenv(EnvPath, path)
+
+	_, created, err := EnsureTemplate(0, 4, time.Hour, 10*time.Minute)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.NoFileExists(t, path)
 }
 
 func TestLoadMissingFileIsEmptyConfig(t *testing.T) {
+	isolateHome(t)
 	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
 	cfg, err := Load()
 	require.NoError(t, err)
@@ -48,15 +155,43 @@ func TestLoadEmptyFileIsEmptyConfig(t *testing.T) {
 }
 
 func TestLoadFullConfig(t *testing.T) {
-	writeConfig(t, "server:\n  idle_timeout: 5m\n  tray: false\ntui:\n  shutdown_on_exit: true\n")
+	writeConfig(t, "server:\n  idle_timeout: 5m\n  tray: false\n  reconnect_attempts: 5\n  max_http_requests: 2\n  notify: true\n  now_playing_file: /tmp/np.txt\n  musicbrainz: true\ntui:\n  shutdown_on_exit: true\n  alt_screen: false\n  compact: true\n  grid: true\n  splash: true\n  utc_times: true\n  prebuffer: true\n  visualizer: true\n  details: true\n  details_width: 56\n")
 	cfg, err := Load()
 	require.NoError(t, err)
 	require.NotNil(t, cfg.Server.IdleTimeout)
 	assert.Equal(t, 5*time.Minute, time.Duration(*cfg.Server.IdleTimeout))
 	require.NotNil(t, cfg.Server.Tray)
 	assert.False(t, *cfg.Server.Tray)
+	require.NotNil(t, cfg.Server.ReconnectAttempts)
+	assert.Equal(t, 5, *cfg.Server.ReconnectAttempts)
+	require.NotNil(t, cfg.Server.MaxHTTPRequests)
+	assert.Equal(t, 2, *cfg.Server.MaxHTTPRequests)
+	require.NotNil(t, cfg.Server.Notify)
+	assert.True(t, *cfg.Server.Notify)
+	require.NotNil(t, cfg.Server.NowPlayingFile)
+	assert.Equal(t, "/tmp/np.txt", *cfg.Server.NowPlayingFile)
+	require.NotNil(t, cfg.Server.MusicBrainz)
+	assert.True(t, *cfg.Server.MusicBrainz)
 	require.NotNil(t, cfg.TUI.ShutdownOnExit)
 	assert.True(t, *cfg.TUI.ShutdownOnExit)
+	require.NotNil(t, cfg.TUI.AltScreen)
+	assert.False(t, *cfg.TUI.AltScreen)
+	require.NotNil(t, cfg.TUI.Compact)
+	assert.True(t, *cfg.TUI.Compact)
+	require.NotNil(t, cfg.TUI.Grid)
+	assert.True(t, *cfg.TUI.Grid)
+	require.NotNil(t, cfg.TUI.Splash)
+	assert.True(t, *cfg.TUI.Splash)
+	require.NotNil(t, cfg.TUI.UTCTimes)
+	assert.True(t, *cfg.TUI.UTCTimes)
+	require.NotNil(t, cfg.TUI.Prebuffer)
+	assert.True(t, *cfg.TUI.Prebuffer)
+	require.NotNil(t, cfg.TUI.Visualizer)
+	assert.True(t, *cfg.TUI.Visualizer)
+	require.NotNil(t, cfg.TUI.Details)
+	assert.True(t, *cfg.TUI.Details)
+	require.NotNil(t, cfg.TUI.DetailsWidth)
+	assert.Equal(t, 56, *cfg.TUI.DetailsWidth)
 }
 
 func TestLoadPartialConfigLeavesRestUnset(t *testing.T) {
@@ -112,10 +247,334 @@ func TestLoadRejectsNegativeIdleTimeout(t *testing.T) {
 	assert.Contains(t, err.Error(), "must not be negative")
 }
 
+func TestLoadSilenceTimeout(t *testing.T) {
+	writeConfig(t, "server:\n  silence_timeout: 2m\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Server.SilenceTimeout)
+	assert.Equal(t, Duration(2*time.Minute), *cfg.Server.SilenceTimeout)
+
+	writeConfig(t, "server:\n  silence_timeout: -1s\n")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "silence_timeout must not be negative")
+}
+
+func TestLoadCatalogTTL(t *testing.T) {
+	writeConfig(t, "server:\n  catalog_ttl: 6h\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Server.CatalogTTL)
+	assert.Equal(t, Duration(6*time.Hour), *cfg.Server.CatalogTTL)
+
+	writeConfig(t, "server:\n  catalog_ttl: -1h\n")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "catalog_ttl must not be negative")
+}
+
+func TestLoadRefreshInterval(t *testing.T) {
+	writeConfig(t, "server:\n  refresh_interval: 30m\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Server.RefreshInterval)
+	assert.Equal(t, Duration(30*time.Minute), *cfg.Server.RefreshInterval)
+
+	writeConfig(t, "server:\n  refresh_interval: -1m\n")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "refresh_interval must not be negative")
+}
+
+func TestLoadProbeInterval(t *testing.T) {
+	writeConfig(t, "server:\n  probe_interval: 1h\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Server.ProbeInterval)
+	assert.Equal(t, Duration(time.Hour), *cfg.Server.ProbeInterval)
+
+	writeConfig(t, "server:\n  probe_interval: -1m\n")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "probe_interval must not be negative")
+}
+
+func TestLoadPreviewDelay(t *testing.T) {
+	writeConfig(t, "tui:\n  preview_delay: 2s\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.TUI.PreviewDelay)
+	assert.Equal(t, Duration(2*time.Second), *cfg.TUI.PreviewDelay)
+
+	writeConfig(t, "tui:\n  preview_delay: -2s\n")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "preview_delay must not be negative")
+}
+
+func TestLoadRejectsNarrowDetailsWidth(t *testing.T) {
+	writeConfig(t, "tui:\n  details_width: 20\n")
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "details_width must be at least 28")
+}
+
+func TestLoadRejectsNegativeReconnectAttempts(t *testing.T) {
+	writeConfig(t, "server:\n  reconnect_attempts: -1\n")
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reconnect_attempts must not be negative")
+}
+
+func TestLoadRejectsZeroMaxHTTPRequests(t *testing.T) {
+	writeConfig(t, "server:\n  max_http_requests: 0\n")
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_http_requests must be at least 1")
+}
+
+func TestLoadCustomAccent(t *testing.T) {
+	writeConfig(t, "tui:\n  custom_accent: \"#0f0\"\n  custom_glyph: \"\"\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.TUI.CustomAccent)
+	assert.Equal(t, "#0f0", *cfg.TUI.CustomAccent)
+	require.NotNil(t, cfg.TUI.CustomGlyph)
+	assert.Empty(t, *cfg.TUI.CustomGlyph, "an explicit empty glyph is kept")
+}
+
+func TestLoadRejectsInvalidCustomAccent(t *testing.T) {
+	for _, accent := range []string{"purple", "#12345", "256", "-1"} {
+		writeConfig(t, "tui:\n  custom_accent: \""+accent+"\"\n")
+		_, err := Load()
+		require.Error(t, err, accent)
+		assert.Contains(t, err.Error(), "tui.custom_accent")
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	writeConfig(t, "tui:\n  theme: nord\n  colors:\n    primary: \"#ff8800\"\n    subtle: \"244\"\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.TUI.Theme)
+	assert.Equal(t, "nord", *cfg.TUI.Theme)
+	assert.Equal(t, map[string]string{"primary": "#ff8800", "subtle": "244"}, cfg.TUI.Colors)
+}
+
+func TestLoadRejectsInvalidTheme(t *testing.T) {
+	for content, want := range map[string]string{
+		"tui:\n  theme: solarized\n":              "tui.theme \"solarized\" is not one of",
+		"tui:\n  colors:\n    accent: \"#fff\"\n": "tui.colors: \"accent\" is not one of",
+		"tui:\n  colors:\n    primary: orange\n":  "tui.colors.primary \"orange\" is not a color",
+	} {
+		writeConfig(t, content)
+		_, err := Load()
+		require.Error(t, err, content)
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestLoadColumns(t *testing.T) {
+	writeConfig(t, "tui:\n  columns: [genre, listeners]\n  column_widths:\n    genre: 24\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"genre", "listeners"}, cfg.TUI.Columns)
+	assert.Equal(t, map[string]int{"genre": 24}, cfg.TUI.ColumnWidths)
+
+	writeConfig(t, "tui:\n  columns: []\n")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.NotNil(t, cfg.TUI.Columns, "an empty list shows the title alone")
+	assert.Empty(t, cfg.TUI.Columns)
+}
+
+func TestLoadRejectsInvalidColumns(t *testing.T) {
+	for content, want := range map[string]string{
+		"tui:\n  columns: [listeners, bitrate]\n":       "tui.columns: \"bitrate\" is not one of",
+		"tui:\n  columns: [genre, genre]\n":             "tui.columns lists \"genre\" twice",
+		"tui:\n  column_widths:\n    bitrate: 10\n":     "tui.column_widths: \"bitrate\" is not one of",
+		"tui:\n  column_widths:\n    last_playing: 2\n": "tui.column_widths.last_playing must be at least 4",
+	} {
+		writeConfig(t, content)
+		_, err := Load()
+		require.Error(t, err, content)
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestLoadSecondaryLine(t *testing.T) {
+	writeConfig(t, "tui:\n  secondary_line: genre\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.TUI.SecondaryLine)
+	assert.Equal(t, "genre", *cfg.TUI.SecondaryLine)
+
+	writeConfig(t, "tui:\n  secondary_line: listeners\n")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tui.secondary_line")
+}
+
+func TestLoadDefaultSort(t *testing.T) {
+	writeConfig(t, "tui:\n  default_sort: alphabetical\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.TUI.DefaultSort)
+	assert.Equal(t, "alphabetical", *cfg.TUI.DefaultSort)
+
+	writeConfig(t, "tui:\n  default_sort: recent\n")
+	_, err = Load()
+	require.NoError(t, err)
+
+	writeConfig(t, "tui:\n  default_sort: popularity\n")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tui.default_sort")
+}
+
+func TestLoadStations(t *testing.T) {
+	writeConfig(t, "stations:\n  - title: Jazz One\n    url: http://jazz.example.org/live\n    genre: jazz\n  - title: Talk\n    url: https://talk.example.org/talk.pls\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []Station{
+		{Title: "Jazz One", URL: "http://jazz.example.org/live", Genre: "jazz"},
+		{Title: "Talk", URL: "https://talk.example.org/talk.pls"},
+	}, cfg.Stations)
+
+	for yaml, want := range map[string]string{
+		"stations:\n  - url: http://jazz.example.org/live\n":                                                                          "has no title",
+		"stations:\n  - title: Jazz\n    url: jazz.example.org\n":                                                                     "not an http(s) URL",
+		"stations:\n  - title: Jazz\n    url: http://a.example.org/\n  - title: jazz\n    url: http://b.example.org/\n":               `both make the station ID "custom:jazz"`,
+		"stations:\n  - title: Jazz & Blues\n    url: http://a.example.org/\n  - title: Jazz Blues\n    url: http://b.example.org/\n": `title "Jazz Blues" is too close to "Jazz & Blues"`,
+		"stations:\n  - title: \"★\"\n    url: http://a.example.org/\n":                                                               "needs a letter or digit",
+	} {
+		writeConfig(t, yaml)
+		_, err = Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestLoadSources(t *testing.T) {
+	writeConfig(t, "server:\n  sources: [radiobrowser, somafm]\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"radiobrowser", "somafm"}, cfg.Server.Sources)
+
+	for yaml, want := range map[string]string{
+		"server:\n  sources: []\n":                "at least one source",
+		"server:\n  sources: [somafm, icecast]\n": `"icecast" is not one of`,
+		"server:\n  sources: [somafm, somafm]\n":  "twice",
+	} {
+		writeConfig(t, yaml)
+		_, err = Load()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), want)
+	}
+}
+
+func TestLoadMinQuality(t *testing.T) {
+	writeConfig(t, "tui:\n  min_quality: high\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.TUI.MinQuality)
+	assert.Equal(t, "high", *cfg.TUI.MinQuality)
+
+	writeConfig(t, "tui:\n  min_quality: 128k\n")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tui.min_quality")
+}
+
+func TestLoadStreamQuality(t *testing.T) {
+	writeConfig(t, "server:\n  stream_quality: low\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Server.StreamQuality)
+	assert.Equal(t, "low", *cfg.Server.StreamQuality)
+
+	writeConfig(t, "server:\n  stream_quality: 320k\n")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server.stream_quality")
+}
+
+func TestLoadEqualizer(t *testing.T) {
+	writeConfig(t, "server:\n  equalizer: speech\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Server.Equalizer)
+	assert.Equal(t, "speech", *cfg.Server.Equalizer)
+
+	writeConfig(t, "server:\n  equalizer: loudness\n")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server.equalizer")
+}
+
+func TestLoadPlayer(t *testing.T) {
+	writeConfig(t, "server:\n  player: mpv\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.Server.Player)
+	assert.Equal(t, "mpv", *cfg.Server.Player)
+
+	writeConfig(t, "server:\n  player: ffplay\n")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "server.player")
+}
+
+func TestLoadSpaceKey(t *testing.T) {
+	for _, action := range []string{"play", "toggle", "none"} {
+		writeConfig(t, "tui:\n  space_key: "+action+"\n")
+		cfg, err := Load()
+		require.NoError(t, err, action)
+		require.NotNil(t, cfg.TUI.SpaceKey)
+		assert.Equal(t, action, *cfg.TUI.SpaceKey)
+	}
+
+	writeConfig(t, "tui:\n  space_key: pause\n")
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tui.space_key")
+}
+
+func TestLoadImageProtocol(t *testing.T) {
+	writeConfig(t, "tui:\n  image_protocol: sixel\n")
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg.TUI.ImageProtocol)
+	assert.Equal(t, "sixel", *cfg.TUI.ImageProtocol)
+
+	writeConfig(t, "tui:\n  image_protocol: ascii\n")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tui.image_protocol")
+}
+
+func TestLoadTrackSearch(t *testing.T) {
+	for _, search := range []string{"bandcamp", "https://www.discogs.com/search?q={query}"} {
+		writeConfig(t, "tui:\n  track_search: \""+search+"\"\n")
+		cfg, err := Load()
+		require.NoError(t, err, search)
+		require.NotNil(t, cfg.TUI.TrackSearch)
+		assert.Equal(t, search, *cfg.TUI.TrackSearch)
+	}
+
+	for _, search := range []string{"spotify", "https://example.com/search", "file:///{query}"} {
+		writeConfig(t, "tui:\n  track_search: \""+search+"\"\n")
+		_, err := Load()
+		require.Error(t, err, search)
+		assert.Contains(t, err.Error(), "tui.track_search")
+	}
+}
+
 func TestEnsureTemplateCreatesParseableDefaults(t *testing.T) {
+	isolateHome(t)
 	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
 
-	path, created, err := EnsureTemplate(2 * time.Minute)
+	path, created, err := EnsureTemplate(2*time.Minute, 4, time.Hour, 10*time.Minute)
 	require.NoError(t, err)
 	assert.True(t, created)
 	wantPath, err := Path()
@@ -149,15 +608,70 @@ func TestEnsureTemplateCreatesParseableDefaults(t *testing.T) {
 	assert.Equal(t, 2*time.Minute, time.Duration(*cfg.Server.IdleTimeout))
 	require.NotNil(t, cfg.Server.Tray)
 	assert.True(t, *cfg.Server.Tray)
+	require.NotNil(t, cfg.Server.MaxHTTPRequests)
+	assert.Equal(t, 4, *cfg.Server.MaxHTTPRequests)
+	require.NotNil(t, cfg.Server.CatalogTTL)
+	assert.Equal(t, time.Hour, time.Duration(*cfg.Server.CatalogTTL))
+	require.NotNil(t, cfg.Server.RefreshInterval)
+	assert.Equal(t, 10*time.Minute, time.Duration(*cfg.Server.RefreshInterval))
+	require.NotNil(t, cfg.Server.ProbeInterval)
+	assert.Zero(t, *cfg.Server.ProbeInterval)
+	require.NotNil(t, cfg.Server.Notify)
+	assert.False(t, *cfg.Server.Notify)
+	require.NotNil(t, cfg.Server.NowPlayingFile)
+	assert.Empty(t, *cfg.Server.NowPlayingFile)
+	require.NotNil(t, cfg.Server.MusicBrainz)
+	assert.False(t, *cfg.Server.MusicBrainz)
 	require.NotNil(t, cfg.TUI.ShutdownOnExit)
 	assert.False(t, *cfg.TUI.ShutdownOnExit)
+	require.NotNil(t, cfg.TUI.AltScreen)
+	assert.True(t, *cfg.TUI.AltScreen)
+	require.NotNil(t, cfg.TUI.Compact)
+	assert.False(t, *cfg.TUI.Compact)
+	require.NotNil(t, cfg.TUI.Grid)
+	assert.False(t, *cfg.TUI.Grid)
+	require.NotNil(t, cfg.TUI.Splash)
+	assert.False(t, *cfg.TUI.Splash)
+	require.NotNil(t, cfg.TUI.UTCTimes)
+	assert.False(t, *cfg.TUI.UTCTimes)
+	require.NotNil(t, cfg.TUI.SecondaryLine)
+	assert.Equal(t, "description", *cfg.TUI.SecondaryLine)
+	require.NotNil(t, cfg.TUI.DefaultSort)
+	assert.Equal(t, "api", *cfg.TUI.DefaultSort)
+	require.NotNil(t, cfg.TUI.MinQuality)
+	assert.Empty(t, *cfg.TUI.MinQuality)
+	require.NotNil(t, cfg.TUI.SpaceKey)
+	assert.Equal(t, "play", *cfg.TUI.SpaceKey)
+	require.NotNil(t, cfg.TUI.Prebuffer)
+	assert.False(t, *cfg.TUI.Prebuffer)
+	require.NotNil(t, cfg.TUI.Visualizer)
+	assert.False(t, *cfg.TUI.Visualizer)
+	require.NotNil(t, cfg.TUI.Details)
+	assert.False(t, *cfg.TUI.Details)
+	require.NotNil(t, cfg.TUI.DetailsWidth)
+	assert.Equal(t, 40, *cfg.TUI.DetailsWidth)
+	assert.Equal(t, []string{"listeners"}, cfg.TUI.Columns)
+	assert.Empty(t, cfg.TUI.ColumnWidths)
+	require.NotNil(t, cfg.TUI.Theme)
+	assert.Equal(t, "somafm", *cfg.TUI.Theme)
+	assert.Empty(t, cfg.TUI.Colors)
+	require.NotNil(t, cfg.TUI.CustomAccent)
+	assert.Equal(t, "#AE81FF", *cfg.TUI.CustomAccent)
+	require.NotNil(t, cfg.TUI.CustomGlyph)
+	assert.Equal(t, "◆", *cfg.TUI.CustomGlyph)
+	require.NotNil(t, cfg.TUI.ImageProtocol)
+	assert.Equal(t, "auto", *cfg.TUI.ImageProtocol)
+	require.NotNil(t, cfg.TUI.TrackSearch)
+	assert.Equal(t, "youtube", *cfg.TUI.TrackSearch)
+	assert.Equal(t, []string{"somafm", "stations"}, cfg.Server.Sources)
+	assert.Equal(t, []Station{{Title: "My Station", URL: "https://radio.example.org/live.mp3", Genre: "jazz|blues"}}, cfg.Stations)
 }
 
 func TestEnsureTemplateNeverTouchesAnExistingFile(t *testing.T) {
 	userContent := "server:\n  tray: false\n"
 	writeConfig(t, userContent)
 
-	path, created, err := EnsureTemplate(2 * time.Minute)
+	path, created, err := EnsureTemplate(2*time.Minute, 4, time.Hour, 10*time.Minute)
 	require.NoError(t, err)
 	assert.False(t, created)
 
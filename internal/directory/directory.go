@@ -0,0 +1,150 @@
+// Package directory abstracts the sources soma lists stations from:
+// SomaFM's channel catalog, the most played stations of the Radio Browser
+// community directory, and the stations the user lists in the config file.
+// The server loads every configured source and merges their stations into
+// one catalog; clients switch between that merge and a single source.
+package directory
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"somad/internal/channels"
+	"somad/internal/radiobrowser"
+)
+
+// Source names, as used in the config file, the state file and the
+// protocol.
+const (
+	SomaFMName       = "somafm"
+	RadioBrowserName = "radiobrowser"
+	StationsName     = "stations"
+)
+
+// Names lists the known source names in their default catalog order.
+var Names = []string{SomaFMName, RadioBrowserName, StationsName}
+
+// Directory is a source of stations. Each keeps its own cache, so one
+// source failing to refresh leaves the others current.
+type Directory interface {
+	// Name identifies the source: one of Names.
+	Name() string
+	// Cached returns the stations as last fetched, without the network.
+	Cached() (*channels.Channels, error)
+	// Fetch downloads the stations and caches them for Cached.
+	Fetch(userAgent string) (*channels.Channels, error)
+}
+
+// Title returns the display name of the source called name, e.g.
+// "Radio Browser"; an unknown name is returned as is.
+func Title(name string) string {
+	switch name {
+	case SomaFMName:
+		return "SomaFM"
+	case RadioBrowserName:
+		return "Radio Browser"
+	case StationsName:
+		return "My stations"
+	default:
+		return name
+	}
+}
+
+// Of returns the name of the source a channel ID belongs to, told apart by
+// the ID prefixes the non-SomaFM sources give their stations.
+func Of(channelID string) string {
+	switch {
+	case radiobrowser.IsStationID(channelID):
+		return RadioBrowserName
+	case channels.IsCustomID(channelID):
+		return StationsName
+	default:
+		return SomaFMName
+	}
+}
+
+// New returns the source called name; stations are the config file's own,
+// the StationsName source's contents.
+func New(name string, stations []channels.Channel) (Directory, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case SomaFMName:
+		return SomaFM{}, nil
+	case RadioBrowserName:
+		return RadioBrowser{}, nil
+	case StationsName:
+		return Stations(stations), nil
+	default:
+		return nil, fmt.Errorf("unknown station source %q (want %s)", name, strings.Join(Names, ", "))
+	}
+}
+
+// SomaFM is SomaFM's channel catalog.
+type SomaFM struct{}
+
+// Name implements Directory.
+func (SomaFM) Name() string { return SomaFMName }
+
+// Cached implements Directory.
+func (SomaFM) Cached() (*channels.Channels, error) { return channels.ReadChannelsFromCache() }
+
+// Fetch implements Directory.
+func (SomaFM) Fetch(userAgent string) (*channels.Channels, error) {
+	return channels.FetchChannelsFromNetwork(userAgent)
+}
+
+// radioBrowserCacheFile is the Radio Browser source's cache, beside
+// SomaFM's.
+const radioBrowserCacheFile = "radiobrowser_stations.json"
+
+// searchDirectory queries Radio Browser. A variable so tests can avoid the
+// network.
+var searchDirectory = radiobrowser.Search
+
+// RadioBrowser is the most played MP3 stations of the Radio Browser
+// directory; the directory view (d) searches the rest of it.
+type RadioBrowser struct{}
+
+// Name implements Directory.
+func (RadioBrowser) Name() string { return RadioBrowserName }
+
+// Cached implements Directory.
+func (RadioBrowser) Cached() (*channels.Channels, error) {
+	return channels.ReadCache(radioBrowserCacheFile)
+}
+
+// Fetch implements Directory.
+func (RadioBrowser) Fetch(userAgent string) (*channels.Channels, error) {
+	stations, err := searchDirectory("", userAgent)
+	if err != nil {
+		return nil, err
+	}
+	c := &channels.Channels{
+		Channels: make([]channels.Channel, len(stations)),
+		Fetched:  time.Now(),
+	}
+	for i, st := range stations {
+		c.Channels[i] = st.Channel()
+	}
+	if err := channels.WriteCache(radioBrowserCacheFile, c); err != nil {
+		// The stations are still good for this run.
+		log.Printf("warning: failed to cache Radio Browser stations: %v", err)
+	}
+	return c, nil
+}
+
+// Stations is the stations listed in the config file. They live in memory,
+// so Cached and Fetch never fail and always return them freshly loaded.
+type Stations []channels.Channel
+
+// Name implements Directory.
+func (Stations) Name() string { return StationsName }
+
+// Cached implements Directory.
+func (s Stations) Cached() (*channels.Channels, error) {
+	return &channels.Channels{Channels: s, Fetched: time.Now()}, nil
+}
+
+// Fetch implements Directory.
+func (s Stations) Fetch(string) (*channels.Channels, error) { return s.Cached() }
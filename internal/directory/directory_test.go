@@ -0,0 +1,81 @@
+package directory
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"somad/internal/channels"
+	"somad/internal/radiobrowser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	stations := []channels.Channel{channels.Custom("Jazz One", "http://jazz.example.org/live", "", "")}
+	for _, name := range Names {
+		src, err := New(name, stations)
+		require.NoError(t, err, name)
+		assert.Equal(t, name, src.Name())
+	}
+
+	_, err := New("icecast", nil)
+	assert.ErrorContains(t, err, `unknown station source "icecast"`)
+}
+
+func TestOf(t *testing.T) {
+	assert.Equal(t, SomaFMName, Of("groovesalad"))
+	assert.Equal(t, RadioBrowserName, Of(radiobrowser.IDPrefix+"9617a958"))
+	assert.Equal(t, StationsName, Of(channels.CustomIDPrefix+"jazz-one"))
+}
+
+func TestTitle(t *testing.T) {
+	assert.Equal(t, "SomaFM", Title(SomaFMName))
+	assert.Equal(t, "Radio Browser", Title(RadioBrowserName))
+	assert.Equal(t, "My stations", Title(StationsName))
+	assert.Equal(t, "other", Title("other"))
+}
+
+func TestRadioBrowser_FetchCachesStations(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	prev := searchDirectory
+	t.Cleanup(func() { searchDirectory = prev })
+	var query string
+	searchDirectory = func(q, _ string) ([]radiobrowser.Station, error) {
+		query = q
+		return []radiobrowser.Station{{UUID: "abc", Name: "Jazz FM", URL: "http://jazz.example.org/live"}}, nil
+	}
+
+	_, err := RadioBrowser{}.Cached()
+	require.Error(t, err, "nothing cached yet")
+
+	c, err := RadioBrowser{}.Fetch("test")
+	require.NoError(t, err)
+	assert.Empty(t, query, "lists the most played stations")
+	require.Len(t, c.Channels, 1)
+	assert.Equal(t, "rb:abc", c.Channels[0].ID)
+	assert.False(t, c.Fetched.IsZero())
+
+	cached, err := RadioBrowser{}.Cached()
+	require.NoError(t, err)
+	assert.Equal(t, c.Channels, cached.Channels)
+	assert.True(t, c.Fetched.Equal(cached.Fetched))
+
+	searchDirectory = func(string, string) ([]radiobrowser.Station, error) {
+		return nil, errors.New("directory down")
+	}
+	_, err = RadioBrowser{}.Fetch("test")
+	require.Error(t, err)
+	cached, err = RadioBrowser{}.Cached()
+	require.NoError(t, err)
+	assert.Len(t, cached.Channels, 1, "a failed fetch leaves the cache alone")
+}
+
+func TestStations_AlwaysFresh(t *testing.T) {
+	stations := Stations{channels.Custom("Jazz One", "http://jazz.example.org/live", "", "")}
+	c, err := stations.Cached()
+	require.NoError(t, err)
+	assert.Equal(t, []channels.Channel(stations), c.Channels)
+	assert.False(t, c.Stale(time.Minute))
+}
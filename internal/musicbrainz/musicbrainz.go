@@ -0,0 +1,203 @@
+// Package musicbrainz looks the playing track up in the MusicBrainz
+// database, for the album, release year and MusicBrainz IDs (MBIDs) a
+// stream title does not carry.
+package musicbrainz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"somad/internal/security"
+)
+
+// RecordingURL is the MusicBrainz recording search - exported for testing.
+var RecordingURL = "https://musicbrainz.org/ws/2/recording"
+
+// maxResponseBytes caps a search response; five recordings are a few KB.
+const maxResponseBytes = 1 << 20 // 1 MiB
+
+// minScore is the lowest search score taken as a match. MusicBrainz scores
+// an exact artist and title 100; lower scores are other songs that merely
+// share words with it.
+const minScore = 90
+
+// ErrNotFound reports that MusicBrainz knows no recording of the track.
+var ErrNotFound = errors.New("no MusicBrainz recording found")
+
+// Recording is what MusicBrainz knows about a track.
+type Recording struct {
+	ID        string // the recording's MBID
+	ArtistID  string // the first credited artist's MBID
+	ReleaseID string // the MBID of the release Album names
+	Album     string
+	// Year is when the recording was first released; 0 if unknown.
+	Year int
+}
+
+// minInterval spaces requests out: MusicBrainz asks clients to send at
+// most one a second, and blocks those that send more.
+var minInterval = time.Second
+
+var (
+	// rateMu serializes requests; lastRequest is when the latest was sent.
+	rateMu      sync.Mutex
+	lastRequest time.Time
+)
+
+// searchResponse is the part of a recording search reply Lookup and
+// ReleaseIDs read.
+type searchResponse struct {
+	Recordings []struct {
+		ID               string `json:"id"`
+		Score            int    `json:"score"`
+		FirstReleaseDate string `json:"first-release-date"`
+		ArtistCredit     []struct {
+			Artist struct {
+				ID string `json:"id"`
+			} `json:"artist"`
+		} `json:"artist-credit"`
+		Releases []release `json:"releases"`
+	} `json:"recordings"`
+}
+
+// release is one release a recording appeared on.
+type release struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	Status       string `json:"status"`
+	ReleaseGroup struct {
+		PrimaryType string `json:"primary-type"`
+	} `json:"release-group"`
+}
+
+// Lookup finds artist's song on MusicBrainz. Of the releases it appeared
+// on, the album is an official album where there is one, rather than a
+// single or bootleg it also came out on. A track MusicBrainz does not know
+// returns ErrNotFound.
+func Lookup(artist, song, userAgent string) (Recording, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	decoded, err := search(ctx, artist, song, userAgent)
+	if err != nil {
+		return Recording{}, err
+	}
+	for _, r := range decoded.Recordings {
+		if r.ID == "" || r.Score < minScore {
+			continue
+		}
+		rec := Recording{ID: r.ID}
+		if len(r.ArtistCredit) > 0 {
+			rec.ArtistID = r.ArtistCredit[0].Artist.ID
+		}
+		if rel, ok := pickRelease(r.Releases); ok {
+			rec.ReleaseID, rec.Album = rel.ID, rel.Title
+		}
+		// Dates are "2006", "2006-05" or "2006-05-30".
+		if year, err := strconv.Atoi(strings.SplitN(r.FirstReleaseDate, "-", 2)[0]); err == nil {
+			rec.Year = year
+		}
+		return rec, nil
+	}
+	return Recording{}, ErrNotFound
+}
+
+// ReleaseIDs returns the MBIDs of the releases artist's song came out on,
+// those of the best matching recording first. A track MusicBrainz does not
+// know returns ErrNotFound.
+func ReleaseIDs(ctx context.Context, artist, song, userAgent string) ([]string, error) {
+	decoded, err := search(ctx, artist, song, userAgent)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, r := range decoded.Recordings {
+		if r.Score < minScore {
+			continue
+		}
+		for _, rel := range r.Releases {
+			if rel.ID != "" {
+				ids = append(ids, rel.ID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil, ErrNotFound
+	}
+	return ids, nil
+}
+
+// search asks MusicBrainz for the recordings of artist's song, waiting for
+// its turn under the rate limit.
+func search(ctx context.Context, artist, song, userAgent string) (searchResponse, error) {
+	params := url.Values{}
+	params.Set("query", fmt.Sprintf("recording:%q AND artist:%q", luceneTerm(song), luceneTerm(artist)))
+	params.Set("fmt", "json")
+	params.Set("limit", "5")
+	req, err := security.NewRequest(ctx, RecordingURL+"?"+params.Encode(), userAgent)
+	if err != nil {
+		return searchResponse{}, fmt.Errorf("invalid MusicBrainz URL: %w", err)
+	}
+
+	waitTurn()
+	resp, err := security.HTTPClient.Do(req) // #nosec G704 -- URL validated by security.NewRequest()
+	if err != nil {
+		return searchResponse{}, fmt.Errorf("failed to query MusicBrainz: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return searchResponse{}, fmt.Errorf("unexpected status code from MusicBrainz: %d", resp.StatusCode)
+	}
+
+	var decoded searchResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&decoded); err != nil {
+		return searchResponse{}, fmt.Errorf("failed to decode MusicBrainz response: %w", err)
+	}
+	return decoded, nil
+}
+
+// pickRelease prefers an official album among releases, then any album,
+// then the first release listed.
+func pickRelease(releases []release) (release, bool) {
+	if len(releases) == 0 {
+		return release{}, false
+	}
+	best, bestRank := releases[0], -1
+	for _, rel := range releases {
+		rank := 0
+		if rel.ReleaseGroup.PrimaryType == "Album" {
+			rank += 2
+		}
+		if rel.Status == "Official" {
+			rank++
+		}
+		if rank > bestRank {
+			best, bestRank = rel, rank
+		}
+	}
+	return best, true
+}
+
+// waitTurn blocks until minInterval has passed since the previous request.
+func waitTurn() {
+	rateMu.Lock()
+	defer rateMu.Unlock()
+	if wait := minInterval - time.Since(lastRequest); wait > 0 {
+		time.Sleep(wait)
+	}
+	lastRequest = time.Now()
+}
+
+// luceneTerm makes s safe inside a quoted MusicBrainz search term.
+func luceneTerm(s string) string {
+	return strings.NewReplacer(`\`, " ", `"`, " ").Replace(s)
+}
@@ -0,0 +1,75 @@
+package musicbrainz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"somad/internal/security/securitytest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubMusicBrainz serves body for every recording search and returns the
+// queries it received.
+func stubMusicBrainz(t *testing.T, status int, body string) *[]string {
+	t.Helper()
+	securitytest.AllowTestHosts(t)
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("query"))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	prevURL, prevInterval := RecordingURL, minInterval
+	RecordingURL = server.URL
+	minInterval = 0
+	t.Cleanup(func() { RecordingURL, minInterval = prevURL, prevInterval })
+	return &queries
+}
+
+func TestLookup_PrefersAnOfficialAlbum(t *testing.T) {
+	queries := stubMusicBrainz(t, http.StatusOK, `{"recordings":[
+		{"id":"weak","score":60,"title":"Awake"},
+		{"id":"rec-1","score":100,"title":"Awake","first-release-date":"2014-02-03",
+		 "artist-credit":[{"artist":{"id":"art-1","name":"Tycho"}}],
+		 "releases":[
+			{"id":"rel-single","title":"Awake","status":"Official","release-group":{"primary-type":"Single"}},
+			{"id":"rel-bootleg","title":"Live","status":"Bootleg","release-group":{"primary-type":"Album"}},
+			{"id":"rel-album","title":"Awake (LP)","status":"Official","release-group":{"primary-type":"Album"}}]}]}`)
+
+	rec, err := Lookup("Tycho", `Awake "Remix"`, "soma/test")
+	require.NoError(t, err)
+	assert.Equal(t, Recording{ID: "rec-1", ArtistID: "art-1", ReleaseID: "rel-album", Album: "Awake (LP)", Year: 2014}, rec)
+	assert.Equal(t, []string{`recording:"Awake  Remix " AND artist:"Tycho"`}, *queries)
+}
+
+func TestLookup_Misses(t *testing.T) {
+	stubMusicBrainz(t, http.StatusOK, `{"recordings":[{"id":"weak","score":70}]}`)
+	_, err := Lookup("Tycho", "Awake", "soma/test")
+	assert.ErrorIs(t, err, ErrNotFound, "a weak match is no match")
+
+	stubMusicBrainz(t, http.StatusOK, `{"recordings":[{"id":"rec-1","score":95}]}`)
+	rec, err := Lookup("Tycho", "Awake", "soma/test")
+	require.NoError(t, err)
+	assert.Equal(t, Recording{ID: "rec-1"}, rec, "a recording on no release has no album or year")
+
+	stubMusicBrainz(t, http.StatusServiceUnavailable, `{}`)
+	_, err = Lookup("Tycho", "Awake", "soma/test")
+	assert.ErrorContains(t, err, "503")
+}
+
+func TestWaitTurn_SpacesRequests(t *testing.T) {
+	prev := minInterval
+	minInterval = 20 * time.Millisecond
+	t.Cleanup(func() { minInterval = prev })
+
+	start := time.Now()
+	waitTurn()
+	waitTurn()
+	assert.GreaterOrEqual(t, time.Since(start), minInterval)
+}
@@ -14,3 +14,23 @@ type PlayChannelMsg struct {
 type ToggleFavoriteMsg struct {
 	ID string
 }
+
+// SleepMsg is sent by the sleep watcher when the system is about to
+// suspend. The watcher holds off the suspend until Send returns, so the
+// receiver should release the stream before returning.
+type SleepMsg struct{}
+
+// WakeMsg is sent by the sleep watcher when the system has resumed.
+type WakeMsg struct{}
+
+// TrackMeta describes the playing track to MPRIS. Like the messages above
+// it is platform-independent, so the server builds it the same everywhere.
+type TrackMeta struct {
+	Station string
+	Title   string // the song, without the artist
+	Artist  string // empty when the title names none
+	Album   string // empty while unknown
+	// Year and RecordingID come from MusicBrainz; zero while unknown.
+	Year        int
+	RecordingID string
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 	"unicode/utf8"
 
 	"github.com/godbus/dbus/v5"
@@ -18,8 +19,16 @@ const (
 	mprisInterface  = "org.mpris.MediaPlayer2"
 	playerInterface = "org.mpris.MediaPlayer2.Player"
 	busName         = "org.mpris.MediaPlayer2.soma"
+
+	// musicBrainzRecordingURL, followed by an MBID, is a recording's page.
+	musicBrainzRecordingURL = "https://musicbrainz.org/recording/"
 )
 
+// positionInterval is how often the Position property advances while a
+// track plays. MPRIS clients read it on demand rather than being told of
+// changes, so it only needs to be about right when read.
+const positionInterval = time.Second
+
 // CmdSender is an interface for sending commands to the application.
 // This matches the tea.Program's Send method signature (tea.Msg is any).
 type CmdSender interface {
@@ -36,6 +45,18 @@ type MPRIS struct {
 	// exported.
 	senderMu sync.Mutex
 	sender   CmdSender
+
+	// posMu guards the position: trackStart is when the track began,
+	// paused how long it has been paused since and pausedAt when the
+	// current pause began (zero while playing). started, trackStart moved
+	// on by paused, is what the Position counts from; zero holds it still.
+	// done ends the goroutine advancing it.
+	posMu      sync.Mutex
+	trackStart time.Time
+	paused     time.Duration
+	pausedAt   time.Time
+	started    time.Time
+	done       chan struct{}
 }
 
 // mprisRoot implements org.mpris.MediaPlayer2 interface.
@@ -57,6 +78,7 @@ func NewMPRIS() (*MPRIS, error) {
 
 	m := &MPRIS{
 		conn: conn,
+		done: make(chan struct{}),
 	}
 
 	// Request bus name
@@ -108,7 +130,7 @@ func NewMPRIS() (*MPRIS, error) {
 			"PlaybackStatus": {Value: "Stopped", Writable: false, Emit: prop.EmitTrue, Callback: nil},
 			"Rate":           {Value: 1.0, Writable: false, Emit: prop.EmitTrue, Callback: nil},
 			"Volume":         {Value: 1.0, Writable: true, Emit: prop.EmitTrue, Callback: m.onVolumeChange},
-			"Position":       {Value: int64(0), Writable: false, Emit: prop.EmitTrue, Callback: nil},
+			"Position":       {Value: int64(0), Writable: false, Emit: prop.EmitFalse, Callback: nil},
 			"Metadata":       {Value: map[string]dbus.Variant{}, Writable: false, Emit: prop.EmitTrue, Callback: nil},
 		},
 	}
@@ -186,9 +208,39 @@ func NewMPRIS() (*MPRIS, error) {
 		return nil, fmt.Errorf("failed to export introspectable: %w", err)
 	}
 
+	go m.advancePosition()
 	return m, nil
 }
 
+// advancePosition keeps the Position property following the playing track
+// until Close.
+func (m *MPRIS) advancePosition() {
+	ticker := time.NewTicker(positionInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.done:
+			return
+		case now := <-ticker.C:
+			m.posMu.Lock()
+			started := m.started
+			m.posMu.Unlock()
+			if !started.IsZero() {
+				m.props.SetMust(playerInterface, "Position", trackPosition(started, now))
+			}
+		}
+	}
+}
+
+// trackPosition is how far into a track started at started now is, in
+// microseconds as MPRIS counts; 0 for an unknown start.
+func trackPosition(started, now time.Time) int64 {
+	if started.IsZero() {
+		return 0
+	}
+	return max(now.Sub(started).Microseconds(), 0)
+}
+
 // SetSender sets the command sender for MPRIS control messages.
 func (m *MPRIS) SetSender(sender CmdSender) {
 	m.senderMu.Lock()
@@ -207,25 +259,56 @@ func (m *MPRIS) send(msg any) {
 }
 
 // SetPlaying updates the playback status to playing and sets metadata.
-func (m *MPRIS) SetPlaying(station, track, artist string) {
+// The position counts from started, when the track began, less the time
+// the track spent paused; a zero started holds it at 0.
+func (m *MPRIS) SetPlaying(track TrackMeta, started time.Time) {
 	if m.props == nil {
 		return
 	}
 
-	// Sanitize strings to ensure valid UTF8 for D-Bus
-	station = SanitizeUTF8(station)
-	track = SanitizeUTF8(track)
-	artist = SanitizeUTF8(artist)
-
-	metadata := map[string]dbus.Variant{
-		"mpris:trackid": dbus.MakeVariant(dbus.ObjectPath("/org/mpris/MediaPlayer2/Track/1")),
-		"xesam:title":   dbus.MakeVariant(track),
-		"xesam:artist":  dbus.MakeVariant([]string{artist}),
-		"xesam:album":   dbus.MakeVariant(station),
-	}
+	metadata := trackMetadata(track)
 
+	now := time.Now()
+	from := m.play(started, now)
 	m.props.SetMust(playerInterface, "PlaybackStatus", "Playing")
 	m.props.SetMust(playerInterface, "Metadata", metadata)
+	m.props.SetMust(playerInterface, "Position", trackPosition(from, now))
+}
+
+// play moves the position to playing the track begun at trackStart, as of
+// now, and returns the time the Position counts from. Resuming the same
+// track adds the pause that ends now to its paused time; another track
+// starts with none.
+func (m *MPRIS) play(trackStart, now time.Time) time.Time {
+	m.posMu.Lock()
+	defer m.posMu.Unlock()
+	if !trackStart.Equal(m.trackStart) {
+		m.trackStart = trackStart
+		m.paused = 0
+		m.pausedAt = time.Time{}
+	}
+	if !m.pausedAt.IsZero() {
+		m.paused += max(now.Sub(m.pausedAt), 0)
+		m.pausedAt = time.Time{}
+	}
+	m.started = time.Time{}
+	if !trackStart.IsZero() {
+		m.started = trackStart.Add(m.paused)
+	}
+	return m.started
+}
+
+// pause holds the position still from now on and returns the time it
+// counted from, zero if it was not moving.
+func (m *MPRIS) pause(now time.Time) time.Time {
+	m.posMu.Lock()
+	defer m.posMu.Unlock()
+	started := m.started
+	if !started.IsZero() {
+		m.pausedAt = now
+	}
+	m.started = time.Time{}
+	return started
 }
 
 // SetVolume mirrors the player volume to the MPRIS Volume property.
@@ -250,33 +333,71 @@ func (m *MPRIS) SetStopped() {
 	if m.props == nil {
 		return
 	}
+	m.posMu.Lock()
+	m.trackStart, m.paused, m.pausedAt, m.started = time.Time{}, 0, time.Time{}, time.Time{}
+	m.posMu.Unlock()
 	m.props.SetMust(playerInterface, "PlaybackStatus", "Stopped")
 	m.props.SetMust(playerInterface, "Metadata", map[string]dbus.Variant{})
+	m.props.SetMust(playerInterface, "Position", int64(0))
+}
+
+// SetPaused updates the playback status to paused, keeping the metadata.
+// The position stops where it is.
+func (m *MPRIS) SetPaused() {
+	if m.props == nil {
+		return
+	}
+	now := time.Now()
+	started := m.pause(now)
+	m.props.SetMust(playerInterface, "PlaybackStatus", "Paused")
+	if !started.IsZero() {
+		m.props.SetMust(playerInterface, "Position", trackPosition(started, now))
+	}
 }
 
 // SetMetadata updates the current track metadata.
-func (m *MPRIS) SetMetadata(station, track, artist string) {
+func (m *MPRIS) SetMetadata(track TrackMeta) {
 	if m.props == nil {
 		return
 	}
 
-	// Sanitize strings to ensure valid UTF8 for D-Bus
-	station = SanitizeUTF8(station)
-	track = SanitizeUTF8(track)
-	artist = SanitizeUTF8(artist)
+	metadata := trackMetadata(track)
+
+	m.props.SetMust(playerInterface, "Metadata", metadata)
+}
 
+// trackMetadata builds the Metadata property for a track. The album is
+// the station until the real one is known; an empty artist and unknown
+// details are left out rather than shown blank.
+func trackMetadata(track TrackMeta) map[string]dbus.Variant {
+	album := track.Album
+	if album == "" {
+		album = track.Station
+	}
+	// Sanitize strings to ensure valid UTF8 for D-Bus
 	metadata := map[string]dbus.Variant{
 		"mpris:trackid": dbus.MakeVariant(dbus.ObjectPath("/org/mpris/MediaPlayer2/Track/1")),
-		"xesam:title":   dbus.MakeVariant(track),
-		"xesam:artist":  dbus.MakeVariant([]string{artist}),
-		"xesam:album":   dbus.MakeVariant(station),
+		"xesam:title":   dbus.MakeVariant(SanitizeUTF8(track.Title)),
+		"xesam:album":   dbus.MakeVariant(SanitizeUTF8(album)),
 	}
-
-	m.props.SetMust(playerInterface, "Metadata", metadata)
+	if track.Artist != "" {
+		metadata["xesam:artist"] = dbus.MakeVariant([]string{SanitizeUTF8(track.Artist)})
+	}
+	if track.Year > 0 {
+		// xesam dates are ISO 8601, which allows a year on its own.
+		metadata["xesam:contentCreated"] = dbus.MakeVariant(fmt.Sprintf("%04d", track.Year))
+	}
+	if track.RecordingID != "" {
+		metadata["xesam:url"] = dbus.MakeVariant(musicBrainzRecordingURL + track.RecordingID)
+	}
+	return metadata
 }
 
 // Close releases D-Bus resources.
 func (m *MPRIS) Close() {
+	if m.done != nil {
+		close(m.done)
+	}
 	if m.conn != nil {
 		_, _ = m.conn.ReleaseName(busName)
 		_ = m.conn.Close()
@@ -316,6 +437,9 @@ func (r *mprisRoot) Quit() *dbus.Error {
 // MPRISPlayMsg is sent when MPRIS requests to play.
 type MPRISPlayMsg struct{}
 
+// MPRISPauseMsg is sent when MPRIS requests to pause.
+type MPRISPauseMsg struct{}
+
 // MPRISStopMsg is sent when MPRIS requests to stop.
 type MPRISStopMsg struct{}
 
@@ -347,7 +471,7 @@ func (p *mprisPlayer) Previous() *dbus.Error {
 }
 
 func (p *mprisPlayer) Pause() *dbus.Error {
-	p.mpris.send(MPRISStopMsg{})
+	p.mpris.send(MPRISPauseMsg{})
 	return nil
 }
 
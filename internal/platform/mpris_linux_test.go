@@ -5,6 +5,7 @@ package platform
 import (
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -46,7 +47,7 @@ func TestMPRIS_PlayerMethodsRouteToSender(t *testing.T) {
 	assert.Equal(t, []any{
 		MPRISNextMsg{},
 		MPRISPrevMsg{},
-		MPRISStopMsg{},
+		MPRISPauseMsg{},
 		MPRISPlayPauseMsg{},
 		MPRISStopMsg{},
 		MPRISPlayMsg{},
@@ -97,6 +98,60 @@ func TestMPRIS_SetSenderConcurrentWithHandlers(t *testing.T) {
 	wg.Wait()
 }
 
+func TestTrackMetadata_OmitsEmptyArtist(t *testing.T) {
+	md := trackMetadata(TrackMeta{Station: "Groove Salad", Title: "Song", Artist: "Artist"})
+	assert.Equal(t, []string{"Artist"}, md["xesam:artist"].Value())
+	assert.Equal(t, "Song", md["xesam:title"].Value())
+	assert.Equal(t, "Groove Salad", md["xesam:album"].Value())
+	assert.NotContains(t, md, "xesam:contentCreated")
+	assert.NotContains(t, md, "xesam:url")
+
+	md = trackMetadata(TrackMeta{Station: "Groove Salad", Title: "Station ID"})
+	assert.NotContains(t, md, "xesam:artist")
+}
+
+func TestTrackMetadata_MusicBrainzDetails(t *testing.T) {
+	md := trackMetadata(TrackMeta{
+		Station: "Groove Salad", Title: "Awake", Artist: "Tycho",
+		Album: "Awake (LP)", Year: 2014, RecordingID: "rec-1",
+	})
+	assert.Equal(t, "Awake (LP)", md["xesam:album"].Value(), "the real album replaces the station")
+	assert.Equal(t, "2014", md["xesam:contentCreated"].Value())
+	assert.Equal(t, "https://musicbrainz.org/recording/rec-1", md["xesam:url"].Value())
+}
+
+func TestTrackPosition(t *testing.T) {
+	started := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+	assert.Equal(t, int64(221_000_000), trackPosition(started, started.Add(3*time.Minute+41*time.Second)))
+	assert.Equal(t, int64(0), trackPosition(started, started.Add(-time.Second)), "a clock step back is no negative position")
+	assert.Equal(t, int64(0), trackPosition(time.Time{}, started))
+}
+
+// TestMPRIS_PositionSkipsPauses guards against the position jumping
+// forward by the length of a pause when playback resumes.
+func TestMPRIS_PositionSkipsPauses(t *testing.T) {
+	m := &MPRIS{}
+	started := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+
+	from := m.play(started, started.Add(time.Minute))
+	assert.Equal(t, int64(60_000_000), trackPosition(from, started.Add(time.Minute)))
+
+	from = m.pause(started.Add(2 * time.Minute))
+	assert.Equal(t, int64(120_000_000), trackPosition(from, started.Add(2*time.Minute)), "pausing holds the position where it is")
+
+	from = m.play(started, started.Add(7*time.Minute))
+	assert.Equal(t, int64(120_000_000), trackPosition(from, started.Add(7*time.Minute)), "resuming picks up where the pause began")
+	assert.Equal(t, int64(150_000_000), trackPosition(from, started.Add(7*time.Minute+30*time.Second)))
+
+	m.pause(started.Add(8 * time.Minute))
+	from = m.play(started, started.Add(9*time.Minute))
+	assert.Equal(t, int64(180_000_000), trackPosition(from, started.Add(9*time.Minute)), "a second pause adds to the first")
+
+	next := started.Add(10 * time.Minute)
+	from = m.play(next, next.Add(5*time.Second))
+	assert.Equal(t, int64(5_000_000), trackPosition(from, next.Add(5*time.Second)), "the next track starts with no pause")
+}
+
 func TestSanitizeUTF8_ValidString(t *testing.T) {
 	input := "Hello, World!"
 	assert.Equal(t, input, SanitizeUTF8(input))
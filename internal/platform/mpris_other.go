@@ -2,6 +2,8 @@
 
 package platform
 
+import "time"
+
 // CmdSender is an interface for sending commands to the application.
 // This matches the tea.Program's Send method signature (tea.Msg is any).
 type CmdSender interface {
@@ -20,13 +22,16 @@ func NewMPRIS() (*MPRIS, error) {
 func (m *MPRIS) SetSender(sender CmdSender) {}
 
 // SetPlaying is a no-op on non-Linux platforms.
-func (m *MPRIS) SetPlaying(station, track, artist string) {}
+func (m *MPRIS) SetPlaying(track TrackMeta, started time.Time) {}
 
 // SetStopped is a no-op on non-Linux platforms.
 func (m *MPRIS) SetStopped() {}
 
+// SetPaused is a no-op on non-Linux platforms.
+func (m *MPRIS) SetPaused() {}
+
 // SetMetadata is a no-op on non-Linux platforms.
-func (m *MPRIS) SetMetadata(station, track, artist string) {}
+func (m *MPRIS) SetMetadata(track TrackMeta) {}
 
 // Close is a no-op on non-Linux platforms.
 func (m *MPRIS) Close() {}
@@ -34,6 +39,9 @@ func (m *MPRIS) Close() {}
 // MPRISPlayMsg is sent when MPRIS requests to play.
 type MPRISPlayMsg struct{}
 
+// MPRISPauseMsg is sent when MPRIS requests to pause.
+type MPRISPauseMsg struct{}
+
 // MPRISStopMsg is sent when MPRIS requests to stop.
 type MPRISStopMsg struct{}
 
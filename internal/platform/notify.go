@@ -0,0 +1,83 @@
+package platform
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// notifyInterval is the least time between two notifications. A variable so
+// tests can shrink it.
+var notifyInterval = 5 * time.Second
+
+// Notifier shows desktop notifications, at most one per notifyInterval: a
+// notification due sooner waits out the interval, and a newer one replaces
+// it meanwhile, so skipping through channels announces only where it
+// stopped. A nil Notifier shows nothing.
+type Notifier struct {
+	send  func(title, body string) error
+	close func()
+
+	sendMu sync.Mutex // serializes send
+
+	mu      sync.Mutex
+	last    time.Time   // when the last notification was sent
+	timer   *time.Timer // the pending notification's, nil when none
+	title   string      // the pending notification
+	body    string
+	stopped bool
+}
+
+// Notify shows a notification with title and body, once the interval since
+// the last one has passed. It never blocks on the notification service.
+func (n *Notifier) Notify(title, body string) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.stopped {
+		return
+	}
+	n.title, n.body = title, body
+	if n.timer == nil {
+		n.timer = time.AfterFunc(max(time.Until(n.last.Add(notifyInterval)), 0), n.flush)
+	}
+}
+
+// flush sends the pending notification.
+func (n *Notifier) flush() {
+	n.sendMu.Lock()
+	defer n.sendMu.Unlock()
+	n.mu.Lock()
+	if n.stopped {
+		n.mu.Unlock()
+		return
+	}
+	title, body := n.title, n.body
+	n.timer = nil
+	n.last = time.Now()
+	n.mu.Unlock()
+	if err := n.send(title, body); err != nil {
+		log.Printf("desktop notification failed: %v", err)
+	}
+}
+
+// Close drops a pending notification and releases the notifier's resources.
+func (n *Notifier) Close() {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	n.stopped = true
+	if n.timer != nil {
+		n.timer.Stop()
+		n.timer = nil
+	}
+	n.mu.Unlock()
+	n.sendMu.Lock()
+	defer n.sendMu.Unlock()
+	if n.close != nil {
+		n.close()
+	}
+}
@@ -0,0 +1,23 @@
+//go:build darwin
+
+package platform
+
+import "os/exec"
+
+// notifyScript shows the notification its arguments describe. Passing the
+// text as arguments rather than splicing it into the script keeps quotes in
+// a track title from being read as AppleScript.
+const notifyScript = `on run argv
+	display notification (item 2 of argv) with title (item 1 of argv)
+end run`
+
+// NewNotifier sends notifications through osascript to Notification Center.
+func NewNotifier() (*Notifier, error) {
+	path, err := exec.LookPath("osascript")
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{send: func(title, body string) error {
+		return exec.Command(path, "-e", notifyScript, title, body).Run() // #nosec G204 -- a fixed binary and script; the text goes in as arguments
+	}}, nil
+}
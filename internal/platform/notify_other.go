@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package platform
+
+// NewNotifier returns nil on platforms without a supported notification
+// service.
+func NewNotifier() (*Notifier, error) {
+	return nil, nil
+}
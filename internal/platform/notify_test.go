@@ -0,0 +1,64 @@
+package platform
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRecordingNotifier returns a Notifier that records what it sends, with
+// the interval shrunk to interval.
+func newRecordingNotifier(t *testing.T, interval time.Duration) (*Notifier, func() []string) {
+	t.Helper()
+	prev := notifyInterval
+	notifyInterval = interval
+	t.Cleanup(func() { notifyInterval = prev })
+
+	var mu sync.Mutex
+	var sent []string
+	n := &Notifier{send: func(title, body string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, title+"|"+body)
+		return nil
+	}}
+	t.Cleanup(n.Close)
+	return n, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), sent...)
+	}
+}
+
+func TestNotifier_CoalescesWithinTheInterval(t *testing.T) {
+	n, sent := newRecordingNotifier(t, 100*time.Millisecond)
+
+	n.Notify("first", "a")
+	require.Eventually(t, func() bool { return len(sent()) == 1 }, time.Second, time.Millisecond)
+	n.Notify("second", "b")
+	n.Notify("third", "c")
+	assert.Len(t, sent(), 1, "the interval has not passed yet")
+
+	require.Eventually(t, func() bool { return len(sent()) == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"first|a", "third|c"}, sent())
+}
+
+func TestNotifier_CloseDropsPending(t *testing.T) {
+	n, sent := newRecordingNotifier(t, time.Hour)
+	n.last = time.Now()
+
+	n.Notify("title", "body")
+	n.Close()
+	n.Notify("later", "body")
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, sent())
+}
+
+func TestNotifier_NilIsSafe(t *testing.T) {
+	var n *Notifier
+	n.Notify("title", "body")
+	n.Close()
+}
@@ -0,0 +1,16 @@
+//go:build darwin
+
+package platform
+
+import "os/exec"
+
+// OpenURL opens url in the default browser through open(1), without
+// waiting for the browser.
+func OpenURL(url string) error {
+	cmd := exec.Command("open", url) // #nosec G204 -- a fixed binary; the URL goes in as an argument
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
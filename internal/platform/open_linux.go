@@ -0,0 +1,17 @@
+//go:build linux
+
+package platform
+
+import "os/exec"
+
+// OpenURL opens url in the default browser through xdg-open. It does not
+// wait for the browser, and the helper's output is discarded so it cannot
+// scribble over a TUI.
+func OpenURL(url string) error {
+	cmd := exec.Command("xdg-open", url) // #nosec G204 -- a fixed binary; the URL goes in as an argument
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package platform
+
+import "errors"
+
+// OpenURL is not supported on this platform.
+func OpenURL(string) error {
+	return errors.New("opening a browser is not supported on this platform")
+}
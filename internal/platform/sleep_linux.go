@@ -0,0 +1,134 @@
+//go:build linux
+
+package platform
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	login1Dest    = "org.freedesktop.login1"
+	login1Path    = "/org/freedesktop/login1"
+	login1Manager = "org.freedesktop.login1.Manager"
+)
+
+// SleepWatcher follows system suspend and resume through logind's
+// PrepareForSleep signal on the system bus. While awake it holds a delay
+// inhibitor lock, so logind waits for SleepMsg to be handled (up to its
+// InhibitDelayMaxSec) before suspending.
+type SleepWatcher struct {
+	conn *dbus.Conn
+
+	mu      sync.Mutex
+	inhibit *os.File // the delay lock; closing it lets the suspend proceed
+
+	senderMu sync.Mutex
+	sender   CmdSender
+}
+
+// NewSleepWatcher subscribes to logind's sleep signals.
+func NewSleepWatcher() (*SleepWatcher, error) {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to system bus: %w", err)
+	}
+	err = conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(login1Path),
+		dbus.WithMatchInterface(login1Manager),
+		dbus.WithMatchMember("PrepareForSleep"),
+	)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to subscribe to sleep signals: %w", err)
+	}
+
+	w := &SleepWatcher{conn: conn}
+	w.takeInhibitor()
+	signals := make(chan *dbus.Signal, 4)
+	conn.Signal(signals)
+	go func() {
+		for sig := range signals {
+			w.handleSignal(sig)
+		}
+	}()
+	return w, nil
+}
+
+// SetSender sets the receiver of SleepMsg and WakeMsg.
+func (w *SleepWatcher) SetSender(sender CmdSender) {
+	w.senderMu.Lock()
+	defer w.senderMu.Unlock()
+	w.sender = sender
+}
+
+// handleSignal forwards PrepareForSleep: before suspending, the receiver
+// gets SleepMsg and the inhibitor is released once it is handled; after
+// resuming, the inhibitor is retaken and the receiver gets WakeMsg.
+func (w *SleepWatcher) handleSignal(sig *dbus.Signal) {
+	if sig.Name != login1Manager+".PrepareForSleep" || len(sig.Body) != 1 {
+		return
+	}
+	sleeping, ok := sig.Body[0].(bool)
+	if !ok {
+		return
+	}
+	if sleeping {
+		w.send(SleepMsg{})
+		w.releaseInhibitor()
+		return
+	}
+	w.takeInhibitor()
+	w.send(WakeMsg{})
+}
+
+func (w *SleepWatcher) send(msg any) {
+	w.senderMu.Lock()
+	sender := w.sender
+	w.senderMu.Unlock()
+	if sender != nil {
+		sender.Send(msg)
+	}
+}
+
+// takeInhibitor asks logind for a delay lock on sleep. Without one (e.g.
+// denied by policy) suspend is still reported, just not waited for.
+func (w *SleepWatcher) takeInhibitor() {
+	if w.conn == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.inhibit != nil {
+		return
+	}
+	var fd dbus.UnixFD
+	err := w.conn.Object(login1Dest, login1Path).Call(login1Manager+".Inhibit", 0,
+		"sleep", "soma", "Stop the stream before sleep", "delay").Store(&fd)
+	if err != nil {
+		log.Printf("warning: could not delay sleep to stop the stream: %v", err)
+		return
+	}
+	w.inhibit = os.NewFile(uintptr(fd), "logind-inhibitor")
+}
+
+func (w *SleepWatcher) releaseInhibitor() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.inhibit != nil {
+		_ = w.inhibit.Close()
+		w.inhibit = nil
+	}
+}
+
+// Close releases the inhibitor and the bus connection.
+func (w *SleepWatcher) Close() {
+	w.releaseInhibitor()
+	if w.conn != nil {
+		_ = w.conn.Close()
+	}
+}
@@ -0,0 +1,22 @@
+//go:build linux
+
+package platform
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSleepWatcher_ForwardsPrepareForSleep(t *testing.T) {
+	w := &SleepWatcher{}
+	s := &recordingSender{}
+	w.SetSender(s)
+
+	w.handleSignal(&dbus.Signal{Name: login1Manager + ".PrepareForSleep", Body: []any{true}})
+	w.handleSignal(&dbus.Signal{Name: login1Manager + ".PrepareForSleep", Body: []any{false}})
+	w.handleSignal(&dbus.Signal{Name: login1Manager + ".SessionNew", Body: []any{"2", dbus.ObjectPath("/")}})
+
+	assert.Equal(t, []any{SleepMsg{}, WakeMsg{}}, s.messages())
+}
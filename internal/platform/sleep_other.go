@@ -0,0 +1,18 @@
+//go:build !linux
+
+package platform
+
+// SleepWatcher is a stub for non-Linux platforms.
+type SleepWatcher struct{}
+
+// NewSleepWatcher returns nil on non-Linux platforms (suspend detection not
+// supported).
+func NewSleepWatcher() (*SleepWatcher, error) {
+	return nil, nil
+}
+
+// SetSender is a no-op on non-Linux platforms.
+func (w *SleepWatcher) SetSender(sender CmdSender) {}
+
+// Close is a no-op on non-Linux platforms.
+func (w *SleepWatcher) Close() {}
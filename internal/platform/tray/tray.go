@@ -43,6 +43,7 @@ type Tray struct {
 
 	ready     bool
 	playing   bool
+	paused    bool // playing, but paused: the channel stays in the menu
 	playingID string
 	station   string
 	track     string
@@ -100,6 +101,7 @@ func (t *Tray) Quit() { systray.Quit() }
 func (t *Tray) SetPlaying(channelID, station, track string) {
 	t.mu.Lock()
 	t.playing = true
+	t.paused = false
 	t.playingID = channelID
 	t.station = platform.SanitizeUTF8(station)
 	t.track = platform.SanitizeUTF8(track)
@@ -108,10 +110,19 @@ func (t *Tray) SetPlaying(channelID, station, track string) {
 	t.mu.Unlock()
 }
 
+// SetPaused marks the stream last passed to SetPlaying as paused.
+func (t *Tray) SetPaused() {
+	t.mu.Lock()
+	t.paused = t.playing
+	t.applyLocked()
+	t.mu.Unlock()
+}
+
 // SetStopped mirrors stopped playback into the menu.
 func (t *Tray) SetStopped() {
 	t.mu.Lock()
 	t.playing = false
+	t.paused = false
 	t.playingID = ""
 	t.station = ""
 	t.track = ""
@@ -151,7 +162,7 @@ func (t *Tray) build() {
 	title := systray.AddMenuItem("Stopped", "")
 	title.Disable()
 	systray.AddSeparator()
-	playStop := systray.AddMenuItem("Play", "Play or stop playback")
+	playStop := systray.AddMenuItem("Play", "Play or pause playback")
 	next := systray.AddMenuItem("Next", "Next channel")
 	prev := systray.AddMenuItem("Previous", "Previous channel")
 	fav := systray.AddMenuItemCheckbox("★ Favorite", "Mark or unmark the playing channel as favorite", false)
@@ -201,8 +212,13 @@ func (t *Tray) applyLocked() {
 	}
 	if t.playing {
 		label := playbackLabel(t.station, t.track)
-		t.titleItem.SetTitle("♪ " + label)
-		t.playStop.SetTitle("Stop")
+		if t.paused {
+			t.titleItem.SetTitle("⏸ " + label)
+			t.playStop.SetTitle("Resume")
+		} else {
+			t.titleItem.SetTitle("♪ " + label)
+			t.playStop.SetTitle("Pause")
+		}
 		systray.SetTooltip("Soma — " + label)
 		t.favItem.Enable()
 		if t.favoriteLocked(t.playingID) {
@@ -42,6 +42,9 @@ func (t *Tray) Quit() {}
 // SetPlaying is a no-op.
 func (t *Tray) SetPlaying(channelID, station, track string) {}
 
+// SetPaused is a no-op.
+func (t *Tray) SetPaused() {}
+
 // SetStopped is a no-op.
 func (t *Tray) SetStopped() {}
 
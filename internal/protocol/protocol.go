@@ -16,13 +16,34 @@ const (
 	MethodAuth           = "auth"
 	MethodHello          = "hello"
 	MethodStatus         = "status"
+	MethodLevel          = "level"
+	MethodSpectrum       = "spectrum"
+	MethodStats          = "stats"
+	MethodHistory        = "history"
+	MethodArtwork        = "artwork"
+	MethodChannelArtwork = "channelArtwork"
+	MethodRecentSongs    = "recentSongs"
+	MethodLoved          = "loved"
+	MethodToggleLove     = "toggleLove"
 	MethodChannels       = "channels"
+	MethodRefresh        = "refreshChannels"
 	MethodPlay           = "play"
+	MethodPreview        = "preview"
+	MethodPrebuffer      = "prebuffer"
 	MethodPlayPause      = "playPause"
 	MethodPlayRelative   = "playRelative"
 	MethodStop           = "stop"
 	MethodSetVolume      = "setVolume"
+	MethodSetQuality     = "setStreamQuality"
+	MethodSetEqualizer   = "setEqualizer"
+	MethodTimeShift      = "timeShift"
+	MethodToggleMute     = "toggleMute"
 	MethodToggleFavorite = "toggleFavorite"
+	MethodMoveFavorite   = "moveFavorite"
+	MethodDismissHint    = "dismissFavoritesHint"
+	MethodSetSort        = "setChannelSort"
+	MethodSetSource      = "setChannelSource"
+	MethodSearchStations = "searchStations"
 	MethodShutdown       = "shutdown"
 )
 
@@ -1,26 +1,88 @@
 package protocol
 
-import "somad/internal/channels"
+import (
+	"time"
+
+	"somad/internal/channels"
+)
 
 // Playback status values for PlaybackState.Status.
 const (
 	StatusStopped      = "stopped"
 	StatusConnecting   = "connecting"
 	StatusPlaying      = "playing"
+	StatusPaused       = "paused"
 	StatusReconnecting = "reconnecting"
 )
 
+// Stream error classes for PlaybackState.StreamErrorKind.
+const (
+	StreamErrorOffline     = "offline"     // the station could not be reached or refused the stream
+	StreamErrorUnsupported = "unsupported" // the station answered with audio that cannot be decoded
+)
+
 // PlaybackState is a full snapshot of the server's playback state. Every
 // state event carries one, so clients can render from the latest snapshot
 // alone without tracking deltas.
 type PlaybackState struct {
-	Status           string  `json:"status"`
-	ChannelID        string  `json:"channelId,omitempty"`
-	ChannelTitle     string  `json:"channelTitle,omitempty"`
-	TrackTitle       string  `json:"trackTitle,omitempty"`
-	Volume           float64 `json:"volume"`
-	StreamError      string  `json:"streamError,omitempty"`
-	ReconnectAttempt int     `json:"reconnectAttempt,omitempty"`
+	Status       string `json:"status"`
+	ChannelID    string `json:"channelId,omitempty"`
+	ChannelTitle string `json:"channelTitle,omitempty"`
+	// Listeners is the channel's listener count, fetched every minute
+	// while a SomaFM channel plays; 0 if unknown.
+	Listeners  int    `json:"listeners,omitempty"`
+	TrackTitle string `json:"trackTitle,omitempty"`
+	// TrackArtist and TrackSong split TrackTitle when it has the usual
+	// "Artist - Song" form; otherwise TrackArtist is empty and TrackSong is
+	// the whole title.
+	TrackArtist string `json:"trackArtist,omitempty"`
+	TrackSong   string `json:"trackSong,omitempty"`
+	// TrackAlbum names the track's album, when the channel's song list or
+	// MusicBrainz does.
+	TrackAlbum string `json:"trackAlbum,omitempty"`
+	// TrackYear and the MusicBrainz IDs (MBIDs) of the track's recording,
+	// release and artist are set once the server has found the track on
+	// MusicBrainz, which it only does when configured to. TrackReleaseID
+	// names MusicBrainz's pick of album, which may not be TrackAlbum when
+	// the song list names another.
+	TrackYear        int    `json:"trackYear,omitempty"`
+	TrackRecordingID string `json:"trackRecordingId,omitempty"`
+	TrackReleaseID   string `json:"trackReleaseId,omitempty"`
+	TrackArtistID    string `json:"trackArtistId,omitempty"`
+	// TrackLoved is set while the playing track is among the loved tracks.
+	TrackLoved bool `json:"trackLoved,omitempty"`
+	// TrackStarted is when TrackTitle first appeared on the channel, for
+	// an elapsed-time display; a reconnect to the same title keeps it.
+	TrackStarted time.Time `json:"trackStarted,omitzero"`
+	Volume       float64   `json:"volume"`
+	// Muted is set while playback is silenced; Volume keeps the level that
+	// unmuting restores.
+	Muted       bool   `json:"muted,omitempty"`
+	StreamError string `json:"streamError,omitempty"`
+	// StreamErrorKind classifies StreamError when the cause is known (see
+	// the StreamError* constants); empty otherwise.
+	StreamErrorKind  string `json:"streamErrorKind,omitempty"`
+	ReconnectAttempt int    `json:"reconnectAttempt,omitempty"`
+	// StreamQuality is the preferred playlist quality for SomaFM channels
+	// ("low", "high" or "highest"); empty means the best available.
+	StreamQuality string `json:"streamQuality,omitempty"`
+	// Equalizer is the equalizer preset applied to the audio ("flat",
+	// "bass" or "speech").
+	Equalizer string `json:"equalizer,omitempty"`
+	// Preview is set while the channel plays as a low-volume preview, not
+	// yet picked; playing it for real or stopping ends the preview.
+	Preview bool `json:"preview,omitempty"`
+	// Behind is how many seconds playback trails the live stream after a
+	// rewind or a pause; 0 when live.
+	Behind float64 `json:"behind,omitempty"`
+	// ListeningSince is when the channel started playing uninterrupted,
+	// for adding the running listen to the channel's listening time; zero
+	// while not playing and during a preview.
+	ListeningSince time.Time `json:"listeningSince,omitzero"`
+	// FailedChannelID is set while stopped after a fatal stream error or
+	// exhausted reconnects: the channel that failed, so clients can offer
+	// to retry it.
+	FailedChannelID string `json:"failedChannelId,omitempty"`
 }
 
 // ChannelsPayload carries the full channel catalog together with the
@@ -29,11 +91,55 @@ type ChannelsPayload struct {
 	Channels      []channels.Channel `json:"channels"`
 	Favorites     []string           `json:"favorites,omitempty"`
 	LastChannelID string             `json:"lastChannelId,omitempty"`
+	// FavoritesHintSeen is set once the favorites onboarding hint has been
+	// dismissed; clients stop offering it.
+	FavoritesHintSeen bool `json:"favoritesHintSeen,omitempty"`
+	// Sort is the channel list order last picked in a client; empty defers
+	// to each client's configured default.
+	Sort string `json:"sort,omitempty"`
+	// Sources names the catalog's station sources ("somafm",
+	// "radiobrowser", "stations") in catalog order. Source is the one last
+	// picked in a client; empty lists them all merged.
+	Sources []string `json:"sources,omitempty"`
+	Source  string   `json:"source,omitempty"`
+	// Updated is when SomaFM last changed the catalog; zero if unknown.
+	Updated time.Time `json:"updated,omitzero"`
+	// Fetched is when the catalog's least recently downloaded source was
+	// fetched; zero if unknown. Stale is set while refreshing it fails, so the catalog is
+	// the cached one.
+	Fetched time.Time `json:"fetched,omitzero"`
+	Stale   bool      `json:"stale,omitempty"`
+	// Offline is set while refreshing fails because SomaFM cannot be
+	// reached at all; the server keeps retrying in the background.
+	Offline bool `json:"offline,omitempty"`
+	// Refreshing is set while the server downloads the catalog.
+	Refreshing bool `json:"refreshing,omitempty"`
+	// Probing is set while the server checks that the channels' streams
+	// answer. Dead maps the channels whose streams did not at the latest
+	// check to why, keyed by channel ID.
+	Probing bool              `json:"probing,omitempty"`
+	Dead    map[string]string `json:"dead,omitempty"`
+	// Listened is how long each channel has been played in total, in
+	// seconds, keyed by channel ID; the running listen (see
+	// PlaybackState.ListeningSince) is not included yet.
+	Listened map[string]int64 `json:"listened,omitempty"`
+	// Recent are the channels played last, newest first.
+	Recent []RecentChannel `json:"recent,omitempty"`
+	// Trends maps channel IDs to how many listeners they gained (positive)
+	// or lost (negative) between the two latest catalog refreshes; steady
+	// channels are left out.
+	Trends map[string]int `json:"trends,omitempty"`
 	// Error is set when the catalog could not be loaded at all (no cache and
 	// the network fetch failed); it clears on the next successful load.
 	Error string `json:"error,omitempty"`
 }
 
+// RecentChannel is a recently played channel and when it was last picked.
+type RecentChannel struct {
+	ChannelID string    `json:"channelId"`
+	Played    time.Time `json:"played"`
+}
+
 // HelloParams is the first request on every connection.
 type HelloParams struct {
 	ClientVersion   string `json:"clientVersion"`
@@ -52,6 +158,18 @@ type PlayParams struct {
 	ChannelID string `json:"channelId"`
 }
 
+// PreviewParams selects the channel to preview; an empty ChannelID ends
+// the preview.
+type PreviewParams struct {
+	ChannelID string `json:"channelId,omitempty"`
+}
+
+// PrebufferParams selects the channel to fetch ahead of a likely play; an
+// empty ChannelID drops the prebuffer.
+type PrebufferParams struct {
+	ChannelID string `json:"channelId,omitempty"`
+}
+
 // PlayRelativeParams selects a channel relative to the current (or last
 // played) one in catalog order: +1 for next, -1 for previous.
 type PlayRelativeParams struct {
@@ -63,12 +181,164 @@ type SetVolumeParams struct {
 	Volume float64 `json:"volume"`
 }
 
+// SetQualityParams carries the preferred playlist quality: "low", "high" or
+// "highest".
+type SetQualityParams struct {
+	Quality string `json:"quality"`
+}
+
+// SetEqualizerParams selects an equalizer preset: "flat", "bass" or
+// "speech".
+type SetEqualizerParams struct {
+	Preset string `json:"preset"`
+}
+
+// SetSortParams selects the channel list order: "api", "alphabetical",
+// "listeners", "genre" or "recent".
+type SetSortParams struct {
+	Sort string `json:"sort"`
+}
+
+// SetSourceParams selects the station source the channel list shows: one
+// of ChannelsPayload.Sources, or empty for all of them merged.
+type SetSourceParams struct {
+	Source string `json:"source"`
+}
+
+// TimeShiftParams moves playback within the stream buffer: Seconds further
+// behind the live stream, or toward it when negative. Live returns straight
+// to the live stream and ignores Seconds.
+type TimeShiftParams struct {
+	Seconds float64 `json:"seconds,omitempty"`
+	Live    bool    `json:"live,omitempty"`
+}
+
 // ToggleFavoriteParams selects the channel whose favorite flag to flip.
 type ToggleFavoriteParams struct {
 	ChannelID string `json:"channelId"`
 }
 
-// FavoritesResult is the favorites list after a toggle.
+// MoveFavoriteParams moves a favorite Delta places up (negative) or down
+// (positive) among the favorites.
+type MoveFavoriteParams struct {
+	ChannelID string `json:"channelId"`
+	Delta     int    `json:"delta"`
+}
+
+// LevelResult is the audio level of the playing stream in [0, 1], for a
+// client's level meter. It is polled rather than pushed with state events,
+// which would otherwise fire many times a second.
+type LevelResult struct {
+	Level float64 `json:"level"`
+}
+
+// SpectrumParams asks for the playing audio's spectrum in Bands frequency
+// bands.
+type SpectrumParams struct {
+	Bands int `json:"bands"`
+}
+
+// SpectrumResult is the level of each frequency band of the playing audio
+// in [0, 1], lowest first, for a client's visualizer; empty while nothing
+// plays or the player backend does not share its audio. Like the level it
+// is polled.
+type SpectrumResult struct {
+	Bands []float64 `json:"bands"`
+}
+
+// StatsResult describes the playing stream's connection, for a client's
+// statistics view. Like the level it is polled, not pushed.
+type StatsResult struct {
+	// BytesReceived counts the stream bytes downloaded since connecting.
+	BytesReceived int64 `json:"bytesReceived"`
+	// ConnectedAt is when the stream connected; zero while not playing.
+	ConnectedAt time.Time `json:"connectedAt"`
+	// Reconnects counts the reconnects since the channel was picked.
+	Reconnects int `json:"reconnects"`
+	// Buffered is how much of the stream waits to be decoded, out of
+	// BufferSize; it grows while paused.
+	Buffered   int `json:"buffered"`
+	BufferSize int `json:"bufferSize"`
+}
+
+// TrackEntry is one now-playing title the server saw, and when. Artist and
+// Song split Title like PlaybackState's TrackArtist and TrackSong.
+type TrackEntry struct {
+	Time      time.Time `json:"time"`
+	ChannelID string    `json:"channelId"`
+	Channel   string    `json:"channel"` // the channel's title
+	Title     string    `json:"title"`
+	Artist    string    `json:"artist,omitempty"`
+	Song      string    `json:"song,omitempty"`
+}
+
+// HistoryResult lists the tracks played since the server started, newest
+// first, up to a fixed number.
+type HistoryResult struct {
+	Tracks []TrackEntry `json:"tracks"`
+}
+
+// ToggleLoveParams selects the track to love or unlove: the one titled
+// Title on ChannelID, or the playing track when Title is empty.
+type ToggleLoveParams struct {
+	ChannelID string `json:"channelId,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// LovedResult lists the loved tracks, newest first, with each Time the
+// moment it was loved. After a toggle, Loved reports which way it went.
+type LovedResult struct {
+	Tracks []TrackEntry `json:"tracks"`
+	Loved  bool         `json:"loved,omitempty"`
+}
+
+// ArtworkResult carries the cover of the track titled Title: JPEG or PNG
+// bytes, or none when no cover was found.
+type ArtworkResult struct {
+	Title string `json:"title"`
+	Image []byte `json:"image,omitempty"`
+}
+
+// ChannelArtworkParams names the channel whose logo to fetch. The result
+// is an ArtworkResult titled with the channel's name.
+type ChannelArtworkParams struct {
+	ChannelID string `json:"channelId"`
+}
+
+// RecentSongsParams names the SomaFM channel whose recent songs to list.
+type RecentSongsParams struct {
+	ChannelID string `json:"channelId"`
+}
+
+// SongEntry is one song a channel played, as the SomaFM songs API lists
+// it; Time is when it started.
+type SongEntry struct {
+	Time   time.Time `json:"time"`
+	Title  string    `json:"title"`
+	Artist string    `json:"artist,omitempty"`
+	Album  string    `json:"album,omitempty"`
+}
+
+// RecentSongsResult lists the songs ChannelID played last, newest first,
+// up to a fixed number.
+type RecentSongsResult struct {
+	ChannelID string      `json:"channelId"`
+	Songs     []SongEntry `json:"songs"`
+}
+
+// FavoritesResult is the favorites list after a toggle or move.
 type FavoritesResult struct {
 	Favorites []string `json:"favorites"`
 }
+
+// SearchStationsParams carries a station directory (Radio Browser) query;
+// an empty one lists the most played stations.
+type SearchStationsParams struct {
+	Query string `json:"query"`
+}
+
+// SearchStationsResult lists the matching directory stations as channels.
+// Their IDs can be passed to play until the next search replaces them.
+type SearchStationsResult struct {
+	Channels []channels.Channel `json:"channels"`
+}
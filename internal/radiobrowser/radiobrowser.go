@@ -0,0 +1,138 @@
+// Package radiobrowser searches the community station directory at
+// radio-browser.info and maps its results onto the channel type the rest of
+// soma plays, so a directory station goes through the same stream and ICY
+// metadata pipeline as a SomaFM channel.
+package radiobrowser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"somad/internal/channels"
+	"somad/internal/security"
+)
+
+// IDPrefix marks channel IDs that name a directory station rather than a
+// SomaFM channel, keeping the two ID spaces from colliding.
+const IDPrefix = "rb:"
+
+const (
+	// searchLimit caps how many stations a search returns.
+	searchLimit = 50
+
+	// maxResponseBytes caps the search response; 50 stations are a few
+	// tens of KB.
+	maxResponseBytes = 1 << 20 // 1 MiB
+)
+
+// SearchURL is the station search endpoint - exported for testing.
+var SearchURL = "https://all.api.radio-browser.info/json/stations/search"
+
+// Station is one entry of a Radio Browser search result. Only the fields
+// soma uses are decoded.
+type Station struct {
+	UUID        string `json:"stationuuid"`
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	URLResolved string `json:"url_resolved"`
+	Homepage    string `json:"homepage"`
+	Favicon     string `json:"favicon"`
+	Tags        string `json:"tags"`
+	Country     string `json:"country"`
+	Codec       string `json:"codec"`
+	Bitrate     int    `json:"bitrate"`
+}
+
+// StreamURL returns the direct stream URL, preferring the one Radio Browser
+// already resolved from a playlist.
+func (s Station) StreamURL() string {
+	if s.URLResolved != "" {
+		return s.URLResolved
+	}
+	return s.URL
+}
+
+// Channel maps the station onto a channel. The stream URL is direct, so it
+// goes into StreamURL and playback skips playlist resolution.
+func (s Station) Channel() channels.Channel {
+	var desc []string
+	if s.Country != "" {
+		desc = append(desc, s.Country)
+	}
+	if s.Bitrate > 0 {
+		desc = append(desc, fmt.Sprintf("%d kbps %s", s.Bitrate, s.Codec))
+	}
+	if s.Homepage != "" {
+		desc = append(desc, s.Homepage)
+	}
+	return channels.Channel{
+		ID:          IDPrefix + s.UUID,
+		Title:       strings.TrimSpace(s.Name),
+		Description: strings.Join(desc, " · "),
+		// Radio Browser separates tags with commas; SomaFM genres use "|".
+		Genre:     strings.ReplaceAll(s.Tags, ",", "|"),
+		Image:     s.Favicon,
+		StreamURL: s.StreamURL(),
+	}
+}
+
+// IsStationID reports whether a channel ID names a directory station.
+func IsStationID(id string) bool {
+	return strings.HasPrefix(id, IDPrefix)
+}
+
+// Search looks up stations whose name matches query, most played first; an
+// empty query lists the most played stations of all. Only MP3 streams are
+// requested, since that is what the player decodes, and stations the
+// directory flags as broken are left out.
+func Search(query, userAgent string) ([]Station, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	params := url.Values{}
+	if query != "" {
+		params.Set("name", query)
+	}
+	params.Set("codec", "MP3")
+	params.Set("hidebroken", "true")
+	params.Set("order", "clickcount")
+	params.Set("reverse", "true")
+	params.Set("limit", strconv.Itoa(searchLimit))
+
+	req, err := security.NewRequest(ctx, SearchURL+"?"+params.Encode(), userAgent)
+	if err != nil {
+		return nil, fmt.Errorf("invalid directory URL: %w", err)
+	}
+
+	resp, err := security.HTTPClient.Do(req) // #nosec G704 -- URL validated by security.NewRequest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to search the station directory: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from the station directory: %d", resp.StatusCode)
+	}
+
+	var stations []Station
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&stations); err != nil {
+		return nil, fmt.Errorf("failed to decode directory response: %w", err)
+	}
+
+	// A station without a stream URL cannot be played; drop it here so the
+	// UI never offers it.
+	playable := stations[:0]
+	for _, st := range stations {
+		if st.UUID != "" && st.StreamURL() != "" {
+			playable = append(playable, st)
+		}
+	}
+	return playable, nil
+}
@@ -0,0 +1,113 @@
+package radiobrowser
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"somad/internal/security/securitytest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStationChannel_MapsFields(t *testing.T) {
+	st := Station{
+		UUID:        "96062a7b-0601-11e8-ae97-52543be04c81",
+		Name:        " Radio Paradise ",
+		URL:         "http://stream.radioparadise.com/mp3-192.m3u",
+		URLResolved: "http://stream.radioparadise.com/mp3-192",
+		Homepage:    "https://radioparadise.com/",
+		Favicon:     "https://radioparadise.com/favicon.ico",
+		Tags:        "eclectic,rock,world",
+		Country:     "United States",
+		Codec:       "MP3",
+		Bitrate:     192,
+	}
+
+	ch := st.Channel()
+
+	assert.Equal(t, "rb:96062a7b-0601-11e8-ae97-52543be04c81", ch.ID)
+	assert.True(t, IsStationID(ch.ID))
+	assert.Equal(t, "Radio Paradise", ch.Title)
+	assert.Equal(t, "eclectic|rock|world", ch.Genre)
+	assert.Equal(t, "United States · 192 kbps MP3 · https://radioparadise.com/", ch.Description)
+	assert.Equal(t, "http://stream.radioparadise.com/mp3-192", ch.StreamURL, "the resolved URL is preferred")
+	assert.Equal(t, "https://radioparadise.com/favicon.ico", ch.Image)
+	assert.Empty(t, ch.Playlists)
+}
+
+func TestStationChannel_FallsBackToUnresolvedURL(t *testing.T) {
+	ch := Station{UUID: "x", Name: "X", URL: "http://example.org/live"}.Channel()
+
+	assert.Equal(t, "http://example.org/live", ch.StreamURL)
+	assert.Empty(t, ch.Description)
+}
+
+func TestIsStationID_SomaFMChannel(t *testing.T) {
+	assert.False(t, IsStationID("groovesalad"))
+}
+
+func TestSearch(t *testing.T) {
+	securitytest.AllowTestHosts(t)
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		assert.Equal(t, "soma/test", r.Header.Get("User-Agent"))
+		_, _ = w.Write([]byte(`[
+			{"stationuuid": "a", "name": "Jazz One", "url_resolved": "http://a.example/jazz"},
+			{"stationuuid": "", "name": "No ID", "url_resolved": "http://b.example/x"},
+			{"stationuuid": "c", "name": "No Stream"}
+		]`))
+	}))
+	defer srv.Close()
+	prev := SearchURL
+	SearchURL = srv.URL
+	defer func() { SearchURL = prev }()
+
+	stations, err := Search("jazz", "soma/test")
+
+	require.NoError(t, err)
+	require.Len(t, stations, 1, "unplayable entries are dropped")
+	assert.Equal(t, "Jazz One", stations[0].Name)
+	assert.Contains(t, query, "name=jazz")
+	assert.Contains(t, query, "codec=MP3")
+	assert.Contains(t, query, "hidebroken=true")
+}
+
+func TestSearch_EmptyQueryListsMostPlayed(t *testing.T) {
+	securitytest.AllowTestHosts(t)
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(`[{"stationuuid": "a", "name": "Top Hits", "url_resolved": "http://a.example/hits"}]`))
+	}))
+	defer srv.Close()
+	prev := SearchURL
+	SearchURL = srv.URL
+	defer func() { SearchURL = prev }()
+
+	stations, err := Search("", "soma/test")
+
+	require.NoError(t, err)
+	require.Len(t, stations, 1)
+	assert.NotContains(t, query, "name=")
+	assert.Contains(t, query, "order=clickcount")
+	assert.Contains(t, query, "reverse=true")
+}
+
+func TestSearch_HTTPError(t *testing.T) {
+	securitytest.AllowTestHosts(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	prev := SearchURL
+	SearchURL = srv.URL
+	defer func() { SearchURL = prev }()
+
+	_, err := Search("jazz", "")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+}
@@ -0,0 +1,67 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"net"
+)
+
+// icyStatus is how legacy Shoutcast servers open their reply ("ICY 200 OK")
+// in place of an HTTP version, which net/http rejects as malformed.
+const icyStatus = "ICY "
+
+// icyDialer wraps dial so every connection it makes reads legacy ICY replies
+// as HTTP/1.0 ones.
+func icyDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &icyConn{Conn: conn}, nil
+	}
+}
+
+// icyConn rewrites a leading "ICY " status line to "HTTP/1.0 ". The headers
+// that follow are ordinary HTTP headers and the body runs until the server
+// hangs up, which is exactly how net/http reads an HTTP/1.0 reply. Anything
+// else, including a TLS handshake, passes through untouched.
+type icyConn struct {
+	net.Conn
+	checked bool
+	pending []byte // bytes read while checking, not yet returned
+	err     error  // the error that ended the check, returned after pending
+}
+
+func (c *icyConn) Read(b []byte) (int, error) {
+	if !c.checked {
+		c.checked = true
+		c.pending, c.err = c.readHead()
+	}
+	if len(c.pending) > 0 {
+		n := copy(b, c.pending)
+		c.pending = c.pending[n:]
+		return n, nil
+	}
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.Conn.Read(b)
+}
+
+// readHead reads just enough of the reply to tell whether it opens with
+// icyStatus, and returns what it read, rewritten if so.
+func (c *icyConn) readHead() ([]byte, error) {
+	head := make([]byte, 0, len(icyStatus))
+	for len(head) < len(icyStatus) && bytes.HasPrefix([]byte(icyStatus), head) {
+		n, err := c.Conn.Read(head[len(head):len(icyStatus)])
+		head = head[:len(head)+n]
+		if err != nil {
+			return head, err
+		}
+	}
+	if string(head) == icyStatus {
+		return []byte("HTTP/1.0 "), nil
+	}
+	return head, nil
+}
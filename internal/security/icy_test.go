@@ -0,0 +1,72 @@
+package security
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveOnce answers the first connection to a local listener with reply and
+// hangs up, returning the listener's URL.
+func serveOnce(t *testing.T, reply string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		buf := make([]byte, 4096)
+		_, _ = conn.Read(buf) // the request
+		_, _ = io.WriteString(conn, reply)
+	}()
+	return "http://" + ln.Addr().String() + "/stream"
+}
+
+func TestStreamHTTPClientAcceptsICYReplies(t *testing.T) {
+	url := serveOnce(t, "ICY 200 OK\r\nicy-name: Legacy\r\nicy-metaint: 8192\r\n\r\naudio")
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, url, nil)
+	require.NoError(t, err)
+	resp, err := StreamHTTPClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "8192", resp.Header.Get("icy-metaint"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "audio", string(body))
+}
+
+func TestICYConn_RewritesOnlyTheStatusLine(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"icy", "ICY 200 OK\r\n\r\nICY ", "HTTP/1.0 200 OK\r\n\r\nICY "},
+		{"http", "HTTP/1.1 200 OK\r\n\r\n", "HTTP/1.1 200 OK\r\n\r\n"},
+		{"short", "IC", "IC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, server := net.Pipe()
+			go func() {
+				// One byte at a time, so the check must span reads.
+				_, _ = io.Copy(server, iotest.OneByteReader(strings.NewReader(tt.in)))
+				_ = server.Close()
+			}()
+			got, err := io.ReadAll(&icyConn{Conn: client})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package security
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// DefaultMaxConcurrentRequests bounds how many HTTPClient requests may be in
+// flight at once unless SetMaxConcurrentRequests says otherwise.
+const DefaultMaxConcurrentRequests = 4
+
+// limitedTransport caps the number of concurrent requests through base. A
+// request holds its slot until its response body is closed, since reading the
+// body is what keeps the connection busy; a request waiting for a slot gives
+// up when its context is done.
+type limitedTransport struct {
+	base http.RoundTripper
+
+	mu  sync.Mutex
+	sem chan struct{}
+}
+
+func newLimitedTransport(base http.RoundTripper, limit int) *limitedTransport {
+	t := &limitedTransport{base: base}
+	t.setLimit(limit)
+	return t
+}
+
+// setLimit replaces the bound. Requests already in flight release the slot
+// they took, so a resize never wedges them.
+func (t *limitedTransport) setLimit(limit int) {
+	if limit < 1 {
+		limit = 1
+	}
+	t.mu.Lock()
+	t.sem = make(chan struct{}, limit)
+	t.mu.Unlock()
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	sem := t.sem
+	t.mu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	release := sync.OnceFunc(func() { <-sem })
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody frees its request's slot when closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
+
+// SetMaxConcurrentRequests bounds how many HTTPClient requests (catalog,
+// playlist, and directory fetches) may be in flight at once; values below 1
+// are treated as 1. Audio streams use StreamHTTPClient or StationHTTPClient
+// and are not counted, since a stream holds its connection for as long as
+// it plays: the player keeps at most the playing stream and one prebuffered
+// one, and channel probes bound themselves to a few short requests at a
+// time.
+func SetMaxConcurrentRequests(n int) {
+	httpLimiter.setLimit(n)
+}
@@ -0,0 +1,83 @@
+package security
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedTransport answers every request once release is closed, tracking how
+// many are in flight at once.
+type gatedTransport struct {
+	release  chan struct{}
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+func (g *gatedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	n := g.inFlight.Add(1)
+	for {
+		peak := g.peak.Load()
+		if n <= peak || g.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	<-g.release
+	g.inFlight.Add(-1)
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok")), Request: req}, nil
+}
+
+func TestLimitedTransport_BoundsConcurrency(t *testing.T) {
+	base := &gatedTransport{release: make(chan struct{})}
+	lt := newLimitedTransport(base, 2)
+
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, "http://somafm.com/channels.json", nil)
+			resp, err := lt.RoundTrip(req)
+			if assert.NoError(t, err) {
+				_ = resp.Body.Close()
+			}
+		}()
+	}
+
+	require.Eventually(t, func() bool { return base.inFlight.Load() == 2 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond) // give a third request the chance to slip through
+	assert.Equal(t, int32(2), base.inFlight.Load())
+
+	close(base.release)
+	wg.Wait()
+	assert.Equal(t, int32(2), base.peak.Load())
+}
+
+func TestLimitedTransport_SlotHeldUntilBodyClosed(t *testing.T) {
+	base := &gatedTransport{release: make(chan struct{})}
+	close(base.release)
+	lt := newLimitedTransport(base, 1)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://somafm.com/channels.json", nil)
+	first, err := lt.RoundTrip(req)
+	require.NoError(t, err)
+
+	// The first body is still open, so the only slot is taken.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = lt.RoundTrip(req.WithContext(ctx))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, first.Body.Close())
+	second, err := lt.RoundTrip(req)
+	require.NoError(t, err)
+	_ = second.Body.Close()
+}
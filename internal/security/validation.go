@@ -3,36 +3,187 @@ package security
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"slices"
 	"strings"
 	"sync"
+	"syscall"
+	"time"
 )
 
 const allowedHostSuffix = ".somafm.com"
 
+// directoryHostSuffix admits the Radio Browser API mirrors
+// (de1.api.radio-browser.info, all.api.radio-browser.info, ...).
+const directoryHostSuffix = ".api.radio-browser.info"
+
+// artworkHosts admit the cover art sources: the iTunes Search API and the
+// CDN its artwork URLs point at, and MusicBrainz (also asked for track
+// details) with the Cover Art Archive, which redirects to archive.org. A
+// leading dot admits any subdomain.
+var artworkHosts = []string{
+	"itunes.apple.com", ".mzstatic.com",
+	"musicbrainz.org", "coverartarchive.org", ".archive.org",
+}
+
 // maxRedirects matches net/http's default redirect limit, re-applied here
 // because supplying CheckRedirect replaces that default.
 const maxRedirects = 10
 
 // HTTPClient is the process-wide HTTP client, shared so connections to the
-// SomaFM hosts are reused across playlist, channel, stream, and metadata
-// requests. Per-request deadlines come from the request context.
-//
-// CheckRedirect re-validates every redirect target: ValidateURL only guards
-// the initial URL, so without this a redirect (feasible over the allowed http
-// scheme) could send a request to an internal or otherwise disallowed host.
+// SomaFM hosts are reused across playlist, channel, and directory requests.
+// Per-request deadlines come from the request context, and at most
+// DefaultMaxConcurrentRequests are in flight at once (see
+// SetMaxConcurrentRequests).
 var HTTPClient = &http.Client{
-	CheckRedirect: func(req *http.Request, via []*http.Request) error {
-		if len(via) >= maxRedirects {
-			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	Transport:     httpLimiter,
+	CheckRedirect: checkRedirect,
+}
+
+// httpLimiter bounds HTTPClient's concurrent requests.
+var httpLimiter = newLimitedTransport(http.DefaultTransport, DefaultMaxConcurrentRequests)
+
+// StreamHTTPClient fetches audio streams. ICY metadata is an HTTP/1.x
+// convention that servers may drop (along with icy-metaint) over HTTP/2, so
+// this client only ever speaks HTTP/1.1; the JSON APIs keep HTTP/2 through
+// HTTPClient. It also accepts the "ICY 200 OK" replies of legacy
+// Shoutcast servers.
+var StreamHTTPClient = &http.Client{
+	Transport:     newStreamTransport(),
+	CheckRedirect: checkRedirect,
+}
+
+// StationHTTPClient is StreamHTTPClient for directory and custom stations,
+// which stream from hosts of their own. It is not bound to the allowlist;
+// instead it refuses to connect to local and private addresses (see
+// NewStreamRequest).
+var StationHTTPClient = &http.Client{
+	Transport:     newStationTransport(),
+	CheckRedirect: checkStreamRedirect,
+}
+
+// newStreamTransport returns a copy of the default transport restricted to
+// HTTP/1.1 that reads ICY status lines as HTTP/1.0.
+func newStreamTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Protocols = new(http.Protocols)
+	t.Protocols.SetHTTP1(true)
+	t.DialContext = icyDialer(t.DialContext)
+	return t
+}
+
+// stationProxy picks the proxy for a station request, by default from the
+// environment. A variable so tests can set one.
+var stationProxy = http.ProxyFromEnvironment
+
+// newStationTransport is newStreamTransport checking every address it
+// dials, so a public hostname resolving to a private address is caught.
+// Through a proxy, which may well listen on the local machine, the proxy
+// is dialed unchecked and the station's host is checked instead.
+func newStationTransport() *http.Transport {
+	t := newStreamTransport()
+	checked := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   checkStreamDial,
+	}
+	direct := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	var proxies sync.Map // the host:port of every proxy in use
+	t.Proxy = func(req *http.Request) (*url.URL, error) {
+		proxy, err := stationProxy(req)
+		if err != nil || proxy == nil {
+			return proxy, err
+		}
+		if err := checkProxiedHost(req.Context(), req.URL.Hostname()); err != nil {
+			return nil, err
+		}
+		proxies.Store(proxyAddr(proxy), true)
+		return proxy, nil
+	}
+	t.DialContext = icyDialer(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if _, ok := proxies.Load(addr); ok {
+			return direct.DialContext(ctx, network, addr)
+		}
+		return checked.DialContext(ctx, network, addr)
+	})
+	return t
+}
+
+// proxyAddr returns the host:port the transport dials to reach proxy.
+func proxyAddr(proxy *url.URL) string {
+	port := proxy.Port()
+	if port == "" {
+		port = map[string]string{"https": "443", "socks5": "1080", "socks5h": "1080"}[proxy.Scheme]
+		if port == "" {
+			port = "80"
 		}
-		if err := ValidateURL(req.URL.String()); err != nil {
-			return fmt.Errorf("redirect to disallowed URL: %w", err)
+	}
+	return net.JoinHostPort(proxy.Hostname(), port)
+}
+
+// checkProxiedHost refuses a station host that resolves to a local or
+// private address. A proxy resolves the host itself, so the addresses it
+// resolves to here stand in for the ones the proxy dials; a host that does
+// not resolve here is left to the proxy, as networks behind one often
+// cannot resolve outside names.
+func checkProxiedHost(ctx context.Context, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if !streamAddrAllowed(addr) {
+			return fmt.Errorf("stream address not allowed: %s (local or private network)", host)
 		}
 		return nil
-	},
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if !streamAddrAllowed(addr) {
+			return fmt.Errorf("stream address not allowed: %s resolves to %s (local or private network)", host, addr)
+		}
+	}
+	return nil
+}
+
+// checkStreamDial refuses a stream connection to a local or private
+// address, after DNS resolution.
+func checkStreamDial(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid address: %w", err)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !streamAddrAllowed(addr) {
+		return fmt.Errorf("stream address not allowed: %s (local or private network)", host)
+	}
+	return nil
+}
+
+// checkRedirect re-validates every redirect target: ValidateURL only guards
+// the initial URL, so without this a redirect (feasible over the allowed http
+// scheme) could send a request to an internal or otherwise disallowed host.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if err := ValidateURL(req.URL.String()); err != nil {
+		return fmt.Errorf("redirect to disallowed URL: %w", err)
+	}
+	return nil
+}
+
+// checkStreamRedirect is checkRedirect for streams, re-validating every
+// redirect target with ValidateStreamURL.
+func checkStreamRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if err := ValidateStreamURL(req.URL.String()); err != nil {
+		return fmt.Errorf("redirect to disallowed URL: %w", err)
+	}
+	return nil
 }
 
 // extraAllowedHostsMu guards extraAllowedHosts. ValidateURL reads this state
@@ -66,13 +217,57 @@ func ValidateURL(rawURL string) error {
 	}
 
 	host := strings.ToLower(parsed.Hostname())
-	if !strings.HasSuffix(host, allowedHostSuffix) && host != "somafm.com" && !isExtraAllowedHost(host) {
-		return fmt.Errorf("URL host not allowed: %s (must be somafm.com or subdomain)", host)
+	if !strings.HasSuffix(host, allowedHostSuffix) && host != "somafm.com" &&
+		!strings.HasSuffix(host, directoryHostSuffix) && !isArtworkHost(host) && !isExtraAllowedHost(host) {
+		return fmt.Errorf("URL host not allowed: %s (not a SomaFM, directory, or artwork host)", host)
 	}
 
 	return nil
 }
 
+// ValidateStreamURL checks the URL of a directory or custom station's
+// stream or playlist. Stations stream from arbitrary hosts, so any http(s)
+// host passes except an address on the local machine or network; hostnames
+// are checked once resolved, when StationHTTPClient dials them.
+func ValidateStreamURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if parsed.Scheme != "https" && parsed.Scheme != "http" {
+		return fmt.Errorf("invalid URL scheme: %s (expected http or https)", parsed.Scheme)
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host == "" {
+		return fmt.Errorf("invalid URL: no host in %q", rawURL)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !streamAddrAllowed(addr) {
+		return fmt.Errorf("URL host not allowed: %s (local or private network)", host)
+	}
+	return nil
+}
+
+// streamAddrAllowed reports whether a stream may come from addr: anything
+// but a loopback, private, link-local, or unspecified address, unless a
+// test admitted it (see securitytest).
+func streamAddrAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsLoopback() && !addr.IsPrivate() && !addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() && !addr.IsUnspecified() {
+		return true
+	}
+	return isExtraAllowedHost(addr.String())
+}
+
+func isArtworkHost(host string) bool {
+	for _, h := range artworkHosts {
+		if host == h || strings.HasPrefix(h, ".") && strings.HasSuffix(host, h) {
+			return true
+		}
+	}
+	return false
+}
+
 func isExtraAllowedHost(host string) bool {
 	extraAllowedHostsMu.RLock()
 	defer extraAllowedHostsMu.RUnlock()
@@ -95,3 +290,27 @@ func NewRequest(ctx context.Context, rawURL, userAgent string) (*http.Request, e
 	}
 	return req, nil
 }
+
+// NewStreamRequest creates the request for an audio stream or a station's
+// playlist, along with the client that must send it. A URL on an allowed
+// host, as SomaFM's streams are, is validated as by NewRequest and goes
+// through StreamHTTPClient; any other belongs to a directory or custom
+// station, is validated by ValidateStreamURL, and goes through
+// StationHTTPClient.
+func NewStreamRequest(ctx context.Context, rawURL, userAgent string) (*http.Request, *http.Client, error) {
+	client := StreamHTTPClient
+	if ValidateURL(rawURL) != nil {
+		if err := ValidateStreamURL(rawURL); err != nil {
+			return nil, nil, fmt.Errorf("invalid URL: %w", err)
+		}
+		client = StationHTTPClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	return req, client, nil
+}
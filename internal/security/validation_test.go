@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -61,6 +62,31 @@ func TestValidateURL(t *testing.T) {
 			url:     "https://Ice1.SomaFM.Com/stream",
 			wantErr: false,
 		},
+		{
+			name:    "radio browser API mirror",
+			url:     "https://de1.api.radio-browser.info/json/stations/search",
+			wantErr: false,
+		},
+		{
+			name:    "radio browser lookalike",
+			url:     "https://api.radio-browser.info.evil.com/json",
+			wantErr: true,
+		},
+		{
+			name:    "artwork CDN",
+			url:     "https://is1-ssl.mzstatic.com/image/thumb/cover/600x600bb.jpg",
+			wantErr: false,
+		},
+		{
+			name:    "cover art archive redirect",
+			url:     "https://ia800100.us.archive.org/cover.jpg",
+			wantErr: false,
+		},
+		{
+			name:    "artwork host lookalike",
+			url:     "https://evilmzstatic.com/cover.jpg",
+			wantErr: true,
+		},
 		{
 			name:    "empty host",
 			url:     "https:///channels.json",
@@ -94,6 +120,121 @@ func TestValidateURLExtraAllowedHost(t *testing.T) {
 	require.Error(t, ValidateURL(url), "host should be rejected again after clearing")
 }
 
+func TestValidateStreamURL(t *testing.T) {
+	for _, u := range []string{
+		"http://stream.example.org:8000/live.mp3",
+		"https://ice1.somafm.com/groovesalad-128-mp3",
+		"http://203.0.113.7/live",
+	} {
+		assert.NoError(t, ValidateStreamURL(u), u)
+	}
+	for _, u := range []string{
+		"file:///etc/passwd",
+		"http:///nohost",
+		"http://127.0.0.1:8080/",
+		"http://[::1]/",
+		"http://10.0.0.5/live",
+		"http://192.168.1.1/",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://0.0.0.0/",
+		"http://[::ffff:127.0.0.1]/",
+	} {
+		assert.Error(t, ValidateStreamURL(u), u)
+	}
+	assert.Error(t, ValidateURL("http://stream.example.org/live"), "a stream host is not admitted for other requests")
+}
+
+func TestNewStreamRequestPicksTheClient(t *testing.T) {
+	_, client, err := NewStreamRequest(t.Context(), "https://ice1.somafm.com/groovesalad-128-mp3", "")
+	require.NoError(t, err)
+	assert.Same(t, StreamHTTPClient, client, "SomaFM streams stay on the allowlist")
+
+	_, client, err = NewStreamRequest(t.Context(), "http://stream.example.org:8000/live.mp3", "")
+	require.NoError(t, err)
+	assert.Same(t, StationHTTPClient, client)
+
+	_, _, err = NewStreamRequest(t.Context(), "http://10.0.0.5/live", "")
+	assert.Error(t, err)
+}
+
+func TestStationHTTPClientRefusesPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// A hostname is only resolved when dialed, so the dial is what refuses it.
+	u := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+	req, client, err := NewStreamRequest(t.Context(), u, "")
+	require.NoError(t, err)
+	require.Same(t, StationHTTPClient, client)
+	resp, err := client.Do(req)
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not allowed")
+
+	// Unless a test admits the address.
+	AddAllowedHost("127.0.0.1")
+	AddAllowedHost("::1")
+	defer ClearAllowedHosts()
+	resp, err = client.Do(req.Clone(t.Context()))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+}
+
+func TestStationHTTPClientThroughLocalProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		_, _ = w.Write([]byte("audio"))
+	}))
+	defer proxy.Close()
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+	prev := stationProxy
+	stationProxy = http.ProxyURL(proxyURL)
+	defer func() { stationProxy = prev }()
+
+	// The proxy listens on loopback, which a station itself may not.
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://203.0.113.7/live", nil)
+	require.NoError(t, err)
+	resp, err := StationHTTPClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, []string{"http://203.0.113.7/live"}, proxied)
+
+	// The station's own host is still checked.
+	for _, u := range []string{"http://10.0.0.5/live", "http://localhost:8000/live"} {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, u, nil)
+		require.NoError(t, err)
+		resp, err := StationHTTPClient.Do(req)
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		require.Error(t, err, u)
+		assert.Contains(t, err.Error(), "not allowed", u)
+	}
+	assert.Len(t, proxied, 1, "refused requests never reach the proxy")
+}
+
+func TestStationHTTPClientRedirectValidation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	}))
+	defer srv.Close()
+	AddAllowedHost("127.0.0.1")
+	defer ClearAllowedHosts()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := StationHTTPClient.Do(req)
+	if resp != nil {
+		_ = resp.Body.Close()
+	}
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "disallowed URL")
+}
+
 func hostOf(t *testing.T, rawURL string) string {
 	t.Helper()
 	u, err := url.Parse(rawURL)
@@ -148,3 +289,31 @@ func TestHTTPClientRedirectValidation(t *testing.T) {
 		assert.Equal(t, 1, endHits)
 	})
 }
+
+func TestStreamHTTPClientUsesHTTP1(t *testing.T) {
+	var proto string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proto = r.Proto
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	// Trust the test certificate; the protocol restriction is what's tested.
+	transport := newStreamTransport()
+	transport.TLSClientConfig = srv.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+	client := &http.Client{Transport: transport}
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, "HTTP/1.1", proto, "streams must not negotiate HTTP/2")
+
+	// The server does speak HTTP/2, so the check above is meaningful.
+	resp, err = srv.Client().Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, "HTTP/2.0", proto)
+}
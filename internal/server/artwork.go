@@ -0,0 +1,68 @@
+package server
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+
+	"somad/internal/artwork"
+	"somad/internal/protocol"
+)
+
+// lookupArtwork finds a track's cover. A variable so tests can avoid the
+// network.
+var lookupArtwork = artwork.Lookup
+
+// lookupChannelImage downloads a channel logo. A variable so tests can
+// avoid the network.
+var lookupChannelImage = artwork.ChannelImage
+
+// Artwork returns the cover of the playing track. A title that names no
+// artist is not looked up, since a song title alone matches too much; it
+// and a track no source knows get no image.
+func (s *Server) Artwork() (protocol.ArtworkResult, error) {
+	s.mu.Lock()
+	track := s.track
+	s.mu.Unlock()
+
+	result := protocol.ArtworkResult{Title: track.Title}
+	if track.Artist == "" {
+		return result, nil
+	}
+	image, err := lookupArtwork(track.Artist, track.Song, s.userAgent)
+	if errors.Is(err, artwork.ErrNotFound) {
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Image = image
+	return result, nil
+}
+
+// ChannelArtwork returns the logo of channelID, preferring the larger of the
+// images channels.json lists. Directory stations and channels without a
+// logo get no image.
+func (s *Server) ChannelArtwork(channelID string) (protocol.ArtworkResult, error) {
+	s.mu.Lock()
+	ch, ok := s.findChannelLocked(channelID)
+	s.mu.Unlock()
+	if !ok {
+		return protocol.ArtworkResult{}, fmt.Errorf("unknown channel: %s", channelID)
+	}
+
+	result := protocol.ArtworkResult{Title: ch.Title}
+	imageURL := cmp.Or(ch.LargeImage, ch.Image, ch.XLImage)
+	if imageURL == "" {
+		return result, nil
+	}
+	image, err := lookupChannelImage(imageURL, s.userAgent)
+	if errors.Is(err, artwork.ErrNotFound) {
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Image = image
+	return result, nil
+}
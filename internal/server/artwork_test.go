@@ -0,0 +1,99 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"somad/internal/artwork"
+	"somad/internal/audio"
+	"somad/internal/protocol"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubArtwork replaces the cover lookup for one test, recording what was
+// looked up.
+func stubArtwork(t *testing.T, image []byte, err error) *[]string {
+	t.Helper()
+	var lookups []string
+	prev := lookupArtwork
+	lookupArtwork = func(artist, song, _ string) ([]byte, error) {
+		lookups = append(lookups, artist+"|"+song)
+		return image, err
+	}
+	t.Cleanup(func() { lookupArtwork = prev })
+	return &lookups
+}
+
+func artworkResult(t *testing.T, c *tclient) protocol.ArtworkResult {
+	t.Helper()
+	resp := c.call(protocol.MethodArtwork, nil)
+	require.Empty(t, resp.Error)
+	var result protocol.ArtworkResult
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	return result
+}
+
+func TestArtwork_LooksUpThePlayingTrack(t *testing.T) {
+	lookups := stubArtwork(t, []byte("cover"), nil)
+	s, _ := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Tycho - Awake", Artist: "Tycho", Song: "Awake"})
+
+	result := artworkResult(t, c)
+	assert.Equal(t, "Tycho - Awake", result.Title)
+	assert.Equal(t, []byte("cover"), result.Image)
+	assert.Equal(t, []string{"Tycho|Awake"}, *lookups)
+}
+
+func TestArtwork_NoImageWithoutArtistOrCover(t *testing.T) {
+	lookups := stubArtwork(t, nil, artwork.ErrNotFound)
+	s, _ := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Station ID", Song: "Station ID"})
+	result := artworkResult(t, c)
+	assert.Equal(t, "Station ID", result.Title)
+	assert.Empty(t, result.Image)
+	assert.Empty(t, *lookups, "a title without an artist is not looked up")
+
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Tycho - Awake", Artist: "Tycho", Song: "Awake"})
+	result = artworkResult(t, c)
+	assert.Empty(t, result.Image)
+	assert.Len(t, *lookups, 1)
+}
+
+func TestChannelArtwork_PrefersTheLargeImage(t *testing.T) {
+	var fetched []string
+	prev := lookupChannelImage
+	lookupChannelImage = func(imageURL, _ string) ([]byte, error) {
+		fetched = append(fetched, imageURL)
+		return []byte("logo"), nil
+	}
+	t.Cleanup(func() { lookupChannelImage = prev })
+	s, _ := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	resp := c.call(protocol.MethodChannelArtwork, protocol.ChannelArtworkParams{ChannelID: "groovesalad"})
+	require.Empty(t, resp.Error)
+	var result protocol.ArtworkResult
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	assert.Equal(t, "Groove Salad", result.Title)
+	assert.Equal(t, []byte("logo"), result.Image)
+	assert.Equal(t, []string{"http://somafm.com/logos/256/groovesalad256.png"}, fetched)
+
+	result, err := s.ChannelArtwork("dronezone")
+	require.NoError(t, err)
+	assert.Empty(t, result.Image, "a channel without a logo gets no image")
+	assert.Len(t, fetched, 1)
+
+	_, err = s.ChannelArtwork("nosuchchannel")
+	require.Error(t, err)
+}
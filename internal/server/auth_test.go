@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"somad/internal/channels"
 	"somad/internal/client"
 	"somad/internal/protocol"
 	"somad/internal/tlsutil"
@@ -203,12 +204,12 @@ func TestAuth_UnauthenticatedConnsReceiveNoBroadcasts(t *testing.T) {
 
 	// A broadcast triggered while the connection has not authenticated must
 	// not reach it: state snapshots leak what is playing.
-	s.setCatalog(testChannels())
+	s.setCatalog(&channels.Channels{Channels: testChannels()})
 	c.expectNoEvent("broadcast to an unauthenticated connection")
 
 	// After authenticating, the same broadcast arrives.
 	require.Empty(t, c.authenticate("secret").Error)
-	s.setCatalog(testChannels())
+	s.setCatalog(&channels.Channels{Channels: testChannels()})
 	c.waitChannels("broadcast after authenticating")
 }
 
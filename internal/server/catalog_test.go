@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -88,21 +89,132 @@ func TestRefreshCatalog_NetworkFailureIsSilentWhenCatalogNonEmpty(t *testing.T)
 	})
 
 	s := newBareServer(t)
-	s.setCatalog(testChannels())
+	s.setCatalog(&channels.Channels{Channels: testChannels()})
 
 	s.refreshCatalog()
 
 	payload := s.ChannelsPayload()
 	assert.Len(t, payload.Channels, len(testChannels()))
 	assert.Empty(t, payload.Error, "a prior successful catalog must not be clobbered by a background refresh failure")
+	assert.True(t, payload.Stale, "clients should learn the catalog is only as current as the cache")
 }
 
-func TestRefreshLoop_PeriodicallyRefreshesCatalog(t *testing.T) {
+func TestSetCatalog_ListenerTrends(t *testing.T) {
+	s := newBareServer(t)
+	withListeners := func(counts ...int) *channels.Channels {
+		chs := testChannels()
+		for i := range chs {
+			chs[i].Listeners = counts[i]
+		}
+		return &channels.Channels{Channels: chs}
+	}
+
+	s.setCatalog(withListeners(100, 50, 10))
+	assert.Empty(t, s.ChannelsPayload().Trends, "the first catalog has no trend yet")
+
+	s.setCatalog(withListeners(112, 47, 10))
+	assert.Equal(t, map[string]int{"groovesalad": 12, "dronezone": -3}, s.ChannelsPayload().Trends)
+}
+
+func TestLoadCatalog_FreshCacheSkipsNetwork(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	fetched := time.Now().Add(-10 * time.Minute).Truncate(time.Second)
+	cached := &channels.Channels{Channels: testChannels(), Fetched: fetched}
+	require.NoError(t, channels.WriteChannelsToCache(cached))
+
+	hits := make(chan struct{}, 1)
+	stubChannelsNetwork(t, func(w http.ResponseWriter, r *http.Request) {
+		hits <- struct{}{}
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	s := newBareServer(t)
+	s.catalogTTL = time.Hour
+	s.loadCatalog()
+
+	payload := s.ChannelsPayload()
+	assert.Len(t, payload.Channels, len(testChannels()))
+	assert.True(t, fetched.Equal(payload.Fetched), "got %v", payload.Fetched)
+	assert.False(t, payload.Stale)
+	select {
+	case <-hits:
+		t.Fatal("a cache younger than the TTL must not be downloaded again")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestLoadCatalog_ExpiredCacheRefreshes(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cached := &channels.Channels{
+		Channels: []channels.Channel{{ID: "cached-only", Title: "Cached Only"}},
+		Fetched:  time.Now().Add(-2 * time.Hour),
+	}
+	require.NoError(t, channels.WriteChannelsToCache(cached))
+
+	stubChannelsNetwork(t, func(w http.ResponseWriter, r *http.Request) {
+		data, _ := json.Marshal(channels.Channels{Channels: testChannels()})
+		_, _ = w.Write(data)
+	})
+
+	s := newBareServer(t)
+	s.catalogTTL = time.Hour
+	s.loadCatalog()
+
+	require.Eventually(t, func() bool {
+		return len(s.ChannelsPayload().Channels) == len(testChannels())
+	}, 2*time.Second, 10*time.Millisecond, "a cache older than the TTL should be downloaded again")
+}
+
+func TestRefreshCatalog_UnreachableGoesOfflineAndRetries(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	prev := offlineRetryInterval
+	offlineRetryInterval = 10 * time.Millisecond
+	t.Cleanup(func() { offlineRetryInterval = prev })
+
+	var reachable atomic.Bool
+	stubChannelsNetwork(t, func(w http.ResponseWriter, r *http.Request) {
+		if !reachable.Load() {
+			// Drop the connection, as a dead network would.
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			_ = conn.Close()
+			return
+		}
+		data, _ := json.Marshal(channels.Channels{Channels: testChannels()})
+		_, _ = w.Write(data)
+	})
+
+	s := newBareServer(t)
+	s.setCatalog(&channels.Channels{Channels: []channels.Channel{{ID: "cached-only", Title: "Cached Only"}}})
+
+	s.refreshCatalog()
+
+	payload := s.ChannelsPayload()
+	assert.True(t, payload.Offline)
+	assert.True(t, payload.Stale)
+	assert.Len(t, payload.Channels, 1, "the cached catalog stays browsable")
+
+	reachable.Store(true)
+	require.Eventually(t, func() bool {
+		p := s.ChannelsPayload()
+		return !p.Offline && len(p.Channels) == len(testChannels())
+	}, 2*time.Second, 10*time.Millisecond, "the background retry should bring the catalog back")
+}
+
+func TestRefreshCatalog_ErrorResponseIsNotOffline(t *testing.T) {
 	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	stubChannelsNetwork(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	s := newBareServer(t)
+	s.setCatalog(&channels.Channels{Channels: testChannels()})
+	s.refreshCatalog()
 
-	prev := channelRefreshInterval
-	channelRefreshInterval = time.Millisecond
-	t.Cleanup(func() { channelRefreshInterval = prev })
+	assert.False(t, s.ChannelsPayload().Offline, "SomaFM answered, so the network is up")
+}
+
+func TestRefreshLoop_PeriodicallyRefreshesCatalog(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
 
 	hits := make(chan struct{}, 8)
 	fresh := channels.Channels{Channels: testChannels()}
@@ -116,7 +228,17 @@ func TestRefreshLoop_PeriodicallyRefreshesCatalog(t *testing.T) {
 	})
 
 	s := newBareServer(t)
-	go s.refreshLoop()
+	s.refreshInterval = time.Millisecond
+	done := make(chan struct{})
+	go func() {
+		s.refreshLoop()
+		close(done)
+	}()
+	// Stop the loop before the stubbed network goes away.
+	t.Cleanup(func() {
+		s.Shutdown()
+		<-done
+	})
 
 	for range 2 {
 		select {
@@ -126,3 +248,52 @@ func TestRefreshLoop_PeriodicallyRefreshesCatalog(t *testing.T) {
 		}
 	}
 }
+
+func TestRefreshChannels_ToldToClients(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	probed := stubProbe(t, nil)
+	prevResolve := resolveStreamURLs
+	resolveStreamURLs = func(playlistURL, _ string) ([]string, error) {
+		return []string{playlistURL + "#stream"}, nil
+	}
+	t.Cleanup(func() { resolveStreamURLs = prevResolve })
+	release := make(chan struct{})
+	stubChannelsNetwork(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		data, _ := json.Marshal(channels.Channels{Channels: testChannels()})
+		_, _ = w.Write(data)
+	})
+
+	s := newBareServer(t)
+	s.probeInterval = time.Hour
+	s.setCatalog(&channels.Channels{Channels: testChannels()[:1]})
+	s.RefreshChannels()
+	require.Eventually(t, func() bool { return s.ChannelsPayload().Refreshing }, 2*time.Second, 5*time.Millisecond)
+
+	s.refreshCatalog() // returns at once, or it would wait for release
+	close(release)
+	require.Eventually(t, func() bool { return len(s.ChannelsPayload().Channels) == 3 }, 2*time.Second, 5*time.Millisecond)
+	assert.False(t, s.ChannelsPayload().Refreshing)
+
+	// The streams are probed after the refresh.
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.deadChannels != nil && !s.probing
+	}, 2*time.Second, 5*time.Millisecond)
+	assert.NotEmpty(t, *probed)
+}
+
+func TestRefreshLoop_DisabledByZeroInterval(t *testing.T) {
+	s := newBareServer(t)
+	done := make(chan struct{})
+	go func() {
+		s.refreshLoop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("refreshLoop kept running without an interval")
+	}
+}
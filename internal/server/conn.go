@@ -218,9 +218,85 @@ func (c *conn) handleRequest(req protocol.Request) {
 	case protocol.MethodStatus:
 		c.respond(req.ID, c.s.Snapshot())
 
+	case protocol.MethodLevel:
+		c.respond(req.ID, protocol.LevelResult{Level: c.s.Level()})
+
+	case protocol.MethodSpectrum:
+		var params protocol.SpectrumParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			c.respondError(req.ID, fmt.Errorf("malformed spectrum params: %w", err))
+			return
+		}
+		bands, err := c.s.Spectrum(params.Bands)
+		if err != nil {
+			c.respondError(req.ID, err)
+			return
+		}
+		c.respond(req.ID, protocol.SpectrumResult{Bands: bands})
+
+	case protocol.MethodStats:
+		c.respond(req.ID, c.s.Stats())
+
+	case protocol.MethodHistory:
+		c.respond(req.ID, c.s.History())
+
+	case protocol.MethodLoved:
+		c.respond(req.ID, c.s.Loved())
+
+	case protocol.MethodToggleLove:
+		var params protocol.ToggleLoveParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			c.respondError(req.ID, fmt.Errorf("malformed toggleLove params: %w", err))
+			return
+		}
+		result, err := c.s.ToggleLove(params.ChannelID, params.Title)
+		if err != nil {
+			c.respondError(req.ID, err)
+			return
+		}
+		c.respond(req.ID, result)
+
+	case protocol.MethodArtwork:
+		result, err := c.s.Artwork()
+		if err != nil {
+			c.respondError(req.ID, err)
+			return
+		}
+		c.respond(req.ID, result)
+
+	case protocol.MethodChannelArtwork:
+		var params protocol.ChannelArtworkParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			c.respondError(req.ID, fmt.Errorf("malformed channelArtwork params: %w", err))
+			return
+		}
+		result, err := c.s.ChannelArtwork(params.ChannelID)
+		if err != nil {
+			c.respondError(req.ID, err)
+			return
+		}
+		c.respond(req.ID, result)
+
+	case protocol.MethodRecentSongs:
+		var params protocol.RecentSongsParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			c.respondError(req.ID, fmt.Errorf("malformed recentSongs params: %w", err))
+			return
+		}
+		result, err := c.s.RecentSongs(params.ChannelID)
+		if err != nil {
+			c.respondError(req.ID, err)
+			return
+		}
+		c.respond(req.ID, result)
+
 	case protocol.MethodChannels:
 		c.respond(req.ID, c.s.ChannelsPayload())
 
+	case protocol.MethodRefresh:
+		c.s.RefreshChannels()
+		c.respond(req.ID, struct{}{})
+
 	case protocol.MethodPlay:
 		var params protocol.PlayParams
 		if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -234,6 +310,31 @@ func (c *conn) handleRequest(req protocol.Request) {
 		}
 		c.respond(req.ID, snap)
 
+	case protocol.MethodPreview:
+		var params protocol.PreviewParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			c.respondError(req.ID, fmt.Errorf("malformed preview params: %w", err))
+			return
+		}
+		snap, err := c.s.Preview(params.ChannelID)
+		if err != nil {
+			c.respondError(req.ID, err)
+			return
+		}
+		c.respond(req.ID, snap)
+
+	case protocol.MethodPrebuffer:
+		var params protocol.PrebufferParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			c.respondError(req.ID, fmt.Errorf("malformed prebuffer params: %w", err))
+			return
+		}
+		if err := c.s.Prebuffer(params.ChannelID); err != nil {
+			c.respondError(req.ID, err)
+			return
+		}
+		c.respond(req.ID, struct{}{})
+
 	case protocol.MethodPlayPause:
 		snap, err := c.s.PlayPause()
 		if err != nil {
@@ -266,6 +367,44 @@ func (c *conn) handleRequest(req protocol.Request) {
 		}
 		c.respond(req.ID, c.s.SetVolume(params.Volume, true))
 
+	case protocol.MethodToggleMute:
+		c.respond(req.ID, c.s.ToggleMute())
+
+	case protocol.MethodSetQuality:
+		var params protocol.SetQualityParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			c.respondError(req.ID, fmt.Errorf("malformed setStreamQuality params: %w", err))
+			return
+		}
+		snap, err := c.s.SetStreamQuality(params.Quality)
+		if err != nil {
+			c.respondError(req.ID, err)
+			return
+		}
+		c.respond(req.ID, snap)
+
+	case protocol.MethodTimeShift:
+		var params protocol.TimeShiftParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			c.respondError(req.ID, fmt.Errorf("malformed timeShift params: %w", err))
+			return
+		}
+		d := time.Duration(params.Seconds * float64(time.Second))
+		c.respond(req.ID, c.s.TimeShift(d, params.Live))
+
+	case protocol.MethodSetEqualizer:
+		var params protocol.SetEqualizerParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			c.respondError(req.ID, fmt.Errorf("malformed setEqualizer params: %w", err))
+			return
+		}
+		snap, err := c.s.SetEqualizer(params.Preset)
+		if err != nil {
+			c.respondError(req.ID, err)
+			return
+		}
+		c.respond(req.ID, snap)
+
 	case protocol.MethodToggleFavorite:
 		var params protocol.ToggleFavoriteParams
 		if err := json.Unmarshal(req.Params, &params); err != nil {
@@ -279,6 +418,60 @@ func (c *conn) handleRequest(req protocol.Request) {
 		}
 		c.respond(req.ID, protocol.FavoritesResult{Favorites: favorites})
 
+	case protocol.MethodMoveFavorite:
+		var params protocol.MoveFavoriteParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			c.respondError(req.ID, fmt.Errorf("malformed moveFavorite params: %w", err))
+			return
+		}
+		favorites, err := c.s.MoveFavorite(params.ChannelID, params.Delta)
+		if err != nil {
+			c.respondError(req.ID, err)
+			return
+		}
+		c.respond(req.ID, protocol.FavoritesResult{Favorites: favorites})
+
+	case protocol.MethodDismissHint:
+		c.s.DismissFavoritesHint()
+		c.respond(req.ID, struct{}{})
+
+	case protocol.MethodSetSort:
+		var params protocol.SetSortParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			c.respondError(req.ID, fmt.Errorf("malformed setChannelSort params: %w", err))
+			return
+		}
+		if err := c.s.SetChannelSort(params.Sort); err != nil {
+			c.respondError(req.ID, err)
+			return
+		}
+		c.respond(req.ID, struct{}{})
+
+	case protocol.MethodSetSource:
+		var params protocol.SetSourceParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			c.respondError(req.ID, fmt.Errorf("malformed setChannelSource params: %w", err))
+			return
+		}
+		if err := c.s.SetChannelSource(params.Source); err != nil {
+			c.respondError(req.ID, err)
+			return
+		}
+		c.respond(req.ID, struct{}{})
+
+	case protocol.MethodSearchStations:
+		var params protocol.SearchStationsParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			c.respondError(req.ID, fmt.Errorf("malformed searchStations params: %w", err))
+			return
+		}
+		chs, err := c.s.SearchStations(params.Query)
+		if err != nil {
+			c.respondError(req.ID, err)
+			return
+		}
+		c.respond(req.ID, protocol.SearchStationsResult{Channels: chs})
+
 	case protocol.MethodShutdown:
 		c.respond(req.ID, struct{}{})
 		c.s.Shutdown()
@@ -318,9 +511,19 @@ func (c *conn) sendEvent(ev protocol.Event) {
 	}
 }
 
-// writeLoop delivers queued events until the connection closes.
+// writeLoop delivers queued events until the connection closes. A pending
+// state event goes before the catalog, which the state it came with
+// broadcasts second, so the snapshot is not held up behind it.
 func (c *conn) writeLoop() {
 	for {
+		select {
+		case <-c.done:
+			return
+		case ev := <-c.stateCh:
+			c.write(ev)
+			continue
+		default:
+		}
 		select {
 		case <-c.done:
 			return
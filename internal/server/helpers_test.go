@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"net"
 	"sync"
 	"testing"
@@ -17,11 +18,20 @@ import (
 
 // mockPlayer is a race-safe test double for the audio.Player interface.
 type mockPlayer struct {
-	mu        sync.Mutex
-	playing   bool
-	playErr   error
+	mu      sync.Mutex
+	playing bool
+	paused  bool
+	playErr error
+	// failURLs fails Play for specific URLs, e.g. a dead playlist server.
+	failURLs  map[string]error
 	playURLs  []string
 	volume    float64
+	level     float64
+	spectrum  []float64
+	equalizer string
+	prebuffer string
+	stats     audio.Stats
+	behind    time.Duration
 	errChan   chan error
 	trackChan chan audio.TrackInfo
 	// blockPlay, when non-nil, makes Play wait until the channel is closed.
@@ -48,7 +58,11 @@ func (p *mockPlayer) Play(url string) error {
 	if p.playErr != nil {
 		return p.playErr
 	}
+	if err := p.failURLs[url]; err != nil {
+		return err
+	}
 	p.playing = true
+	p.behind = 0
 	p.playURLs = append(p.playURLs, url)
 	return nil
 }
@@ -59,6 +73,63 @@ func (p *mockPlayer) Stop() {
 	p.playing = false
 }
 
+func (p *mockPlayer) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = true
+}
+
+func (p *mockPlayer) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = false
+}
+
+func (p *mockPlayer) Level() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.level
+}
+
+func (p *mockPlayer) Spectrum(bands int) []float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.spectrum) == 0 {
+		return nil
+	}
+	return p.spectrum[:min(bands, len(p.spectrum))]
+}
+
+func (p *mockPlayer) Stats() audio.Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stats
+}
+
+func (p *mockPlayer) TimeShift(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.behind = max(p.behind+d, 0)
+}
+
+func (p *mockPlayer) Behind() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.behind
+}
+
+func (p *mockPlayer) SetEqualizer(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.equalizer = name
+}
+
+func (p *mockPlayer) Prebuffer(url string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prebuffer = url
+}
+
 func (p *mockPlayer) Errors() <-chan error { return p.errChan }
 
 func (p *mockPlayer) TrackUpdates() <-chan audio.TrackInfo { return p.trackChan }
@@ -85,9 +156,11 @@ func (p *mockPlayer) setPlayErr(err error) {
 func testChannels() []channels.Channel {
 	return []channels.Channel{
 		{
-			ID:        "groovesalad",
-			Title:     "Groove Salad",
-			Playlists: []channels.Playlist{{URL: "http://somafm.com/groovesalad.pls", Format: "mp3"}},
+			ID:         "groovesalad",
+			Title:      "Groove Salad",
+			Image:      "http://somafm.com/img/groovesalad120.png",
+			LargeImage: "http://somafm.com/logos/256/groovesalad256.png",
+			Playlists:  []channels.Playlist{{URL: "http://somafm.com/groovesalad.pls", Format: "mp3"}},
 		},
 		{
 			ID:        "dronezone",
@@ -117,14 +190,28 @@ func newTestServer(t *testing.T, cfg Config) (*Server, *mockPlayer) {
 		cfg.Version = "test"
 	}
 
-	prevResolve := resolveStreamURL
-	resolveStreamURL = func(playlistURL, _ string) (string, error) {
-		return playlistURL + "#stream", nil
+	prevResolve, prevStation := resolveStreamURLs, resolveStationStreamURLs
+	resolveStreamURLs = func(playlistURL, _ string) ([]string, error) {
+		return []string{playlistURL + "#stream"}, nil
+	}
+	resolveStationStreamURLs = resolveStreamURLs
+	t.Cleanup(func() { resolveStreamURLs, resolveStationStreamURLs = prevResolve, prevStation })
+
+	// Without a songs list, tracks come from the stream's metadata, which
+	// tests feed through handleTrackUpdate.
+	prevSongs := fetchSongs
+	fetchSongs = func(string, string) ([]channels.Song, error) {
+		return nil, errors.New("songs API stubbed out")
+	}
+	t.Cleanup(func() { fetchSongs = prevSongs })
+	prevListeners := fetchListeners
+	fetchListeners = func(string, string) (int, error) {
+		return 0, errors.New("listeners stubbed out")
 	}
-	t.Cleanup(func() { resolveStreamURL = prevResolve })
+	t.Cleanup(func() { fetchListeners = prevListeners })
 
 	s := New(cfg)
-	s.setCatalog(testChannels())
+	s.setCatalog(&channels.Channels{Channels: testChannels()})
 	t.Cleanup(s.Shutdown)
 	return s, player
 }
@@ -264,6 +351,20 @@ func (c *tclient) waitChannels(desc string) protocol.ChannelsPayload {
 	}
 }
 
+// play starts channelID and reads events until the channels payload lists
+// it as recently played. A test that drops the stream next then gets its
+// state events without the catalog write of the play still in the way.
+func (c *tclient) play(channelID string) protocol.PlaybackState {
+	c.t.Helper()
+	st := decodeState(c.t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: channelID}))
+	for {
+		payload := c.waitChannels("recently played " + channelID)
+		if len(payload.Recent) > 0 && payload.Recent[0].ChannelID == channelID {
+			return st
+		}
+	}
+}
+
 // decodeState unmarshals a PlaybackState result.
 func decodeState(t *testing.T, resp protocol.Response) protocol.PlaybackState {
 	t.Helper()
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"time"
+
+	"somad/internal/channels"
+	"somad/internal/protocol"
+)
+
+// fetchListeners reads a SomaFM channel's listener count. A variable so
+// tests can stub the network out.
+var fetchListeners = channels.FetchListeners
+
+// listenersPollInterval is how often the playing channel's listener count
+// is fetched, much more often than the whole catalog is refreshed.
+var listenersPollInterval = time.Minute
+
+// pollListeners keeps the listener count of a playing SomaFM channel up to
+// date until play generation gen ends. The count from the catalog stands
+// until the first fetch, and a failed fetch keeps the last one. fetch and
+// interval are passed in so the goroutine never reads the package
+// variables tests swap.
+func (s *Server) pollListeners(gen uint64, channelID string, fetch func(channelID, userAgent string) (int, error), interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+		n, err := fetch(channelID, s.userAgent)
+		if !s.applyListeners(gen, n, err) {
+			return
+		}
+	}
+}
+
+// applyListeners publishes a fetched listener count. It reports false once
+// gen is superseded, ending the poll.
+func (s *Server) applyListeners(gen uint64, n int, err error) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if gen != s.playGen {
+		return false
+	}
+	if err != nil || n == s.listeners || s.status == protocol.StatusStopped {
+		return true
+	}
+	s.listeners = n
+	s.broadcastStateLocked()
+	return true
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"somad/internal/channels"
+	"somad/internal/protocol"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListeners_PolledWhilePlaying(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+	var count atomic.Int32
+	count.Store(-1) // fail until set
+	prevFetch, prevInterval := fetchListeners, listenersPollInterval
+	fetchListeners = func(channelID, _ string) (int, error) {
+		assert.Equal(t, "groovesalad", channelID)
+		if n := count.Load(); n >= 0 {
+			return int(n), nil
+		}
+		return 0, errors.New("not yet")
+	}
+	listenersPollInterval = 5 * time.Millisecond
+	t.Cleanup(func() { fetchListeners, listenersPollInterval = prevFetch, prevInterval })
+
+	chs := testChannels()
+	chs[0].Listeners = 1000
+	s.setCatalog(&channels.Channels{Channels: chs})
+	c := connect(t, s)
+	c.hello()
+
+	st := decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	assert.Equal(t, 1000, st.Listeners, "the catalog's count until the first fetch")
+
+	count.Store(1042)
+	require.Eventually(t, func() bool { return s.Snapshot().Listeners == 1042 }, 2*time.Second, 5*time.Millisecond)
+
+	count.Store(-1)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 1042, s.Snapshot().Listeners, "a failed fetch keeps the last count")
+
+	assert.Zero(t, s.Stop().Listeners)
+}
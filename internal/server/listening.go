@@ -0,0 +1,50 @@
+package server
+
+import (
+	"maps"
+	"time"
+
+	"somad/internal/protocol"
+)
+
+// setStatusLocked moves playback to status, keeping the per-channel
+// listening time: a listen starts when a picked channel (not a preview)
+// starts playing and is added to the channel's total when playback stops,
+// pauses, drops, or moves to another channel. A channel that plays is no
+// longer dead.
+func (s *Server) setStatusLocked(status string) {
+	s.endListenLocked()
+	s.status = status
+	s.startListenLocked()
+	s.reviveChannelLocked()
+}
+
+// startListenLocked starts timing a listen if a picked channel is playing.
+func (s *Server) startListenLocked() {
+	if s.status == protocol.StatusPlaying && !s.preview && s.listenSince.IsZero() {
+		s.listenChannel = s.channelID
+		s.listenSince = time.Now()
+	}
+}
+
+// endListenLocked adds the running listen, if any, to its channel's total,
+// for clients to get with the state of the status change, and persists it
+// in the background; Shutdown waits for that save.
+func (s *Server) endListenLocked() {
+	if s.listenSince.IsZero() {
+		return
+	}
+	s.st.AddListened(s.listenChannel, time.Since(s.listenSince))
+	s.listenSince = time.Time{}
+	s.listenChannel = ""
+	stateToSave := s.st.Clone()
+	saveSeq := s.nextSaveSeqLocked()
+	s.channelsPending = true
+	s.listenSaves.Go(func() { s.saveState(saveSeq, stateToSave) })
+}
+
+// listenedLocked returns the total listening time per channel in seconds,
+// without the running listen (clients add that from ListeningSince).
+func (s *Server) listenedLocked() map[string]int64 {
+	return maps.Clone(s.st.ListenedSeconds)
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"somad/internal/protocol"
+	"somad/internal/state"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// backdateListen pretends the running listen began d ago.
+func backdateListen(t *testing.T, s *Server, d time.Duration) {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	require.False(t, s.listenSince.IsZero(), "no listen is running")
+	s.listenSince = s.listenSince.Add(-d)
+}
+
+func TestListening_AccumulatesPerChannelAndPersists(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+
+	st, err := s.Play("groovesalad")
+	require.NoError(t, err)
+	assert.False(t, st.ListeningSince.IsZero())
+	backdateListen(t, s, 90*time.Second)
+
+	// Switching channels ends the listen on the previous one.
+	_, err = s.Play("dronezone")
+	require.NoError(t, err)
+	backdateListen(t, s, time.Minute)
+
+	// A pause ends it too; resuming starts a new one.
+	s.Pause()
+	_, err = s.Resume()
+	require.NoError(t, err)
+	backdateListen(t, s, time.Minute)
+	st = s.Stop()
+	assert.True(t, st.ListeningSince.IsZero())
+
+	listened := s.ChannelsPayload().Listened
+	assert.InDelta(t, 90, listened["groovesalad"], 1)
+	assert.InDelta(t, 120, listened["dronezone"], 1)
+
+	s.Shutdown()
+	persisted, err := state.LoadState()
+	require.NoError(t, err)
+	assert.InDelta(t, 120, persisted.ListenedSeconds["dronezone"], 1)
+}
+
+func TestListening_PreviewDoesNotCountUntilPicked(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+
+	st, err := s.Preview("dronezone")
+	require.NoError(t, err)
+	assert.True(t, st.ListeningSince.IsZero(), "a preview is not a listen")
+
+	st, err = s.Play("dronezone")
+	require.NoError(t, err)
+	assert.Equal(t, protocol.StatusPlaying, st.Status)
+	assert.False(t, st.ListeningSince.IsZero(), "picking the previewed channel starts the listen")
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"somad/internal/protocol"
+	"somad/internal/state"
+)
+
+// ToggleLove loves the track titled title on channelID, or unloves it when
+// it is loved already. An empty title picks the playing track; any other
+// title must be in the history to be loved. The loved tracks persist in
+// the state file.
+func (s *Server) ToggleLove(channelID, title string) (protocol.LovedResult, error) {
+	s.mu.Lock()
+	track := state.LovedTrack{Time: time.Now(), ChannelID: channelID, Title: title}
+	if title == "" {
+		if s.status == protocol.StatusStopped || s.track.Title == "" {
+			s.mu.Unlock()
+			return protocol.LovedResult{}, errors.New("no track is playing")
+		}
+		track.ChannelID, track.Channel = s.channelID, s.channelTitle
+		track.Title, track.Artist, track.Song = s.track.Title, s.track.Artist, s.track.Song
+	} else if !s.st.IsLoved(channelID, title) {
+		// Only a track in the history, the playing one included, can be
+		// newly loved by name; any other title can only be taken off the
+		// list.
+		seen, ok := s.seenTrackLocked(channelID, title)
+		if !ok {
+			s.mu.Unlock()
+			return protocol.LovedResult{}, fmt.Errorf("not a recent track: %s", title)
+		}
+		track.Channel = seen.Channel
+		track.Artist, track.Song = seen.Artist, seen.Song
+	}
+	loved := s.st.ToggleLoved(track)
+	stateToSave := s.st.Clone()
+	saveSeq := s.nextSaveSeqLocked()
+	s.broadcastStateLocked()
+	result := s.lovedLocked()
+	s.mu.Unlock()
+
+	s.saveState(saveSeq, stateToSave)
+	result.Loved = loved
+	return result, nil
+}
+
+// seenTrackLocked returns the latest history entry for the track titled
+// title on channelID.
+func (s *Server) seenTrackLocked(channelID, title string) (protocol.TrackEntry, bool) {
+	for i := len(s.history) - 1; i >= 0; i-- {
+		if t := s.history[i]; t.ChannelID == channelID && t.Title == title {
+			return t, true
+		}
+	}
+	return protocol.TrackEntry{}, false
+}
+
+// Loved returns the loved tracks, newest first.
+func (s *Server) Loved() protocol.LovedResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lovedLocked()
+}
+
+func (s *Server) lovedLocked() protocol.LovedResult {
+	tracks := make([]protocol.TrackEntry, len(s.st.LovedTracks))
+	for i, t := range s.st.LovedTracks {
+		tracks[len(tracks)-1-i] = protocol.TrackEntry{
+			Time:      t.Time,
+			ChannelID: t.ChannelID,
+			Channel:   t.Channel,
+			Title:     t.Title,
+			Artist:    t.Artist,
+			Song:      t.Song,
+		}
+	}
+	return protocol.LovedResult{Tracks: tracks}
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+
+	"somad/internal/audio"
+	"somad/internal/protocol"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func toggleLove(t *testing.T, c *tclient, params protocol.ToggleLoveParams) protocol.LovedResult {
+	t.Helper()
+	resp := c.call(protocol.MethodToggleLove, params)
+	require.Empty(t, resp.Error)
+	var result protocol.LovedResult
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	return result
+}
+
+func TestToggleLove_PlayingTrack(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	resp := c.call(protocol.MethodToggleLove, protocol.ToggleLoveParams{})
+	assert.Contains(t, resp.Error, "no track is playing")
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Tycho - Awake", Artist: "Tycho", Song: "Awake"})
+
+	result := toggleLove(t, c, protocol.ToggleLoveParams{})
+	assert.True(t, result.Loved)
+	require.Len(t, result.Tracks, 1)
+	loved := result.Tracks[0]
+	assert.Equal(t, "groovesalad", loved.ChannelID)
+	assert.Equal(t, "Tycho - Awake", loved.Title)
+	assert.Equal(t, "Tycho", loved.Artist)
+	assert.Equal(t, "Awake", loved.Song)
+	assert.NotEmpty(t, loved.Channel)
+	assert.False(t, loved.Time.IsZero())
+	assert.True(t, decodeState(t, c.call(protocol.MethodStatus, nil)).TrackLoved)
+
+	s.mu.Lock()
+	require.Len(t, s.st.LovedTracks, 1, "loved tracks are kept in the state")
+	s.mu.Unlock()
+
+	result = toggleLove(t, c, protocol.ToggleLoveParams{})
+	assert.False(t, result.Loved)
+	assert.Empty(t, result.Tracks)
+	assert.False(t, decodeState(t, c.call(protocol.MethodStatus, nil)).TrackLoved)
+}
+
+func TestToggleLove_ByTitleOnlyRecentTracks(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Tycho - Awake", Artist: "Tycho", Song: "Awake"})
+	toggleLove(t, c, protocol.ToggleLoveParams{})
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Bonobo - Kerala", Artist: "Bonobo", Song: "Kerala"})
+
+	resp := c.call(protocol.MethodToggleLove, protocol.ToggleLoveParams{ChannelID: "groovesalad", Title: "Lorn - Acid Rain"})
+	assert.Contains(t, resp.Error, "not a recent track")
+	resp = c.call(protocol.MethodToggleLove, protocol.ToggleLoveParams{ChannelID: "dronezone", Title: "Bonobo - Kerala"})
+	assert.Contains(t, resp.Error, "not a recent track", "the channel must match too")
+
+	result := toggleLove(t, c, protocol.ToggleLoveParams{ChannelID: "groovesalad", Title: "Bonobo - Kerala"})
+	assert.True(t, result.Loved, "the playing track can be loved by name")
+	require.Len(t, result.Tracks, 2)
+	assert.Equal(t, "Bonobo - Kerala", result.Tracks[0].Title, "newest first")
+
+	result = toggleLove(t, c, protocol.ToggleLoveParams{ChannelID: "groovesalad", Title: "Tycho - Awake"})
+	assert.False(t, result.Loved)
+	require.Len(t, result.Tracks, 1)
+
+	resp = c.call(protocol.MethodLoved, nil)
+	require.Empty(t, resp.Error)
+	var loved protocol.LovedResult
+	require.NoError(t, json.Unmarshal(resp.Result, &loved))
+	assert.Equal(t, result.Tracks, loved.Tracks)
+}
+
+func TestToggleLove_ByTitleFromHistory(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Tycho - Awake", Artist: "Tycho", Song: "Awake"})
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Bonobo - Kerala", Artist: "Bonobo", Song: "Kerala"})
+
+	result := toggleLove(t, c, protocol.ToggleLoveParams{ChannelID: "groovesalad", Title: "Tycho - Awake"})
+	assert.True(t, result.Loved, "a track that already played can be loved")
+	require.Len(t, result.Tracks, 1)
+	assert.Equal(t, "Tycho", result.Tracks[0].Artist, "with the details the history kept")
+	assert.Equal(t, "Awake", result.Tracks[0].Song)
+	assert.NotEmpty(t, result.Tracks[0].Channel)
+	assert.False(t, decodeState(t, c.call(protocol.MethodStatus, nil)).TrackLoved, "the playing track is another")
+}
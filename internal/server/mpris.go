@@ -18,6 +18,8 @@ func (m mprisSender) Send(msg any) {
 	switch v := msg.(type) {
 	case platform.MPRISPlayMsg:
 		go func() { _, _ = m.s.PlayCurrent() }()
+	case platform.MPRISPauseMsg:
+		m.s.Pause()
 	case platform.MPRISStopMsg:
 		m.s.Stop()
 	case platform.MPRISPlayPauseMsg:
@@ -36,6 +38,11 @@ func (m mprisSender) Send(msg any) {
 	case platform.MPRISVolumeMsg:
 		// The MPRIS property is already updated, so don't mirror it back.
 		m.s.SetVolume(v.Volume, false)
+	case platform.SleepMsg:
+		// Synchronous: the watcher holds the suspend until this returns.
+		m.s.Sleep()
+	case platform.WakeMsg:
+		go m.s.Wake()
 	case platform.MPRISQuitMsg:
 		// Off-goroutine: Shutdown tears down D-Bus among other things and
 		// must not deadlock the dispatcher that delivered this message.
@@ -45,9 +52,13 @@ func (m mprisSender) Send(msg any) {
 
 // PlayCurrent plays the last-played channel (falling back to the top of the
 // catalog) unless something is already playing or connecting, in which case
-// it is a no-op.
+// it is a no-op. A paused channel resumes.
 func (s *Server) PlayCurrent() (protocol.PlaybackState, error) {
 	s.mu.Lock()
+	if s.status == protocol.StatusPaused {
+		s.mu.Unlock()
+		return s.Resume()
+	}
 	if s.status != protocol.StatusStopped {
 		snap := s.snapshotLocked()
 		s.mu.Unlock()
@@ -67,16 +78,21 @@ func (s *Server) PlayCurrent() (protocol.PlaybackState, error) {
 	return s.Play(id)
 }
 
-// PlayPause toggles between stopped and playing. SomaFM is live radio, so
-// "pause" tears the stream down and "unpause" reconnects to the live stream
-// rather than resuming a position. Used by MPRIS PlayPause and the pause CLI
-// command.
+// PlayPause pauses a playing stream and resumes a paused one. Stopped
+// playback starts the current channel, and a connect or reconnect in
+// flight is stopped. Used by MPRIS PlayPause, the tray, the TUI, and the
+// pause CLI command.
 func (s *Server) PlayPause() (protocol.PlaybackState, error) {
 	s.mu.Lock()
-	stopped := s.status == protocol.StatusStopped
+	status := s.status
 	s.mu.Unlock()
-	if stopped {
+	switch status {
+	case protocol.StatusStopped:
 		return s.PlayCurrent()
+	case protocol.StatusPlaying:
+		return s.Pause(), nil
+	case protocol.StatusPaused:
+		return s.Resume()
 	}
 	return s.Stop(), nil
 }
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"errors"
+	"log"
+
+	"somad/internal/musicbrainz"
+)
+
+// lookupRecording finds a track on MusicBrainz. A variable so tests can
+// avoid the network.
+var lookupRecording = musicbrainz.Lookup
+
+// lookupRecordingLocked looks the new playing track up on MusicBrainz, when
+// enabled and its title names an artist, for the album, year and IDs the
+// title lacks.
+func (s *Server) lookupRecordingLocked() {
+	if !s.musicBrainz || s.track.Artist == "" {
+		return
+	}
+	go s.enrichTrack(s.track.Title, s.track.Artist, s.track.Song, lookupRecording)
+}
+
+// enrichTrack attaches what MusicBrainz knows about the track titled title
+// and pushes it to clients and MPRIS, unless another track plays by then.
+// lookup is passed in so the goroutine never reads the variable tests
+// swap.
+func (s *Server) enrichTrack(title, artist, song string, lookup func(artist, song, userAgent string) (musicbrainz.Recording, error)) {
+	rec, err := lookup(artist, song, s.userAgent)
+	if err != nil {
+		if !errors.Is(err, musicbrainz.ErrNotFound) {
+			log.Printf("MusicBrainz lookup of %q failed: %v", title, err)
+		}
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.track.Title != title {
+		return
+	}
+	s.recording, s.recordingTitle = rec, title
+	s.updateMPRISLocked()
+	s.broadcastStateLocked()
+}
+
+// recordingLocked is what MusicBrainz knows about the playing track, if it
+// was looked up.
+func (s *Server) recordingLocked() (musicbrainz.Recording, bool) {
+	if s.track.Title == "" || s.recordingTitle != s.track.Title {
+		return musicbrainz.Recording{}, false
+	}
+	return s.recording, true
+}
+
+// trackAlbumLocked names the playing track's album: the channel's song
+// list's, or else MusicBrainz's.
+func (s *Server) trackAlbumLocked() string {
+	if s.track.Album != "" {
+		return s.track.Album
+	}
+	rec, _ := s.recordingLocked()
+	return rec.Album
+}
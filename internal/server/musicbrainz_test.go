@@ -0,0 +1,123 @@
+package server
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"somad/internal/audio"
+	"somad/internal/musicbrainz"
+	"somad/internal/protocol"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRecordings answers MusicBrainz lookups from recs, keyed by song, and
+// returns the songs looked up. Call it before newTestServer, so it is
+// restored only after the server has shut down.
+func stubRecordings(t *testing.T, recs map[string]musicbrainz.Recording) func() []string {
+	t.Helper()
+	var mu sync.Mutex
+	var songs []string
+	prev := lookupRecording
+	lookupRecording = func(_, song, _ string) (musicbrainz.Recording, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		songs = append(songs, song)
+		rec, ok := recs[song]
+		if !ok {
+			return musicbrainz.Recording{}, musicbrainz.ErrNotFound
+		}
+		return rec, nil
+	}
+	t.Cleanup(func() { lookupRecording = prev })
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), songs...)
+	}
+}
+
+func TestMusicBrainz_EnrichesTheTrack(t *testing.T) {
+	lookups := stubRecordings(t, map[string]musicbrainz.Recording{
+		"Awake": {ID: "rec-1", ArtistID: "art-1", ReleaseID: "rel-1", Album: "Awake (LP)", Year: 2014},
+	})
+	s, _ := newTestServer(t, Config{MusicBrainz: true})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Tycho - Awake", Artist: "Tycho", Song: "Awake"})
+	st := c.waitState("MusicBrainz details", func(st protocol.PlaybackState) bool {
+		return st.TrackRecordingID != ""
+	})
+	assert.Equal(t, "rec-1", st.TrackRecordingID)
+	assert.Equal(t, "rel-1", st.TrackReleaseID)
+	assert.Equal(t, "art-1", st.TrackArtistID)
+	assert.Equal(t, "Awake (LP)", st.TrackAlbum)
+	assert.Equal(t, 2014, st.TrackYear)
+
+	// A title without an artist is not looked up, and the previous
+	// track's details do not carry over.
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Station ID", Song: "Station ID"})
+	st = s.Snapshot()
+	assert.Empty(t, st.TrackRecordingID)
+	assert.Zero(t, st.TrackYear)
+	assert.Equal(t, []string{"Awake"}, lookups())
+}
+
+func TestMusicBrainz_SongListAlbumWins(t *testing.T) {
+	stubRecordings(t, map[string]musicbrainz.Recording{
+		"Awake": {ID: "rec-1", Album: "Awake (LP)", Year: 2014},
+	})
+	s, _ := newTestServer(t, Config{MusicBrainz: true})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Tycho - Awake", Artist: "Tycho", Song: "Awake", Album: "Awake"})
+	st := c.waitState("MusicBrainz details", func(st protocol.PlaybackState) bool {
+		return st.TrackRecordingID != ""
+	})
+	assert.Equal(t, "Awake", st.TrackAlbum)
+	assert.Equal(t, 2014, st.TrackYear)
+}
+
+func TestMusicBrainz_StaleAnswerDropped(t *testing.T) {
+	release := make(chan struct{})
+	prev := lookupRecording
+	lookupRecording = func(_, song, _ string) (musicbrainz.Recording, error) {
+		if song == "Awake" {
+			<-release
+		}
+		return musicbrainz.Recording{ID: "rec-" + song}, nil
+	}
+	t.Cleanup(func() { lookupRecording = prev })
+	s, _ := newTestServer(t, Config{MusicBrainz: true})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Tycho - Awake", Artist: "Tycho", Song: "Awake"})
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Bonobo - Kerala", Artist: "Bonobo", Song: "Kerala"})
+	c.waitState("the newer track's details", func(st protocol.PlaybackState) bool {
+		return st.TrackRecordingID == "rec-Kerala"
+	})
+	close(release)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, "rec-Kerala", s.Snapshot().TrackRecordingID, "the slow answer for the old track is dropped")
+}
+
+func TestMusicBrainz_OffByDefault(t *testing.T) {
+	lookups := stubRecordings(t, nil)
+	s, _ := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Tycho - Awake", Artist: "Tycho", Song: "Awake"})
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, lookups())
+	require.Empty(t, s.Snapshot().TrackRecordingID)
+}
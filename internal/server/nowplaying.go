@@ -0,0 +1,73 @@
+package server
+
+import (
+	"log"
+	"strings"
+
+	"somad/internal/atomicfile"
+	"somad/internal/protocol"
+)
+
+// nowPlayingLineLocked is the line the now-playing file holds for the
+// current state: "Channel — Artist — Song" while a picked channel plays,
+// dropping what the stream does not name, and empty otherwise.
+func (s *Server) nowPlayingLineLocked() string {
+	if s.status != protocol.StatusPlaying || s.preview {
+		return ""
+	}
+	parts := []string{s.channelTitle}
+	if s.track.Artist != "" {
+		parts = append(parts, s.track.Artist)
+	}
+	if s.track.Song != "" {
+		parts = append(parts, s.track.Song)
+	}
+	return strings.Join(parts, " — ")
+}
+
+// updateNowPlayingLocked rewrites the now-playing file when its line
+// changed. The write runs off the lock, ordered like state saves.
+func (s *Server) updateNowPlayingLocked() {
+	if s.nowPlayingPath == "" {
+		return
+	}
+	line := s.nowPlayingLineLocked()
+	if line == s.nowPlayingLine {
+		return
+	}
+	s.nowPlayingLine = line
+	s.nowPlayingSeq++
+	go s.writeNowPlaying(s.nowPlayingSeq, line)
+}
+
+// writeNowPlaying writes line to the now-playing file, unless a newer line
+// has been written already.
+func (s *Server) writeNowPlaying(seq uint64, line string) {
+	s.nowPlayingMu.Lock()
+	defer s.nowPlayingMu.Unlock()
+	if seq <= s.nowPlayingWritten {
+		return
+	}
+	s.nowPlayingWritten = seq
+	if line != "" {
+		line += "\n"
+	}
+	if err := atomicfile.WriteFile(s.nowPlayingPath, []byte(line), 0o600); err != nil {
+		log.Printf("error writing now-playing file: %v", err)
+	}
+}
+
+// clearNowPlaying empties the now-playing file on shutdown, so an overlay
+// does not go on showing a track that stopped with the server.
+func (s *Server) clearNowPlaying() {
+	s.mu.Lock()
+	if s.nowPlayingPath == "" || s.nowPlayingLine == "" {
+		s.mu.Unlock()
+		return
+	}
+	s.nowPlayingLine = ""
+	s.nowPlayingSeq++
+	seq := s.nowPlayingSeq
+	s.mu.Unlock()
+	s.writeNowPlaying(seq, "")
+}
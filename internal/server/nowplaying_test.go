@@ -0,0 +1,57 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"somad/internal/audio"
+	"somad/internal/protocol"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertNowPlaying waits for the now-playing file to hold want.
+func assertNowPlaying(t *testing.T, path, want string) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(path) // #nosec G304 -- test path under t.TempDir
+		return err == nil && string(data) == want
+	}, 2*time.Second, 5*time.Millisecond, "now-playing file should hold %q", want)
+}
+
+func TestNowPlayingFile_FollowsTheTrack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "now-playing.txt")
+	s, _ := newTestServer(t, Config{NowPlayingFile: path})
+	c := connect(t, s)
+	c.hello()
+
+	st := decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	assertNowPlaying(t, path, st.ChannelTitle+"\n")
+
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Tycho - Awake", Artist: "Tycho", Song: "Awake"})
+	assertNowPlaying(t, path, st.ChannelTitle+" — Tycho — Awake\n")
+
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Station ID", Song: "Station ID"})
+	assertNowPlaying(t, path, st.ChannelTitle+" — Station ID\n")
+
+	c.call(protocol.MethodStop, nil)
+	assertNowPlaying(t, path, "")
+}
+
+func TestNowPlayingFile_ClearedOnShutdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "now-playing.txt")
+	s, _ := newTestServer(t, Config{NowPlayingFile: path})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Tycho - Awake", Artist: "Tycho", Song: "Awake"})
+	s.Shutdown()
+
+	data, err := os.ReadFile(path) // #nosec G304 -- test path under t.TempDir
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
@@ -3,19 +3,24 @@ package server
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"somad/internal/audio"
 	"somad/internal/channels"
+	"somad/internal/platform"
 	"somad/internal/protocol"
+	"somad/internal/radiobrowser"
+	"somad/internal/security"
 	"somad/internal/state"
 	"somad/pkg/playlist"
 )
 
 // reconnectMaxDelay caps the exponential backoff between reconnect
-// attempts. Retries never give up — the server is a long-running daemon and
-// playback should come back whenever the network does — so past the cap it
-// keeps retrying at this steady interval.
+// attempts. By default retries never give up — the server is a long-running
+// daemon and playback should come back whenever the network does — so past
+// the cap it keeps retrying at this steady interval. Config.ReconnectAttempts
+// opts into giving up.
 const reconnectMaxDelay = time.Minute
 
 // reconnectBaseDelay is a variable so tests can shrink the backoff.
@@ -37,21 +42,105 @@ func reconnectDelay(attempt int) time.Duration {
 	return d
 }
 
-// resolveStreamURL resolves a playlist URL to a stream URL. A variable so
-// tests can avoid the network.
-var resolveStreamURL = playlist.GetStreamURLFromPlaylist
+// resolveStreamURLs resolves a playlist URL to its stream URLs, the primary
+// server first. A variable so tests can avoid the network.
+var resolveStreamURLs = playlist.GetStreamURLsFromPlaylist
+
+// resolveStationStreamURLs is resolveStreamURLs for the playlist of a
+// directory or custom station, on a host of the station's own.
+var resolveStationStreamURLs = playlist.GetStationStreamURLs
 
 // Play starts playback of the given channel. It blocks until the stream is
 // connected and decoding (or has failed), so callers get synchronous
 // semantics; progress snapshots are broadcast to all clients along the way.
 func (s *Server) Play(channelID string) (protocol.PlaybackState, error) {
-	return s.playChannel(channelID, true)
+	if snap, ok := s.commitPreview(channelID); ok {
+		return snap, nil
+	}
+	return s.playChannel(channelID, true, false)
+}
+
+// previewVolume scales the volume a preview plays at.
+const previewVolume = 0.3
+
+// Preview plays a channel quietly without picking it: the channel is not
+// persisted, and playing it for real keeps the stream while stopping ends
+// it. An empty channelID ends a running preview. It only starts while
+// nothing else plays, so browsing never interrupts the music.
+func (s *Server) Preview(channelID string) (protocol.PlaybackState, error) {
+	if channelID != "" {
+		return s.playChannel(channelID, false, true)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.preview && s.status != protocol.StatusStopped {
+		return s.stopLocked(), nil
+	}
+	return s.snapshotLocked(), nil
+}
+
+// commitPreview turns a playing preview of channelID into the picked
+// channel without reconnecting. It reports false when there is no such
+// preview.
+func (s *Server) commitPreview(channelID string) (protocol.PlaybackState, bool) {
+	s.mu.Lock()
+	if !s.preview || s.status != protocol.StatusPlaying || s.channelID != channelID {
+		s.mu.Unlock()
+		return protocol.PlaybackState{}, false
+	}
+	s.endPreviewLocked()
+	s.startListenLocked()
+	s.st.LastSelectedChannelID = channelID
+	s.addRecentChannelLocked(channelID)
+	stateToSave := s.st.Clone()
+	saveSeq := s.nextSaveSeqLocked()
+	s.updateNowPlayingLocked()
+	s.broadcastStateLocked()
+	snap := s.snapshotLocked()
+	s.mu.Unlock()
+
+	s.saveState(saveSeq, stateToSave)
+	return snap, true
+}
+
+// addRecentChannelLocked records a picked channel among the recently
+// played ones, for clients to get with the state of the switch. Directory
+// stations are left out: they can only be played until the next search.
+func (s *Server) addRecentChannelLocked(channelID string) {
+	if radiobrowser.IsStationID(channelID) {
+		return
+	}
+	s.st.AddRecentChannel(channelID, time.Now())
+	s.channelsPending = true
+}
+
+// endPreviewLocked leaves preview mode, restoring the full volume.
+func (s *Server) endPreviewLocked() {
+	if s.preview {
+		s.preview = false
+		s.player.SetVolume(s.playerVolumeLocked())
+	}
+}
+
+// playerVolumeLocked is the volume the player should run at: the persisted
+// one, silenced while muted and lowered while previewing.
+func (s *Server) playerVolumeLocked() float64 {
+	switch {
+	case s.muted:
+		return 0
+	case s.preview:
+		return s.st.GetVolume() * previewVolume
+	}
+	return s.st.GetVolume()
 }
 
 // playChannel connects to a channel. userInitiated distinguishes explicit
 // play requests (which persist the channel and reset the reconnect budget)
-// from automatic reconnect attempts.
-func (s *Server) playChannel(channelID string, userInitiated bool) (protocol.PlaybackState, error) {
+// from automatic reconnect attempts. preview starts a Preview instead,
+// which only happens while nothing but another preview plays; deciding that
+// under the same lock as the switch keeps a concurrent Play from being cut
+// off by it.
+func (s *Server) playChannel(channelID string, userInitiated, preview bool) (protocol.PlaybackState, error) {
 	s.mu.Lock()
 	ch, ok := s.findChannelLocked(channelID)
 	if !ok {
@@ -59,48 +148,75 @@ func (s *Server) playChannel(channelID string, userInitiated bool) (protocol.Pla
 		s.mu.Unlock()
 		return snap, fmt.Errorf("unknown channel: %s", channelID)
 	}
+	if preview {
+		if s.status != protocol.StatusStopped && !s.preview {
+			snap := s.snapshotLocked()
+			s.mu.Unlock()
+			return snap, nil
+		}
+		s.preview = true
+		s.reconnectAttempt = 0
+		s.reconnects = 0
+		s.streamServer = 0
+		s.player.SetVolume(s.playerVolumeLocked())
+	}
 	s.playGen++
 	gen := s.playGen
 	s.cancelReconnectLocked()
 	s.disarmIdleLocked()
-	s.status = protocol.StatusConnecting
+	s.setStatusLocked(protocol.StatusConnecting)
 	s.channelID = ch.ID
 	s.channelTitle = ch.Title
-	s.trackTitle = ""
+	s.listeners = ch.Listeners
+	s.track = audio.TrackInfo{}
+	s.icyTrack = audio.TrackInfo{}
+	s.songsLive = false
 	s.streamErr = ""
+	s.streamErrKind = ""
+	s.pauseDropped = false
 	var stateToSave *state.State
 	var saveSeq uint64
 	if userInitiated {
+		s.endPreviewLocked()
 		s.reconnectAttempt = 0
+		s.reconnects = 0
+		s.streamServer = 0
 		s.st.LastSelectedChannelID = ch.ID
+		s.addRecentChannelLocked(ch.ID)
 		stateToSave = s.st.Clone()
 		saveSeq = s.nextSaveSeqLocked()
 	}
 	s.broadcastStateLocked()
-	playlists := ch.Playlists
-	title := ch.Title
+	quality := s.streamQualityLocked()
+	firstServer := s.streamServer
 	s.mu.Unlock()
 
 	if stateToSave != nil {
 		s.saveState(saveSeq, stateToSave)
 	}
 
-	playlistURL := channels.SelectMP3PlaylistURL(playlists)
-	if playlistURL == "" {
-		// Reconnecting cannot conjure up a playlist, so never retry this.
-		return s.failConnect(gen, fmt.Errorf("no MP3 playlist available for %s", title), false)
-	}
-
-	streamURL, err := resolveStreamURL(playlistURL, s.userAgent)
+	streamURLs, retry, err := s.channelStreamURLs(ch, quality)
 	if err != nil {
-		return s.failConnect(gen, fmt.Errorf("failed to get stream URL: %w", err), true)
+		return s.failConnect(gen, err, retry)
 	}
 
-	if err := s.player.Play(streamURL); err != nil {
-		if errors.Is(err, audio.ErrSuperseded) {
-			// A newer play/stop request won; it owns the state now.
-			return s.Snapshot(), err
+	// Fail over through the playlist's servers, starting from the one that
+	// last worked (or the one after it, when it dropped the stream).
+	server := 0
+	for i := range streamURLs {
+		server = (firstServer + i) % len(streamURLs)
+		if i > 0 && s.supersededBy(gen) {
+			return s.Snapshot(), audio.ErrSuperseded
+		}
+		if err = s.player.Play(streamURLs[server]); err == nil || errors.Is(err, audio.ErrSuperseded) {
+			break
 		}
+	}
+	if errors.Is(err, audio.ErrSuperseded) {
+		// A newer play/stop request won; it owns the state now.
+		return s.Snapshot(), err
+	}
+	if err != nil {
 		return s.failConnect(gen, fmt.Errorf("failed to start playback: %w", err), true)
 	}
 
@@ -109,13 +225,109 @@ func (s *Server) playChannel(channelID string, userInitiated bool) (protocol.Pla
 	if gen != s.playGen {
 		return s.snapshotLocked(), audio.ErrSuperseded
 	}
-	s.status = protocol.StatusPlaying
+	s.streamServer = server
+	s.setStatusLocked(protocol.StatusPlaying)
 	s.reconnectAttempt = 0 // connected: a later drop starts a fresh backoff
+	if ch.StreamURL == "" {
+		go s.pollSongs(gen, ch.ID, fetchSongs, songsPollInterval)
+		go s.pollListeners(gen, ch.ID, fetchListeners, listenersPollInterval)
+	}
 	s.updateMPRISLocked()
 	s.broadcastStateLocked()
 	return s.snapshotLocked(), nil
 }
 
+// channelStreamURLs resolves where a channel streams from, the primary
+// server first: a directory or custom station's own URL (or the servers
+// its .pls playlist lists), or the servers of its MP3 playlist closest to
+// quality. retry reports whether a failure is worth retrying.
+func (s *Server) channelStreamURLs(ch channels.Channel, quality string) (urls []string, retry bool, err error) {
+	if ch.StreamURL != "" {
+		// A directory or custom station streams from a host of its own,
+		// which only the stream client may reach.
+		if err := security.ValidateStreamURL(ch.StreamURL); err != nil {
+			return nil, false, fmt.Errorf("invalid stream URL: %w", err)
+		}
+		if !playlist.IsPlaylistURL(ch.StreamURL) {
+			return []string{ch.StreamURL}, false, nil
+		}
+		urls, err = resolveStationStreamURLs(ch.StreamURL, s.userAgent)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to get stream URL: %w", err)
+		}
+		for _, u := range urls {
+			if err := security.ValidateStreamURL(u); err != nil {
+				return nil, false, fmt.Errorf("invalid stream URL in playlist: %w", err)
+			}
+		}
+		return urls, false, nil
+	}
+	playlistURL := channels.SelectPlaylist(ch.Playlists, "mp3", quality)
+	if playlistURL == "" {
+		// Reconnecting cannot conjure up a playlist, so never retry this.
+		return nil, false, fmt.Errorf("no MP3 playlist available for %s", ch.Title)
+	}
+	urls, err = resolveStreamURLs(playlistURL, s.userAgent)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to get stream URL: %w", err)
+	}
+	// SomaFM's playlists are fetched over plain http, so the streams they
+	// list must still be on an allowed host.
+	for _, u := range urls {
+		if err := security.ValidateURL(u); err != nil {
+			return nil, false, fmt.Errorf("invalid stream URL in playlist: %w", err)
+		}
+	}
+	return urls, false, nil
+}
+
+// Prebuffer has the player fetch a channel's stream ahead of a likely
+// play, so playing it starts at once; an empty channelID drops the
+// prebuffer. A channel that is already playing needs none.
+func (s *Server) Prebuffer(channelID string) error {
+	if channelID == "" {
+		s.player.Prebuffer("")
+		return nil
+	}
+	s.mu.Lock()
+	ch, ok := s.findChannelLocked(channelID)
+	quality := s.streamQualityLocked()
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown channel: %s", channelID)
+	}
+	if s.isActive(channelID) {
+		return nil
+	}
+	urls, _, err := s.channelStreamURLs(ch, quality)
+	if err != nil {
+		return err
+	}
+	// Resolving the playlist takes a moment; it may have started playing
+	// meanwhile.
+	if s.isActive(channelID) {
+		return nil
+	}
+	// A picked channel plays from its primary server first.
+	s.player.Prebuffer(urls[0])
+	return nil
+}
+
+// isActive reports whether channelID is playing or on its way to playing.
+func (s *Server) isActive(channelID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status != protocol.StatusStopped && s.channelID == channelID
+}
+
+// supersededBy reports whether a newer play or stop replaced the play
+// attempt identified by gen.
+func (s *Server) supersededBy(gen uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return gen != s.playGen
+}
+
 // failConnect records a connect failure for the play attempt identified by
 // gen, scheduling a reconnect when the error is retryable.
 func (s *Server) failConnect(gen uint64, err error, retry bool) (protocol.PlaybackState, error) {
@@ -126,12 +338,28 @@ func (s *Server) failConnect(gen uint64, err error, retry bool) (protocol.Playba
 		return s.snapshotLocked(), err
 	}
 	s.streamErr = err.Error()
-	s.trackTitle = ""
+	s.streamErrKind = streamErrorKind(err)
+	s.track = audio.TrackInfo{}
 	s.scheduleReconnectOrStopLocked(retry)
 	s.broadcastStateLocked()
 	return s.snapshotLocked(), err
 }
 
+// streamErrorKind maps a classified player error to its protocol class.
+func streamErrorKind(err error) string {
+	var se *audio.StreamError
+	if !errors.As(err, &se) {
+		return ""
+	}
+	switch se.Kind {
+	case audio.StreamOffline:
+		return protocol.StreamErrorOffline
+	case audio.StreamUnsupported:
+		return protocol.StreamErrorUnsupported
+	}
+	return ""
+}
+
 // handleStreamError reacts to an async error on the running stream: release
 // the audio session and schedule a reconnect.
 func (s *Server) handleStreamError(err error) {
@@ -139,26 +367,38 @@ func (s *Server) handleStreamError(err error) {
 	defer s.mu.Unlock()
 	// Errors surfacing while connecting are (also) returned synchronously by
 	// player.Play, and errors after a stop belong to a torn-down session.
+	if s.status == protocol.StatusPaused {
+		// The buffer ends where the connection did; stay paused and
+		// reconnect to the live stream on resume instead.
+		s.player.Stop()
+		s.pauseDropped = true
+		return
+	}
 	if s.status != protocol.StatusPlaying {
 		return
 	}
 	// Stop the player so the failed session's goroutine and audio resources
 	// are released instead of lingering until the next play.
 	s.player.Stop()
-	s.trackTitle = ""
+	// Reconnect through the next server in the playlist: the one that
+	// dropped may keep dropping.
+	s.streamServer++
+	s.track = audio.TrackInfo{}
 	s.streamErr = err.Error()
+	s.streamErrKind = streamErrorKind(err)
 	s.scheduleReconnectOrStopLocked(true)
 	s.broadcastStateLocked()
 }
 
 // scheduleReconnectOrStopLocked moves to reconnecting with capped
 // exponential backoff when the error is retryable, and to stopped otherwise.
-// Reconnecting never gives up on its own; only an explicit stop or a new
-// play ends it.
+// Unless maxReconnects is set, reconnecting never gives up on its own; only
+// an explicit stop or a new play ends it.
 func (s *Server) scheduleReconnectOrStopLocked(retry bool) {
-	if retry {
+	if retry && (s.maxReconnects == 0 || s.reconnectAttempt < s.maxReconnects) {
 		s.reconnectAttempt++
-		s.status = protocol.StatusReconnecting
+		s.reconnects++
+		s.setStatusLocked(protocol.StatusReconnecting)
 		gen := s.playGen
 		channelID := s.channelID
 		s.reconnectTimer = time.AfterFunc(reconnectDelay(s.reconnectAttempt), func() {
@@ -168,11 +408,11 @@ func (s *Server) scheduleReconnectOrStopLocked(retry bool) {
 			if stale {
 				return
 			}
-			_, _ = s.playChannel(channelID, false)
+			_, _ = s.playChannel(channelID, false, false)
 		})
 		return
 	}
-	s.status = protocol.StatusStopped
+	s.setStatusLocked(protocol.StatusStopped)
 	s.reconnectAttempt = 0
 	s.updateMPRISLocked()
 	s.maybeArmIdleLocked()
@@ -210,21 +450,169 @@ func (s *Server) PlayRelative(delta int) (protocol.PlaybackState, error) {
 func (s *Server) Stop() protocol.PlaybackState {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.stopLocked()
+}
+
+func (s *Server) stopLocked() protocol.PlaybackState {
 	s.playGen++
 	s.cancelReconnectLocked()
 	s.player.Stop()
-	s.status = protocol.StatusStopped
-	s.trackTitle = ""
+	s.endPreviewLocked()
+	s.setStatusLocked(protocol.StatusStopped)
+	s.track = audio.TrackInfo{}
 	s.streamErr = ""
+	s.streamErrKind = ""
 	s.reconnectAttempt = 0
+	s.pauseDropped = false
 	s.updateMPRISLocked()
 	s.maybeArmIdleLocked()
 	s.broadcastStateLocked()
 	return s.snapshotLocked()
 }
 
+// Sleep releases the stream before a system suspend, since its connection
+// would be dead on wake. A channel that is playing or on its way to playing
+// is remembered for Wake; a paused one stays paused and reconnects when
+// resumed. A preview just stops.
+func (s *Server) Sleep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch s.status {
+	case protocol.StatusPaused:
+		s.player.Stop()
+		s.pauseDropped = true
+	case protocol.StatusPlaying, protocol.StatusConnecting, protocol.StatusReconnecting:
+		id := s.channelID
+		if s.preview {
+			id = ""
+		}
+		s.stopLocked()
+		s.wakeChannel = id
+	}
+	// A prebuffered connection would not survive the suspend either.
+	s.player.Prebuffer("")
+}
+
+// Wake plays the channel Sleep released, unless playback was started or
+// stopped some other way meanwhile. A network that is not back yet is
+// handled like any failed connect: the server keeps reconnecting.
+func (s *Server) Wake() {
+	s.mu.Lock()
+	id := s.wakeChannel
+	s.wakeChannel = ""
+	idle := s.status == protocol.StatusStopped
+	s.mu.Unlock()
+	if id == "" || !idle {
+		return
+	}
+	_, _ = s.playChannel(id, false, false)
+}
+
+// Pause silences a playing stream without disconnecting it; the player
+// keeps buffering so Resume continues where it left off. In any other state
+// it changes nothing.
+func (s *Server) Pause() protocol.PlaybackState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status != protocol.StatusPlaying {
+		return s.snapshotLocked()
+	}
+	s.player.Pause()
+	s.setStatusLocked(protocol.StatusPaused)
+	s.updateMPRISLocked()
+	s.broadcastStateLocked()
+	return s.snapshotLocked()
+}
+
+// Resume continues a paused stream from its buffer, or reconnects to the
+// live stream when the connection dropped during the pause. In any other
+// state it changes nothing.
+func (s *Server) Resume() (protocol.PlaybackState, error) {
+	s.mu.Lock()
+	if s.status != protocol.StatusPaused {
+		snap := s.snapshotLocked()
+		s.mu.Unlock()
+		return snap, nil
+	}
+	if s.pauseDropped {
+		id := s.channelID
+		s.mu.Unlock()
+		return s.playChannel(id, false, false)
+	}
+	s.player.Resume()
+	s.setStatusLocked(protocol.StatusPlaying)
+	s.updateMPRISLocked()
+	s.broadcastStateLocked()
+	snap := s.snapshotLocked()
+	s.mu.Unlock()
+	return snap, nil
+}
+
+// Level returns the audio level of the playing stream, or 0 when nothing is
+// playing.
+func (s *Server) Level() float64 {
+	s.mu.Lock()
+	playing := s.status == protocol.StatusPlaying
+	s.mu.Unlock()
+	if !playing {
+		return 0
+	}
+	return s.player.Level()
+}
+
+// Spectrum returns the frequency bands of the playing stream's audio, or
+// nil when nothing is playing.
+func (s *Server) Spectrum(bands int) ([]float64, error) {
+	if bands < 1 || bands > audio.MaxSpectrumBands {
+		return nil, fmt.Errorf("bands must be between 1 and %d", audio.MaxSpectrumBands)
+	}
+	s.mu.Lock()
+	playing := s.status == protocol.StatusPlaying
+	s.mu.Unlock()
+	if !playing {
+		return nil, nil
+	}
+	return s.player.Spectrum(bands), nil
+}
+
+// Stats reports on the playing or paused stream's connection. Only the
+// reconnect count is reported otherwise.
+func (s *Server) Stats() protocol.StatsResult {
+	s.mu.Lock()
+	connected := s.status == protocol.StatusPlaying || s.status == protocol.StatusPaused
+	result := protocol.StatsResult{Reconnects: s.reconnects}
+	s.mu.Unlock()
+	if !connected {
+		return result
+	}
+	st := s.player.Stats()
+	result.BytesReceived = st.BytesReceived
+	result.ConnectedAt = st.Connected
+	result.Buffered = st.Buffered
+	result.BufferSize = st.BufferSize
+	return result
+}
+
+// TimeShift rewinds the playing or paused stream d into its buffer, or
+// moves it toward live when d is negative; live returns to the live stream
+// whatever d. In any other state it changes nothing.
+func (s *Server) TimeShift(d time.Duration, live bool) protocol.PlaybackState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status != protocol.StatusPlaying && s.status != protocol.StatusPaused {
+		return s.snapshotLocked()
+	}
+	if live {
+		d = -s.player.Behind()
+	}
+	s.player.TimeShift(d)
+	s.broadcastStateLocked()
+	return s.snapshotLocked()
+}
+
 // SetVolume clamps and applies the volume, persists it, and broadcasts the
-// new state. mirrorToMPRIS is false when the change came from MPRIS itself.
+// new state. It also unmutes. mirrorToMPRIS is false when the change came
+// from MPRIS itself.
 func (s *Server) SetVolume(v float64, mirrorToMPRIS bool) protocol.PlaybackState {
 	if v < 0 {
 		v = 0
@@ -233,8 +621,9 @@ func (s *Server) SetVolume(v float64, mirrorToMPRIS bool) protocol.PlaybackState
 		v = 1
 	}
 	s.mu.Lock()
-	s.player.SetVolume(v)
+	s.muted = false
 	s.st.SetVolume(v)
+	s.player.SetVolume(s.playerVolumeLocked())
 	stateToSave := s.st.Clone()
 	saveSeq := s.nextSaveSeqLocked()
 	if mirrorToMPRIS && s.mpris != nil {
@@ -248,19 +637,177 @@ func (s *Server) SetVolume(v float64, mirrorToMPRIS bool) protocol.PlaybackState
 	return snap
 }
 
+// ToggleMute silences the player, or restores the persisted volume, without
+// touching the stream connection. MPRIS sees a muted player at volume 0.
+// The mute is not persisted: a restarted server plays at the saved volume.
+func (s *Server) ToggleMute() protocol.PlaybackState {
+	s.mu.Lock()
+	s.muted = !s.muted
+	v := s.st.GetVolume()
+	if s.muted {
+		v = 0
+	}
+	s.player.SetVolume(s.playerVolumeLocked())
+	if s.mpris != nil {
+		s.mpris.SetVolume(v)
+	}
+	s.broadcastStateLocked()
+	snap := s.snapshotLocked()
+	s.mu.Unlock()
+	return snap
+}
+
+// streamQualityLocked returns the playlist quality to play: the one a client
+// picked, else the configured default.
+func (s *Server) streamQualityLocked() string {
+	if s.st.StreamQuality != "" {
+		return s.st.StreamQuality
+	}
+	return s.defaultQuality
+}
+
+// SetStreamQuality persists the preferred playlist quality and, when a
+// SomaFM channel is playing, reconnects it at the new quality. A paused
+// channel stays paused; the quality applies from its next connect.
+func (s *Server) SetStreamQuality(quality string) (protocol.PlaybackState, error) {
+	if !channels.ValidQuality(quality) {
+		return s.Snapshot(), fmt.Errorf("unknown stream quality %q (want low, high or highest)", quality)
+	}
+	s.mu.Lock()
+	changed := quality != s.streamQualityLocked()
+	s.st.StreamQuality = quality
+	stateToSave := s.st.Clone()
+	saveSeq := s.nextSaveSeqLocked()
+	replay := ""
+	if changed && s.status != protocol.StatusStopped && s.status != protocol.StatusPaused {
+		if ch, ok := s.findChannelLocked(s.channelID); ok && ch.StreamURL == "" {
+			replay = ch.ID
+		}
+	}
+	s.broadcastStateLocked()
+	snap := s.snapshotLocked()
+	s.mu.Unlock()
+
+	s.saveState(saveSeq, stateToSave)
+	if replay != "" {
+		// Connecting blocks on the network; the reply (and the clients)
+		// follow the switch through state events.
+		go func() { _, _ = s.playChannel(replay, false, false) }()
+	}
+	return snap, nil
+}
+
+// equalizerLocked returns the equalizer preset to apply: the one a client
+// picked, else the configured default, else flat.
+func (s *Server) equalizerLocked() string {
+	if s.st.Equalizer != "" {
+		return s.st.Equalizer
+	}
+	if s.defaultEqualizer != "" {
+		return s.defaultEqualizer
+	}
+	return "flat"
+}
+
+// SetEqualizer persists the equalizer preset and applies it to the playing
+// stream without reconnecting.
+func (s *Server) SetEqualizer(preset string) (protocol.PlaybackState, error) {
+	if !audio.ValidEqualizer(preset) {
+		return s.Snapshot(), fmt.Errorf("unknown equalizer preset %q (want %s)",
+			preset, strings.Join(audio.EqualizerPresets(), ", "))
+	}
+	s.mu.Lock()
+	s.st.Equalizer = preset
+	s.player.SetEqualizer(preset)
+	stateToSave := s.st.Clone()
+	saveSeq := s.nextSaveSeqLocked()
+	s.broadcastStateLocked()
+	snap := s.snapshotLocked()
+	s.mu.Unlock()
+
+	s.saveState(saveSeq, stateToSave)
+	return snap, nil
+}
+
+// historySize caps how many tracks the server remembers.
+const historySize = 200
+
 // handleTrackUpdate publishes a now-playing title from the stream's ICY
-// metadata.
+// metadata and records it in the history, unless the songs API supplies
+// the track (see pollSongs).
 func (s *Server) handleTrackUpdate(ti audio.TrackInfo) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if s.status != protocol.StatusPlaying {
+	if s.status != protocol.StatusPlaying && s.status != protocol.StatusPaused {
 		return
 	}
-	s.trackTitle = ti.Title
+	s.icyTrack = ti
+	if s.songsLive {
+		return
+	}
+	s.setTrackLocked(ti)
+}
+
+// setTrackLocked makes ti the playing track: it is recorded in the history,
+// announced, and pushed to clients.
+func (s *Server) setTrackLocked(ti audio.TrackInfo) {
+	s.track = ti
+	if s.recordTrackLocked(ti.Title) {
+		s.trackStarted = time.Now()
+		s.lookupRecordingLocked()
+		if !s.preview {
+			s.notifyTrackLocked()
+		}
+	}
 	s.updateMPRISLocked()
 	s.broadcastStateLocked()
 }
 
+// recordTrackLocked appends a title to the history, reporting whether it
+// was a new track. A title repeated on the same channel, as after a
+// reconnect, is the same track.
+func (s *Server) recordTrackLocked(title string) bool {
+	if title == "" {
+		return false
+	}
+	if n := len(s.history); n > 0 && s.history[n-1].ChannelID == s.channelID && s.history[n-1].Title == title {
+		return false
+	}
+	if len(s.history) == historySize {
+		s.history = append(s.history[:0], s.history[1:]...)
+	}
+	s.history = append(s.history, protocol.TrackEntry{
+		Time:      time.Now(),
+		ChannelID: s.channelID,
+		Channel:   s.channelTitle,
+		Title:     title,
+		Artist:    s.track.Artist,
+		Song:      s.track.Song,
+	})
+	return true
+}
+
+// notifyTrackLocked announces the playing track on the desktop: the song as
+// the heading, then its artist and the channel.
+func (s *Server) notifyTrackLocked() {
+	body := s.channelTitle
+	if s.track.Artist != "" {
+		body = s.track.Artist + "\n" + body
+	}
+	s.notifier.Notify(s.track.Song, body)
+}
+
+// History returns the tracks seen since the server started, newest first.
+func (s *Server) History() protocol.HistoryResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tracks := make([]protocol.TrackEntry, len(s.history))
+	for i, t := range s.history {
+		tracks[len(tracks)-1-i] = t
+	}
+	return protocol.HistoryResult{Tracks: tracks}
+}
+
 func (s *Server) cancelReconnectLocked() {
 	if s.reconnectTimer != nil {
 		s.reconnectTimer.Stop()
@@ -268,24 +815,54 @@ func (s *Server) cancelReconnectLocked() {
 	}
 }
 
+// mprisTrackLocked describes the playing track for MPRIS, with the
+// MusicBrainz details when they are known.
+func (s *Server) mprisTrackLocked() platform.TrackMeta {
+	meta := platform.TrackMeta{
+		Station: s.channelTitle,
+		Title:   s.track.Song,
+		Artist:  s.track.Artist,
+		Album:   s.trackAlbumLocked(),
+	}
+	if rec, ok := s.recordingLocked(); ok {
+		meta.Year = rec.Year
+		meta.RecordingID = rec.ID
+	}
+	return meta
+}
+
+// mprisTrackStartLocked is when the playing track started, for the MPRIS
+// position; zero, which holds the position at 0, while there is no title.
+func (s *Server) mprisTrackStartLocked() time.Time {
+	if s.track.Title == "" {
+		return time.Time{}
+	}
+	return s.trackStarted
+}
+
 // updateMPRISLocked mirrors the playback state to the desktop integrations
-// (MPRIS and the tray). Both are optional and skipped when absent.
+// (MPRIS and the tray) and the now-playing file. All are optional and
+// skipped when absent.
 func (s *Server) updateMPRISLocked() {
-	playing := s.status == protocol.StatusPlaying
 	if s.mpris != nil {
-		if playing {
-			// Use the channel title as artist since SomaFM streams don't have
-			// separate artist info.
-			s.mpris.SetPlaying(s.channelTitle, s.trackTitle, s.channelTitle)
-		} else {
+		switch s.status {
+		case protocol.StatusPlaying:
+			s.mpris.SetPlaying(s.mprisTrackLocked(), s.mprisTrackStartLocked())
+		case protocol.StatusPaused:
+			s.mpris.SetPaused()
+		default:
 			s.mpris.SetStopped()
 		}
 	}
 	if s.tray != nil {
-		if playing {
-			s.tray.SetPlaying(s.channelID, s.channelTitle, s.trackTitle)
-		} else {
+		switch s.status {
+		case protocol.StatusPlaying:
+			s.tray.SetPlaying(s.channelID, s.channelTitle, s.track.Title)
+		case protocol.StatusPaused:
+			s.tray.SetPaused()
+		default:
 			s.tray.SetStopped()
 		}
 	}
+	s.updateNowPlayingLocked()
 }
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"somad/internal/channels"
+	"somad/internal/protocol"
+	"somad/internal/security"
+)
+
+const (
+	// probeTimeout bounds one stream probe: connecting and reading the
+	// first byte.
+	probeTimeout = 15 * time.Second
+
+	// probeConcurrency caps the streams probed at once.
+	probeConcurrency = 4
+)
+
+// probeStream asks a stream for its first byte alone, the cheapest proof
+// that it is on the air; a server that ignores the range still sends just
+// that byte before the connection is dropped. A variable so tests can
+// avoid the network.
+var probeStream = func(streamURL, userAgent string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	req, client, err := security.NewStreamRequest(ctx, streamURL, userAgent)
+	if err != nil {
+		return fmt.Errorf("invalid stream URL: %w", err)
+	}
+	req.Header.Set("Range", "bytes=0-0")
+	resp, err := client.Do(req) // #nosec G704 -- URL validated by security.NewStreamRequest()
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if _, err := io.ReadFull(resp.Body, make([]byte, 1)); err != nil {
+		return fmt.Errorf("no stream data: %w", err)
+	}
+	return nil
+}
+
+// probeLoop probes the channels' streams every probeInterval, unless that
+// is 0.
+func (s *Server) probeLoop() {
+	if s.probeInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.probeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.probeChannels()
+		}
+	}
+}
+
+// probeChannels checks that every catalog channel's stream answers, except
+// the one playing, and marks the ones that do not as dead, telling clients
+// while it runs. A probe asked for while one is underway is dropped.
+func (s *Server) probeChannels() {
+	s.mu.Lock()
+	if s.probing || len(s.catalog) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	s.probing = true
+	chs := slices.Clone(s.catalog)
+	quality := s.streamQualityLocked()
+	playing := ""
+	if s.status != protocol.StatusStopped {
+		playing = s.channelID
+	}
+	s.broadcastChannelsLocked()
+	s.mu.Unlock()
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		dead = make(map[string]string)
+		sem  = make(chan struct{}, probeConcurrency)
+	)
+probe:
+	for _, ch := range chs {
+		if ch.ID == playing {
+			continue
+		}
+		select {
+		case <-s.done:
+			break probe
+		case sem <- struct{}{}:
+		}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			if err := s.probeChannel(ch, quality); err != nil {
+				log.Printf("channel %s unreachable: %v", ch.ID, err)
+				mu.Lock()
+				dead[ch.ID] = err.Error()
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	s.probing = false
+	s.deadChannels = dead
+	s.broadcastChannelsLocked()
+	s.mu.Unlock()
+}
+
+// probeChannel resolves a channel's stream URLs the way playing it does and
+// reports an error unless one of its servers answers. A channel without a
+// stream the server can play is not dead, just unplayable, so it passes;
+// playing it says why.
+func (s *Server) probeChannel(ch channels.Channel, quality string) error {
+	urls, retry, err := s.channelStreamURLs(ch, quality)
+	if err != nil && !retry {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, u := range urls {
+		if err = probeStream(u, s.userAgent); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// reviveChannelLocked drops the dead mark of the playing channel, which has
+// just proven it is on the air, for clients to get with the state of the
+// status change.
+func (s *Server) reviveChannelLocked() {
+	if s.status != protocol.StatusPlaying {
+		return
+	}
+	if _, ok := s.deadChannels[s.channelID]; !ok {
+		return
+	}
+	// Copy on write: payloads share the map and are marshaled unlocked.
+	s.deadChannels = maps.Clone(s.deadChannels)
+	delete(s.deadChannels, s.channelID)
+	s.channelsPending = true
+}
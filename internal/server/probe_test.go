@@ -0,0 +1,123 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"somad/internal/channels"
+	"somad/internal/protocol"
+	"somad/internal/security/securitytest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubProbe replaces the stream probe for one test: streams whose URL
+// contains a key of down fail with its error. It records the probed URLs.
+func stubProbe(t *testing.T, down map[string]error) *[]string {
+	t.Helper()
+	var mu sync.Mutex
+	var probed []string
+	prev := probeStream
+	probeStream = func(streamURL, _ string) error {
+		mu.Lock()
+		probed = append(probed, streamURL)
+		mu.Unlock()
+		for key, err := range down {
+			if strings.Contains(streamURL, key) {
+				return err
+			}
+		}
+		return nil
+	}
+	t.Cleanup(func() { probeStream = prev })
+	return &probed
+}
+
+func TestProbeChannels_MarksUnreachableStreams(t *testing.T) {
+	probed := stubProbe(t, map[string]error{"dronezone": errors.New("unexpected status code: 404")})
+	s, _ := newTestServer(t, Config{})
+
+	s.probeChannels()
+
+	payload := s.ChannelsPayload()
+	assert.False(t, payload.Probing)
+	assert.Equal(t, map[string]string{"dronezone": "unexpected status code: 404"}, payload.Dead)
+	assert.Len(t, *probed, 2, "aacchannel has no stream to probe")
+}
+
+func TestProbeChannels_SkipsThePlayingChannel(t *testing.T) {
+	probed := stubProbe(t, nil)
+	s, _ := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+
+	s.probeChannels()
+
+	assert.Equal(t, []string{"http://somafm.com/dronezone.pls#stream"}, *probed)
+}
+
+func TestPlay_RevivesADeadChannel(t *testing.T) {
+	stubProbe(t, map[string]error{"groovesalad": errors.New("failed to connect")})
+	s, _ := newTestServer(t, Config{})
+	s.probeChannels()
+	require.Contains(t, s.ChannelsPayload().Dead, "groovesalad")
+
+	_, err := s.Play("groovesalad")
+	require.NoError(t, err)
+
+	assert.NotContains(t, s.ChannelsPayload().Dead, "groovesalad", "it plays, so it is on the air")
+}
+
+func TestProbeStream_AsksForTheFirstByteOnly(t *testing.T) {
+	securitytest.AllowTestHosts(t)
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if r.URL.Path == "/gone" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write([]byte{0xff})
+	}))
+	defer srv.Close()
+
+	require.NoError(t, probeStream(srv.URL+"/live", "soma/test"))
+	assert.Error(t, probeStream(srv.URL+"/gone", "soma/test"))
+	assert.Equal(t, []string{"bytes=0-0", "bytes=0-0"}, ranges)
+}
+
+func TestProbing_OffByDefault(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	probed := stubProbe(t, nil)
+	stubChannelsNetwork(t, func(w http.ResponseWriter, r *http.Request) {
+		data, _ := json.Marshal(channels.Channels{Channels: testChannels()})
+		_, _ = w.Write(data)
+	})
+	s := newBareServer(t)
+	s.setCatalog(&channels.Channels{Channels: testChannels()[:1]})
+
+	done := make(chan struct{})
+	go func() {
+		s.probeLoop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("probeLoop kept running without an interval")
+	}
+
+	s.RefreshChannels()
+	require.Eventually(t, func() bool { return len(s.ChannelsPayload().Channels) == 3 }, 2*time.Second, 5*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, *probed, "a refresh probes nothing unless probing is on")
+}
@@ -4,18 +4,23 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"somad/internal/audio"
 	"somad/internal/channels"
+	"somad/internal/directory"
+	"somad/internal/musicbrainz"
 	"somad/internal/platform"
 	"somad/internal/platform/tray"
 	"somad/internal/protocol"
+	"somad/internal/radiobrowser"
 	"somad/internal/state"
 )
 
@@ -24,18 +29,64 @@ import (
 // idle exit, so by default the server runs until stopped explicitly.
 const DefaultIdleTimeout time.Duration = 0
 
-// channelRefreshInterval is a variable so tests can shrink it.
-var channelRefreshInterval = 10 * time.Minute
+// DefaultCatalogTTL is how long after downloading it the server starts
+// from the cached channel catalog without downloading it again.
+const DefaultCatalogTTL = time.Hour
+
+// DefaultRefreshInterval is how often the server downloads the channel
+// catalog again while it runs.
+const DefaultRefreshInterval = 10 * time.Minute
+
+// offlineRetryInterval is how often the catalog is refreshed while SomaFM
+// is unreachable; a variable so tests can shrink it.
+var offlineRetryInterval = 30 * time.Second
 
 // Config carries the dependencies for a Server.
 type Config struct {
-	Version     string
-	UserAgent   string
-	Player      audio.Player
-	State       *state.State
-	MPRIS       *platform.MPRIS // may be nil
-	Tray        *tray.Tray      // may be nil
-	IdleTimeout time.Duration   // 0 disables idle exit
+	Version   string
+	UserAgent string
+	Player    audio.Player
+	State     *state.State
+	MPRIS     *platform.MPRIS // may be nil
+	Tray      *tray.Tray      // may be nil
+	// Sleep reports system suspend and resume, so the stream is released
+	// before sleep and picked up again on wake; may be nil.
+	Sleep *platform.SleepWatcher
+	// Notifier announces each new track on the desktop; nil disables
+	// notifications.
+	Notifier *platform.Notifier
+	// NowPlayingFile, when set, is kept holding the playing channel, artist
+	// and song on one line (empty while nothing plays), for streaming
+	// overlays to show.
+	NowPlayingFile string
+	// MusicBrainz looks each new track up on MusicBrainz for its album,
+	// release year and IDs.
+	MusicBrainz bool
+	IdleTimeout time.Duration // 0 disables idle exit
+	// ReconnectAttempts caps consecutive reconnect attempts after a stream
+	// drops; once exhausted the server stops and reports the failed
+	// channel. 0 retries forever.
+	ReconnectAttempts int
+	// StreamQuality is the default playlist quality ("low", "high" or
+	// "highest"; empty for the best available) until a client picks one,
+	// which is persisted in State.
+	StreamQuality string
+	// Equalizer is the default equalizer preset until a client picks one,
+	// which is persisted in State; empty plays flat.
+	Equalizer string
+	// CatalogTTL is how long after downloading it the cached channel
+	// catalog is used at startup without downloading it again; 0 downloads
+	// it at every start.
+	CatalogTTL time.Duration
+	// RefreshInterval is how often the catalog is downloaded again while
+	// the server runs; 0 leaves it to startup and RefreshChannels.
+	RefreshInterval time.Duration
+	// ProbeInterval is how often every channel's stream is checked for
+	// answering, also on RefreshChannels; 0 (the default) never checks.
+	ProbeInterval time.Duration
+	// Sources are where the catalog's stations come from, merged in this
+	// order; nil lists SomaFM's channels alone.
+	Sources []directory.Directory
 	// PSK, when non-empty, is the pre-shared key every non-local (TCP)
 	// connection must authenticate with before hello. Unix-socket
 	// connections are exempt: the socket directory's permissions already
@@ -52,8 +103,19 @@ type Server struct {
 	st          *state.State
 	mpris       *platform.MPRIS
 	tray        *tray.Tray
+	sleep       *platform.SleepWatcher
+	notifier    *platform.Notifier
 	idleTimeout time.Duration
 	psk         string
+	musicBrainz bool
+	// maxReconnects is Config.ReconnectAttempts; 0 means unlimited.
+	maxReconnects int
+	// defaultQuality is Config.StreamQuality, used while the state has no
+	// quality of its own.
+	defaultQuality string
+	// defaultEqualizer is Config.Equalizer, used while the state has no
+	// preset of its own.
+	defaultEqualizer string
 
 	// persist writes user state to disk. It defaults to state.SaveState;
 	// tests override it to avoid fsync-heavy disk writes on every mutation.
@@ -69,6 +131,13 @@ type Server struct {
 	savedSeq uint64
 	dirty    *state.State
 
+	// nowPlayingPath is Config.NowPlayingFile. nowPlayingMu serializes its
+	// writes the way saveMu does state saves; nowPlayingWritten (guarded by
+	// it) is the sequence of the newest line written.
+	nowPlayingPath    string
+	nowPlayingMu      sync.Mutex
+	nowPlayingWritten uint64
+
 	shutdownOnce sync.Once
 	done         chan struct{} // closed by Shutdown
 
@@ -76,37 +145,89 @@ type Server struct {
 	lns              []net.Listener
 	conns            map[*conn]struct{}
 	closing          bool
-	catalog          []channels.Channel // favorites-first order
-	catalogErr       string             // load failure while the catalog is empty
+	catalog          []channels.Channel            // favorites-first order
+	sources          []directory.Directory         // Config.Sources, in catalog order
+	sourceCatalogs   map[string]*channels.Channels // latest catalog of each source, by name
+	catalogErr       string                        // load failure while the catalog is empty
+	catalogUpdated   time.Time                     // when SomaFM last changed the catalog, if known
+	catalogFetched   time.Time                     // when the catalog was downloaded, if known
+	catalogStale     bool                          // the latest refresh failed, leaving the cached catalog
+	catalogOffline   bool                          // the latest refresh could not reach SomaFM at all
+	catalogFetching  bool                          // a catalog download is underway
+	probing          bool                          // a probe of the channels' streams is underway
+	deadChannels     map[string]string             // why each channel failed the latest probe, by ID
+	listenerTrends   map[string]int                // listener change per channel since the previous catalog
+	channelsPending  bool                          // the catalog payload changed with the state; see broadcastStateLocked
+	listenChannel    string                        // channel of the running listen
+	listenSince      time.Time                     // when the running listen began; zero while not listening
+	listenSaves      sync.WaitGroup                // background saves of listening time
+	catalogTTL       time.Duration                 // Config.CatalogTTL
+	refreshInterval  time.Duration                 // Config.RefreshInterval
+	probeInterval    time.Duration                 // Config.ProbeInterval
+	stations         []channels.Channel            // latest directory search results, playable by ID
 	status           string
 	channelID        string // active channel while not stopped
 	channelTitle     string
-	trackTitle       string
+	listeners        int                   // the active channel's listener count; 0 if unknown
+	track            audio.TrackInfo       // the now-playing title, split
+	trackStarted     time.Time             // when track first appeared on its channel
+	icyTrack         audio.TrackInfo       // the stream's own latest title, kept while songsLive
+	songsLive        bool                  // the songs API supplies track; ICY titles only update icyTrack
+	recording        musicbrainz.Recording // MusicBrainz's details of the track titled recordingTitle
+	recordingTitle   string
+	history          []protocol.TrackEntry // titles seen, oldest first, at most historySize
 	streamErr        string
+	streamErrKind    string // protocol.StreamError* class of streamErr, if known
 	reconnectAttempt int
+	reconnects       int    // reconnects since the user picked the channel
+	muted            bool   // player silenced; st keeps the volume to restore
+	preview          bool   // playing a channel as a quiet preview, not picked
+	pauseDropped     bool   // the stream failed while paused; resume reconnects
+	wakeChannel      string // channel released for a system suspend, replayed on wake
+	streamServer     int    // playlist server to connect to first; failover advances it
 	playGen          uint64 // bumped by every play/stop; stale async work backs out
 	saveSeq          uint64 // bumped per state mutation; orders persist writes
+	nowPlayingLine   string // last line handed to the now-playing file
+	nowPlayingSeq    uint64 // bumped per now-playing line; orders its writes
 	reconnectTimer   *time.Timer
 	idleTimer        *time.Timer
 }
 
-// New creates a Server and applies the persisted volume to the player.
+// New creates a Server and applies the persisted volume and equalizer to the
+// player.
 func New(cfg Config) *Server {
 	s := &Server{
-		version:     cfg.Version,
-		userAgent:   cfg.UserAgent,
-		player:      cfg.Player,
-		st:          cfg.State,
-		mpris:       cfg.MPRIS,
-		tray:        cfg.Tray,
-		idleTimeout: cfg.IdleTimeout,
-		psk:         cfg.PSK,
-		persist:     state.SaveState,
-		done:        make(chan struct{}),
-		conns:       make(map[*conn]struct{}),
-		status:      protocol.StatusStopped,
+		version:         cfg.Version,
+		userAgent:       cfg.UserAgent,
+		player:          cfg.Player,
+		st:              cfg.State,
+		mpris:           cfg.MPRIS,
+		tray:            cfg.Tray,
+		sleep:           cfg.Sleep,
+		notifier:        cfg.Notifier,
+		nowPlayingPath:  cfg.NowPlayingFile,
+		idleTimeout:     cfg.IdleTimeout,
+		musicBrainz:     cfg.MusicBrainz,
+		psk:             cfg.PSK,
+		sources:         cfg.Sources,
+		sourceCatalogs:  make(map[string]*channels.Channels),
+		catalogTTL:      cfg.CatalogTTL,
+		refreshInterval: cfg.RefreshInterval,
+		probeInterval:   cfg.ProbeInterval,
+		persist:         state.SaveState,
+		done:            make(chan struct{}),
+		conns:           make(map[*conn]struct{}),
+		status:          protocol.StatusStopped,
+
+		maxReconnects:    cfg.ReconnectAttempts,
+		defaultQuality:   cfg.StreamQuality,
+		defaultEqualizer: cfg.Equalizer,
+	}
+	if len(s.sources) == 0 {
+		s.sources = []directory.Directory{directory.SomaFM{}}
 	}
 	s.player.SetVolume(cfg.State.GetVolume())
+	s.player.SetEqualizer(s.equalizerLocked())
 	// MPRIS Play with no prior play in this process targets the last-played
 	// channel from the previous session.
 	s.channelID = cfg.State.LastSelectedChannelID
@@ -120,6 +241,9 @@ func New(cfg Config) *Server {
 		s.tray.SetSender(mprisSender{s})
 		s.tray.SetOnQuit(s.Shutdown)
 	}
+	if s.sleep != nil {
+		s.sleep.SetSender(mprisSender{s})
+	}
 	return s
 }
 
@@ -138,6 +262,7 @@ func (s *Server) Run(lns ...net.Listener) error {
 	go s.watchPlayerErrors()
 	go s.watchTrackUpdates()
 	go s.refreshLoop()
+	go s.probeLoop()
 	s.loadCatalog()
 
 	errCh := make(chan error, len(lns))
@@ -181,6 +306,7 @@ func (s *Server) Shutdown() {
 		s.closing = true
 		s.cancelReconnectLocked()
 		s.disarmIdleLocked()
+		s.endListenLocked()
 		lns := s.lns
 		open := make([]*conn, 0, len(s.conns))
 		for c := range s.conns {
@@ -195,6 +321,11 @@ func (s *Server) Shutdown() {
 		if s.tray != nil {
 			s.tray.Quit()
 		}
+		if s.sleep != nil {
+			s.sleep.Close()
+		}
+		s.notifier.Close()
+		s.clearNowPlaying()
 		close(s.done)
 		for _, ln := range lns {
 			_ = ln.Close()
@@ -202,6 +333,7 @@ func (s *Server) Shutdown() {
 		for _, c := range open {
 			c.close()
 		}
+		s.listenSaves.Wait()
 		s.flushDirtyState()
 	})
 }
@@ -245,9 +377,13 @@ func (s *Server) watchTrackUpdates() {
 	}
 }
 
-// refreshLoop refreshes the channel catalog periodically.
+// refreshLoop refreshes the channel catalog every refreshInterval, unless
+// that is 0.
 func (s *Server) refreshLoop() {
-	ticker := time.NewTicker(channelRefreshInterval)
+	if s.refreshInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.refreshInterval)
 	defer ticker.Stop()
 	for {
 		select {
@@ -259,68 +395,212 @@ func (s *Server) refreshLoop() {
 	}
 }
 
-// loadCatalog seeds the catalog from the disk cache, then refreshes from the
-// network in the background.
+// loadCatalog seeds the catalog from each source's cache, then refreshes
+// from the network in the background unless every cache is younger than
+// catalogTTL; refreshLoop keeps a fresh catalog current.
 func (s *Server) loadCatalog() {
-	if chs, err := channels.ReadChannelsFromCache(); err == nil {
-		s.setCatalog(chs.Channels)
+	cached := make(map[string]*channels.Channels, len(s.sources))
+	stale := false
+	for _, src := range s.sources {
+		c, err := src.Cached()
+		if err != nil {
+			stale = true
+			continue
+		}
+		cached[src.Name()] = c
+		stale = stale || c.Stale(s.catalogTTL)
+	}
+	if len(cached) > 0 {
+		s.setCatalogs(cached)
+	}
+	if stale {
+		go s.refreshCatalog()
 	}
-	go s.refreshCatalog()
 }
 
-// refreshCatalog fetches the catalog from the network. Failures are silent
-// while a previous catalog exists (background refresh), but surfaced to
-// clients when there is nothing to show at all.
+// refreshCatalog fetches every source from the network, telling clients
+// while it does; a refresh asked for while one is underway is dropped.
+// A source that fails keeps its previous stations, which marks the catalog
+// stale; with nothing to show at all the failure is surfaced to clients as
+// an error. Failing to reach a source at all also marks the server offline
+// and retries every offlineRetryInterval until a refresh gets through.
 func (s *Server) refreshCatalog() {
-	chs, err := channels.FetchChannelsFromNetwork(s.userAgent)
-	if err != nil {
-		log.Printf("channel refresh failed: %v", err)
-		s.mu.Lock()
+	s.mu.Lock()
+	if s.catalogFetching {
+		s.mu.Unlock()
+		return
+	}
+	s.catalogFetching = true
+	s.broadcastChannelsLocked()
+	s.mu.Unlock()
+
+	fetched := make(map[string]*channels.Channels, len(s.sources))
+	var errs []error
+	for _, src := range s.sources {
+		c, err := src.Fetch(s.userAgent)
+		if err != nil {
+			log.Printf("channel refresh failed: %v", err)
+			errs = append(errs, err)
+			continue
+		}
+		fetched[src.Name()] = c
+	}
+	err := errors.Join(errs...)
+
+	s.mu.Lock()
+	s.catalogFetching = false
+	if len(fetched) > 0 {
+		s.installCatalogsLocked(fetched)
+	}
+	wentOffline := false
+	if err == nil {
+		s.clearCatalogErrorLocked()
+	} else {
+		offline := channels.IsUnreachable(err)
+		wentOffline = offline && !s.catalogOffline
+		s.catalogOffline = offline
 		if len(s.catalog) == 0 {
 			s.catalogErr = err.Error()
-			s.broadcastChannelsLocked()
+		} else {
+			s.catalogStale = true
+			s.catalogErr = ""
+		}
+	}
+	s.broadcastChannelsLocked()
+	s.mu.Unlock()
+	if wentOffline {
+		go s.retryWhileOffline()
+	}
+}
+
+// RefreshChannels downloads the catalog, and probes its streams if probing
+// is on, now rather than at the next periodic refresh. It returns at once;
+// the outcome reaches clients as channels events.
+func (s *Server) RefreshChannels() {
+	go func() {
+		s.refreshCatalog()
+		if s.probeInterval > 0 {
+			s.probeChannels()
 		}
+	}()
+}
+
+// retryWhileOffline refreshes the catalog every offlineRetryInterval until
+// SomaFM can be reached again or the server shuts down; refreshLoop's
+// slower pace takes over from there.
+func (s *Server) retryWhileOffline() {
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-time.After(offlineRetryInterval):
+		}
+		s.refreshCatalog()
+		s.mu.Lock()
+		offline := s.catalogOffline
 		s.mu.Unlock()
-		return
+		if !offline {
+			return
+		}
 	}
-	s.setCatalog(chs.Channels)
 }
 
-func (s *Server) setCatalog(chs []channels.Channel) {
+// setCatalog installs SomaFM's catalog; see setCatalogs.
+func (s *Server) setCatalog(c *channels.Channels) {
+	s.setCatalogs(map[string]*channels.Channels{directory.SomaFMName: c})
+}
+
+// setCatalogs installs freshly loaded catalogs of the sources they are
+// keyed by, clearing any refresh failure, and notifies all clients.
+func (s *Server) setCatalogs(updates map[string]*channels.Channels) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.installCatalogsLocked(updates)
+	s.clearCatalogErrorLocked()
+	s.broadcastChannelsLocked()
+}
+
+// installCatalogsLocked replaces the catalogs of the sources updates is
+// keyed by and rebuilds the merged catalog from every source's latest one,
+// in source order, with when it was last updated upstream (the newest
+// source's) and downloaded (the oldest source's), zero if unknown, and how
+// its listener counts moved since the one it replaces.
+func (s *Server) installCatalogsLocked(updates map[string]*channels.Channels) {
+	for name, c := range updates {
+		s.sourceCatalogs[name] = c
+	}
+	var (
+		chs              []channels.Channel
+		updated, fetched time.Time
+	)
+	for _, src := range s.sources {
+		c := s.sourceCatalogs[src.Name()]
+		if c == nil {
+			continue
+		}
+		chs = append(chs, c.Channels...)
+		if c.Updated.After(updated) {
+			updated = c.Updated
+		}
+		if !c.Fetched.IsZero() && (fetched.IsZero() || c.Fetched.Before(fetched)) {
+			fetched = c.Fetched
+		}
+	}
+	s.listenerTrends = channels.ListenerTrends(s.catalog, chs)
+	if i := slices.IndexFunc(chs, func(ch channels.Channel) bool { return ch.ID == s.channelID }); i >= 0 &&
+		s.status != protocol.StatusStopped && s.listeners != chs[i].Listeners {
+		s.listeners = chs[i].Listeners
+		s.broadcastStateLocked()
+	}
 	s.catalog = sortChannelsWithFavorites(chs, s.st.FavoriteChannelIDs)
+	s.catalogUpdated = updated
+	s.catalogFetched = fetched
+}
+
+// clearCatalogErrorLocked forgets a refresh failure once every source
+// loaded.
+func (s *Server) clearCatalogErrorLocked() {
+	s.catalogStale = false
+	s.catalogOffline = false
 	s.catalogErr = ""
-	s.broadcastChannelsLocked()
 }
 
-// sortChannelsWithFavorites returns the channels with favorites first, both
-// groups keeping their relative order.
+// sortChannelsWithFavorites returns the channels with favorites first, in
+// the order of the favorites list (the user's pinned order), followed by
+// the rest in their relative order.
 func sortChannelsWithFavorites(chs []channels.Channel, favorites []string) []channels.Channel {
-	fav := make(map[string]bool, len(favorites))
-	for _, id := range favorites {
-		fav[id] = true
+	pos := make(map[string]int, len(favorites))
+	for i, id := range favorites {
+		pos[id] = i
 	}
 	sorted := make([]channels.Channel, 0, len(chs))
 	for _, ch := range chs {
-		if fav[ch.ID] {
+		if _, ok := pos[ch.ID]; ok {
 			sorted = append(sorted, ch)
 		}
 	}
+	slices.SortStableFunc(sorted, func(a, b channels.Channel) int { return pos[a.ID] - pos[b.ID] })
 	for _, ch := range chs {
-		if !fav[ch.ID] {
+		if _, ok := pos[ch.ID]; !ok {
 			sorted = append(sorted, ch)
 		}
 	}
 	return sorted
 }
 
+// findChannelLocked looks a channel up in the catalog, then among the latest
+// directory search results.
 func (s *Server) findChannelLocked(id string) (channels.Channel, bool) {
 	for _, ch := range s.catalog {
 		if ch.ID == id {
 			return ch, true
 		}
 	}
+	for _, ch := range s.stations {
+		if ch.ID == id {
+			return ch, true
+		}
+	}
 	return channels.Channel{}, false
 }
 
@@ -333,18 +613,49 @@ func (s *Server) Snapshot() protocol.PlaybackState {
 
 func (s *Server) snapshotLocked() protocol.PlaybackState {
 	ps := protocol.PlaybackState{
-		Status:      s.status,
-		Volume:      s.player.Volume(),
-		StreamError: s.streamErr,
+		Status:          s.status,
+		Volume:          s.player.Volume(),
+		Muted:           s.muted,
+		StreamError:     s.streamErr,
+		StreamErrorKind: s.streamErrKind,
+		StreamQuality:   s.streamQualityLocked(),
+		Equalizer:       s.equalizerLocked(),
+		ListeningSince:  s.listenSince,
+	}
+	if s.muted || s.preview {
+		ps.Volume = s.st.GetVolume()
 	}
 	if s.status != protocol.StatusStopped {
+		ps.Preview = s.preview
 		ps.ChannelID = s.channelID
 		ps.ChannelTitle = s.channelTitle
-		ps.TrackTitle = s.trackTitle
+		ps.Listeners = s.listeners
+		ps.TrackTitle = s.track.Title
+		ps.TrackArtist = s.track.Artist
+		ps.TrackSong = s.track.Song
+		ps.TrackAlbum = s.trackAlbumLocked()
+		if rec, ok := s.recordingLocked(); ok {
+			ps.TrackYear = rec.Year
+			ps.TrackRecordingID = rec.ID
+			ps.TrackReleaseID = rec.ReleaseID
+			ps.TrackArtistID = rec.ArtistID
+		}
+		ps.TrackLoved = s.track.Title != "" && s.st.IsLoved(s.channelID, s.track.Title)
+		if s.track.Title != "" {
+			ps.TrackStarted = s.trackStarted
+		}
+	}
+	if s.status == protocol.StatusPlaying || s.status == protocol.StatusPaused {
+		ps.Behind = s.player.Behind().Seconds()
 	}
 	if s.status == protocol.StatusReconnecting {
 		ps.ReconnectAttempt = s.reconnectAttempt
 	}
+	if s.status == protocol.StatusStopped && s.streamErr != "" {
+		// Stopped by a failure rather than by request: name the channel so
+		// clients can offer to retry it.
+		ps.FailedChannelID = s.channelID
+	}
 	return ps
 }
 
@@ -362,21 +673,54 @@ func (s *Server) channelsPayloadLocked() protocol.ChannelsPayload {
 		// while the exported ChannelsPayload path marshals the result after
 		// releasing it. s.catalog is only ever replaced wholesale, so it needs
 		// no copy.
-		Favorites:     slices.Clone(s.st.FavoriteChannelIDs),
-		LastChannelID: s.st.LastSelectedChannelID,
-		Error:         s.catalogErr,
+		Favorites:         slices.Clone(s.st.FavoriteChannelIDs),
+		LastChannelID:     s.st.LastSelectedChannelID,
+		FavoritesHintSeen: s.st.FavoritesHintSeen,
+		Sort:              s.st.ChannelSort,
+		Sources:           s.sourceNames(),
+		Source:            s.st.ChannelSource,
+		Updated:           s.catalogUpdated,
+		Fetched:           s.catalogFetched,
+		Stale:             s.catalogStale,
+		Offline:           s.catalogOffline,
+		Refreshing:        s.catalogFetching,
+		Probing:           s.probing,
+		Dead:              s.deadChannels,
+		Recent:            recentChannels(s.st.RecentChannels),
+		Listened:          s.listenedLocked(),
+		Trends:            s.listenerTrends,
+		Error:             s.catalogErr,
+	}
+}
+
+// recentChannels converts the persisted recently played channels for the
+// wire.
+func recentChannels(recent []state.RecentChannel) []protocol.RecentChannel {
+	if len(recent) == 0 {
+		return nil
+	}
+	out := make([]protocol.RecentChannel, len(recent))
+	for i, r := range recent {
+		out[i] = protocol.RecentChannel{ChannelID: r.ChannelID, Played: r.Played}
 	}
+	return out
 }
 
 // ToggleFavorite flips a channel's favorite flag, persists it, re-sorts the
 // catalog, and notifies all clients.
 func (s *Server) ToggleFavorite(channelID string) ([]string, error) {
+	if radiobrowser.IsStationID(channelID) {
+		// Favorites order the SomaFM catalog; a directory station is not in
+		// it and would vanish with the next search anyway.
+		return nil, fmt.Errorf("directory stations cannot be favorited: %s", channelID)
+	}
 	s.mu.Lock()
 	if _, ok := s.findChannelLocked(channelID); !ok {
 		s.mu.Unlock()
 		return nil, fmt.Errorf("unknown channel: %s", channelID)
 	}
 	s.st.ToggleFavorite(channelID)
+	s.st.FavoritesHintSeen = true // the user found favorites on their own
 	stateToSave := s.st.Clone()
 	saveSeq := s.nextSaveSeqLocked()
 	s.catalog = sortChannelsWithFavorites(s.catalog, s.st.FavoriteChannelIDs)
@@ -390,6 +734,103 @@ func (s *Server) ToggleFavorite(channelID string) ([]string, error) {
 	return favorites, nil
 }
 
+// MoveFavorite moves a favorite delta places up (negative) or down
+// (positive) in the pinned order, persists it, re-sorts the catalog, and
+// notifies all clients. A move past either end leaves the order as is.
+func (s *Server) MoveFavorite(channelID string, delta int) ([]string, error) {
+	s.mu.Lock()
+	if !s.st.IsFavorite(channelID) {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("not a favorite: %s", channelID)
+	}
+	if !s.st.MoveFavorite(channelID, delta) {
+		favorites := slices.Clone(s.st.FavoriteChannelIDs)
+		s.mu.Unlock()
+		return favorites, nil
+	}
+	stateToSave := s.st.Clone()
+	saveSeq := s.nextSaveSeqLocked()
+	s.catalog = sortChannelsWithFavorites(s.catalog, s.st.FavoriteChannelIDs)
+	s.broadcastChannelsLocked()
+	favorites := slices.Clone(s.st.FavoriteChannelIDs)
+	s.mu.Unlock()
+
+	s.saveState(saveSeq, stateToSave)
+	return favorites, nil
+}
+
+// DismissFavoritesHint records that the favorites onboarding hint was seen,
+// persists it, and notifies all clients so none shows the hint again.
+func (s *Server) DismissFavoritesHint() {
+	s.mu.Lock()
+	if s.st.FavoritesHintSeen {
+		s.mu.Unlock()
+		return
+	}
+	s.st.FavoritesHintSeen = true
+	stateToSave := s.st.Clone()
+	saveSeq := s.nextSaveSeqLocked()
+	s.broadcastChannelsLocked()
+	s.mu.Unlock()
+
+	s.saveState(saveSeq, stateToSave)
+}
+
+// SetChannelSort persists the channel list order picked in a client and
+// notifies all clients, so they list the channels alike.
+func (s *Server) SetChannelSort(sort string) error {
+	switch sort {
+	case "api", "alphabetical", "listeners", "genre", "recent":
+	default:
+		return fmt.Errorf("unknown channel sort %q (want api, alphabetical, listeners, genre or recent)", sort)
+	}
+	s.mu.Lock()
+	if s.st.ChannelSort == sort {
+		s.mu.Unlock()
+		return nil
+	}
+	s.st.ChannelSort = sort
+	stateToSave := s.st.Clone()
+	saveSeq := s.nextSaveSeqLocked()
+	s.broadcastChannelsLocked()
+	s.mu.Unlock()
+
+	s.saveState(saveSeq, stateToSave)
+	return nil
+}
+
+// SetChannelSource persists the station source picked in a client, or ""
+// for every source merged, and notifies all clients, so they list the same
+// stations.
+func (s *Server) SetChannelSource(source string) error {
+	if source != "" && !slices.Contains(s.sourceNames(), source) {
+		return fmt.Errorf("unknown channel source %q (want one of %s, or empty for all)",
+			source, strings.Join(s.sourceNames(), ", "))
+	}
+	s.mu.Lock()
+	if s.st.ChannelSource == source {
+		s.mu.Unlock()
+		return nil
+	}
+	s.st.ChannelSource = source
+	stateToSave := s.st.Clone()
+	saveSeq := s.nextSaveSeqLocked()
+	s.broadcastChannelsLocked()
+	s.mu.Unlock()
+
+	s.saveState(saveSeq, stateToSave)
+	return nil
+}
+
+// sourceNames returns the names of the catalog's sources in catalog order.
+func (s *Server) sourceNames() []string {
+	names := make([]string, len(s.sources))
+	for i, src := range s.sources {
+		names[i] = src.Name()
+	}
+	return names
+}
+
 // nextSaveSeqLocked stamps a state mutation with a monotonic sequence so
 // saveState can serialize writes and drop out-of-order ones. Caller holds s.mu.
 func (s *Server) nextSaveSeqLocked() uint64 {
@@ -482,7 +923,12 @@ func (s *Server) disarmIdleLocked() {
 	}
 }
 
-// broadcastStateLocked pushes the current playback snapshot to all clients.
+// broadcastStateLocked pushes the current playback snapshot to all clients,
+// followed by the catalog payload if it changed along with the state. The
+// catalog goes second: it is far larger, and a client's writer busy with it
+// would let the next snapshot replace this one before it is sent. For the
+// same reason it waits out connecting and reconnecting, whose snapshots
+// follow each other within milliseconds, until playback settles.
 func (s *Server) broadcastStateLocked() {
 	ev, err := protocol.NewEvent(protocol.EventState, s.snapshotLocked())
 	if err != nil {
@@ -492,11 +938,15 @@ func (s *Server) broadcastStateLocked() {
 	for c := range s.conns {
 		c.sendEvent(ev)
 	}
+	if s.channelsPending && s.status != protocol.StatusConnecting && s.status != protocol.StatusReconnecting {
+		s.broadcastChannelsLocked()
+	}
 }
 
 // broadcastChannelsLocked pushes the catalog payload to all clients and mirrors
 // it into the tray's channel picker.
 func (s *Server) broadcastChannelsLocked() {
+	s.channelsPending = false
 	s.pushChannelsToTrayLocked()
 	ev, err := protocol.NewEvent(protocol.EventChannels, s.channelsPayloadLocked())
 	if err != nil {
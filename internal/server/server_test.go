@@ -3,12 +3,14 @@ package server
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"slices"
 	"sync"
 	"testing"
 	"time"
 
 	"somad/internal/audio"
+	"somad/internal/channels"
 	"somad/internal/platform"
 	"somad/internal/protocol"
 	"somad/internal/state"
@@ -114,7 +116,7 @@ func TestStreamDrop_ReconnectsAndRecovers(t *testing.T) {
 	c := connect(t, s)
 	c.hello()
 
-	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "dronezone"}))
+	c.play("dronezone")
 
 	player.errChan <- errors.New("stream read error")
 
@@ -126,6 +128,81 @@ func TestStreamDrop_ReconnectsAndRecovers(t *testing.T) {
 	})
 }
 
+// mirroredPlaylists makes every playlist resolve to three servers.
+func mirroredPlaylists(t *testing.T) {
+	t.Helper()
+	prev := resolveStreamURLs
+	resolveStreamURLs = func(playlistURL, _ string) ([]string, error) {
+		return []string{playlistURL + "#ice1", playlistURL + "#ice2", playlistURL + "#ice3"}, nil
+	}
+	t.Cleanup(func() { resolveStreamURLs = prev })
+}
+
+func TestPlay_FailsOverToBackupServer(t *testing.T) {
+	s, player := newTestServer(t, Config{})
+	mirroredPlaylists(t)
+	player.failURLs = map[string]error{
+		"http://somafm.com/dronezone.pls#ice1": errors.New("connection refused"),
+	}
+	c := connect(t, s)
+	c.hello()
+
+	st := decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "dronezone"}))
+
+	assert.Equal(t, protocol.StatusPlaying, st.Status)
+	assert.Empty(t, st.StreamError)
+	player.mu.Lock()
+	assert.Equal(t, []string{"http://somafm.com/dronezone.pls#ice2"}, player.playURLs)
+	player.mu.Unlock()
+}
+
+func TestPlay_SomaFMPlaylistMustListAllowedHosts(t *testing.T) {
+	s, player := newTestServer(t, Config{})
+	resolveStreamURLs = func(string, string) ([]string, error) {
+		return []string{"http://stream.example.org/live"}, nil
+	}
+	c := connect(t, s)
+	c.hello()
+
+	resp := c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "dronezone"})
+	assert.Contains(t, resp.Error, "invalid stream URL in playlist")
+	player.mu.Lock()
+	assert.Empty(t, player.playURLs)
+	player.mu.Unlock()
+}
+
+func TestStreamDrop_ReconnectsThroughNextServer(t *testing.T) {
+	prev := reconnectBaseDelay
+	reconnectBaseDelay = time.Millisecond
+	defer func() { reconnectBaseDelay = prev }()
+
+	s, player := newTestServer(t, Config{})
+	mirroredPlaylists(t)
+	go s.watchPlayerErrors()
+	c := connect(t, s)
+	c.hello()
+
+	c.play("dronezone")
+	for range 3 {
+		player.errChan <- errors.New("stream read error")
+		c.waitState("reconnecting", func(st protocol.PlaybackState) bool {
+			return st.Status == protocol.StatusReconnecting
+		})
+		c.waitState("recovered", func(st protocol.PlaybackState) bool {
+			return st.Status == protocol.StatusPlaying
+		})
+	}
+
+	player.mu.Lock()
+	assert.Equal(t, []string{
+		"http://somafm.com/dronezone.pls#ice1",
+		"http://somafm.com/dronezone.pls#ice2",
+		"http://somafm.com/dronezone.pls#ice3",
+		"http://somafm.com/dronezone.pls#ice1",
+	}, player.playURLs, "each drop moves on to the next server, wrapping around")
+	player.mu.Unlock()
+}
+
 func TestStreamDrop_KeepsRetryingUntilRecovery(t *testing.T) {
 	prev := reconnectBaseDelay
 	reconnectBaseDelay = time.Millisecond
@@ -136,7 +213,7 @@ func TestStreamDrop_KeepsRetryingUntilRecovery(t *testing.T) {
 	c := connect(t, s)
 	c.hello()
 
-	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "dronezone"}))
+	c.play("dronezone")
 
 	// Every reconnect attempt fails at the player during the outage.
 	player.setPlayErr(errors.New("connection refused"))
@@ -160,6 +237,107 @@ func TestStreamDrop_KeepsRetryingUntilRecovery(t *testing.T) {
 	})
 }
 
+// TestStreamDrop_CatalogWaitsForPlayback checks that the listening time a
+// drop ends reaches clients once playback resumes, not between the
+// reconnect attempts, where the catalog write would hold up their
+// snapshots.
+func TestStreamDrop_CatalogWaitsForPlayback(t *testing.T) {
+	prev := reconnectBaseDelay
+	reconnectBaseDelay = time.Millisecond
+	defer func() { reconnectBaseDelay = prev }()
+
+	s, player := newTestServer(t, Config{})
+	go s.watchPlayerErrors()
+	c := connect(t, s)
+	c.hello()
+
+	c.play("dronezone")
+	player.setPlayErr(errors.New("connection refused"))
+	player.errChan <- errors.New("stream read error")
+	c.waitState("second attempt", func(st protocol.PlaybackState) bool {
+		return st.Status == protocol.StatusReconnecting && st.ReconnectAttempt == 2
+	})
+	player.setPlayErr(nil)
+
+	var last string
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case ev := <-c.events:
+			if ev.Event == protocol.EventState {
+				var st protocol.PlaybackState
+				require.NoError(t, json.Unmarshal(ev.Data, &st))
+				last = st.Status
+				continue
+			}
+			assert.Equal(t, protocol.StatusPlaying, last, "the catalog follows the recovered playback")
+			return
+		case <-deadline:
+			t.Fatal("timed out waiting for the catalog")
+		}
+	}
+}
+
+func TestStreamDrop_GivesUpAfterConfiguredAttempts(t *testing.T) {
+	prev := reconnectBaseDelay
+	reconnectBaseDelay = time.Millisecond
+	defer func() { reconnectBaseDelay = prev }()
+
+	s, player := newTestServer(t, Config{ReconnectAttempts: 3})
+	go s.watchPlayerErrors()
+	c := connect(t, s)
+	c.hello()
+
+	c.play("dronezone")
+	player.setPlayErr(errors.New("connection refused"))
+	player.errChan <- errors.New("stream read error")
+
+	st := c.waitState("gave up", func(st protocol.PlaybackState) bool {
+		return st.Status == protocol.StatusStopped
+	})
+	assert.Equal(t, "dronezone", st.FailedChannelID)
+	assert.Contains(t, st.StreamError, "connection refused")
+
+	// Stopping acknowledges the failure.
+	st = decodeState(t, c.call(protocol.MethodStop, nil))
+	assert.Empty(t, st.FailedChannelID)
+}
+
+func TestPlay_FatalErrorReportsFailedChannel(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "aacchannel"})
+
+	assert.Equal(t, "aacchannel", s.Snapshot().FailedChannelID)
+}
+
+func TestPlay_ClassifiedFailureReportsKind(t *testing.T) {
+	s, player := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	player.setPlayErr(&audio.StreamError{Kind: audio.StreamOffline, Err: errors.New("unexpected status code: 503")})
+	resp := c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "dronezone"})
+	require.NotEmpty(t, resp.Error)
+	st := s.Snapshot()
+	assert.Equal(t, protocol.StreamErrorOffline, st.StreamErrorKind)
+	assert.Contains(t, st.StreamError, "unexpected status code: 503")
+
+	// An explicit stop clears the error and its class together.
+	st = decodeState(t, c.call(protocol.MethodStop, nil))
+	assert.Empty(t, st.StreamErrorKind)
+}
+
+func TestStreamErrorKind(t *testing.T) {
+	assert.Equal(t, protocol.StreamErrorOffline,
+		streamErrorKind(fmt.Errorf("failed to start playback: %w", &audio.StreamError{Kind: audio.StreamOffline, Err: errors.New("refused")})))
+	assert.Equal(t, protocol.StreamErrorUnsupported,
+		streamErrorKind(&audio.StreamError{Kind: audio.StreamUnsupported, Err: errors.New("bad frame")}))
+	assert.Empty(t, streamErrorKind(errors.New("stream read error")))
+}
+
 func TestReconnectDelay_DoublesThenCaps(t *testing.T) {
 	assert.Equal(t, 2*time.Second, reconnectDelay(1))
 	assert.Equal(t, 4*time.Second, reconnectDelay(2))
@@ -178,7 +356,7 @@ func TestStop_CancelsPendingReconnect(t *testing.T) {
 	c := connect(t, s)
 	c.hello()
 
-	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "dronezone"}))
+	c.play("dronezone")
 	player.errChan <- errors.New("stream read error")
 	c.waitState("reconnecting", func(st protocol.PlaybackState) bool {
 		return st.Status == protocol.StatusReconnecting
@@ -264,20 +442,117 @@ func TestPlayRelative_FromStoppedUsesLastPlayed(t *testing.T) {
 	assert.Equal(t, protocol.StatusPlaying, st.Status)
 }
 
-func TestPlayPause_TogglesBetweenPlayingAndStopped(t *testing.T) {
-	s, _ := newTestServer(t, Config{})
+func TestPlayPause_PausesAndResumesWithoutReconnecting(t *testing.T) {
+	s, player := newTestServer(t, Config{})
 	c := connect(t, s)
 	c.hello()
 
 	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
 
 	st := decodeState(t, c.call(protocol.MethodPlayPause, nil))
-	assert.Equal(t, protocol.StatusStopped, st.Status)
+	assert.Equal(t, protocol.StatusPaused, st.Status)
+	assert.Equal(t, "groovesalad", st.ChannelID)
+	player.mu.Lock()
+	assert.True(t, player.paused)
+	player.mu.Unlock()
+
+	st = decodeState(t, c.call(protocol.MethodPlayPause, nil))
+	assert.Equal(t, protocol.StatusPlaying, st.Status)
+	assert.Equal(t, "groovesalad", st.ChannelID)
+
+	player.mu.Lock()
+	defer player.mu.Unlock()
+	assert.False(t, player.paused)
+	assert.Len(t, player.playURLs, 1, "resuming continues the same stream")
+}
+
+func TestPause_StreamDropReconnectsOnResume(t *testing.T) {
+	s, player := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	decodeState(t, c.call(protocol.MethodPlayPause, nil))
+
+	s.handleStreamError(errors.New("stream read error"))
+	st := s.Snapshot()
+	assert.Equal(t, protocol.StatusPaused, st.Status, "a drop while paused stays paused")
+	assert.Empty(t, st.StreamError)
 
-	// Unpause reconnects to the same channel.
 	st = decodeState(t, c.call(protocol.MethodPlayPause, nil))
 	assert.Equal(t, protocol.StatusPlaying, st.Status)
+	player.mu.Lock()
+	defer player.mu.Unlock()
+	assert.Len(t, player.playURLs, 2, "resume reconnects to the live stream")
+}
+
+func TestSleep_ResumesPlaybackOnWake(t *testing.T) {
+	s, player := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	s.Sleep()
+	assert.Equal(t, protocol.StatusStopped, s.Snapshot().Status)
+
+	s.Wake()
+	st := s.Snapshot()
+	assert.Equal(t, protocol.StatusPlaying, st.Status)
 	assert.Equal(t, "groovesalad", st.ChannelID)
+	player.mu.Lock()
+	assert.Len(t, player.playURLs, 2, "wake reconnects to the live stream")
+	player.mu.Unlock()
+
+	s.Wake()
+	player.mu.Lock()
+	defer player.mu.Unlock()
+	assert.Len(t, player.playURLs, 2, "a second wake does nothing")
+}
+
+func TestSleep_PausedStaysPaused(t *testing.T) {
+	s, player := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	decodeState(t, c.call(protocol.MethodPlayPause, nil))
+	s.Sleep()
+	s.Wake()
+	assert.Equal(t, protocol.StatusPaused, s.Snapshot().Status)
+
+	st := decodeState(t, c.call(protocol.MethodPlayPause, nil))
+	assert.Equal(t, protocol.StatusPlaying, st.Status)
+	player.mu.Lock()
+	defer player.mu.Unlock()
+	assert.Len(t, player.playURLs, 2, "resume reconnects after the suspend")
+}
+
+func TestSleep_PreviewIsNotResumed(t *testing.T) {
+	s, player := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPreview, protocol.PreviewParams{ChannelID: "dronezone"}))
+	s.Sleep()
+	s.Wake()
+	assert.Equal(t, protocol.StatusStopped, s.Snapshot().Status)
+	player.mu.Lock()
+	defer player.mu.Unlock()
+	assert.Len(t, player.playURLs, 1)
+}
+
+func TestPlayCurrent_ResumesPaused(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "dronezone"}))
+	s.Pause()
+
+	st, err := s.PlayCurrent()
+	require.NoError(t, err)
+	assert.Equal(t, protocol.StatusPlaying, st.Status)
+	assert.Equal(t, "dronezone", st.ChannelID)
 }
 
 func TestPlayPause_FromStoppedPlaysLastPlayed(t *testing.T) {
@@ -308,6 +583,208 @@ func TestSetVolume_ClampsAndPersists(t *testing.T) {
 	assert.InDelta(t, 0.4, persisted.GetVolume(), 1e-9)
 }
 
+func TestToggleMute_SilencesAndRestores(t *testing.T) {
+	s, player := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+	decodeState(t, c.call(protocol.MethodSetVolume, protocol.SetVolumeParams{Volume: 0.4}))
+
+	st := decodeState(t, c.call(protocol.MethodToggleMute, nil))
+	assert.True(t, st.Muted)
+	assert.InDelta(t, 0.4, st.Volume, 1e-9, "the level to restore is still reported")
+	assert.InDelta(t, 0.0, player.Volume(), 1e-9)
+
+	st = decodeState(t, c.call(protocol.MethodToggleMute, nil))
+	assert.False(t, st.Muted)
+	assert.InDelta(t, 0.4, player.Volume(), 1e-9)
+
+	// Changing the volume while muted unmutes.
+	decodeState(t, c.call(protocol.MethodToggleMute, nil))
+	st = decodeState(t, c.call(protocol.MethodSetVolume, protocol.SetVolumeParams{Volume: 0.6}))
+	assert.False(t, st.Muted)
+	assert.InDelta(t, 0.6, player.Volume(), 1e-9)
+}
+
+func TestSetStreamQuality_PersistsAndReconnects(t *testing.T) {
+	s, player := newTestServer(t, Config{StreamQuality: "high"})
+	c := connect(t, s)
+	c.hello()
+
+	st := decodeState(t, c.call(protocol.MethodStatus, nil))
+	assert.Equal(t, "high", st.StreamQuality, "the configured default is reported")
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	st = decodeState(t, c.call(protocol.MethodSetQuality, protocol.SetQualityParams{Quality: "low"}))
+	assert.Equal(t, "low", st.StreamQuality)
+
+	// The playing channel is reconnected in the background.
+	assert.Eventually(t, func() bool {
+		player.mu.Lock()
+		defer player.mu.Unlock()
+		return len(player.playURLs) == 2
+	}, time.Second, 5*time.Millisecond, "channel was not replayed")
+
+	persisted, err := state.LoadState()
+	require.NoError(t, err)
+	assert.Equal(t, "low", persisted.StreamQuality)
+
+	resp := c.call(protocol.MethodSetQuality, protocol.SetQualityParams{Quality: "128k"})
+	assert.Contains(t, resp.Error, "unknown stream quality")
+}
+
+func TestSetEqualizer_AppliesAndPersists(t *testing.T) {
+	s, player := newTestServer(t, Config{Equalizer: "bass"})
+	c := connect(t, s)
+	c.hello()
+
+	st := decodeState(t, c.call(protocol.MethodStatus, nil))
+	assert.Equal(t, "bass", st.Equalizer, "the configured default is reported")
+	player.mu.Lock()
+	assert.Equal(t, "bass", player.equalizer, "the default is applied at startup")
+	player.mu.Unlock()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	st = decodeState(t, c.call(protocol.MethodSetEqualizer, protocol.SetEqualizerParams{Preset: "speech"}))
+	assert.Equal(t, "speech", st.Equalizer)
+	assert.Equal(t, protocol.StatusPlaying, st.Status)
+
+	player.mu.Lock()
+	assert.Equal(t, "speech", player.equalizer)
+	assert.Len(t, player.playURLs, 1, "the stream is not reconnected")
+	player.mu.Unlock()
+
+	persisted, err := state.LoadState()
+	require.NoError(t, err)
+	assert.Equal(t, "speech", persisted.Equalizer)
+
+	resp := c.call(protocol.MethodSetEqualizer, protocol.SetEqualizerParams{Preset: "loudness"})
+	assert.Contains(t, resp.Error, "unknown equalizer preset")
+}
+
+func TestPrebuffer_FetchesThePrimaryStream(t *testing.T) {
+	s, player := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	resp := c.call(protocol.MethodPrebuffer, protocol.PrebufferParams{ChannelID: "dronezone"})
+	require.Empty(t, resp.Error)
+	player.mu.Lock()
+	prebuffered := player.prebuffer
+	player.mu.Unlock()
+	require.NotEmpty(t, prebuffered)
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "dronezone"}))
+	player.mu.Lock()
+	assert.Equal(t, []string{prebuffered}, player.playURLs, "play asks for the prebuffered stream")
+	player.mu.Unlock()
+
+	// The playing channel needs no prebuffer; moving on drops it.
+	require.NoError(t, s.Prebuffer(""))
+	require.NoError(t, s.Prebuffer("dronezone"))
+	player.mu.Lock()
+	assert.Empty(t, player.prebuffer)
+	player.mu.Unlock()
+
+	assert.Error(t, s.Prebuffer("nope"))
+}
+
+func TestPreview_PlaysQuietlyAndCommits(t *testing.T) {
+	s, player := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	st := decodeState(t, c.call(protocol.MethodPreview, protocol.PreviewParams{ChannelID: "dronezone"}))
+	assert.Equal(t, protocol.StatusPlaying, st.Status)
+	assert.True(t, st.Preview)
+	assert.InDelta(t, 1, st.Volume, 0.001, "clients see the volume to come back to")
+	player.mu.Lock()
+	assert.InDelta(t, previewVolume, player.volume, 0.001)
+	player.mu.Unlock()
+	persisted, err := state.LoadState()
+	require.NoError(t, err)
+	assert.Empty(t, persisted.LastSelectedChannelID, "a preview is not a pick")
+
+	st = decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "dronezone"}))
+	assert.False(t, st.Preview)
+	player.mu.Lock()
+	assert.InDelta(t, 1, player.volume, 0.001)
+	assert.Len(t, player.playURLs, 1, "playing the previewed channel keeps its stream")
+	player.mu.Unlock()
+	persisted, err = state.LoadState()
+	require.NoError(t, err)
+	assert.Equal(t, "dronezone", persisted.LastSelectedChannelID)
+
+	st = decodeState(t, c.call(protocol.MethodPreview, protocol.PreviewParams{ChannelID: "groovesalad"}))
+	assert.Equal(t, "dronezone", st.ChannelID, "a preview never interrupts playback")
+	st = decodeState(t, c.call(protocol.MethodPreview, protocol.PreviewParams{}))
+	assert.Equal(t, protocol.StatusPlaying, st.Status, "ending no preview stops nothing")
+}
+
+func TestPreview_NeverCutsOffAConcurrentPlay(t *testing.T) {
+	s, player := newTestServer(t, Config{})
+
+	for range 200 {
+		s.Stop()
+		var wg sync.WaitGroup
+		wg.Go(func() { _, _ = s.Play("groovesalad") })
+		wg.Go(func() { _, _ = s.Preview("dronezone") })
+		wg.Wait()
+
+		// Whichever came first, the pick wins: a preview started after it
+		// is refused, one started before it is replaced.
+		st := s.Snapshot()
+		require.Equal(t, "groovesalad", st.ChannelID)
+		require.False(t, st.Preview)
+		player.mu.Lock()
+		volume := player.volume
+		player.mu.Unlock()
+		require.InDelta(t, 1, volume, 0.001)
+	}
+}
+
+func TestPreview_EndingStops(t *testing.T) {
+	s, player := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPreview, protocol.PreviewParams{ChannelID: "dronezone"}))
+	decodeState(t, c.call(protocol.MethodPreview, protocol.PreviewParams{ChannelID: "groovesalad"}))
+	st := decodeState(t, c.call(protocol.MethodPreview, protocol.PreviewParams{}))
+
+	assert.Equal(t, protocol.StatusStopped, st.Status)
+	assert.False(t, st.Preview)
+	player.mu.Lock()
+	assert.Len(t, player.playURLs, 2, "moving on switches the preview")
+	assert.InDelta(t, 1, player.volume, 0.001)
+	player.mu.Unlock()
+}
+
+func TestTimeShift_RewindsAndReturnsToLive(t *testing.T) {
+	s, player := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	st := decodeState(t, c.call(protocol.MethodTimeShift, protocol.TimeShiftParams{Seconds: 30}))
+	assert.Zero(t, st.Behind, "nothing to rewind while stopped")
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	st = decodeState(t, c.call(protocol.MethodTimeShift, protocol.TimeShiftParams{Seconds: 30}))
+	assert.InDelta(t, 30, st.Behind, 0.001)
+	c.waitState("rewind broadcast", func(st protocol.PlaybackState) bool { return st.Behind > 0 })
+
+	st = decodeState(t, c.call(protocol.MethodTimeShift, protocol.TimeShiftParams{Seconds: -10}))
+	assert.InDelta(t, 20, st.Behind, 0.001)
+
+	decodeState(t, c.call(protocol.MethodPlayPause, nil))
+	st = decodeState(t, c.call(protocol.MethodTimeShift, protocol.TimeShiftParams{Live: true}))
+	assert.Equal(t, protocol.StatusPaused, st.Status)
+	assert.Zero(t, st.Behind, "live works while paused too")
+
+	player.mu.Lock()
+	assert.Len(t, player.playURLs, 1, "the stream is not reconnected")
+	player.mu.Unlock()
+}
+
 func TestToggleFavorite_PersistsAndBroadcasts(t *testing.T) {
 	s, _ := newTestServer(t, Config{})
 	c := connect(t, s)
@@ -330,6 +807,109 @@ func TestToggleFavorite_PersistsAndBroadcasts(t *testing.T) {
 	assert.Equal(t, []string{"dronezone"}, persisted.FavoriteChannelIDs)
 }
 
+func TestMoveFavorite_PersistsAndBroadcasts(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+	_, err := s.ToggleFavorite("groovesalad")
+	require.NoError(t, err)
+	_, err = s.ToggleFavorite("aacchannel")
+	require.NoError(t, err)
+	c := connect(t, s)
+	c.hello()
+
+	resp := c.call(protocol.MethodMoveFavorite, protocol.MoveFavoriteParams{ChannelID: "aacchannel", Delta: -1})
+	require.Empty(t, resp.Error)
+	var result protocol.FavoritesResult
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	assert.Equal(t, []string{"aacchannel", "groovesalad"}, result.Favorites)
+
+	payload := c.waitChannels("after move")
+	ids := make([]string, len(payload.Channels))
+	for i, ch := range payload.Channels {
+		ids[i] = ch.ID
+	}
+	assert.Equal(t, []string{"aacchannel", "groovesalad", "dronezone"}, ids)
+
+	persisted, err := state.LoadState()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aacchannel", "groovesalad"}, persisted.FavoriteChannelIDs)
+
+	// The pinned order survives a catalog refresh.
+	s.setCatalog(&channels.Channels{Channels: testChannels()})
+	assert.Equal(t, "aacchannel", s.ChannelsPayload().Channels[0].ID)
+}
+
+func TestMoveFavorite_NotAFavorite(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+
+	_, err := s.MoveFavorite("dronezone", -1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a favorite")
+}
+
+func TestPlay_RecordsRecentChannels(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+
+	_, err := s.Play("groovesalad")
+	require.NoError(t, err)
+	_, err = s.Play("dronezone")
+	require.NoError(t, err)
+
+	recent := s.ChannelsPayload().Recent
+	require.Len(t, recent, 2)
+	assert.Equal(t, "dronezone", recent[0].ChannelID)
+	assert.Equal(t, "groovesalad", recent[1].ChannelID)
+	assert.False(t, recent[0].Played.IsZero())
+
+	require.Eventually(t, func() bool {
+		persisted, err := state.LoadState()
+		return err == nil && len(persisted.RecentChannels) == 2
+	}, 2*time.Second, 10*time.Millisecond, "recently played channels are persisted")
+}
+
+func TestDismissFavoritesHint_PersistsAndBroadcasts(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+	require.False(t, s.ChannelsPayload().FavoritesHintSeen)
+
+	resp := c.call(protocol.MethodDismissHint, nil)
+	require.Empty(t, resp.Error)
+
+	payload := c.waitChannels("after dismissal")
+	assert.True(t, payload.FavoritesHintSeen)
+	persisted, err := state.LoadState()
+	require.NoError(t, err)
+	assert.True(t, persisted.FavoritesHintSeen)
+}
+
+func TestSetChannelSort_PersistsAndBroadcasts(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	resp := c.call(protocol.MethodSetSort, protocol.SetSortParams{Sort: "listeners"})
+	require.Empty(t, resp.Error)
+
+	payload := c.waitChannels("after sorting")
+	assert.Equal(t, "listeners", payload.Sort)
+	persisted, err := state.LoadState()
+	require.NoError(t, err)
+	assert.Equal(t, "listeners", persisted.ChannelSort)
+
+	assert.Empty(t, c.call(protocol.MethodSetSort, protocol.SetSortParams{Sort: "recent"}).Error)
+	resp = c.call(protocol.MethodSetSort, protocol.SetSortParams{Sort: "random"})
+	assert.Contains(t, resp.Error, "unknown channel sort")
+}
+
+func TestToggleFavorite_RetiresFavoritesHint(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+
+	_, err := s.ToggleFavorite("dronezone")
+	require.NoError(t, err)
+
+	assert.True(t, s.ChannelsPayload().FavoritesHintSeen)
+}
+
 func TestToggleFavorite_UnknownChannel(t *testing.T) {
 	s, _ := newTestServer(t, Config{})
 	c := connect(t, s)
@@ -519,6 +1099,58 @@ func TestTrackUpdate_BroadcastsTitle(t *testing.T) {
 	assert.Equal(t, protocol.StatusPlaying, st.Status)
 }
 
+func TestTrackStarted_ResetsOnNewTitleOnly(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	assert.True(t, s.Snapshot().TrackStarted.IsZero(), "no title, no start")
+
+	before := time.Now()
+	s.handleTrackUpdate(audio.TrackInfo{Title: "One"})
+	started := s.Snapshot().TrackStarted
+	assert.False(t, started.Before(before))
+
+	time.Sleep(2 * time.Millisecond)
+	s.handleTrackUpdate(audio.TrackInfo{Title: "One"}) // re-reported after a reconnect
+	assert.Equal(t, started, s.Snapshot().TrackStarted)
+
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Two"})
+	assert.True(t, s.Snapshot().TrackStarted.After(started))
+}
+
+func TestHistory_RecordsTracksNewestFirst(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	s.handleTrackUpdate(audio.TrackInfo{Title: "One"})
+	s.handleTrackUpdate(audio.TrackInfo{Title: "One"}) // re-reported after a reconnect
+	s.handleTrackUpdate(audio.TrackInfo{Title: ""})
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "dronezone"}))
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Two"})
+
+	var result protocol.HistoryResult
+	resp := c.call(protocol.MethodHistory, nil)
+	require.Empty(t, resp.Error)
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	require.Len(t, result.Tracks, 2)
+	assert.Equal(t, "Two", result.Tracks[0].Title)
+	assert.Equal(t, "dronezone", result.Tracks[0].ChannelID)
+	assert.Equal(t, "One", result.Tracks[1].Title)
+	assert.Equal(t, "groovesalad", result.Tracks[1].ChannelID)
+	assert.False(t, result.Tracks[1].Time.IsZero())
+
+	for i := range historySize + 5 {
+		s.handleTrackUpdate(audio.TrackInfo{Title: fmt.Sprint(i)})
+	}
+	tracks := s.History().Tracks
+	assert.Len(t, tracks, historySize)
+	assert.Equal(t, fmt.Sprint(historySize+4), tracks[0].Title)
+}
+
 func TestIdleExit_FiresWhenStoppedAndNoClients(t *testing.T) {
 	s, _ := newTestServer(t, Config{IdleTimeout: 30 * time.Millisecond})
 
@@ -587,3 +1219,77 @@ func TestUnknownMethod(t *testing.T) {
 
 	assert.Contains(t, resp.Error, "unknown method")
 }
+
+func TestLevel_OnlyWhilePlaying(t *testing.T) {
+	s, player := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+	player.mu.Lock()
+	player.level = 0.3
+	player.mu.Unlock()
+
+	var result protocol.LevelResult
+	require.NoError(t, json.Unmarshal(c.call(protocol.MethodLevel, nil).Result, &result))
+	assert.Zero(t, result.Level, "nothing is playing")
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	require.NoError(t, json.Unmarshal(c.call(protocol.MethodLevel, nil).Result, &result))
+	assert.InDelta(t, 0.3, result.Level, 1e-9)
+}
+
+func TestSpectrum_OnlyWhilePlaying(t *testing.T) {
+	s, player := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+	player.mu.Lock()
+	player.spectrum = []float64{0.1, 0.5, 0.9}
+	player.mu.Unlock()
+
+	var result protocol.SpectrumResult
+	require.NoError(t, json.Unmarshal(c.call(protocol.MethodSpectrum, protocol.SpectrumParams{Bands: 3}).Result, &result))
+	assert.Empty(t, result.Bands, "nothing is playing")
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	require.NoError(t, json.Unmarshal(c.call(protocol.MethodSpectrum, protocol.SpectrumParams{Bands: 3}).Result, &result))
+	assert.Equal(t, []float64{0.1, 0.5, 0.9}, result.Bands)
+
+	resp := c.call(protocol.MethodSpectrum, protocol.SpectrumParams{Bands: 0})
+	assert.Contains(t, resp.Error, "bands must be between 1 and")
+}
+
+func TestStats_ReportStreamAndReconnects(t *testing.T) {
+	prev := reconnectBaseDelay
+	reconnectBaseDelay = time.Millisecond
+	defer func() { reconnectBaseDelay = prev }()
+
+	s, player := newTestServer(t, Config{})
+	go s.watchPlayerErrors()
+	c := connect(t, s)
+	c.hello()
+	connected := time.Now().Truncate(time.Second)
+	player.mu.Lock()
+	player.stats = audio.Stats{BytesReceived: 4096, Connected: connected, Buffered: 512, BufferSize: 1 << 20}
+	player.mu.Unlock()
+
+	var result protocol.StatsResult
+	require.NoError(t, json.Unmarshal(c.call(protocol.MethodStats, nil).Result, &result))
+	assert.Zero(t, result.BytesReceived, "nothing is playing")
+
+	c.play("groovesalad")
+	player.errChan <- errors.New("stream read error")
+	c.waitState("reconnecting", func(st protocol.PlaybackState) bool {
+		return st.Status == protocol.StatusReconnecting
+	})
+	c.waitState("recovered", func(st protocol.PlaybackState) bool {
+		return st.Status == protocol.StatusPlaying
+	})
+
+	require.NoError(t, json.Unmarshal(c.call(protocol.MethodStats, nil).Result, &result))
+	assert.WithinDuration(t, connected, result.ConnectedAt, 0)
+	result.ConnectedAt = time.Time{}
+	assert.Equal(t, protocol.StatsResult{BytesReceived: 4096, Reconnects: 1, Buffered: 512, BufferSize: 1 << 20}, result)
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "dronezone"}))
+	require.NoError(t, json.Unmarshal(c.call(protocol.MethodStats, nil).Result, &result))
+	assert.Zero(t, result.Reconnects, "picking a channel starts the count over")
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"somad/internal/audio"
+	"somad/internal/channels"
+	"somad/internal/protocol"
+)
+
+// fetchSongs lists a SomaFM channel's recent songs. A variable so tests
+// can stub the network out.
+var fetchSongs = channels.FetchSongs
+
+// songsPollInterval is how often the playing channel's song list is
+// fetched. SomaFM updates it as each song starts; songs run minutes long.
+var songsPollInterval = 20 * time.Second
+
+// maxRecentSongs caps the songs RecentSongs returns; the API lists about
+// twenty, more than a glance at a channel's mood needs.
+const maxRecentSongs = 10
+
+// RecentSongs lists the songs a SomaFM channel played last, newest first,
+// so a channel can be judged before it is played. Directory stations have
+// no song list.
+func (s *Server) RecentSongs(channelID string) (protocol.RecentSongsResult, error) {
+	s.mu.Lock()
+	ch, ok := s.findChannelLocked(channelID)
+	s.mu.Unlock()
+	if !ok {
+		return protocol.RecentSongsResult{}, fmt.Errorf("unknown channel: %s", channelID)
+	}
+	if ch.StreamURL != "" {
+		return protocol.RecentSongsResult{}, fmt.Errorf("no song list for %s", channelID)
+	}
+	songs, err := fetchSongs(channelID, s.userAgent)
+	if err != nil {
+		return protocol.RecentSongsResult{}, err
+	}
+	result := protocol.RecentSongsResult{ChannelID: channelID, Songs: []protocol.SongEntry{}}
+	for _, song := range songs[:min(len(songs), maxRecentSongs)] {
+		result.Songs = append(result.Songs, protocol.SongEntry{
+			Time: song.Played, Title: song.Title, Artist: song.Artist, Album: song.Album,
+		})
+	}
+	return result, nil
+}
+
+// pollSongs keeps the track of a playing SomaFM channel up to date from
+// the songs API until play generation gen ends. While the API answers, its
+// songs stand in for the stream's ICY titles, adding the album; when it
+// fails, the latest ICY title takes over again. fetch and interval are
+// passed in so the goroutine never reads the package variables tests swap.
+func (s *Server) pollSongs(gen uint64, channelID string, fetch func(channelID, userAgent string) ([]channels.Song, error), interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		songs, err := fetch(channelID, s.userAgent)
+		if !s.applySongs(gen, channelID, songs, err) {
+			return
+		}
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// applySongs publishes the newest of songs as the playing track, or falls
+// back to the stream's metadata when the fetch failed. It reports false
+// once gen is superseded, ending the poll.
+func (s *Server) applySongs(gen uint64, channelID string, songs []channels.Song, err error) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if gen != s.playGen {
+		return false
+	}
+	if s.status != protocol.StatusPlaying && s.status != protocol.StatusPaused {
+		return true
+	}
+	if err != nil || len(songs) == 0 || songs[0].Title == "" {
+		if s.songsLive {
+			log.Printf("songs API unavailable for %s, using stream metadata: %v", channelID, err)
+			s.songsLive = false
+			s.setTrackLocked(s.icyTrack)
+		}
+		return true
+	}
+	s.songsLive = true
+	if ti := songTrackInfo(songs[0]); ti != s.track {
+		s.setTrackLocked(ti)
+	}
+	return true
+}
+
+// songTrackInfo describes a song from the songs API like a stream title:
+// "Artist - Song", split.
+func songTrackInfo(song channels.Song) audio.TrackInfo {
+	ti := audio.TrackInfo{Title: song.Title, Artist: song.Artist, Song: song.Title, Album: song.Album}
+	if song.Artist != "" {
+		ti.Title = song.Artist + " - " + song.Title
+	}
+	return ti
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"somad/internal/audio"
+	"somad/internal/channels"
+	"somad/internal/protocol"
+	"somad/internal/radiobrowser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSongs replaces the songs API for one test with a list the test can
+// change, polled every few milliseconds.
+type stubSongs struct {
+	mu    sync.Mutex
+	songs []channels.Song
+	err   error
+}
+
+func (f *stubSongs) set(songs []channels.Song, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.songs, f.err = songs, err
+}
+
+func newStubSongs(t *testing.T) *stubSongs {
+	t.Helper()
+	f := &stubSongs{err: errors.New("not yet")}
+	prevFetch, prevInterval := fetchSongs, songsPollInterval
+	fetchSongs = func(string, string) ([]channels.Song, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return f.songs, f.err
+	}
+	songsPollInterval = 5 * time.Millisecond
+	t.Cleanup(func() { fetchSongs, songsPollInterval = prevFetch, prevInterval })
+	return f
+}
+
+// waitForTrack waits until the server's playing track is title.
+func waitForTrack(t *testing.T, s *Server, title string) protocol.PlaybackState {
+	t.Helper()
+	var st protocol.PlaybackState
+	require.Eventually(t, func() bool {
+		st = s.Snapshot()
+		return st.TrackTitle == title
+	}, 2*time.Second, 5*time.Millisecond, "track should become %q", title)
+	return st
+}
+
+func TestSongsAPI_SuppliesTheTrack(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+	songs := newStubSongs(t)
+	songs.set([]channels.Song{
+		{Title: "Awake", Artist: "Tycho", Album: "Awake"},
+		{Title: "Kerala", Artist: "Bonobo", Album: "Migration"},
+	}, nil)
+	c := connect(t, s)
+	c.hello()
+
+	decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "groovesalad"}))
+	st := waitForTrack(t, s, "Tycho - Awake")
+	assert.Equal(t, "Tycho", st.TrackArtist)
+	assert.Equal(t, "Awake", st.TrackSong)
+	assert.Equal(t, "Awake", st.TrackAlbum)
+
+	// The stream's own title waits while the API answers.
+	s.handleTrackUpdate(audio.TrackInfo{Title: "Tycho - Awake (edit)", Artist: "Tycho", Song: "Awake (edit)"})
+	assert.Equal(t, "Tycho - Awake", s.Snapshot().TrackTitle)
+
+	songs.set(nil, errors.New("503"))
+	st = waitForTrack(t, s, "Tycho - Awake (edit)")
+	assert.Empty(t, st.TrackAlbum)
+
+	songs.set([]channels.Song{{Title: "Kerala", Artist: "Bonobo", Album: "Migration"}}, nil)
+	waitForTrack(t, s, "Bonobo - Kerala")
+
+	history := s.History().Tracks
+	require.Len(t, history, 3)
+	assert.Equal(t, "Bonobo - Kerala", history[0].Title)
+}
+
+func TestSongsAPI_NotForDirectoryStations(t *testing.T) {
+	stubDirectory(t, []radiobrowser.Station{
+		{UUID: "abc", Name: "Jazz One", URLResolved: "http://jazz.example.org/live"},
+	}, nil)
+	s, _ := newTestServer(t, Config{})
+	songs := newStubSongs(t)
+	songs.set([]channels.Song{{Title: "Awake", Artist: "Tycho"}}, nil)
+	c := connect(t, s)
+	c.hello()
+
+	require.Empty(t, c.call(protocol.MethodSearchStations, protocol.SearchStationsParams{Query: "jazz"}).Error)
+	st := decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "rb:abc"}))
+	require.Equal(t, protocol.StatusPlaying, st.Status)
+	time.Sleep(30 * time.Millisecond)
+	assert.Empty(t, s.Snapshot().TrackTitle, "stations outside SomaFM have no song list")
+	assert.Contains(t, c.call(protocol.MethodRecentSongs, protocol.RecentSongsParams{ChannelID: "rb:abc"}).Error, "no song list")
+}
+
+func TestRecentSongs_ListsTheNewestSongs(t *testing.T) {
+	s, _ := newTestServer(t, Config{})
+	songs := newStubSongs(t)
+	at := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+	var list []channels.Song
+	for i := range 15 {
+		list = append(list, channels.Song{
+			Title: fmt.Sprintf("Song %d", i), Artist: "Tycho", Album: "Awake",
+			Played: at.Add(-time.Duration(i) * 4 * time.Minute),
+		})
+	}
+	songs.set(list, nil)
+	c := connect(t, s)
+	c.hello()
+
+	resp := c.call(protocol.MethodRecentSongs, protocol.RecentSongsParams{ChannelID: "groovesalad"})
+	require.Empty(t, resp.Error)
+	var result protocol.RecentSongsResult
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	assert.Equal(t, "groovesalad", result.ChannelID)
+	require.Len(t, result.Songs, maxRecentSongs)
+	assert.Equal(t, protocol.SongEntry{Time: at, Title: "Song 0", Artist: "Tycho", Album: "Awake"}, result.Songs[0])
+	assert.Equal(t, "Song 9", result.Songs[9].Title)
+	assert.Equal(t, protocol.StatusStopped, s.Snapshot().Status, "listing plays nothing")
+
+	resp = c.call(protocol.MethodRecentSongs, protocol.RecentSongsParams{ChannelID: "nope"})
+	assert.Contains(t, resp.Error, "unknown channel")
+
+	songs.set(nil, errors.New("503"))
+	resp = c.call(protocol.MethodRecentSongs, protocol.RecentSongsParams{ChannelID: "groovesalad"})
+	assert.Contains(t, resp.Error, "503")
+}
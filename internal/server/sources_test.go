@@ -0,0 +1,136 @@
+package server
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"somad/internal/channels"
+	"somad/internal/directory"
+	"somad/internal/protocol"
+	"somad/internal/state"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource is a station source whose cache and network are in memory.
+type fakeSource struct {
+	name string
+
+	mu       sync.Mutex
+	cached   *channels.Channels
+	fetched  *channels.Channels
+	fetchErr error
+}
+
+func (f *fakeSource) Name() string { return f.name }
+
+func (f *fakeSource) Cached() (*channels.Channels, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.cached == nil {
+		return nil, errors.New("no cache")
+	}
+	return f.cached, nil
+}
+
+func (f *fakeSource) Fetch(string) (*channels.Channels, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.fetched, f.fetchErr
+}
+
+func (f *fakeSource) setFetch(c *channels.Channels, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fetched, f.fetchErr = c, err
+}
+
+// newSourcesServer builds a bare Server listing the given sources.
+func newSourcesServer(t *testing.T, sources ...directory.Directory) *Server {
+	t.Helper()
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	s := New(Config{Player: newMockPlayer(), State: &state.State{}, Version: "test", Sources: sources})
+	t.Cleanup(s.Shutdown)
+	return s
+}
+
+func channelIDs(chs []channels.Channel) []string {
+	ids := make([]string, len(chs))
+	for i, ch := range chs {
+		ids[i] = ch.ID
+	}
+	return ids
+}
+
+func TestRefreshCatalog_MergesSourcesInOrder(t *testing.T) {
+	somafm := &fakeSource{name: directory.SomaFMName}
+	somafm.setFetch(&channels.Channels{Channels: []channels.Channel{{ID: "groovesalad"}}}, nil)
+	rb := &fakeSource{name: directory.RadioBrowserName}
+	rb.setFetch(&channels.Channels{Channels: []channels.Channel{{ID: "rb:one"}, {ID: "rb:two"}}}, nil)
+	s := newSourcesServer(t, rb, somafm)
+
+	s.refreshCatalog()
+
+	payload := s.ChannelsPayload()
+	assert.Equal(t, []string{"rb:one", "rb:two", "groovesalad"}, channelIDs(payload.Channels))
+	assert.Equal(t, []string{directory.RadioBrowserName, directory.SomaFMName}, payload.Sources)
+	assert.False(t, payload.Stale)
+}
+
+func TestRefreshCatalog_FailedSourceKeepsItsStations(t *testing.T) {
+	somafm := &fakeSource{name: directory.SomaFMName}
+	somafm.setFetch(&channels.Channels{Channels: []channels.Channel{{ID: "groovesalad"}}}, nil)
+	rb := &fakeSource{name: directory.RadioBrowserName}
+	rb.setFetch(&channels.Channels{Channels: []channels.Channel{{ID: "rb:one"}}}, nil)
+	s := newSourcesServer(t, somafm, rb)
+	s.refreshCatalog()
+
+	somafm.setFetch(&channels.Channels{Channels: []channels.Channel{{ID: "groovesalad"}, {ID: "dronezone"}}}, nil)
+	rb.setFetch(nil, errors.New("unexpected status code from the station directory: 502"))
+	s.refreshCatalog()
+
+	payload := s.ChannelsPayload()
+	assert.Equal(t, []string{"groovesalad", "dronezone", "rb:one"}, channelIDs(payload.Channels),
+		"SomaFM updates while Radio Browser keeps its last stations")
+	assert.True(t, payload.Stale)
+	assert.Empty(t, payload.Error)
+}
+
+func TestLoadCatalog_MergesSourceCaches(t *testing.T) {
+	fresh := time.Now()
+	somafm := &fakeSource{name: directory.SomaFMName,
+		cached: &channels.Channels{Channels: []channels.Channel{{ID: "groovesalad"}}, Fetched: fresh.Add(-time.Minute)}}
+	stations := directory.Stations{channels.Custom("Jazz One", "http://jazz.example.org/live", "", "")}
+	s := newSourcesServer(t, somafm, stations)
+	s.catalogTTL = time.Hour
+
+	s.loadCatalog()
+
+	payload := s.ChannelsPayload()
+	assert.Equal(t, []string{"groovesalad", "custom:jazz-one"}, channelIDs(payload.Channels))
+	assert.WithinDuration(t, fresh.Add(-time.Minute), payload.Fetched, time.Second, "the oldest source's download time")
+	assert.False(t, payload.Refreshing, "fresh caches need no refresh")
+}
+
+func TestSetChannelSource_PersistsAndBroadcasts(t *testing.T) {
+	s, _ := newTestServer(t, Config{Sources: []directory.Directory{directory.SomaFM{}, directory.Stations{}}})
+	c := connect(t, s)
+	c.hello()
+
+	resp := c.call(protocol.MethodSetSource, protocol.SetSourceParams{Source: directory.StationsName})
+	require.Empty(t, resp.Error)
+
+	payload := c.waitChannels("after switching source")
+	assert.Equal(t, directory.StationsName, payload.Source)
+	persisted, err := state.LoadState()
+	require.NoError(t, err)
+	assert.Equal(t, directory.StationsName, persisted.ChannelSource)
+
+	resp = c.call(protocol.MethodSetSource, protocol.SetSourceParams{Source: directory.RadioBrowserName})
+	assert.Contains(t, resp.Error, "unknown channel source", "only configured sources can be picked")
+	resp = c.call(protocol.MethodSetSource, protocol.SetSourceParams{Source: ""})
+	assert.Empty(t, resp.Error, "empty merges every source")
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"strings"
+
+	"somad/internal/channels"
+	"somad/internal/radiobrowser"
+)
+
+// searchDirectory queries the station directory. A variable so tests can
+// avoid the network.
+var searchDirectory = radiobrowser.Search
+
+// SearchStations searches the Radio Browser directory, or lists its most
+// played stations for an empty query, and remembers the results, so a
+// following play request can name one by ID. Each search replaces the
+// previous results; a station that is already playing keeps playing
+// regardless.
+func (s *Server) SearchStations(query string) ([]channels.Channel, error) {
+	query = strings.TrimSpace(query)
+	stations, err := searchDirectory(query, s.userAgent)
+	if err != nil {
+		return nil, err
+	}
+	chs := make([]channels.Channel, len(stations))
+	for i, st := range stations {
+		chs[i] = st.Channel()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stations = chs
+	return chs, nil
+}
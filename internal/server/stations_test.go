@@ -0,0 +1,165 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"somad/internal/channels"
+	"somad/internal/directory"
+	"somad/internal/protocol"
+	"somad/internal/radiobrowser"
+	"somad/internal/state"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDirectory replaces the station directory search for one test.
+func stubDirectory(t *testing.T, stations []radiobrowser.Station, err error) {
+	t.Helper()
+	prev := searchDirectory
+	searchDirectory = func(string, string) ([]radiobrowser.Station, error) {
+		return stations, err
+	}
+	t.Cleanup(func() { searchDirectory = prev })
+}
+
+func TestSearchStations_ResultsArePlayable(t *testing.T) {
+	stubDirectory(t, []radiobrowser.Station{
+		{UUID: "abc", Name: "Jazz One", URLResolved: "http://jazz.example.org/live"},
+	}, nil)
+	s, player := newTestServer(t, Config{})
+	c := connect(t, s)
+	c.hello()
+
+	resp := c.call(protocol.MethodSearchStations, protocol.SearchStationsParams{Query: "jazz"})
+	require.Empty(t, resp.Error)
+
+	resp = c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "rb:abc"})
+	st := decodeState(t, resp)
+
+	assert.Equal(t, protocol.StatusPlaying, st.Status)
+	assert.Equal(t, "Jazz One", st.ChannelTitle)
+	player.mu.Lock()
+	// The direct stream URL is played as-is, without playlist resolution.
+	assert.Equal(t, []string{"http://jazz.example.org/live"}, player.playURLs)
+	player.mu.Unlock()
+}
+
+func TestSearchStations_EmptyQueryListsMostPlayed(t *testing.T) {
+	var asked []string
+	prev := searchDirectory
+	searchDirectory = func(query, _ string) ([]radiobrowser.Station, error) {
+		asked = append(asked, query)
+		return []radiobrowser.Station{{UUID: "top", Name: "Top Hits", URLResolved: "http://hits.example.org/"}}, nil
+	}
+	t.Cleanup(func() { searchDirectory = prev })
+	s, _ := newTestServer(t, Config{})
+
+	chs, err := s.SearchStations("  ")
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{""}, asked)
+	require.Len(t, chs, 1)
+	assert.Equal(t, "rb:top", chs[0].ID)
+}
+
+func TestSearchStations_DirectoryError(t *testing.T) {
+	stubDirectory(t, nil, errors.New("directory down"))
+	s, _ := newTestServer(t, Config{})
+
+	_, err := s.SearchStations("jazz")
+
+	assert.EqualError(t, err, "directory down")
+}
+
+func TestToggleFavorite_RejectsDirectoryStation(t *testing.T) {
+	stubDirectory(t, []radiobrowser.Station{{UUID: "abc", Name: "Jazz", URL: "http://jazz.example.org/"}}, nil)
+	s, _ := newTestServer(t, Config{})
+	_, err := s.SearchStations("jazz")
+	require.NoError(t, err)
+
+	_, err = s.ToggleFavorite("rb:abc")
+
+	assert.Error(t, err)
+	assert.Empty(t, s.ChannelsPayload().Favorites)
+}
+
+func TestCustomStations_LocalStreamRefused(t *testing.T) {
+	stations := directory.Stations{channels.Custom("Router", "http://192.168.1.1/admin", "", "")}
+	s, player := newTestServer(t, Config{Sources: []directory.Directory{directory.SomaFM{}, stations}})
+	s.setCatalogs(map[string]*channels.Channels{directory.StationsName: {Channels: stations}})
+	c := connect(t, s)
+	c.hello()
+
+	resp := c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "custom:router"})
+	assert.Contains(t, resp.Error, "invalid stream URL")
+	player.mu.Lock()
+	assert.Empty(t, player.playURLs)
+	player.mu.Unlock()
+}
+
+func TestCustomStations_ListedAfterCatalogAndPlayable(t *testing.T) {
+	stations := directory.Stations{
+		channels.Custom("Jazz One", "http://jazz.example.org/live", "", "jazz"),
+		channels.Custom("Talk", "http://talk.example.org/listen.pls", "", ""),
+	}
+	s, player := newTestServer(t, Config{Sources: []directory.Directory{directory.SomaFM{}, stations}})
+	s.setCatalogs(map[string]*channels.Channels{directory.StationsName: {Channels: stations}})
+	c := connect(t, s)
+	c.hello()
+
+	payload := s.ChannelsPayload()
+	ids := make([]string, len(payload.Channels))
+	for i, ch := range payload.Channels {
+		ids[i] = ch.ID
+	}
+	assert.Equal(t, []string{"groovesalad", "dronezone", "aacchannel", "custom:jazz-one", "custom:talk"}, ids)
+
+	st := decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "custom:jazz-one"}))
+	assert.Equal(t, protocol.StatusPlaying, st.Status)
+	st = decodeState(t, c.call(protocol.MethodPlay, protocol.PlayParams{ChannelID: "custom:talk"}))
+	assert.Equal(t, "Talk", st.ChannelTitle)
+
+	player.mu.Lock()
+	// A stream URL plays as-is; a .pls URL is resolved like SomaFM's.
+	assert.Equal(t, []string{"http://jazz.example.org/live", "http://talk.example.org/listen.pls#stream"}, player.playURLs)
+	player.mu.Unlock()
+
+	favorites, err := s.ToggleFavorite("custom:talk")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"custom:talk"}, favorites)
+	assert.Equal(t, "custom:talk", s.ChannelsPayload().Channels[0].ID, "favorites go first")
+}
+
+func TestCustomStations_ListedWithoutSomaFMCatalog(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	refreshed := make(chan struct{}, 1)
+	stubChannelsNetwork(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		select {
+		case refreshed <- struct{}{}:
+		default:
+		}
+	})
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	s := New(Config{Player: newMockPlayer(), State: &state.State{}, Version: "test", Sources: []directory.Directory{
+		directory.SomaFM{},
+		directory.Stations{channels.Custom("Jazz One", "http://jazz.example.org/live", "", "")},
+	}})
+	t.Cleanup(s.Shutdown)
+
+	s.loadCatalog()
+
+	payload := s.ChannelsPayload()
+	require.Len(t, payload.Channels, 1)
+	assert.Equal(t, "custom:jazz-one", payload.Channels[0].ID)
+
+	// Let the background refresh of the SomaFM catalog finish before the
+	// stubbed network goes away.
+	<-refreshed
+	require.Eventually(t, func() bool { return !s.ChannelsPayload().Refreshing }, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, payload.Channels, s.ChannelsPayload().Channels, "the failed refresh keeps the stations")
+}
@@ -4,21 +4,70 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime"
 	"slices"
+	"time"
 
 	"somad/internal/atomicfile"
 )
 
 // State holds application state that persists between sessions.
 type State struct {
-	LastSelectedChannelID string   `json:"last_selected_channel_id"`
-	FavoriteChannelIDs    []string `json:"favorite_channel_ids,omitempty"`
+	LastSelectedChannelID string `json:"last_selected_channel_id"`
+	// FavoriteChannelIDs are listed on top in this order, which the user
+	// can rearrange.
+	FavoriteChannelIDs []string `json:"favorite_channel_ids,omitempty"`
 	// Volume is a pointer so an explicit 0 (muted) is distinguishable from
 	// "never set" (which defaults to full volume).
 	Volume *float64 `json:"volume,omitempty"`
+	// FavoritesHintSeen records that the "press f to favorite" hint was
+	// dismissed (or made moot by favoriting), so it is never shown again.
+	FavoritesHintSeen bool `json:"favorites_hint_seen,omitempty"`
+	// StreamQuality is the playlist quality picked in a client ("low",
+	// "high" or "highest"); empty defers to the configured default.
+	StreamQuality string `json:"stream_quality,omitempty"`
+	// Equalizer is the equalizer preset picked in a client; empty defers to
+	// the configured default.
+	Equalizer string `json:"equalizer,omitempty"`
+	// ChannelSort is the channel list order picked in a client ("api",
+	// "alphabetical", "listeners", "genre" or "recent"); empty defers to
+	// the client's configured default.
+	ChannelSort string `json:"channel_sort,omitempty"`
+	// ChannelSource is the station source picked in a client ("somafm",
+	// "radiobrowser" or "stations"); empty lists every source merged.
+	ChannelSource string `json:"channel_source,omitempty"`
+	// LovedTracks are the tracks the user marked as loved, oldest first.
+	LovedTracks []LovedTrack `json:"loved_tracks,omitempty"`
+	// RecentChannels are the last channels played, newest first, at most
+	// MaxRecentChannels of them.
+	RecentChannels []RecentChannel `json:"recent_channels,omitempty"`
+	// ListenedSeconds is how long each channel has been played in total,
+	// in whole seconds, keyed by channel ID.
+	ListenedSeconds map[string]int64 `json:"listened_seconds,omitempty"`
+}
+
+// MaxRecentChannels is how many recently played channels State keeps.
+const MaxRecentChannels = 10
+
+// RecentChannel is a recently played channel and when it was last picked.
+type RecentChannel struct {
+	ChannelID string    `json:"channel_id"`
+	Played    time.Time `json:"played"`
+}
+
+// LovedTrack is a track marked as loved, with the channel it played on and
+// when it was marked. Artist and Song split Title when the stream used the
+// usual "Artist - Song" form.
+type LovedTrack struct {
+	Time      time.Time `json:"time"`
+	ChannelID string    `json:"channel_id"`
+	Channel   string    `json:"channel"`
+	Title     string    `json:"title"`
+	Artist    string    `json:"artist,omitempty"`
+	Song      string    `json:"song,omitempty"`
 }
 
 // Clone returns an independent copy suitable for saving without holding the
@@ -30,6 +79,14 @@ func (s *State) Clone() *State {
 	clone := &State{
 		LastSelectedChannelID: s.LastSelectedChannelID,
 		FavoriteChannelIDs:    slices.Clone(s.FavoriteChannelIDs),
+		FavoritesHintSeen:     s.FavoritesHintSeen,
+		StreamQuality:         s.StreamQuality,
+		Equalizer:             s.Equalizer,
+		ChannelSort:           s.ChannelSort,
+		ChannelSource:         s.ChannelSource,
+		LovedTracks:           slices.Clone(s.LovedTracks),
+		RecentChannels:        slices.Clone(s.RecentChannels),
+		ListenedSeconds:       maps.Clone(s.ListenedSeconds),
 	}
 	if s.Volume != nil {
 		v := *s.Volume
@@ -76,6 +133,75 @@ func (s *State) ToggleFavorite(id string) {
 	s.FavoriteChannelIDs = append(slices.Clone(s.FavoriteChannelIDs), id)
 }
 
+// MoveFavorite moves a favorite delta places towards the front (negative)
+// or back (positive) of the favorites list, stopping at either end, and
+// reports whether it moved. Like ToggleFavorite it is copy-on-write.
+func (s *State) MoveFavorite(id string, delta int) bool {
+	i := slices.Index(s.FavoriteChannelIDs, id)
+	if i < 0 {
+		return false
+	}
+	j := min(max(i+delta, 0), len(s.FavoriteChannelIDs)-1)
+	if j == i {
+		return false
+	}
+	favs := slices.Delete(slices.Clone(s.FavoriteChannelIDs), i, i+1)
+	s.FavoriteChannelIDs = slices.Insert(favs, j, id)
+	return true
+}
+
+// AddRecentChannel records that channelID was played at played, moving it
+// to the front of RecentChannels and dropping the oldest entries beyond
+// MaxRecentChannels. Like ToggleFavorite it is copy-on-write.
+func (s *State) AddRecentChannel(channelID string, played time.Time) {
+	recent := make([]RecentChannel, 0, MaxRecentChannels)
+	recent = append(recent, RecentChannel{ChannelID: channelID, Played: played})
+	for _, r := range s.RecentChannels {
+		if r.ChannelID != channelID && len(recent) < MaxRecentChannels {
+			recent = append(recent, r)
+		}
+	}
+	s.RecentChannels = recent
+}
+
+// AddListened adds d to the time channelID has been played. Like
+// ToggleFavorite it is copy-on-write.
+func (s *State) AddListened(channelID string, d time.Duration) {
+	secs := int64(d.Round(time.Second) / time.Second)
+	if secs <= 0 {
+		return
+	}
+	listened := maps.Clone(s.ListenedSeconds)
+	if listened == nil {
+		listened = make(map[string]int64)
+	}
+	listened[channelID] += secs
+	s.ListenedSeconds = listened
+}
+
+// IsLoved reports whether the track titled title is loved on channelID.
+func (s *State) IsLoved(channelID, title string) bool {
+	return s.lovedIndex(channelID, title) >= 0
+}
+
+// ToggleLoved adds t to the loved tracks, or removes it when the same title
+// is loved on the same channel already, and reports whether it is loved
+// now. Like ToggleFavorite it is copy-on-write.
+func (s *State) ToggleLoved(t LovedTrack) bool {
+	if i := s.lovedIndex(t.ChannelID, t.Title); i >= 0 {
+		s.LovedTracks = slices.Delete(slices.Clone(s.LovedTracks), i, i+1)
+		return false
+	}
+	s.LovedTracks = append(slices.Clone(s.LovedTracks), t)
+	return true
+}
+
+func (s *State) lovedIndex(channelID, title string) int {
+	return slices.IndexFunc(s.LovedTracks, func(t LovedTrack) bool {
+		return t.ChannelID == channelID && t.Title == title
+	})
+}
+
 const (
 	stateFileName = "state.json"
 	appDirName    = "somad"
@@ -2,9 +2,11 @@ package state
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -79,6 +81,7 @@ func TestClone_IsIndependent(t *testing.T) {
 	original := &State{
 		LastSelectedChannelID: "groovesalad",
 		FavoriteChannelIDs:    []string{"dronezone"},
+		FavoritesHintSeen:     true,
 	}
 	original.SetVolume(0.4)
 
@@ -90,6 +93,7 @@ func TestClone_IsIndependent(t *testing.T) {
 	assert.Equal(t, "groovesalad", clone.LastSelectedChannelID)
 	assert.Equal(t, []string{"dronezone"}, clone.FavoriteChannelIDs)
 	assert.InDelta(t, 0.4, clone.GetVolume(), 1e-9)
+	assert.True(t, clone.FavoritesHintSeen)
 }
 
 func TestSaveAndLoadState_WithVolume(t *testing.T) {
@@ -229,6 +233,85 @@ func TestToggleFavorite_DoesNotMutateHandedOutSlices(t *testing.T) {
 	assert.Equal(t, []string{"dronezone", "secretagent"}, state.FavoriteChannelIDs)
 }
 
+func TestMoveFavorite(t *testing.T) {
+	state := &State{FavoriteChannelIDs: []string{"groovesalad", "dronezone", "secretagent"}}
+	before := state.FavoriteChannelIDs
+
+	assert.True(t, state.MoveFavorite("secretagent", -2))
+	assert.Equal(t, []string{"secretagent", "groovesalad", "dronezone"}, state.FavoriteChannelIDs)
+	assert.Equal(t, []string{"groovesalad", "dronezone", "secretagent"}, before,
+		"mutation corrupted a previously handed-out slice")
+
+	assert.True(t, state.MoveFavorite("secretagent", 5), "a move past the end stops there")
+	assert.Equal(t, []string{"groovesalad", "dronezone", "secretagent"}, state.FavoriteChannelIDs)
+
+	assert.False(t, state.MoveFavorite("secretagent", 1))
+	assert.False(t, state.MoveFavorite("lush", -1), "not a favorite")
+}
+
+func TestAddRecentChannel(t *testing.T) {
+	state := &State{}
+	start := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	for i := range MaxRecentChannels + 2 {
+		state.AddRecentChannel(fmt.Sprintf("ch%d", i), start.Add(time.Duration(i)*time.Minute))
+	}
+	require.Len(t, state.RecentChannels, MaxRecentChannels, "the oldest entries are dropped")
+	assert.Equal(t, "ch11", state.RecentChannels[0].ChannelID)
+	assert.Equal(t, "ch2", state.RecentChannels[MaxRecentChannels-1].ChannelID)
+
+	before := state.RecentChannels
+	replay := start.Add(time.Hour)
+	state.AddRecentChannel("ch5", replay)
+	assert.Equal(t, RecentChannel{ChannelID: "ch5", Played: replay}, state.RecentChannels[0])
+	assert.Len(t, state.RecentChannels, MaxRecentChannels, "a replayed channel moves to the front instead of repeating")
+	assert.Equal(t, "ch11", before[0].ChannelID, "mutation corrupted a previously handed-out slice")
+}
+
+func TestAddListened(t *testing.T) {
+	state := &State{}
+
+	state.AddListened("groovesalad", 90*time.Second)
+	before := state.ListenedSeconds
+	state.AddListened("groovesalad", 30*time.Second+400*time.Millisecond)
+	state.AddListened("dronezone", 200*time.Millisecond)
+
+	assert.Equal(t, map[string]int64{"groovesalad": 120}, state.ListenedSeconds, "a listen shorter than a second is not worth recording")
+	assert.Equal(t, map[string]int64{"groovesalad": 90}, before, "mutation corrupted a previously handed-out map")
+}
+
+func TestToggleLoved(t *testing.T) {
+	state := &State{}
+	track := LovedTrack{ChannelID: "groovesalad", Channel: "Groove Salad", Title: "Tycho - Awake"}
+
+	assert.True(t, state.ToggleLoved(track))
+	assert.True(t, state.IsLoved("groovesalad", "Tycho - Awake"))
+	assert.False(t, state.IsLoved("dronezone", "Tycho - Awake"), "loved per channel")
+
+	before := state.LovedTracks
+	assert.False(t, state.ToggleLoved(track))
+	assert.False(t, state.IsLoved("groovesalad", "Tycho - Awake"))
+	assert.Empty(t, state.LovedTracks)
+	assert.Len(t, before, 1, "mutation corrupted a previously handed-out slice")
+}
+
+func TestSaveAndLoadState_WithLovedTracks(t *testing.T) {
+	SetStateDir(t)
+
+	at := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+	original := &State{LovedTracks: []LovedTrack{{
+		Time: at, ChannelID: "groovesalad", Channel: "Groove Salad",
+		Title: "Tycho - Awake", Artist: "Tycho", Song: "Awake",
+	}}}
+	require.NoError(t, SaveState(original))
+
+	loaded, err := LoadState()
+	require.NoError(t, err)
+	require.Len(t, loaded.LovedTracks, 1)
+	assert.True(t, at.Equal(loaded.LovedTracks[0].Time))
+	loaded.LovedTracks[0].Time = at
+	assert.Equal(t, original.LovedTracks, loaded.LovedTracks)
+}
+
 func TestSaveAndLoadState_WithFavorites(t *testing.T) {
 	SetStateDir(t)
 
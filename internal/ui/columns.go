@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// The columns the channel list can show right of the title.
+const (
+	ColumnListeners   = "listeners"
+	ColumnGenre       = "genre"
+	ColumnQuality     = "quality"
+	ColumnLastPlaying = "last_playing"
+)
+
+// Column is one column of the channel list right of the title, and how
+// many cells wide it is; a zero Width takes the column's default.
+type Column struct {
+	Name  string
+	Width int
+}
+
+// columnSpec is what a column shows atop the list and how it aligns.
+type columnSpec struct {
+	header string
+	width  int
+	align  lipgloss.Position
+}
+
+var columnSpecs = map[string]columnSpec{
+	ColumnListeners:   {header: "Listeners", width: 12, align: lipgloss.Right},
+	ColumnGenre:       {header: "Genre", width: 18, align: lipgloss.Left},
+	ColumnQuality:     {header: "Quality", width: 9, align: lipgloss.Left},
+	ColumnLastPlaying: {header: "Last playing", width: 32, align: lipgloss.Left},
+}
+
+// ColumnNames lists the columns the list can show, in their usual order.
+var ColumnNames = []string{ColumnListeners, ColumnGenre, ColumnQuality, ColumnLastPlaying}
+
+// DefaultColumns are the columns the list shows unless configured: the
+// listener count.
+var DefaultColumns = []Column{{Name: ColumnListeners}}
+
+// ListColumns returns the columns named by names, in order, with widths
+// by name; nil names keep DefaultColumns, resized by widths. It returns nil,
+// for DefaultColumns, when neither is given.
+func ListColumns(names []string, widths map[string]int) []Column {
+	if names == nil && len(widths) == 0 {
+		return nil
+	}
+	if names == nil {
+		for _, c := range DefaultColumns {
+			names = append(names, c.Name)
+		}
+	}
+	cols := make([]Column, len(names))
+	for i, name := range names {
+		cols[i] = Column{Name: name, Width: widths[name]}
+	}
+	return cols
+}
+
+// qualityRank orders the stream qualities, best first.
+var qualityRank = []string{"highest", "high", "low"}
+
+// minLeftColumnWidth is the narrowest the title column gets; columns that
+// would squeeze it further are left out, the last first.
+const minLeftColumnWidth = 20
+
+// ColumnLayout is the channel list's columns placed across its width: the
+// title's width and the columns that fit, each with its width.
+type ColumnLayout struct {
+	Title   int
+	Columns []Column
+}
+
+// LayoutColumns places cols across totalWidth: each column gets its width
+// and the title the rest, but at least minLeftColumnWidth. Columns that do
+// not fit are dropped from the end, keeping the first one at least. Unknown
+// columns are skipped, and nil cols means DefaultColumns.
+func LayoutColumns(totalWidth int, cols []Column) ColumnLayout {
+	if cols == nil {
+		cols = DefaultColumns
+	}
+	var layout ColumnLayout
+	used := 0
+	for _, c := range cols {
+		spec, ok := columnSpecs[c.Name]
+		if !ok {
+			continue
+		}
+		if c.Width <= 0 {
+			c.Width = spec.width
+		}
+		layout.Columns = append(layout.Columns, c)
+		used += c.Width
+	}
+	for len(layout.Columns) > 1 && totalWidth-used-4 < minLeftColumnWidth {
+		used -= layout.Columns[len(layout.Columns)-1].Width
+		layout.Columns = layout.Columns[:len(layout.Columns)-1]
+	}
+	layout.Title = max(totalWidth-used-4, minLeftColumnWidth)
+	return layout
+}
+
+// Header renders the column headers, each aligned like its cells; blank
+// leaves out the header of any column named in it.
+func (l ColumnLayout) Header(style lipgloss.Style, blank ...string) string {
+	headers := make([]string, len(l.Columns))
+	for i, c := range l.Columns {
+		text := columnSpecs[c.Name].header
+		if slices.Contains(blank, c.Name) {
+			text = ""
+		}
+		headers[i] = l.cell(style, c, text)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, headers...)
+}
+
+// cell renders text in column c: cut to fit, aligned, with a space before
+// left-aligned text to part it from the column to its left.
+func (l ColumnLayout) cell(style lipgloss.Style, c Column, text string) string {
+	spec := columnSpecs[c.Name]
+	if spec.align == lipgloss.Left {
+		style = style.PaddingLeft(1)
+		text = ansi.Truncate(text, c.Width-1, "…")
+	} else {
+		text = ansi.Truncate(text, c.Width, "…")
+	}
+	return style.Width(c.Width).Align(spec.align).Render(text)
+}
+
+// columnText returns what column name shows for i.
+func columnText(name string, i Item) string {
+	switch name {
+	case ColumnListeners:
+		// Directory stations carry no listener count; leave the column blank.
+		if l := i.Listeners(); l != "" {
+			return l + " ♪"
+		}
+	case ColumnGenre:
+		return i.Genres()
+	case ColumnQuality:
+		return i.BestQuality()
+	case ColumnLastPlaying:
+		return i.Channel.LastPlaying
+	}
+	return ""
+}
+
+// BestQuality returns the best quality among the channel's streams, e.g.
+// "highest", or "" for a station without playlists.
+func (i Item) BestQuality() string {
+	for _, q := range qualityRank {
+		for _, p := range i.Channel.Playlists {
+			if p.Quality == q {
+				return q
+			}
+		}
+	}
+	return ""
+}
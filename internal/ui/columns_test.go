@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayoutColumns_DefaultIsTheListeners(t *testing.T) {
+	layout := LayoutColumns(80, nil)
+	assert.Equal(t, []Column{{Name: ColumnListeners, Width: 12}}, layout.Columns)
+	assert.Equal(t, 80-12-4, layout.Title)
+
+	assert.Equal(t, minLeftColumnWidth, LayoutColumns(20, nil).Title, "the title keeps its minimum")
+}
+
+func TestLayoutColumns_WidthsAndDropping(t *testing.T) {
+	cols := []Column{{Name: ColumnGenre, Width: 10}, {Name: "bitrate"}, {Name: ColumnLastPlaying}}
+	layout := LayoutColumns(100, cols)
+	assert.Equal(t, []Column{{Name: ColumnGenre, Width: 10}, {Name: ColumnLastPlaying, Width: 32}}, layout.Columns,
+		"unknown columns are skipped, zero widths take the default")
+	assert.Equal(t, 100-10-32-4, layout.Title)
+
+	layout = LayoutColumns(60, cols)
+	assert.Equal(t, []Column{{Name: ColumnGenre, Width: 10}}, layout.Columns, "the last column goes first")
+	assert.Equal(t, 60-10-4, layout.Title)
+
+	assert.Len(t, LayoutColumns(20, cols).Columns, 1, "the first column stays")
+	assert.Empty(t, LayoutColumns(80, []Column{}).Columns, "no columns leaves the title alone")
+	assert.Equal(t, 76, LayoutColumns(80, []Column{}).Title)
+}
+
+func TestListColumns(t *testing.T) {
+	assert.Nil(t, ListColumns(nil, nil))
+	assert.Equal(t, []Column{{Name: ColumnListeners, Width: 14}}, ListColumns(nil, map[string]int{ColumnListeners: 14}))
+	assert.Equal(t, []Column{{Name: ColumnQuality}, {Name: ColumnGenre, Width: 20}},
+		ListColumns([]string{ColumnQuality, ColumnGenre}, map[string]int{ColumnGenre: 20, ColumnLastPlaying: 40}))
+	assert.Equal(t, []Column{}, ListColumns([]string{}, nil))
+}
+
+func TestColumnLayout_Header(t *testing.T) {
+	layout := LayoutColumns(100, []Column{{Name: ColumnGenre}, {Name: ColumnListeners}})
+	header := layout.Header(lipgloss.NewStyle())
+	assert.Equal(t, " Genre            "+"   Listeners", header)
+	assert.Equal(t, strings.Repeat(" ", 30), layout.Header(lipgloss.NewStyle(), ColumnGenre, ColumnListeners))
+}
+
+func TestDelegateRender_Columns(t *testing.T) {
+	playingID := ""
+	chs := testChannels()
+	chs[0].Playlists[1].Quality = "highest"
+	chs[1].LastPlaying = "Stars of the Lid - Requiem for Dying Mothers, Part 2"
+	l, delegate := newTestList(chs, &playingID, func(int) bool { return false })
+	delegate.Columns = []Column{{Name: ColumnGenre}, {Name: ColumnQuality}, {Name: ColumnLastPlaying, Width: 20}, {Name: ColumnListeners}}
+	delegate.TrendChecker = func(idx int) int { return map[int]int{1: 7}[idx] }
+	l.SetSize(120, 24)
+
+	var buf bytes.Buffer
+	delegate.Render(&buf, l, 1, l.Items()[1])
+	rows := strings.Split(ansi.Strip(buf.String()), "\n")
+	assert.Contains(t, rows[0], " ambient, space    high     Stars of the Lid -…       500 ♪")
+	assert.True(t, strings.HasSuffix(rows[1], "▲7"), "the trend sits under the listeners")
+	for _, row := range rows {
+		assert.LessOrEqual(t, ansi.StringWidth(row), 120)
+	}
+
+	buf.Reset()
+	delegate.Render(&buf, l, 0, l.Items()[0])
+	rows = strings.Split(ansi.Strip(buf.String()), "\n")
+	assert.Contains(t, rows[0], "highest", "the best of the channel's streams")
+	assert.Equal(t, LayoutColumns(120, delegate.Columns).Title, ansi.StringWidth(rows[1]), "nothing trails a row without a trend")
+}
@@ -3,6 +3,8 @@ package ui
 import (
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 
 	"somad/internal/channels"
 
@@ -24,11 +26,45 @@ func (i Item) Title() string {
 // Description returns the description of the channel for display in the list.
 func (i Item) Description() string { return i.Channel.Description }
 
+// Genres returns the channel's genre tags as a readable list, e.g.
+// "ambient, space".
+func (i Item) Genres() string { return strings.Join(i.Channel.Genres(), ", ") }
+
 // FilterValue returns the title of the channel for filtering purposes.
 func (i Item) FilterValue() string { return i.Channel.Title }
 
-// Listeners returns the listener count for display.
-func (i Item) Listeners() string { return i.Channel.Listeners }
+// Listeners returns the listener count for display with thousands
+// separators, or "" for stations that carry none.
+func (i Item) Listeners() string {
+	if i.Channel.Listeners == 0 {
+		return ""
+	}
+	return FormatCount(i.Channel.Listeners)
+}
+
+// FormatCount renders n with thousands separators, e.g. "12,345".
+func FormatCount(n int) string {
+	s := strconv.Itoa(n)
+	neg := n < 0
+	if neg {
+		s = s[1:]
+	}
+	var b strings.Builder
+	if neg {
+		b.WriteByte('-')
+	}
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// DefaultCustomGlyph prefixes the titles of stations that are not SomaFM
+// channels.
+const DefaultCustomGlyph = "◆"
 
 // StyledDelegate is a custom delegate for styling list items.
 type StyledDelegate struct {
@@ -36,15 +72,28 @@ type StyledDelegate struct {
 	PlayingID       *string
 	MatchChecker    func(int) bool // Function to check if index is a search match
 	FavoriteChecker func(int) bool // Function to check if index is a favorite
+	CustomChecker   func(int) bool // Function to check if index is a non-SomaFM station
+	TrendChecker    func(int) int  // Function returning the listener change at index since the last refresh
+	DeadChecker     func(int) bool // Function to check if index's stream did not answer the last probe
+	// CustomColor and CustomGlyph accent non-SomaFM stations; an empty
+	// glyph leaves only the color.
+	CustomColor lipgloss.TerminalColor
+	CustomGlyph string
+	// ShowGenre, when set and true, puts the genres on the second line
+	// instead of the description.
+	ShowGenre *bool
+	// Columns are the columns right of the title; nil shows
+	// DefaultColumns.
+	Columns []Column
 }
 
 // NewStyledDelegate creates a styled delegate for the list.
-func NewStyledDelegate(playingID *string, matchChecker func(int) bool, favoriteChecker func(int) bool) StyledDelegate {
+func NewStyledDelegate(playingID *string, matchChecker, favoriteChecker, customChecker func(int) bool) StyledDelegate {
 	d := list.NewDefaultDelegate()
 
 	// Normal item styles
 	d.Styles.NormalTitle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
+		Foreground(BrightColor).
 		Padding(0, 0, 0, 2)
 
 	d.Styles.NormalDesc = lipgloss.NewStyle().
@@ -62,10 +111,18 @@ func NewStyledDelegate(playingID *string, matchChecker func(int) bool, favoriteC
 	d.Styles.SelectedDesc = lipgloss.NewStyle().
 		Border(lipgloss.NormalBorder(), false, false, false, true).
 		BorderForeground(PrimaryColor).
-		Foreground(lipgloss.Color("#CCCCCC")).
+		Foreground(TextColor).
 		Padding(0, 0, 0, 1)
 
-	return StyledDelegate{DefaultDelegate: d, PlayingID: playingID, MatchChecker: matchChecker, FavoriteChecker: favoriteChecker}
+	return StyledDelegate{
+		DefaultDelegate: d,
+		PlayingID:       playingID,
+		MatchChecker:    matchChecker,
+		FavoriteChecker: favoriteChecker,
+		CustomChecker:   customChecker,
+		CustomColor:     CustomColor,
+		CustomGlyph:     DefaultCustomGlyph,
+	}
 }
 
 // Render renders a list item with custom styling, including a playing indicator.
@@ -75,58 +132,32 @@ func (d StyledDelegate) Render(w io.Writer, m list.Model, index int, listItem li
 		return
 	}
 
-	// Check if this item is currently playing
-	isPlaying := d.PlayingID != nil && *d.PlayingID == i.Channel.ID
+	isPlaying, isMatch, isCustom, isDead := d.marks(index, i)
 	isSelected := index == m.Index()
-	isMatch := d.MatchChecker != nil && d.MatchChecker(index)
-	isFavorite := d.FavoriteChecker != nil && d.FavoriteChecker(index)
-
-	// Build title with playing/favorite indicator
-	title := i.Title()
-	if isFavorite {
-		title = "♥ " + title
-	}
-	if isPlaying {
-		title = "▶ " + title
-	}
-
-	// Calculate column widths
-	leftColWidth, listenerColWidth := CalculateColumnWidths(m.Width())
-
-	// Listener count styles
-	listenerStyle := lipgloss.NewStyle().
-		Foreground(SubtleColor).
-		Width(listenerColWidth).
-		Align(lipgloss.Right)
-
-	listenerSelectedStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#CCCCCC")).
-		Width(listenerColWidth).
-		Align(lipgloss.Right)
+	title := d.title(index, i)
 
-	listenerPlayingStyle := lipgloss.NewStyle().
-		Foreground(PlayingColor).
-		Width(listenerColWidth).
-		Align(lipgloss.Right)
+	layout := LayoutColumns(m.Width(), d.Columns)
+	leftColWidth := layout.Title
 
-	listenerMatchStyle := lipgloss.NewStyle().
-		Foreground(SearchMatchColor).
-		Width(listenerColWidth).
-		Align(lipgloss.Right)
+	// Column cells take the color of the row's state.
+	cellStyle := lipgloss.NewStyle().Foreground(SubtleColor)
 
 	// Apply styles based on state
-	var titleStr, descStr, listenerStr string
-	listeners := i.Listeners() + " ♪"
+	var titleStr, descStr string
 
 	// Truncate description to prevent wrapping (content area is leftColWidth - 2 for padding)
-	desc := ansi.Truncate(i.Description(), leftColWidth-2, "…")
+	secondary := i.Description()
+	if d.ShowGenre != nil && *d.ShowGenre {
+		secondary = i.Genres()
+	}
+	desc := ansi.Truncate(secondary, leftColWidth-2, "…")
 
 	switch {
 	case isSelected:
 		// Subtract 1 from width to account for left border character
 		titleStr = d.Styles.SelectedTitle.Width(leftColWidth - 1).Render(title)
 		descStr = d.Styles.SelectedDesc.Width(leftColWidth - 1).Render(desc)
-		listenerStr = listenerSelectedStyle.Render(listeners)
+		cellStyle = cellStyle.Foreground(TextColor)
 	case isPlaying:
 		// Playing but not selected - show green indicator
 		playingTitleStyle := lipgloss.NewStyle().
@@ -139,7 +170,7 @@ func (d StyledDelegate) Render(w io.Writer, m list.Model, index int, listItem li
 			Width(leftColWidth)
 		titleStr = playingTitleStyle.Render(title)
 		descStr = playingDescStyle.Render(desc)
-		listenerStr = listenerPlayingStyle.Render(listeners)
+		cellStyle = cellStyle.Foreground(PlayingColor)
 	case isMatch:
 		// Search match - highlight with match color
 		matchTitleStyle := lipgloss.NewStyle().
@@ -152,33 +183,145 @@ func (d StyledDelegate) Render(w io.Writer, m list.Model, index int, listItem li
 			Width(leftColWidth)
 		titleStr = matchTitleStyle.Render(title)
 		descStr = matchDescStyle.Render(desc)
-		listenerStr = listenerMatchStyle.Render(listeners)
+		cellStyle = cellStyle.Foreground(SearchMatchColor)
+	case isDead:
+		// Stream unreachable at the last probe - dimmed
+		deadTitleStyle := d.Styles.NormalTitle.Foreground(SubtleColor).Faint(true)
+		titleStr = deadTitleStyle.Width(leftColWidth).Render(title)
+		descStr = d.Styles.NormalDesc.Faint(true).Width(leftColWidth).Render(desc)
+		cellStyle = cellStyle.Faint(true)
+	case isCustom:
+		// Non-SomaFM station - title in the custom accent
+		customTitleStyle := d.Styles.NormalTitle.Foreground(d.CustomColor)
+		titleStr = customTitleStyle.Width(leftColWidth).Render(title)
+		descStr = d.Styles.NormalDesc.Width(leftColWidth).Render(desc)
 	default:
 		titleStr = d.Styles.NormalTitle.Width(leftColWidth).Render(title)
 		descStr = d.Styles.NormalDesc.Width(leftColWidth).Render(desc)
-		listenerStr = listenerStyle.Render(listeners)
 	}
 
-	// Build two-column layout
-	// Title row with listener count
-	titleRow := lipgloss.JoinHorizontal(lipgloss.Top, titleStr, listenerStr)
-	// Description row (no listener count, just padding to align)
-	descRow := descStr
+	// Title row with the columns; description row with the listener trend
+	// under the count, and nothing past it.
+	titleCells := []string{titleStr}
+	descCells := []string{descStr}
+	descEnd := 1
+	trend := d.trendMark(index)
+	for _, c := range layout.Columns {
+		titleCells = append(titleCells, layout.cell(cellStyle, c, columnText(c.Name, i)))
+		below := ""
+		if c.Name == ColumnListeners && trend != "" {
+			below = trend
+		}
+		descCells = append(descCells, layout.cell(lipgloss.NewStyle(), c, below))
+		if below != "" {
+			descEnd = len(descCells)
+		}
+	}
+	titleRow := lipgloss.JoinHorizontal(lipgloss.Top, titleCells...)
+	descRow := lipgloss.JoinHorizontal(lipgloss.Top, descCells[:descEnd]...)
 
 	_, _ = fmt.Fprintf(w, "%s\n%s", titleRow, descRow)
 }
 
-const (
-	listenerColumnWidth = 12
-	minLeftColumnWidth  = 20
-)
+// marks reports how the item at index stands out: playing, a search
+// match, a non-SomaFM station, or unreachable at the last probe.
+func (d StyledDelegate) marks(index int, i Item) (isPlaying, isMatch, isCustom, isDead bool) {
+	isPlaying = d.PlayingID != nil && *d.PlayingID == i.Channel.ID
+	isMatch = d.MatchChecker != nil && d.MatchChecker(index)
+	isCustom = d.CustomChecker != nil && d.CustomChecker(index)
+	isDead = d.DeadChecker != nil && d.DeadChecker(index)
+	return
+}
 
-// CalculateColumnWidths returns the left and listener column widths for a given total width.
-func CalculateColumnWidths(totalWidth int) (leftCol, listenerCol int) {
-	listenerCol = listenerColumnWidth
-	leftCol = totalWidth - listenerCol - 4
-	if leftCol < minLeftColumnWidth {
-		leftCol = minLeftColumnWidth
+// title returns the item's title with its playing, unreachable, favorite
+// and custom station indicators.
+func (d StyledDelegate) title(index int, i Item) string {
+	isPlaying, _, isCustom, isDead := d.marks(index, i)
+	title := i.Title()
+	if isCustom && d.CustomGlyph != "" {
+		title = d.CustomGlyph + " " + title
 	}
-	return
+	if d.FavoriteChecker != nil && d.FavoriteChecker(index) {
+		title = "♥ " + title
+	}
+	if isDead {
+		title = "⚠ " + title
+	}
+	if isPlaying {
+		title = "▶ " + title
+	}
+	return title
+}
+
+// CardHeight is how many rows RenderCard takes, border included.
+const CardHeight = 5
+
+// RenderCard renders the item at index as a bordered card width columns
+// wide for the grid layout: the title with its indicators, the genres,
+// and the listener count with its trend. The card is colored like the
+// item's row in the list, with the selected one's border lit.
+func (d StyledDelegate) RenderCard(m list.Model, index int, width int) string {
+	i, ok := m.Items()[index].(Item)
+	if !ok {
+		return ""
+	}
+	isPlaying, isMatch, isCustom, isDead := d.marks(index, i)
+	inner := max(width-4, 1) // border and padding
+
+	titleStyle := lipgloss.NewStyle().Foreground(BrightColor)
+	border := SubtleColor
+	switch {
+	case index == m.Index():
+		titleStyle = titleStyle.Foreground(PrimaryColor).Bold(true)
+		border = PrimaryColor
+	case isPlaying:
+		titleStyle = titleStyle.Foreground(PlayingColor)
+	case isMatch:
+		titleStyle = titleStyle.Foreground(SearchMatchColor)
+	case isDead:
+		titleStyle = titleStyle.Foreground(SubtleColor).Faint(true)
+	case isCustom:
+		titleStyle = titleStyle.Foreground(d.CustomColor)
+	}
+	subtle := lipgloss.NewStyle().Foreground(SubtleColor)
+
+	genres := i.Genres()
+	if genres == "" {
+		genres = "—"
+	}
+	listeners := i.Listeners()
+	if listeners != "" {
+		listeners += " ♪"
+	}
+	listeners = subtle.Render(listeners)
+	if mark := d.trendMark(index); mark != "" {
+		listeners += " " + mark
+	}
+
+	lines := []string{
+		titleStyle.Render(ansi.Truncate(d.title(index, i), inner, "…")),
+		subtle.Render(ansi.Truncate(genres, inner, "…")),
+		listeners,
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(border).
+		Padding(0, 1).
+		Width(width - 2).
+		Render(strings.Join(lines, "\n"))
+}
+
+// trendMark renders the listener change at index as "▲12" or "▼3", or ""
+// when it held steady.
+func (d StyledDelegate) trendMark(index int) string {
+	if d.TrendChecker == nil {
+		return ""
+	}
+	switch delta := d.TrendChecker(index); {
+	case delta > 0:
+		return lipgloss.NewStyle().Foreground(PlayingColor).Render(fmt.Sprintf("▲%d", delta))
+	case delta < 0:
+		return lipgloss.NewStyle().Foreground(ErrorColor).Render(fmt.Sprintf("▼%d", -delta))
+	}
+	return ""
 }
@@ -2,11 +2,15 @@ package ui
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	"somad/internal/channels"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,7 +23,7 @@ func newTestListWithFavorites(channelItems []channels.Channel, playingID *string
 	for i, ch := range channelItems {
 		items[i] = Item{Channel: ch}
 	}
-	delegate := NewStyledDelegate(playingID, matchChecker, favoriteChecker)
+	delegate := NewStyledDelegate(playingID, matchChecker, favoriteChecker, func(int) bool { return false })
 	l := list.New(items, delegate, 80, 24)
 	l.SetShowTitle(false)
 	l.SetFilteringEnabled(false)
@@ -33,7 +37,7 @@ func testChannels() []channels.Channel {
 			Title:       "Groove Salad",
 			Description: "A nicely chilled plate of ambient beats",
 			Genre:       "ambient",
-			Listeners:   "1000",
+			Listeners:   1000,
 			Playlists: []channels.Playlist{
 				{URL: "http://somafm.com/groovesalad.pls", Format: "mp3", Quality: "high"},
 				{URL: "http://somafm.com/groovesalad.pls", Format: "aac", Quality: "low"},
@@ -44,7 +48,7 @@ func testChannels() []channels.Channel {
 			Title:       "Drone Zone",
 			Description: "Atmospheric texture and ambient space music",
 			Genre:       "ambient|space",
-			Listeners:   "500",
+			Listeners:   500,
 			Playlists: []channels.Playlist{
 				{URL: "http://somafm.com/dronezone.pls", Format: "mp3", Quality: "high"},
 			},
@@ -54,7 +58,7 @@ func testChannels() []channels.Channel {
 			Title:       "Secret Agent",
 			Description: "The soundtrack for your spy movie marathon",
 			Genre:       "lounge|spy",
-			Listeners:   "750",
+			Listeners:   750,
 			Playlists: []channels.Playlist{
 				{URL: "http://somafm.com/secretagent.pls", Format: "mp3", Quality: "high"},
 			},
@@ -122,6 +126,92 @@ func TestDelegateRender_Favorite(t *testing.T) {
 	assert.Contains(t, output, "♥") // favorite indicator
 }
 
+func TestDelegateRender_CustomStation(t *testing.T) {
+	prev := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	defer lipgloss.SetColorProfile(prev)
+
+	playingID := ""
+	l, delegate := newTestList(testChannels(), &playingID, func(int) bool { return false })
+	delegate.CustomChecker = func(idx int) bool { return idx == 2 }
+	delegate.CustomColor = lipgloss.Color("#00ff00")
+	delegate.CustomGlyph = "+"
+
+	var custom, somafm bytes.Buffer
+	delegate.Render(&custom, l, 2, l.Items()[2]) // Secret Agent is custom, not selected
+	delegate.Render(&somafm, l, 1, l.Items()[1]) // Drone Zone is a SomaFM channel
+
+	accent := "38;2;0;255;0" // truecolor foreground #00ff00
+	assert.Contains(t, custom.String(), "+ Secret Agent")
+	assert.Contains(t, custom.String(), accent)
+	assert.NotContains(t, somafm.String(), "+ ")
+	assert.NotContains(t, somafm.String(), accent)
+}
+
+func TestDelegateRender_CustomGlyphDefault(t *testing.T) {
+	playingID := ""
+	l, delegate := newTestList(testChannels(), &playingID, func(int) bool { return false })
+	delegate.CustomChecker = func(idx int) bool { return idx == 1 }
+
+	var buf bytes.Buffer
+	delegate.Render(&buf, l, 1, l.Items()[1])
+
+	assert.Contains(t, buf.String(), DefaultCustomGlyph+" Drone Zone")
+}
+
+func TestDelegateRender_ListenerTrend(t *testing.T) {
+	playingID := ""
+	l, delegate := newTestList(testChannels(), &playingID, func(int) bool { return false })
+	trends := map[int]int{1: 12, 2: -3}
+	delegate.TrendChecker = func(idx int) int { return trends[idx] }
+
+	render := func(idx int) string {
+		var buf bytes.Buffer
+		delegate.Render(&buf, l, idx, l.Items()[idx])
+		return buf.String()
+	}
+
+	assert.Contains(t, render(1), "▲12")
+	assert.Contains(t, render(2), "▼3")
+	steady := render(0)
+	assert.NotContains(t, steady, "▲")
+	assert.NotContains(t, steady, "▼")
+}
+
+func TestDelegateRender_DeadChannel(t *testing.T) {
+	playingID := ""
+	l, delegate := newTestList(testChannels(), &playingID, func(int) bool { return false })
+	delegate.DeadChecker = func(idx int) bool { return idx == 1 }
+
+	var buf bytes.Buffer
+	delegate.Render(&buf, l, 1, l.Items()[1])
+	assert.Contains(t, buf.String(), "⚠ ")
+
+	buf.Reset()
+	delegate.Render(&buf, l, 2, l.Items()[2])
+	assert.NotContains(t, buf.String(), "⚠")
+}
+
+func TestDelegateRender_GenreAsSecondLine(t *testing.T) {
+	playingID := ""
+	l, delegate := newTestList(testChannels(), &playingID, func(int) bool { return false })
+	showGenre := true
+	delegate.ShowGenre = &showGenre
+
+	var buf bytes.Buffer
+	delegate.Render(&buf, l, 1, l.Items()[1])
+
+	output := buf.String()
+	assert.Contains(t, output, "ambient, space")
+	assert.NotContains(t, output, "Atmospheric texture")
+
+	// The toggle is live: the delegate reads it on every render.
+	showGenre = false
+	buf.Reset()
+	delegate.Render(&buf, l, 1, l.Items()[1])
+	assert.Contains(t, buf.String(), "Atmospheric texture")
+}
+
 func TestDelegateRender_InvalidItem(t *testing.T) {
 	playingID := ""
 	l, delegate := newTestList(testChannels(), &playingID, func(int) bool { return false })
@@ -138,16 +228,54 @@ type mockListItem struct{}
 
 func (m mockListItem) FilterValue() string { return "" }
 
+func TestRenderCard(t *testing.T) {
+	playingID := "dronezone"
+	favoriteChecker := func(idx int) bool { return idx == 1 }
+	l, delegate := newTestListWithFavorites(testChannels(), &playingID, func(int) bool { return false }, favoriteChecker)
+	delegate.TrendChecker = func(idx int) int { return map[int]int{1: 12}[idx] }
+
+	card := ansi.Strip(delegate.RenderCard(l, 1, 24))
+	lines := strings.Split(card, "\n")
+	assert.Len(t, lines, CardHeight)
+	assert.Contains(t, lines[1], "▶ ♥ Drone Zone")
+	assert.Contains(t, lines[2], "ambient, space")
+	assert.Contains(t, lines[3], "500 ♪ ▲12")
+	for _, line := range lines {
+		assert.Equal(t, 24, ansi.StringWidth(line))
+	}
+
+	long := ansi.Strip(delegate.RenderCard(l, 2, 12))
+	assert.Contains(t, long, "│ Secret … │", "titles are cut to the card")
+}
+
 func TestItemMethods(t *testing.T) {
 	ch := channels.Channel{
 		Title:       "Groove Salad",
 		Description: "Ambient beats",
-		Listeners:   "1234",
+		Listeners:   1234,
 	}
 	i := Item{Channel: ch}
 
 	assert.Equal(t, "Groove Salad", i.Title())
 	assert.Equal(t, "Ambient beats", i.Description())
 	assert.Equal(t, "Groove Salad", i.FilterValue())
-	assert.Equal(t, "1234", i.Listeners())
+	assert.Equal(t, "1,234", i.Listeners())
+}
+
+func TestFormatCount(t *testing.T) {
+	for n, want := range map[int]string{
+		0:       "0",
+		7:       "7",
+		999:     "999",
+		1000:    "1,000",
+		12345:   "12,345",
+		1234567: "1,234,567",
+		-4200:   "-4,200",
+	} {
+		assert.Equal(t, want, FormatCount(n))
+	}
+}
+
+func TestItem_ListenersBlankWithoutCount(t *testing.T) {
+	assert.Empty(t, Item{Channel: channels.Channel{Title: "KEXP"}}.Listeners())
 }
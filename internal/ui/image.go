@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ImageProtocol is a terminal graphics protocol.
+type ImageProtocol string
+
+// The supported image protocols. ImageNone draws no images.
+const (
+	ImageNone  ImageProtocol = "none"
+	ImageKitty ImageProtocol = "kitty"
+	ImageSixel ImageProtocol = "sixel"
+	ImageITerm ImageProtocol = "iterm"
+)
+
+// Terminals do not report the pixel size of a cell, so images are scaled
+// for a typical one; kitty and iTerm2 rescale to the cells they are given
+// anyway, so only sixel output depends on the guess.
+const (
+	cellPixelWidth  = 10
+	cellPixelHeight = 20
+)
+
+// kittyChunkSize is the most base64 a kitty graphics escape may carry.
+const kittyChunkSize = 4096
+
+// DetectImageProtocol guesses the image protocol of the terminal from its
+// environment. Inside tmux or screen, which do not pass graphics through
+// by default, it returns ImageNone.
+func DetectImageProtocol() ImageProtocol {
+	term, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("TMUX") != "" || strings.HasPrefix(term, "screen") || strings.HasPrefix(term, "tmux"):
+		return ImageNone
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || term == "xterm-ghostty" || program == "ghostty":
+		return ImageKitty
+	case program == "iTerm.app" || program == "WezTerm" || os.Getenv("LC_TERMINAL") == "iTerm2":
+		return ImageITerm
+	case strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "mlterm") || strings.HasPrefix(term, "contour") ||
+		os.Getenv("KONSOLE_VERSION") != "" || os.Getenv("WT_SESSION") != "":
+		return ImageSixel
+	}
+	return ImageNone
+}
+
+// RenderImage returns the escape sequence that draws img over an area of
+// cols by rows cells when written right after the area's last cell, and
+// leaves the cursor where it was. It is zero cells wide to lipgloss and the
+// renderer, so it can end the area's last line as it is laid out. Drawing
+// last matters: sixel and iTerm2 images are erased by text written over
+// their cells, and the renderer writes lines top to bottom. ImageNone, or
+// an area of no cells, renders nothing.
+func RenderImage(img image.Image, cols, rows int, p ImageProtocol) string {
+	if img == nil || cols <= 0 || rows <= 0 {
+		return ""
+	}
+	scaled := scaleImage(img, cols*cellPixelWidth, rows*cellPixelHeight)
+	var seq string
+	switch p {
+	case ImageKitty:
+		seq = kittyImage(scaled, cols, rows)
+	case ImageITerm:
+		seq = itermImage(scaled, cols, rows)
+	case ImageSixel:
+		seq = sixelImage(scaled)
+	default:
+		return ""
+	}
+	move := ansi.CursorBackward(cols)
+	if rows > 1 {
+		move = ansi.CursorUp(rows-1) + move
+	}
+	return ansi.SaveCursor + move + seq + ansi.RestoreCursor
+}
+
+// ClearImages returns the escape sequence that removes the images drawn
+// with p. Only kitty keeps images apart from the text, where drawing over
+// them does not erase them; for the other protocols it is empty.
+func ClearImages(p ImageProtocol) string {
+	if p != ImageKitty {
+		return ""
+	}
+	return "\x1b_Ga=d,q=2\x1b\\"
+}
+
+// kittyImage transmits img as PNG and places it over cols by rows cells,
+// replacing any image placed before. q=2 keeps the terminal from answering,
+// which would arrive as keyboard input.
+func kittyImage(img image.Image, cols, rows int) string {
+	data := encodePNG(img)
+	var b strings.Builder
+	b.WriteString(ClearImages(ImageKitty))
+	for i := 0; i < len(data); i += kittyChunkSize {
+		chunk := data[i:min(i+kittyChunkSize, len(data))]
+		more := 0
+		if i+kittyChunkSize < len(data) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,q=2,C=1,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return b.String()
+}
+
+// itermImage sends img inline as an iTerm2 file, sized to cols by rows.
+func itermImage(img image.Image, cols, rows int) string {
+	raw := new(bytes.Buffer)
+	_ = png.Encode(raw, img)
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=0:%s\a",
+		raw.Len(), cols, rows, base64.StdEncoding.EncodeToString(raw.Bytes()))
+}
+
+// encodePNG returns img as base64-encoded PNG.
+func encodePNG(img image.Image) string {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, img) // encoding an in-memory RGBA cannot fail
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// scaleImage resizes img to w by h pixels, averaging the source pixels that
+// fall into each target pixel so a large cover shrinks without aliasing.
+func scaleImage(img image.Image, w, h int) *image.RGBA {
+	src := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		y0 := src.Min.Y + y*src.Dy()/h
+		y1 := max(src.Min.Y+(y+1)*src.Dy()/h, y0+1)
+		for x := range w {
+			x0 := src.Min.X + x*src.Dx()/w
+			x1 := max(src.Min.X+(x+1)*src.Dx()/w, x0+1)
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a, n = r+pr, g+pg, b+pb, a+pa, n+1
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i+0] = uint8(r / n >> 8) // #nosec G115 -- an average of 16-bit values, shifted to 8 bits
+			dst.Pix[i+1] = uint8(g / n >> 8) // #nosec G115 -- as above
+			dst.Pix[i+2] = uint8(b / n >> 8) // #nosec G115 -- as above
+			dst.Pix[i+3] = uint8(a / n >> 8) // #nosec G115 -- as above
+		}
+	}
+	return dst
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stretchr/testify/assert"
+)
+
+// noisyImage returns an image that compresses badly, so its PNG spans
+// several kitty chunks.
+func noisyImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	seed := uint32(1)
+	for i := range img.Pix {
+		seed = seed*1664525 + 1013904223
+		img.Pix[i] = uint8(seed >> 24)
+	}
+	return img
+}
+
+func TestRenderImage_IsZeroWidthAndRestoresTheCursor(t *testing.T) {
+	img := noisyImage(8, 8)
+	for _, p := range []ImageProtocol{ImageKitty, ImageSixel, ImageITerm} {
+		t.Run(string(p), func(t *testing.T) {
+			seq := RenderImage(img, 4, 2, p)
+			assert.True(t, strings.HasPrefix(seq, ansi.SaveCursor+ansi.CursorUp(1)+ansi.CursorBackward(4)),
+				"it moves from the area's last cell to its first")
+			assert.True(t, strings.HasSuffix(seq, ansi.RestoreCursor))
+			assert.Zero(t, ansi.StringWidth(seq), "the layout must not count the image")
+		})
+	}
+	assert.Empty(t, RenderImage(img, 4, 2, ImageNone))
+	assert.Empty(t, RenderImage(img, 0, 2, ImageKitty))
+	assert.Empty(t, RenderImage(nil, 4, 2, ImageKitty))
+}
+
+func TestRenderImage_SizesToCells(t *testing.T) {
+	img := noisyImage(8, 8)
+	assert.Contains(t, RenderImage(img, 4, 2, ImageKitty), "c=4,r=2")
+	assert.Contains(t, RenderImage(img, 4, 2, ImageITerm), "width=4;height=2")
+	assert.Contains(t, RenderImage(img, 4, 2, ImageSixel), `"1;1;40;40`)
+}
+
+func TestKittyImage_ChunksLargeImages(t *testing.T) {
+	seq := kittyImage(noisyImage(64, 64), 4, 2)
+	assert.True(t, strings.HasPrefix(seq, ClearImages(ImageKitty)), "earlier images are replaced")
+	assert.Contains(t, seq, "m=1;")
+	assert.Equal(t, 1, strings.Count(seq, "m=0;"), "only the last chunk ends the image")
+	for chunk := range strings.SplitSeq(seq, "\x1b\\") {
+		if _, payload, ok := strings.Cut(chunk, ";"); ok {
+			assert.LessOrEqual(t, len(payload), kittyChunkSize)
+		}
+	}
+}
+
+func TestSixelImage_EncodesBands(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 5, 7))
+	for y := range 7 {
+		for x := range 5 {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	img.Set(4, 6, color.RGBA{}) // transparent, left undrawn
+
+	seq := sixelImage(img)
+	assert.True(t, strings.HasPrefix(seq, "\x1bP0;1;0q\"1;1;5;7"))
+	assert.True(t, strings.HasSuffix(seq, "\x1b\\"))
+	// Pure red is entry 5*36 of the cube. The first band is five full
+	// sixels; the second has only its top pixel, and not in the last column.
+	assert.Contains(t, seq, "#180!5~$-")
+	assert.Contains(t, seq, "#180!4@?$-")
+}
+
+func TestWriteSixelRun(t *testing.T) {
+	var b strings.Builder
+	writeSixelRun(&b, []byte("~~~~~@@@?"))
+	assert.Equal(t, "!5~@@@?", b.String())
+}
+
+func TestDetectImageProtocol(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want ImageProtocol
+	}{
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, ImageKitty},
+		{"ghostty", map[string]string{"TERM_PROGRAM": "ghostty"}, ImageKitty},
+		{"iterm", map[string]string{"TERM_PROGRAM": "iTerm.app"}, ImageITerm},
+		{"wezterm", map[string]string{"TERM_PROGRAM": "WezTerm"}, ImageITerm},
+		{"foot", map[string]string{"TERM": "foot"}, ImageSixel},
+		{"windows terminal", map[string]string{"WT_SESSION": "1"}, ImageSixel},
+		{"tmux in kitty", map[string]string{"TERM": "tmux-256color", "KITTY_WINDOW_ID": "1"}, ImageNone},
+		{"plain xterm", map[string]string{"TERM": "xterm-256color"}, ImageNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"TERM", "TERM_PROGRAM", "TMUX", "KITTY_WINDOW_ID", "LC_TERMINAL", "KONSOLE_VERSION", "WT_SESSION"} {
+				t.Setenv(key, tt.env[key])
+			}
+			assert.Equal(t, tt.want, DetectImageProtocol())
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package ui
+
+import (
+	"io"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// UseTerminalColorProfile detects the color profile of the terminal behind w
+// (honoring TERM, COLORTERM, NO_COLOR and CLICOLOR_FORCE) and makes every
+// style render in it, so the hex palette degrades to the nearest 256 or 16
+// colors instead of emitting true-color escapes a terminal cannot show. Call
+// it before the program starts: lipgloss otherwise detects lazily, mid-render.
+func UseTerminalColorProfile(w io.Writer) termenv.Profile {
+	profile := termenv.NewOutput(w).EnvColorProfile()
+	lipgloss.SetColorProfile(profile)
+	return profile
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keepColorProfile restores the global lipgloss profile after the test.
+func keepColorProfile(t *testing.T) {
+	t.Helper()
+	prev := lipgloss.ColorProfile()
+	t.Cleanup(func() { lipgloss.SetColorProfile(prev) })
+}
+
+func TestUseTerminalColorProfile_DownsamplesToANSI(t *testing.T) {
+	keepColorProfile(t)
+	t.Setenv("NO_COLOR", "")
+	// A forced, non-TTY output falls back to the 16-color profile.
+	t.Setenv("CLICOLOR_FORCE", "1")
+
+	profile := UseTerminalColorProfile(&bytes.Buffer{})
+	require.Equal(t, termenv.ANSI, profile)
+
+	out := lipgloss.NewStyle().Foreground(ErrorColor).Render("x")
+	assert.Contains(t, out, "\x1b[91m", "#FF3333 maps to bright red")
+	assert.NotContains(t, out, "38;2;", "no true-color escape")
+}
+
+func TestUseTerminalColorProfile_NoColor(t *testing.T) {
+	keepColorProfile(t)
+	t.Setenv("NO_COLOR", "1")
+
+	assert.Equal(t, termenv.Ascii, UseTerminalColorProfile(&bytes.Buffer{}))
+	assert.Equal(t, "x", lipgloss.NewStyle().Foreground(ErrorColor).Render("x"))
+}
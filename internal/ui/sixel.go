@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// sixelLevels is how many levels of each primary the sixel palette has: a
+// 6x6x6 cube of 216 colors, which every sixel terminal can hold and which
+// is plenty for a cover a few dozen cells wide.
+const sixelLevels = 6
+
+// sixelImage encodes img as sixel graphics in the 216-color cube palette.
+// Fully transparent pixels are left undrawn.
+func sixelImage(img *image.RGBA) string {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	// idx holds each pixel's palette entry, or -1 for a transparent one.
+	idx := make([]int, w*h)
+	for y := range h {
+		for x := range w {
+			p := img.Pix[img.PixOffset(x, y):]
+			if p[3] == 0 {
+				idx[y*w+x] = -1
+				continue
+			}
+			idx[y*w+x] = (sixelLevel(p[0])*sixelLevels+sixelLevel(p[1]))*sixelLevels + sixelLevel(p[2])
+		}
+	}
+
+	var b strings.Builder
+	// P2=1 keeps undrawn pixels transparent; the raster attributes give the
+	// 1:1 pixel aspect and the size.
+	fmt.Fprintf(&b, "\x1bP0;1;0q\"1;1;%d;%d", w, h)
+	for c := range sixelLevels * sixelLevels * sixelLevels {
+		r, g, bl := c/(sixelLevels*sixelLevels), c/sixelLevels%sixelLevels, c%sixelLevels
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", c, r*100/(sixelLevels-1), g*100/(sixelLevels-1), bl*100/(sixelLevels-1))
+	}
+
+	row := make([]byte, w)
+	for band := 0; band < h; band += 6 {
+		var used [sixelLevels * sixelLevels * sixelLevels]bool
+		for i := band * w; i < min(band+6, h)*w; i++ {
+			if idx[i] >= 0 {
+				used[idx[i]] = true
+			}
+		}
+		for c, ok := range used {
+			if !ok {
+				continue
+			}
+			for x := range w {
+				var bits byte
+				for k := 0; k < 6 && band+k < h; k++ {
+					if idx[(band+k)*w+x] == c {
+						bits |= 1 << k
+					}
+				}
+				row[x] = '?' + bits
+			}
+			fmt.Fprintf(&b, "#%d", c)
+			writeSixelRun(&b, row)
+			b.WriteByte('$') // back to the start of the band for the next color
+		}
+		b.WriteByte('-') // next band
+	}
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+// sixelLevel maps an 8-bit channel to the nearest of the palette's levels.
+func sixelLevel(v uint8) int {
+	return (int(v)*(sixelLevels-1) + 127) / 255
+}
+
+// writeSixelRun writes row, collapsing runs of a repeated sixel with the
+// "!count" repeat introducer where that is shorter.
+func writeSixelRun(b *strings.Builder, row []byte) {
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(b, "!%d%c", n, row[i])
+		} else {
+			b.Write(row[i:j])
+		}
+		i = j
+	}
+}
@@ -2,56 +2,88 @@ package ui
 
 import "github.com/charmbracelet/lipgloss"
 
-// Color palette - SomaFM inspired
+// Color palette, set from the active Theme by ApplyTheme. Defaults to the
+// SomaFM-inspired DefaultTheme.
 var (
-	TitleColor       = lipgloss.Color("#ff0709") // Red for title
-	PrimaryColor     = lipgloss.Color("#D8A24D") // Golden accent
-	PlayingColor     = lipgloss.Color("#1a9096") // Teal for playing
-	ErrorColor       = lipgloss.Color("#FF3333") // Red for errors
-	SubtleColor      = lipgloss.Color("#666666") // Gray for secondary text
-	SearchMatchColor = lipgloss.Color("#E6DB74") // Yellow for search matches
+	TitleColor       lipgloss.Color // Title of the app
+	PrimaryColor     lipgloss.Color // Accent: selection, headings, spinners
+	PlayingColor     lipgloss.Color // The playing channel
+	ErrorColor       lipgloss.Color // Errors
+	SubtleColor      lipgloss.Color // Secondary text
+	SearchMatchColor lipgloss.Color // Search matches and the search bar
+	CustomColor      lipgloss.Color // Non-SomaFM stations
+	TextColor        lipgloss.Color // Body text of panes and overlays
+	BrightColor      lipgloss.Color // Channel titles
 )
 
-// Styles
+// Styles, rebuilt from the palette by ApplyTheme.
 var (
+	TitleStyle            lipgloss.Style
+	StatusBarStyle        lipgloss.Style
+	StatusPlayingStyle    lipgloss.Style
+	StatusStoppedStyle    lipgloss.Style
+	StatusConnectingStyle lipgloss.Style
+	TrackInfoStyle        lipgloss.Style
+	LoadingStyle          lipgloss.Style
+	ErrorBoxStyle         lipgloss.Style
+	SearchBarStyle        lipgloss.Style
+)
+
+func init() {
+	ApplyTheme(Themes[DefaultTheme])
+}
+
+// ApplyTheme makes t the palette every style renders with. Call it before
+// building the list delegate, which copies its colors at construction.
+func ApplyTheme(t Theme) {
+	TitleColor = t.Title
+	PrimaryColor = t.Primary
+	PlayingColor = t.Playing
+	ErrorColor = t.Error
+	SubtleColor = t.Subtle
+	SearchMatchColor = t.SearchMatch
+	CustomColor = t.Custom
+	TextColor = t.Text
+	BrightColor = t.Bright
+
 	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(TitleColor).
-			MarginLeft(2)
+		Bold(true).
+		Foreground(TitleColor).
+		MarginLeft(2)
 
 	StatusBarStyle = lipgloss.NewStyle().
-			Padding(0, 1).
-			MarginTop(1)
+		Padding(0, 1).
+		MarginTop(1)
 
 	StatusPlayingStyle = lipgloss.NewStyle().
-				Foreground(PlayingColor).
-				Bold(true)
+		Foreground(PlayingColor).
+		Bold(true)
 
 	StatusStoppedStyle = lipgloss.NewStyle().
-				Foreground(SubtleColor)
+		Foreground(SubtleColor)
 
 	StatusConnectingStyle = lipgloss.NewStyle().
-				Foreground(PrimaryColor).
-				Bold(true)
+		Foreground(PrimaryColor).
+		Bold(true)
 
 	TrackInfoStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#CCCCCC")).
-			Italic(true)
+		Foreground(TextColor).
+		Italic(true)
 
 	LoadingStyle = lipgloss.NewStyle().
-			Foreground(PrimaryColor).
-			Bold(true).
-			Padding(2, 4)
+		Foreground(PrimaryColor).
+		Bold(true).
+		Padding(2, 4)
 
 	ErrorBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ErrorColor).
-			Foreground(ErrorColor).
-			Padding(1, 2).
-			MarginTop(2).
-			MarginLeft(2)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ErrorColor).
+		Foreground(ErrorColor).
+		Padding(1, 2).
+		MarginTop(2).
+		MarginLeft(2)
 
 	SearchBarStyle = lipgloss.NewStyle().
-			Foreground(SearchMatchColor).
-			MarginLeft(2)
-)
+		Foreground(SearchMatchColor).
+		MarginLeft(2)
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a color palette for the whole TUI. Colors are "#rrggbb", "#rgb",
+// or an ANSI index 0-255; UseTerminalColorProfile degrades them to what the
+// terminal shows.
+type Theme struct {
+	Title       lipgloss.Color
+	Primary     lipgloss.Color
+	Playing     lipgloss.Color
+	Error       lipgloss.Color
+	Subtle      lipgloss.Color
+	SearchMatch lipgloss.Color
+	Custom      lipgloss.Color
+	Text        lipgloss.Color
+	Bright      lipgloss.Color
+}
+
+// DefaultTheme names the built-in theme used when none is configured.
+const DefaultTheme = "somafm"
+
+// Themes holds the built-in themes by name.
+var Themes = map[string]Theme{
+	"somafm": {
+		Title:       "#ff0709",
+		Primary:     "#D8A24D",
+		Playing:     "#1a9096",
+		Error:       "#FF3333",
+		Subtle:      "#666666",
+		SearchMatch: "#E6DB74",
+		Custom:      "#AE81FF",
+		Text:        "#CCCCCC",
+		Bright:      "#FFFFFF",
+	},
+	"nord": {
+		Title:       "#BF616A",
+		Primary:     "#88C0D0",
+		Playing:     "#A3BE8C",
+		Error:       "#BF616A",
+		Subtle:      "#616E88",
+		SearchMatch: "#EBCB8B",
+		Custom:      "#B48EAD",
+		Text:        "#D8DEE9",
+		Bright:      "#ECEFF4",
+	},
+	"gruvbox": {
+		Title:       "#fb4934",
+		Primary:     "#fabd2f",
+		Playing:     "#8ec07c",
+		Error:       "#fb4934",
+		Subtle:      "#928374",
+		SearchMatch: "#fe8019",
+		Custom:      "#d3869b",
+		Text:        "#d5c4a1",
+		Bright:      "#fbf1c7",
+	},
+	// light suits terminals with a light background.
+	"light": {
+		Title:       "#C4000A",
+		Primary:     "#9A6700",
+		Playing:     "#00767C",
+		Error:       "#D70000",
+		Subtle:      "#8A8A8A",
+		SearchMatch: "#B35900",
+		Custom:      "#7B3FC4",
+		Text:        "#3A3A3A",
+		Bright:      "#000000",
+	},
+}
+
+// ThemeColors lists the color names a theme's colors are overridden by, in
+// the order of the Theme fields.
+var ThemeColors = []string{"title", "primary", "playing", "error", "subtle", "search_match", "custom", "text", "bright"}
+
+// color returns the field of t called name, one of ThemeColors.
+func (t *Theme) color(name string) *lipgloss.Color {
+	switch name {
+	case "title":
+		return &t.Title
+	case "primary":
+		return &t.Primary
+	case "playing":
+		return &t.Playing
+	case "error":
+		return &t.Error
+	case "subtle":
+		return &t.Subtle
+	case "search_match":
+		return &t.SearchMatch
+	case "custom":
+		return &t.Custom
+	case "text":
+		return &t.Text
+	case "bright":
+		return &t.Bright
+	default:
+		return nil
+	}
+}
+
+// LookupTheme returns the built-in theme called name ("" for DefaultTheme)
+// with the colors in overrides, keyed by ThemeColors names, replacing its
+// own.
+func LookupTheme(name string, overrides map[string]string) (Theme, error) {
+	if name == "" {
+		name = DefaultTheme
+	}
+	t, ok := Themes[name]
+	if !ok {
+		names := make([]string, 0, len(Themes))
+		for n := range Themes {
+			names = append(names, n)
+		}
+		slices.Sort(names)
+		return Theme{}, fmt.Errorf("unknown theme %q (want %s)", name, strings.Join(names, ", "))
+	}
+	for key, value := range overrides {
+		c := t.color(key)
+		if c == nil {
+			return Theme{}, fmt.Errorf("unknown theme color %q (want %s)", key, strings.Join(ThemeColors, ", "))
+		}
+		*c = lipgloss.Color(value)
+	}
+	return t, nil
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupTheme_Overrides(t *testing.T) {
+	theme, err := LookupTheme("nord", map[string]string{"primary": "#ff8800", "search_match": "214"})
+	require.NoError(t, err)
+	assert.Equal(t, lipgloss.Color("#ff8800"), theme.Primary)
+	assert.Equal(t, lipgloss.Color("214"), theme.SearchMatch)
+	assert.Equal(t, Themes["nord"].Playing, theme.Playing, "other colors stay the theme's")
+	assert.Equal(t, lipgloss.Color("#88C0D0"), Themes["nord"].Primary, "the built-in is left alone")
+
+	theme, err = LookupTheme("", nil)
+	require.NoError(t, err)
+	assert.Equal(t, Themes[DefaultTheme], theme)
+}
+
+func TestLookupTheme_Unknown(t *testing.T) {
+	_, err := LookupTheme("solarized", nil)
+	assert.ErrorContains(t, err, `unknown theme "solarized" (want gruvbox, light, nord, somafm)`)
+
+	_, err = LookupTheme("nord", map[string]string{"accent": "#fff"})
+	assert.ErrorContains(t, err, `unknown theme color "accent"`)
+}
+
+func TestThemes_SetEveryColor(t *testing.T) {
+	for name, theme := range Themes {
+		for _, color := range ThemeColors {
+			assert.NotEmpty(t, *theme.color(color), "%s: %s", name, color)
+		}
+	}
+}
+
+func TestApplyTheme_RestylesPalette(t *testing.T) {
+	t.Cleanup(func() { ApplyTheme(Themes[DefaultTheme]) })
+
+	ApplyTheme(Themes["gruvbox"])
+
+	assert.Equal(t, Themes["gruvbox"].Primary, PrimaryColor)
+	assert.Equal(t, lipgloss.TerminalColor(Themes["gruvbox"].SearchMatch), SearchBarStyle.GetForeground())
+	assert.Equal(t, lipgloss.TerminalColor(Themes["gruvbox"].Playing), StatusPlayingStyle.GetForeground())
+	d := NewStyledDelegate(nil, nil, nil, nil)
+	assert.Equal(t, lipgloss.TerminalColor(Themes["gruvbox"].Primary), d.Styles.SelectedTitle.GetForeground())
+	assert.Equal(t, lipgloss.TerminalColor(Themes["gruvbox"].Custom), d.CustomColor)
+}
@@ -2,10 +2,15 @@ package playlist
 
 import (
 	"bufio"
+	"cmp"
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"net/url"
+	"path"
+	"slices"
 	"strings"
 	"time"
 
@@ -17,45 +22,93 @@ import (
 // so an unbounded read would be a memory hazard.
 const maxPlaylistBytes = 1 << 20 // 1 MiB
 
+// IsPlaylistURL reports whether rawURL names a .pls playlist rather than a
+// stream, judging by the extension of its path.
+func IsPlaylistURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(path.Ext(u.Path), ".pls")
+}
+
 // GetStreamURLFromPlaylist fetches a playlist file from a URL, parses it,
 // and returns the first stream URL found within the playlist.
 // It supports .pls playlist formats.
 func GetStreamURLFromPlaylist(playlistURL, userAgent string) (string, error) {
+	urls, err := GetStreamURLsFromPlaylist(playlistURL, userAgent)
+	if err != nil {
+		return "", err
+	}
+	return urls[0], nil
+}
+
+// GetStreamURLsFromPlaylist fetches a .pls playlist and returns every stream
+// URL in it, in FileN order without duplicates. SomaFM lists the same
+// stream on several servers, so the later entries are fallbacks for the
+// first. The result is never empty when the error is nil.
+func GetStreamURLsFromPlaylist(playlistURL, userAgent string) ([]string, error) {
 	// Fetch the playlist file content
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
 	req, err := security.NewRequest(ctx, playlistURL, userAgent)
 	if err != nil {
-		return "", fmt.Errorf("invalid playlist URL: %w", err)
+		return nil, fmt.Errorf("invalid playlist URL: %w", err)
 	}
+	return fetchStreamURLs(security.HTTPClient, req)
+}
+
+// GetStationStreamURLs is GetStreamURLsFromPlaylist for the playlist of a
+// directory or custom station, which lives on the station's own host rather
+// than an allowed one, so it is fetched like a stream.
+func GetStationStreamURLs(playlistURL, userAgent string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
 
-	resp, err := security.HTTPClient.Do(req) // #nosec G704 -- URL validated by security.NewRequest()
+	req, client, err := security.NewStreamRequest(ctx, playlistURL, userAgent)
 	if err != nil {
-		return "", fmt.Errorf("failed to get playlist from %s: %w", playlistURL, err)
+		return nil, fmt.Errorf("invalid playlist URL: %w", err)
+	}
+	return fetchStreamURLs(client, req)
+}
+
+// fetchStreamURLs sends a validated playlist request with client and
+// returns the stream URLs in the reply.
+func fetchStreamURLs(client *http.Client, req *http.Request) ([]string, error) {
+	playlistURL := req.URL.String()
+	resp, err := client.Do(req) // #nosec G704 -- URL validated by security.NewRequest() or security.NewStreamRequest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playlist from %s: %w", playlistURL, err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	// Check if the HTTP request was successful
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code %d for playlist %s", resp.StatusCode, playlistURL)
+		return nil, fmt.Errorf("unexpected status code %d for playlist %s", resp.StatusCode, playlistURL)
 	}
 
-	url, err := parseFirstStreamURL(io.LimitReader(resp.Body, maxPlaylistBytes))
+	urls, err := parseStreamURLs(io.LimitReader(resp.Body, maxPlaylistBytes))
 	if err != nil {
-		return "", fmt.Errorf("error reading playlist body from %s: %w", playlistURL, err)
+		return nil, fmt.Errorf("error reading playlist body from %s: %w", playlistURL, err)
 	}
-	if url == "" {
-		return "", fmt.Errorf("no stream URL found in playlist %s", playlistURL)
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no stream URL found in playlist %s", playlistURL)
 	}
-	return url, nil
+	return urls, nil
 }
 
-// parseFirstStreamURL scans .pls content for the first FileN entry and
-// returns its URL, or "" when none is found. Real-world playlists are not
-// always spec-exact, so keys match case-insensitively and whitespace around
-// keys, values, and the "=" is tolerated.
-func parseFirstStreamURL(r io.Reader) (string, error) {
+// parseStreamURLs scans .pls content for FileN entries and returns their
+// URLs ordered by N, dropping repeats. Real-world playlists are not always
+// spec-exact, so keys match case-insensitively, whitespace around keys,
+// values, and the "=" is tolerated, and entries may be out of order or
+// have gaps.
+func parseStreamURLs(r io.Reader) ([]string, error) {
+	type entry struct {
+		n   int
+		url string
+	}
+	var entries []entry
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -63,28 +116,42 @@ func parseFirstStreamURL(r io.Reader) (string, error) {
 		if !ok {
 			continue
 		}
-		if isFileKey(strings.TrimSpace(key)) {
-			if url := strings.TrimSpace(value); url != "" {
-				return url, nil
-			}
+		n, ok := fileIndex(strings.TrimSpace(key))
+		if !ok {
+			continue
+		}
+		if url := strings.TrimSpace(value); url != "" {
+			entries = append(entries, entry{n, url})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(entries, func(a, b entry) int { return cmp.Compare(a.n, b.n) })
+	var urls []string
+	for _, e := range entries {
+		if !slices.Contains(urls, e.url) {
+			urls = append(urls, e.url)
 		}
 	}
-	return "", scanner.Err()
+	return urls, nil
 }
 
-// isFileKey reports whether a .pls key names a stream entry: "file" followed
-// by digits, in any case.
-func isFileKey(key string) bool {
+// fileIndex parses a .pls key naming a stream entry, "file" followed by
+// digits in any case, and returns its number.
+func fileIndex(key string) (int, bool) {
 	rest, ok := cutPrefixFold(key, "file")
 	if !ok || rest == "" {
-		return false
+		return 0, false
 	}
+	n := 0
 	for _, r := range rest {
 		if r < '0' || r > '9' {
-			return false
+			return 0, false
 		}
+		n = min(n*10+int(r-'0'), math.MaxInt32)
 	}
-	return true
+	return n, true
 }
 
 // cutPrefixFold is strings.CutPrefix with ASCII case-insensitive matching.
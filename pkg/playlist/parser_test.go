@@ -3,6 +3,7 @@ package playlist
 import (
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 
 	"somad/internal/security/securitytest"
@@ -125,3 +126,67 @@ func TestGetStreamURLFromPlaylistInvalidURL(t *testing.T) {
 		t.Error("GetStreamURLFromPlaylist() should return error for invalid URL")
 	}
 }
+
+func TestGetStreamURLsFromPlaylist(t *testing.T) {
+	securitytest.AllowTestHosts(t)
+
+	// Entries out of order, with a gap and a repeat.
+	content := "[playlist]\n" +
+		"File3=http://ice3.somafm.com/groovesalad-128-mp3\n" +
+		"File1=http://ice1.somafm.com/groovesalad-128-mp3\n" +
+		"File5=http://ice1.somafm.com/groovesalad-128-mp3\n" +
+		"File2=http://ice2.somafm.com/groovesalad-128-mp3\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	got, err := GetStreamURLsFromPlaylist(server.URL, "soma/test")
+	if err != nil {
+		t.Fatalf("GetStreamURLsFromPlaylist() error = %v", err)
+	}
+	want := []string{
+		"http://ice1.somafm.com/groovesalad-128-mp3",
+		"http://ice2.somafm.com/groovesalad-128-mp3",
+		"http://ice3.somafm.com/groovesalad-128-mp3",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("GetStreamURLsFromPlaylist() = %v, want %v", got, want)
+	}
+}
+
+func TestGetStationStreamURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("[playlist]\nFile1=http://stream.example.org:8000/live\n"))
+	}))
+	defer server.Close()
+
+	// A station's own host needs no allowlisting, but a local one is refused.
+	if _, err := GetStationStreamURLs(server.URL, "soma/test"); err == nil {
+		t.Error("GetStationStreamURLs() should refuse a loopback host")
+	}
+
+	securitytest.AllowTestHosts(t)
+	got, err := GetStationStreamURLs(server.URL, "soma/test")
+	if err != nil {
+		t.Fatalf("GetStationStreamURLs() error = %v", err)
+	}
+	if want := []string{"http://stream.example.org:8000/live"}; !slices.Equal(got, want) {
+		t.Errorf("GetStationStreamURLs() = %v, want %v", got, want)
+	}
+}
+
+func TestIsPlaylistURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://somafm.com/groovesalad.pls":         true,
+		"http://radio.example.org/LISTEN.PLS?sid=1":  true,
+		"http://radio.example.org/live.mp3":          false,
+		"http://radio.example.org/stream?format=pls": false,
+		"http://radio.example.org/":                  false,
+	}
+	for rawURL, want := range tests {
+		if got := IsPlaylistURL(rawURL); got != want {
+			t.Errorf("IsPlaylistURL(%q) = %v, want %v", rawURL, got, want)
+		}
+	}
+}